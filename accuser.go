@@ -0,0 +1,334 @@
+package tgo
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+)
+
+// Kinds of double signing found by an Accuser
+const (
+	DoubleBaking      = "double_baking"
+	DoubleEndorsement = "double_endorsement"
+)
+
+// defaultAccuserLevels is how many levels below the highest level seen an Accuser remembers
+const defaultAccuserLevels = 64
+
+// DoubleSigning is a delegate signing two different blocks or endorsements at the same level
+type DoubleSigning struct {
+	Kind     string
+	Delegate string
+	Level    int64
+	// Headers are set for double baking, Endorsements for double endorsement
+	Headers      [2]BlockHeader
+	Endorsements [2]InlinedEndorsement
+	// OperationHash is the hash of the injected evidence when the accuser injects it
+	OperationHash string
+}
+
+// Accuser remembers the blocks and endorsements signed by each delegate at recent levels and
+// reports delegates signing twice at the same level, optionally injecting the evidence
+type Accuser struct {
+	rpc *RPC
+	// Inject makes the accuser inject the evidence of every double signing found
+	Inject bool
+	// Levels is how many levels below the highest level seen are remembered, 64 by default
+	Levels int64
+
+	mu           sync.Mutex
+	chainID      ChainID
+	highest      int64
+	blocks       map[int64]map[string]BlockHeader
+	endorsements map[int64]map[string]InlinedEndorsement
+	// endorsers caches the public keys of the delegates with endorsing rights at each level
+	endorsers map[int64]map[string]string
+	// reported holds the kinds and delegates already reported at each level
+	reported map[int64]map[string]bool
+}
+
+// NewAccuser returns an accuser reading blocks and rights through rpc
+func NewAccuser(rpc *RPC) *Accuser {
+	return &Accuser{
+		rpc:          rpc,
+		blocks:       map[int64]map[string]BlockHeader{},
+		endorsements: map[int64]map[string]InlinedEndorsement{},
+		endorsers:    map[int64]map[string]string{},
+		reported:     map[int64]map[string]bool{},
+	}
+}
+
+// AddBlock records the block blockHash along with the endorsements it includes and returns
+// the double signings they reveal
+func (a *Accuser) AddBlock(ctx context.Context, blockHash BlockHash) ([]DoubleSigning, error) {
+	header, err := a.rpc.GetBlockHeader(ctx, blockHash.ID())
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := a.rpc.GetBlockMetadata(ctx, header.Hash.ID())
+	if err != nil {
+		return nil, err
+	}
+	passes, err := a.rpc.GetBlockOperations(ctx, header.Hash.ID())
+	if err != nil {
+		return nil, err
+	}
+	found := []DoubleSigning{}
+	a.mu.Lock()
+	if d := a.recordBlock(string(metadata.Baker), header); d != nil {
+		found = append(found, *d)
+	}
+	if len(passes) > 0 {
+		for _, op := range passes[0] {
+			endorsement, err := op.InlinedEndorsement()
+			if err != nil {
+				continue
+			}
+			if d := a.recordEndorsement(op.Contents[0].Metadata.Delegate, endorsement); d != nil {
+				found = append(found, *d)
+			}
+		}
+	}
+	a.mu.Unlock()
+	return a.accuse(ctx, found)
+}
+
+// AddEndorsement records an endorsement seen in the mempool and returns the double signing it
+// reveals if any. Its delegate is found by checking its signature against the keys of the
+// delegates with endorsing rights at its level, only ed25519 delegates can be identified.
+func (a *Accuser) AddEndorsement(ctx context.Context, op MempoolOperation) ([]DoubleSigning, error) {
+	endorsement, err := BlockOperation{Hash: op.Hash, Branch: op.Branch, Signature: op.Signature,
+		Contents: appliedContents(op.Contents)}.InlinedEndorsement()
+	if err != nil {
+		return nil, err
+	}
+	delegate, err := a.endorser(ctx, endorsement)
+	if err != nil || delegate == "" {
+		return nil, err
+	}
+	a.mu.Lock()
+	d := a.recordEndorsement(delegate, endorsement)
+	a.mu.Unlock()
+	if d == nil {
+		return nil, nil
+	}
+	return a.accuse(ctx, []DoubleSigning{*d})
+}
+
+// Watch feeds the accuser with the blocks validated by the node and the endorsements entering
+// its mempool and emits the double signings found until ctx is cancelled. Both channels are
+// closed once watching stops. errs receives the failures met while processing blocks and
+// endorsements and the errors that stopped the streams if any, as described in the package
+// documentation.
+func (a *Accuser) Watch(ctx context.Context) (<-chan DoubleSigning, <-chan error) {
+	found := make(chan DoubleSigning)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(found)
+		defer close(errs)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		blocks, blockErrs := a.rpc.MonitorValidBlocks(ctx, ValidBlocksOptions{Chains: []string{a.rpc.ChainAlias(ctx)}})
+		ops, opErrs := a.rpc.MonitorMempoolOperations(ctx, a.rpc.ChainAlias(ctx), MempoolMonitorOptions{Applied: true, BranchDelayed: true, BranchRefused: true, Refused: true})
+		for blocks != nil || ops != nil {
+			var doubles []DoubleSigning
+			var err error
+			select {
+			case block, ok := <-blocks:
+				if !ok {
+					blocks = nil
+					if err := <-blockErrs; err != nil {
+						errs <- err
+					}
+					continue
+				}
+				doubles, err = a.AddBlock(ctx, block.Hash)
+			case op, ok := <-ops:
+				if !ok {
+					ops = nil
+					if err := <-opErrs; err != nil {
+						errs <- err
+					}
+					continue
+				}
+				if len(op.Contents) != 1 || op.Contents[0].Kind != "endorsement" {
+					continue
+				}
+				doubles, err = a.AddEndorsement(ctx, op)
+			}
+			if err != nil {
+				reportError(errs, err)
+			}
+			for _, d := range doubles {
+				select {
+				case found <- d:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return found, errs
+}
+
+// recordBlock remembers the block of baker and returns the double baking it reveals, a.mu must be held
+func (a *Accuser) recordBlock(baker string, header BlockHeader) *DoubleSigning {
+	if !a.track(header.Level) || baker == "" {
+		return nil
+	}
+	if a.blocks[header.Level] == nil {
+		a.blocks[header.Level] = map[string]BlockHeader{}
+	}
+	previous, seen := a.blocks[header.Level][baker]
+	if !seen {
+		a.blocks[header.Level][baker] = header
+		return nil
+	}
+	if previous.Hash == header.Hash {
+		return nil
+	}
+	return a.report(DoubleSigning{Kind: DoubleBaking, Delegate: baker, Level: header.Level, Headers: [2]BlockHeader{previous, header}})
+}
+
+// recordEndorsement remembers the endorsement of delegate and returns the double endorsement
+// it reveals, a.mu must be held
+func (a *Accuser) recordEndorsement(delegate string, endorsement InlinedEndorsement) *DoubleSigning {
+	level := endorsement.Operations.Level
+	if !a.track(level) || delegate == "" {
+		return nil
+	}
+	if a.endorsements[level] == nil {
+		a.endorsements[level] = map[string]InlinedEndorsement{}
+	}
+	previous, seen := a.endorsements[level][delegate]
+	if !seen {
+		a.endorsements[level][delegate] = endorsement
+		return nil
+	}
+	if previous.Branch == endorsement.Branch {
+		return nil
+	}
+	return a.report(DoubleSigning{Kind: DoubleEndorsement, Delegate: delegate, Level: level, Endorsements: [2]InlinedEndorsement{previous, endorsement}})
+}
+
+// report returns d unless the delegate was already reported for the same kind and level, a.mu must be held
+func (a *Accuser) report(d DoubleSigning) *DoubleSigning {
+	key := d.Kind + "/" + d.Delegate
+	if a.reported[d.Level][key] {
+		return nil
+	}
+	if a.reported[d.Level] == nil {
+		a.reported[d.Level] = map[string]bool{}
+	}
+	a.reported[d.Level][key] = true
+	return &d
+}
+
+// track reports whether level is recent enough to be remembered, forgetting levels that
+// fall out of the window as higher levels are seen. a.mu must be held.
+func (a *Accuser) track(level int64) bool {
+	window := a.Levels
+	if window <= 0 {
+		window = defaultAccuserLevels
+	}
+	if level > a.highest {
+		a.highest = level
+		for l := range a.blocks {
+			if l < level-window {
+				delete(a.blocks, l)
+			}
+		}
+		for l := range a.reported {
+			if l < level-window {
+				delete(a.reported, l)
+			}
+		}
+		for l := range a.endorsements {
+			if l < level-window {
+				delete(a.endorsements, l)
+				delete(a.endorsers, l)
+			}
+		}
+	}
+	return level >= a.highest-window
+}
+
+// endorser returns the delegate having signed endorsement, or an empty string if none of the
+// delegates with endorsing rights at its level did
+func (a *Accuser) endorser(ctx context.Context, endorsement InlinedEndorsement) (string, error) {
+	level := endorsement.Operations.Level
+	a.mu.Lock()
+	chainID, keys := a.chainID, a.endorsers[level]
+	a.mu.Unlock()
+	if chainID == "" {
+		var err error
+		if chainID, err = a.rpc.GetChainID(ctx, a.rpc.ChainAlias(ctx)); err != nil {
+			return "", err
+		}
+	}
+	if keys == nil {
+		rights, err := a.rpc.GetEndorsingRights(ctx, "head", RightsQuery{Levels: []int64{level}})
+		if err != nil {
+			return "", err
+		}
+		keys = map[string]string{}
+		for _, r := range rights {
+			key, err := a.rpc.GetManagerKey(ctx, r.Delegate)
+			if err != nil {
+				return "", err
+			}
+			keys[r.Delegate] = key
+		}
+	}
+	a.mu.Lock()
+	a.chainID, a.endorsers[level] = chainID, keys
+	a.mu.Unlock()
+
+	forged, err := ForgeEndorsement(endorsement.Branch, level)
+	if err != nil {
+		return "", err
+	}
+	chain, err := b58CheckDecode(string(chainID), prefixChainID)
+	if err != nil {
+		return "", err
+	}
+	raw, _ := hex.DecodeString(forged)
+	message := append(append([]byte{endorsementPrefix}, chain...), raw...)
+	for delegate, key := range keys {
+		if ok, err := verifySignature(key, endorsement.Signature, message); err == nil && ok {
+			return delegate, nil
+		}
+	}
+	return "", nil
+}
+
+// accuse injects the evidence of found when Inject is set
+func (a *Accuser) accuse(ctx context.Context, found []DoubleSigning) ([]DoubleSigning, error) {
+	if !a.Inject {
+		return found, nil
+	}
+	var firstErr error
+	for i, d := range found {
+		var hash string
+		var err error
+		if d.Kind == DoubleBaking {
+			hash, err = a.rpc.InjectDoubleBakingEvidence(ctx, d.Headers[0], d.Headers[1])
+		} else {
+			hash, err = a.rpc.InjectDoubleEndorsementEvidence(ctx, d.Endorsements[0], d.Endorsements[1])
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		found[i].OperationHash = hash
+	}
+	return found, firstErr
+}
+
+// appliedContents wraps contents without receipts
+func appliedContents(contents []OperationContents) []AppliedContents {
+	applied := make([]AppliedContents, len(contents))
+	for i, c := range contents {
+		applied[i] = AppliedContents{OperationContents: c}
+	}
+	return applied
+}
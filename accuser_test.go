@@ -0,0 +1,123 @@
+package tgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestAccuserDoubleBaking(t *testing.T) {
+	const baker = "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	routes := map[string]interface{}{
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 40),
+		"POST /injection/operation":                              "ooEvidence",
+	}
+	for _, hash := range []string{"BLa", "BLb"} {
+		routes["GET /chains/main/blocks/"+hash+"/header"] = map[string]interface{}{"hash": hash, "level": 300, "signature": "sig" + hash}
+		routes["GET /chains/main/blocks/"+hash+"/metadata"] = map[string]interface{}{"baker": baker}
+		routes["GET /chains/main/blocks/"+hash+"/operations"] = [][]interface{}{{}, {}, {}, {}}
+	}
+	node, client := newFakeNode(t, routes)
+	accuser := tgo.NewAccuser(client)
+	accuser.Inject = true
+	for i, hash := range []string{"BLa", "BLa", "BLb", "BLb"} {
+		found, err := accuser.AddBlock(context.Background(), tgo.BlockHash(hash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i != 2 {
+			if len(found) != 0 {
+				t.Fatalf("unexpected double signing after block %d: %+v", i, found)
+			}
+			continue
+		}
+		if len(found) != 1 || found[0].Kind != tgo.DoubleBaking || found[0].Delegate != baker || found[0].OperationHash != "ooEvidence" ||
+			found[0].Headers[0].Hash != "BLa" || found[0].Headers[1].Hash != "BLb" {
+			t.Fatalf("unexpected double baking %+v", found)
+		}
+	}
+	if body := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]; len(body) != 1 || !strings.Contains(body[0], `"kind":"double_baking_evidence"`) {
+		t.Fatalf("unexpected evidence %v", body)
+	}
+}
+
+func TestAccuserDoubleEndorsement(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/chain_id": "NetXdQprcVkpaWU",
+		"GET /chains/main/blocks/head/helpers/endorsing_rights": []map[string]interface{}{
+			{"level": 300, "delegate": "tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF", "slots": []int{1}},
+			{"level": 300, "delegate": key.PublicKeyHash(), "slots": []int{2}},
+		},
+		"GET /chains/main/blocks/head/context/contracts/tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF/manager_key": "",
+		"GET /chains/main/blocks/head/context/contracts/" + key.PublicKeyHash() + "/manager_key":          key.PublicKey(),
+	})
+	accuser := tgo.NewAccuser(client)
+	endorse := func(branch string) tgo.MempoolOperation {
+		forged, err := tgo.ForgeEndorsement(tgo.BlockHash(branch), 300)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, _, err := tgo.SignEndorsement(key, "NetXdQprcVkpaWU", forged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tgo.MempoolOperation{Hash: tgo.OperationHash("oo" + branch), Branch: tgo.BlockHash(branch), Signature: sig, Contents: []tgo.OperationContents{{Kind: "endorsement", Level: 300}}}
+	}
+	op1, op2 := endorse("BKiHLREqU3JkXfzEDYAkmmfX48gBDtYhMrpA98s7Aq4SzbUAB6M"), endorse("BKiiym5cWWUEL6xzjK7FtMdP3RzHXYvGYGqmRLj5KvfhsCcaAQb")
+	if found, err := accuser.AddEndorsement(context.Background(), op1); err != nil || len(found) != 0 {
+		t.Fatalf("unexpected double signing %+v: %v", found, err)
+	}
+	found, err := accuser.AddEndorsement(context.Background(), op2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Kind != tgo.DoubleEndorsement || found[0].Level != 300 ||
+		found[0].Endorsements[0].Branch != op1.Branch || found[0].Endorsements[1].Signature != op2.Signature {
+		t.Fatalf("unexpected double endorsement %+v", found)
+	}
+	if found[0].Delegate != key.PublicKeyHash() {
+		t.Fatalf("unexpected delegate %s", found[0].Delegate)
+	}
+
+	forged := tgo.MempoolOperation{Hash: "ooX", Branch: op1.Branch, Signature: op2.Signature, Contents: op1.Contents}
+	if found, err := accuser.AddEndorsement(context.Background(), forged); err != nil || len(found) != 0 {
+		t.Fatalf("expected an unidentified endorsement to be ignored, got %+v: %v", found, err)
+	}
+}
+
+func TestAccuserGenericSignatures(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/chain_id": "NetXdQprcVkpaWU",
+		"GET /chains/main/blocks/head/helpers/endorsing_rights": []map[string]interface{}{
+			{"level": 300, "delegate": key.PublicKeyHash(), "slots": []int{2}},
+		},
+		"GET /chains/main/blocks/head/context/contracts/" + key.PublicKeyHash() + "/manager_key": key.PublicKey(),
+	})
+	accuser := tgo.NewAccuser(client)
+	// the mempool of the node returns the endorsements with generic sig signatures
+	for i, op := range []tgo.MempoolOperation{
+		{Hash: "ooA", Branch: "BKiHLREqU3JkXfzEDYAkmmfX48gBDtYhMrpA98s7Aq4SzbUAB6M", Contents: []tgo.OperationContents{{Kind: "endorsement", Level: 300}},
+			Signature: "sigPo1W3SMofonycikKcveaBYifMXA6Rz8Xusrt188WxPaGHAiGQXhbBntD7icqD11omPpSxG2r8SBnqmTXECyisYSsTTW1Z"},
+		{Hash: "ooB", Branch: "BKiiym5cWWUEL6xzjK7FtMdP3RzHXYvGYGqmRLj5KvfhsCcaAQb", Contents: []tgo.OperationContents{{Kind: "endorsement", Level: 300}},
+			Signature: "siga7gjqLEbjoyF5cZkdjtCSrpq2hyvohf1GrawCiRgkhxA7W423HUckTMNNEC5EEEthtyju3fQZjqmojFACmUYdD1nLbBU1"},
+	} {
+		found, err := accuser.AddEndorsement(context.Background(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && (len(found) != 1 || found[0].Kind != tgo.DoubleEndorsement || found[0].Delegate != key.PublicKeyHash()) {
+			t.Fatalf("unexpected double endorsement %+v", found)
+		}
+	}
+}
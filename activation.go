@@ -0,0 +1,103 @@
+package tgo
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// activationSecretSize is the size in bytes of a fundraiser activation secret
+const activationSecretSize = 20
+
+// Faucet is a fundraiser or testnet faucet account, as found in faucet JSON files
+type Faucet struct {
+	Mnemonic []string `json:"mnemonic"`
+	Secret   string   `json:"secret"`
+	Amount   string   `json:"amount"`
+	Pkh      string   `json:"pkh"`
+	Password string   `json:"password"`
+	Email    string   `json:"email"`
+}
+
+// LoadFaucet reads a faucet JSON file
+func LoadFaucet(path string) (*Faucet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	faucet := &Faucet{}
+	if err := json.Unmarshal(data, faucet); err != nil {
+		return nil, fmt.Errorf("invalid faucet %s: %w", path, err)
+	}
+	return faucet, nil
+}
+
+// Key derives the key of the faucet account from its mnemonic, email and password and checks
+// that it matches Pkh
+func (f *Faucet) Key() (*Key, error) {
+	mnemonic := strings.Join(f.Mnemonic, " ")
+	seed := pbkdf2SHA512([]byte(mnemonic), []byte("mnemonic"+f.Email+f.Password), 2048, 64)
+	key := &Key{privateKey: ed25519.NewKeyFromSeed(seed[:ed25519.SeedSize])}
+	if f.Pkh != "" && key.PublicKeyHash() != f.Pkh {
+		return nil, fmt.Errorf("faucet key %s does not match %s", key.PublicKeyHash(), f.Pkh)
+	}
+	return key, nil
+}
+
+// ActivateAccount injects an activate_account operation crediting the fundraiser balance of
+// the tz1 account pkh, given its hex encoded activation secret, and returns the operation hash.
+// Activations are anonymous operations, the secret proves the ownership of the account.
+func (rpc *RPC) ActivateAccount(ctx context.Context, pkh, secret string) (string, error) {
+	if !strings.HasPrefix(pkh, "tz1") {
+		return "", fmt.Errorf("only tz1 accounts can be activated, got %s", pkh)
+	}
+	if raw, err := hex.DecodeString(secret); err != nil || len(raw) != activationSecretSize {
+		return "", fmt.Errorf("invalid activation secret %q", secret)
+	}
+	return rpc.sendUnsigned(ctx, []OperationContents{{
+		Kind:   "activate_account",
+		Pkh:    pkh,
+		Secret: secret,
+	}})
+}
+
+// ActivateFaucet activates the faucet account and returns the operation hash
+func (rpc *RPC) ActivateFaucet(ctx context.Context, faucet *Faucet) (string, error) {
+	key, err := faucet.Key()
+	if err != nil {
+		return "", err
+	}
+	return rpc.ActivateAccount(ctx, key.PublicKeyHash(), faucet.Secret)
+}
+
+// pbkdf2SHA512 derives a key of keyLen bytes from password with PBKDF2-HMAC-SHA512
+func pbkdf2SHA512(password, salt []byte, iterations, keyLen int) []byte {
+	mac := hmac.New(sha512.New, password)
+	key := make([]byte, 0, keyLen)
+	for block := uint32(1); len(key) < keyLen; block++ {
+		mac.Reset()
+		mac.Write(salt)
+		var counter [4]byte
+		binary.BigEndian.PutUint32(counter[:], block)
+		mac.Write(counter[:])
+		u := mac.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			mac.Reset()
+			mac.Write(u)
+			u = mac.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
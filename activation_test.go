@@ -0,0 +1,66 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestActivateFaucet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "faucet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "faucet.json")
+	faucet := `{
+		"mnemonic": ["zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "zoo", "wrong"],
+		"secret": "0123456789abcdef0123456789abcdef01234567",
+		"amount": "12345678",
+		"pkh": "tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF",
+		"password": "pw",
+		"email": "a@b.c"
+	}`
+	if err := ioutil.WriteFile(path, []byte(faucet), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := tgo.LoadFaucet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 60),
+		"POST /injection/operation":                              "ooHash",
+	})
+	hash, err := client.ActivateFaucet(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := tgo.Operation{}
+	if err := json.Unmarshal([]byte(node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]), &forged); err != nil {
+		t.Fatal(err)
+	}
+	c := forged.Contents[0]
+	if hash != "ooHash" || c.Kind != "activate_account" || c.Pkh != f.Pkh || c.Secret != f.Secret || c.Source != "" {
+		t.Fatalf("unexpected activation %+v", c)
+	}
+	// activations are injected unsigned
+	if injected := node.bodies["POST /injection/operation"][0]; injected != `"`+strings.Repeat("ab", 60)+`"` {
+		t.Fatalf("unexpected injected operation %s", injected)
+	}
+
+	f.Password = "wrong"
+	if _, err := f.Key(); err == nil {
+		t.Fatal("expected error for a key not matching the faucet pkh")
+	}
+	if _, err := client.ActivateAccount(context.Background(), f.Pkh, "0123"); err == nil {
+		t.Fatal("expected error for a short secret")
+	}
+}
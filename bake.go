@@ -0,0 +1,355 @@
+package tgo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sizes of the fixed length fields of block headers
+const (
+	proofOfWorkNonceSize = 8
+	blockHashSize        = 32
+)
+
+// base58 prefixes of the hashes committed to by block headers
+var (
+	prefixOperationListList = []byte{29, 159, 109}
+	prefixContext           = []byte{79, 199}
+)
+
+// ShellHeader is the protocol independent part of a block header, as returned by block preapplication
+type ShellHeader struct {
+	Level          int64     `json:"level"`
+	Proto          int64     `json:"proto"`
+	Predecessor    BlockHash `json:"predecessor"`
+	Timestamp      Timestamp `json:"timestamp"`
+	ValidationPass int64     `json:"validation_pass"`
+	OperationsHash string    `json:"operations_hash"`
+	Fitness        []string  `json:"fitness"`
+	Context        string    `json:"context"`
+}
+
+// BlockProtocolData is the part of a block header chosen by the baker
+type BlockProtocolData struct {
+	Protocol         ProtocolHash `json:"protocol"`
+	Priority         int64        `json:"priority"`
+	ProofOfWorkNonce string       `json:"proof_of_work_nonce"`
+	// SeedNonceHash is the nonce commitment, required at levels expecting one
+	SeedNonceHash string `json:"seed_nonce_hash,omitempty"`
+	Signature     string `json:"signature"`
+}
+
+// InjectableOperation is an operation forged and signed, as returned by block preapplication
+// and expected by block injection
+type InjectableOperation struct {
+	Hash   OperationHash `json:"hash,omitempty"`
+	Branch BlockHash     `json:"branch"`
+	Data   string        `json:"data"`
+}
+
+// BlockTemplate describes the block to bake on top of head
+type BlockTemplate struct {
+	Priority      int64
+	SeedNonceHash string
+	// Operations are the signed operations to include, one list per validation pass
+	Operations [][]Operation
+	// Timestamp defaults to the time the node preapplies the block
+	Timestamp time.Time
+}
+
+// ForgeBlockHeader forges locally the header made of shell and data without its signature
+// and returns the bytes as hex
+func ForgeBlockHeader(shell ShellHeader, data BlockProtocolData) (string, error) {
+	forged, err := forgeBlockHeader(shell, data)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(forged), nil
+}
+
+// ForgeShellHeader forges locally the shell header and returns the bytes as hex, which are
+// followed by the protocol data in forged block headers
+func ForgeShellHeader(shell ShellHeader) (string, error) {
+	forged, err := forgeShellHeader(shell)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(forged), nil
+}
+
+// forgeShellHeader encodes the protocol independent fields of a block header
+func forgeShellHeader(shell ShellHeader) ([]byte, error) {
+	if shell.Level <= 0 || shell.Level > 1<<31-1 {
+		return nil, fmt.Errorf("invalid level %d", shell.Level)
+	}
+	if shell.Proto < 0 || shell.Proto > 255 || shell.ValidationPass < 0 || shell.ValidationPass > 255 {
+		return nil, fmt.Errorf("invalid proto %d or validation pass %d", shell.Proto, shell.ValidationPass)
+	}
+	predecessor, err := b58CheckDecode(string(shell.Predecessor), prefixBlock)
+	if err != nil {
+		return nil, fmt.Errorf("invalid predecessor %s: %w", shell.Predecessor, err)
+	}
+	if shell.Timestamp.IsZero() {
+		return nil, errors.New("missing timestamp")
+	}
+	timestamp := shell.Timestamp.Time
+	operationsHash, err := b58CheckDecode(shell.OperationsHash, prefixOperationListList)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operations hash %s: %w", shell.OperationsHash, err)
+	}
+	contextHash, err := b58CheckDecode(shell.Context, prefixContext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid context %s: %w", shell.Context, err)
+	}
+	fitness, err := forgeFitness(shell.Fitness)
+	if err != nil {
+		return nil, err
+	}
+	forged := appendUint32(nil, uint32(shell.Level))
+	forged = append(forged, byte(shell.Proto))
+	forged = append(forged, predecessor...)
+	forged = appendUint64(forged, uint64(timestamp.Unix()))
+	forged = append(forged, byte(shell.ValidationPass))
+	forged = append(forged, operationsHash...)
+	forged = append(forged, fitness...)
+	return append(forged, contextHash...), nil
+}
+
+// forgeFitness encodes the fitness as a list of byte strings preceded by its size
+func forgeFitness(fitness []string) ([]byte, error) {
+	elements := []byte{}
+	for _, f := range fitness {
+		b, err := hex.DecodeString(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fitness %s: %w", f, err)
+		}
+		elements = appendUint32(elements, uint32(len(b)))
+		elements = append(elements, b...)
+	}
+	return append(appendUint32(nil, uint32(len(elements))), elements...), nil
+}
+
+// forgeBlockHeader encodes the shell header followed by the protocol data contents
+func forgeBlockHeader(shell ShellHeader, data BlockProtocolData) ([]byte, error) {
+	forged, err := forgeShellHeader(shell)
+	if err != nil {
+		return nil, err
+	}
+	if data.Priority < 0 || data.Priority > 1<<16-1 {
+		return nil, fmt.Errorf("invalid priority %d", data.Priority)
+	}
+	nonce, err := hex.DecodeString(data.ProofOfWorkNonce)
+	if err != nil || len(nonce) != proofOfWorkNonceSize {
+		return nil, fmt.Errorf("invalid proof of work nonce %q", data.ProofOfWorkNonce)
+	}
+	forged = append(forged, byte(data.Priority>>8), byte(data.Priority))
+	forged = append(forged, nonce...)
+	if data.SeedNonceHash == "" {
+		return append(forged, 0x00), nil
+	}
+	seedNonceHash, err := b58CheckDecode(data.SeedNonceHash, prefixNonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed nonce hash %s: %w", data.SeedNonceHash, err)
+	}
+	return append(append(forged, 0xff), seedNonceHash...), nil
+}
+
+// StampProofOfWork searches a proof of work nonce for which the hash of the header, signed
+// with a zero signature, is below threshold and sets it in data. The search starts from
+// the nonce in data, or from a random one when it is empty.
+func StampProofOfWork(ctx context.Context, shell ShellHeader, data *BlockProtocolData, threshold int64) error {
+	if data.ProofOfWorkNonce == "" {
+		nonce := make([]byte, proofOfWorkNonceSize)
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		data.ProofOfWorkNonce = hex.EncodeToString(nonce)
+	}
+	forged, err := forgeBlockHeader(shell, *data)
+	if err != nil {
+		return err
+	}
+	// the nonce sits right before the seed nonce hash option
+	offset := len(forged) - 1 - proofOfWorkNonceSize
+	if data.SeedNonceHash != "" {
+		offset -= blockHashSize
+	}
+	forged = append(forged, make([]byte, signatureSize)...)
+	nonce := forged[offset : offset+proofOfWorkNonceSize]
+	for i := 0; ; i++ {
+		if i%(1<<16) == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if binary.BigEndian.Uint64(blake2b(forged, blockHashSize)) <= uint64(threshold) {
+			data.ProofOfWorkNonce = hex.EncodeToString(nonce)
+			return nil
+		}
+		binary.BigEndian.PutUint64(nonce, binary.BigEndian.Uint64(nonce)+1)
+	}
+}
+
+// SignBlockHeader signs a forged block header with the block watermark of chainID,
+// returning the signature and the signed header ready for injection
+func SignBlockHeader(signer Signer, chainID ChainID, forgedHex string) (string, string, error) {
+	chain, err := b58CheckDecode(string(chainID), prefixChainID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid chain id %s: %w", chainID, err)
+	}
+	return signWatermarked(signer, append([]byte{blockPrefix}, chain...), forgedHex)
+}
+
+// PreapplyBlock calls POST /chains/<chain>/blocks/head/helpers/preapply/block, returning the shell
+// header of the block made of data and operations along with the operations to inject with it.
+// A zero timestamp lets the node pick the earliest valid one.
+func (rpc *RPC) PreapplyBlock(ctx context.Context, data BlockProtocolData, operations [][]Operation, timestamp time.Time) (ShellHeader, [][]InjectableOperation, error) {
+	params := query{}.addBool("sort", true)
+	if !timestamp.IsZero() {
+		params.addInts("timestamp", timestamp.Unix())
+	}
+	type protocolOperation struct {
+		Protocol ProtocolHash `json:"protocol"`
+		Operation
+	}
+	passes := make([][]protocolOperation, len(operations))
+	for i, ops := range operations {
+		passes[i] = make([]protocolOperation, len(ops))
+		for j, op := range ops {
+			passes[i][j] = protocolOperation{data.Protocol, op}
+		}
+	}
+	req := struct {
+		ProtocolData BlockProtocolData     `json:"protocol_data"`
+		Operations   [][]protocolOperation `json:"operations"`
+	}{data, passes}
+	resp := struct {
+		ShellHeader ShellHeader `json:"shell_header"`
+		Operations  []struct {
+			Applied []InjectableOperation `json:"applied"`
+		} `json:"operations"`
+	}{}
+	if err := rpc.post(ctx, params.path(fmt.Sprintf("/chains/%s/blocks/head/helpers/preapply/block", rpc.ChainAlias(ctx))), req, &resp); err != nil {
+		return ShellHeader{}, nil, err
+	}
+	injectable := make([][]InjectableOperation, len(resp.Operations))
+	for i, pass := range resp.Operations {
+		injectable[i] = make([]InjectableOperation, len(pass.Applied))
+		for j, op := range pass.Applied {
+			injectable[i][j] = InjectableOperation{Branch: op.Branch, Data: op.Data}
+		}
+	}
+	return resp.ShellHeader, injectable, nil
+}
+
+// InjectBlock calls POST /injection/block with a signed header and its operations and returns the block hash
+func (rpc *RPC) InjectBlock(ctx context.Context, signedHex string, operations [][]InjectableOperation) (BlockHash, error) {
+	req := struct {
+		Data       string                  `json:"data"`
+		Operations [][]InjectableOperation `json:"operations"`
+	}{signedHex, operations}
+	var hash BlockHash
+	err := rpc.post(ctx, query{}.add("chain", rpc.ChainAlias(ctx)).path("/injection/block"), req, &hash)
+	return hash, err
+}
+
+// BakeBlock preapplies the block described by template on top of head, stamps its proof of
+// work, signs it with signer and injects it, returning the block hash. The signer must have
+// baking rights at the priority of the template.
+func (rpc *RPC) BakeBlock(ctx context.Context, signer Signer, template BlockTemplate) (BlockHash, error) {
+	protocols := struct {
+		NextProtocol ProtocolHash `json:"next_protocol"`
+	}{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/head/protocols", rpc.ChainAlias(ctx)), &protocols); err != nil {
+		return "", err
+	}
+	chainID, err := rpc.GetChainID(ctx, rpc.ChainAlias(ctx))
+	if err != nil {
+		return "", err
+	}
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return "", err
+	}
+	data := BlockProtocolData{
+		Protocol:         protocols.NextProtocol,
+		Priority:         template.Priority,
+		ProofOfWorkNonce: hex.EncodeToString(make([]byte, proofOfWorkNonceSize)),
+		SeedNonceHash:    template.SeedNonceHash,
+		Signature:        b58CheckEncode(prefixEdsig, make([]byte, signatureSize)),
+	}
+	operations := template.Operations
+	if operations == nil {
+		operations = [][]Operation{{}, {}, {}, {}}
+	}
+	shell, injectable, err := rpc.PreapplyBlock(ctx, data, operations, template.Timestamp)
+	if err != nil {
+		return "", err
+	}
+	data.ProofOfWorkNonce = ""
+	if err := StampProofOfWork(ctx, shell, &data, constants.ProofOfWorkThreshold); err != nil {
+		return "", err
+	}
+	forged, err := ForgeBlockHeader(shell, data)
+	if err != nil {
+		return "", err
+	}
+	_, signed, err := SignBlockHeader(signer, chainID, forged)
+	if err != nil {
+		return "", err
+	}
+	return rpc.InjectBlock(ctx, signed, injectable)
+}
+
+// ActivationCommand is the protocol data of the block activating a protocol on top of the
+// genesis block of a test or sandboxed chain
+type ActivationCommand struct {
+	Protocol ProtocolHash
+	// Fitness is the fitness of the activation block, e.g. ["01", "0000000000000001"]
+	Fitness []string
+	// Parameters are the protocol parameters in their binary encoding
+	Parameters []byte
+}
+
+// ForgeActivationBlock forges locally the header made of shell and the genesis command
+// activating a protocol without its signature and returns the bytes as hex
+func ForgeActivationBlock(shell ShellHeader, command ActivationCommand) (string, error) {
+	forged, err := forgeShellHeader(shell)
+	if err != nil {
+		return "", err
+	}
+	protocol, err := b58CheckDecode(string(command.Protocol), prefixProtocol)
+	if err != nil {
+		return "", fmt.Errorf("invalid protocol %s: %w", command.Protocol, err)
+	}
+	fitness, err := forgeFitness(command.Fitness)
+	if err != nil {
+		return "", err
+	}
+	// the activate command is the first case of the genesis command union
+	forged = append(forged, 0x00)
+	forged = append(forged, protocol...)
+	forged = append(forged, fitness...)
+	forged = appendUint32(forged, uint32(len(command.Parameters)))
+	forged = append(forged, command.Parameters...)
+	return hex.EncodeToString(forged), nil
+}
+
+// appendUint32 appends v to b in big endian order
+func appendUint32(b []byte, v uint32) []byte {
+	var raw [4]byte
+	binary.BigEndian.PutUint32(raw[:], v)
+	return append(b, raw[:]...)
+}
+
+// appendUint64 appends v to b in big endian order
+func appendUint64(b []byte, v uint64) []byte {
+	var raw [8]byte
+	binary.BigEndian.PutUint64(raw[:], v)
+	return append(b, raw[:]...)
+}
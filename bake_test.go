@@ -0,0 +1,147 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+var testShellHeader = tgo.ShellHeader{
+	Level:          300,
+	Proto:          2,
+	Predecessor:    "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+	Timestamp:      tgo.NewTimestamp(time.Date(2019, 9, 1, 12, 0, 0, 0, time.UTC)),
+	ValidationPass: 4,
+	OperationsHash: "LLoZKi7YfF6zf8vpKTbstYfpJaDu8fMmnJShSvApkx7uaQ2rsAa4T",
+	Fitness:        []string{"01", "000000000000000a"},
+	Context:        "CoUtTZPbHbP2fb3hZ2xUa7WGKn9WRRaXqvxfAP1p5raecRUkyKjF",
+}
+
+const testForgedHeader = "0000012c028fcf233671b6a04fcf679d2a381c2544ea6c1ea29ba6157776ed8424c7ccd00b000000005d6bb2c004" +
+	"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f00000011000000010100000008000000000000000a" +
+	"202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f" + "0001" + "0000000000000000" + "00"
+
+func TestForgeBlockHeader(t *testing.T) {
+	data := tgo.BlockProtocolData{Priority: 1, ProofOfWorkNonce: "0000000000000000"}
+	forged, err := tgo.ForgeBlockHeader(testShellHeader, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forged != testForgedHeader {
+		t.Fatalf("unexpected forged header %s", forged)
+	}
+	data.SeedNonceHash = "nceUDx2TmFGaB9DVubHuxPp6igLDEwkL6JgcvJ2QNhh368HbYrk8E"
+	if forged, err = tgo.ForgeBlockHeader(testShellHeader, data); err != nil {
+		t.Fatal(err)
+	}
+	if forged != strings.TrimSuffix(testForgedHeader, "00")+"ff"+strings.Repeat("00", 32) {
+		t.Fatalf("unexpected forged header with seed nonce hash %s", forged)
+	}
+	data.Priority = 1 << 16
+	if _, err := tgo.ForgeBlockHeader(testShellHeader, data); err == nil {
+		t.Fatal("expected error for an invalid priority")
+	}
+}
+
+func TestStampProofOfWork(t *testing.T) {
+	for _, test := range []struct {
+		seedNonceHash string
+		nonce         string
+	}{
+		{"", "0000000000000006"},
+		{"nceUDx2TmFGaB9DVubHuxPp6igLDEwkL6JgcvJ2QNhh368HbYrk8E", "000000000000000f"},
+	} {
+		data := tgo.BlockProtocolData{Priority: 1, ProofOfWorkNonce: "0000000000000000", SeedNonceHash: test.seedNonceHash}
+		if err := tgo.StampProofOfWork(context.Background(), testShellHeader, &data, 1<<60); err != nil {
+			t.Fatal(err)
+		}
+		if data.ProofOfWorkNonce != test.nonce {
+			t.Fatalf("unexpected proof of work nonce %s, expected %s", data.ProofOfWorkNonce, test.nonce)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tgo.StampProofOfWork(ctx, testShellHeader, &tgo.BlockProtocolData{}, 0); err == nil {
+		t.Fatal("expected error once cancelled")
+	}
+}
+
+func TestBakeBlock(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/protocols":         map[string]interface{}{"next_protocol": "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"},
+		"GET /chains/main/chain_id":                      "NetXdQprcVkpaWU",
+		"GET /chains/main/blocks/head/context/constants": map[string]interface{}{"proof_of_work_threshold": "-1"},
+		"POST /chains/main/blocks/head/helpers/preapply/block": map[string]interface{}{
+			"shell_header": testShellHeader,
+			"operations": []interface{}{
+				map[string]interface{}{"applied": []interface{}{map[string]interface{}{"hash": "ooA", "branch": "BLb", "data": "0102"}}},
+				map[string]interface{}{"applied": []interface{}{}},
+			},
+		},
+		"POST /injection/block": "BLockHash",
+	})
+	ops := [][]tgo.Operation{{{Branch: "BLb", Contents: []tgo.OperationContents{{Kind: "endorsement", Level: 299}}, Signature: "sigA"}}, {}}
+	hash, err := client.BakeBlock(context.Background(), key, tgo.BlockTemplate{Priority: 1, Operations: ops})
+	if err != nil {
+		t.Fatal(err)
+	}
+	preapply := node.bodies["POST /chains/main/blocks/head/helpers/preapply/block"][0]
+	if !strings.Contains(preapply, `"operations":[[{"protocol":"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS","branch":"BLb"`) {
+		t.Fatalf("unexpected preapply request %s", preapply)
+	}
+	if node.queries["POST /chains/main/blocks/head/helpers/preapply/block"][0] != "sort=true" {
+		t.Fatalf("unexpected preapply query %s", node.queries["POST /chains/main/blocks/head/helpers/preapply/block"][0])
+	}
+	injection := struct {
+		Data       string                      `json:"data"`
+		Operations [][]tgo.InjectableOperation `json:"operations"`
+	}{}
+	if err := json.Unmarshal([]byte(node.bodies["POST /injection/block"][0]), &injection); err != nil {
+		t.Fatal(err)
+	}
+	if hash != "BLockHash" || len(injection.Data) != len(testForgedHeader)+128 || injection.Data[:len(testForgedHeader)-18] != testForgedHeader[:len(testForgedHeader)-18] {
+		t.Fatalf("unexpected injected header %s", injection.Data)
+	}
+	if len(injection.Operations) != 2 || injection.Operations[0][0] != (tgo.InjectableOperation{Branch: "BLb", Data: "0102"}) {
+		t.Fatalf("unexpected injected operations %+v", injection.Operations)
+	}
+	forged := injection.Data[:len(testForgedHeader)]
+	if _, signed, err := tgo.SignBlockHeader(key, "NetXdQprcVkpaWU", forged); err != nil || signed != injection.Data {
+		t.Fatalf("block not signed with the chain watermark: %v", err)
+	}
+}
+
+func TestForgeActivationBlock(t *testing.T) {
+	shell, err := tgo.ForgeShellHeader(testShellHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shell != testForgedHeader[:len(testForgedHeader)-22] {
+		t.Fatalf("unexpected forged shell header %s", shell)
+	}
+	command := tgo.ActivationCommand{
+		Protocol:   "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS",
+		Fitness:    []string{"01", "0000000000000001"},
+		Parameters: []byte{0xab},
+	}
+	forged, err := tgo.ForgeActivationBlock(testShellHeader, command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tail := "00000011000000010100000008000000000000000100000001ab"
+	if !strings.HasPrefix(forged, shell+"00") || !strings.HasSuffix(forged, tail) || len(forged) != len(shell)+2+64+len(tail) {
+		t.Fatalf("unexpected forged activation block %s", forged)
+	}
+	command.Protocol = "PsBaby"
+	if _, err := tgo.ForgeActivationBlock(testShellHeader, command); err == nil {
+		t.Fatal("expected error for an invalid protocol hash")
+	}
+}
@@ -1,8 +1,9 @@
 package tgo
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
+	"net/http"
 )
 
 // GetBakingRightsForDelegateAtCycle is used to get the baking rights for a particular delegate with a customizable priority
@@ -10,13 +11,10 @@ func (rpc *RPC) GetBakingRightsForDelegateAtCycle(delegate, cycle, priority stri
 	if priority == "" {
 		priority = "2"
 	}
-	url := fmt.Sprintf("%s/chains/main/blocks/head/helpers/baking_rights?cycle=%s&delegate=%s&max_priority=%s", rpc.URL, cycle, delegate, priority)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
-		return err
-	}
-
-	respBytes, err := ioutil.ReadAll(resp.Body)
+	ctx := context.Background()
+	path := query{}.add("cycle", cycle).add("delegate", delegate).add("max_priority", priority).
+		path(fmt.Sprintf("/chains/%s/blocks/head/helpers/baking_rights", rpc.ChainAlias(ctx)))
+	respBytes, err := rpc.fetch(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return err
 	}
@@ -24,3 +22,50 @@ func (rpc *RPC) GetBakingRightsForDelegateAtCycle(delegate, cycle, priority stri
 	fmt.Printf("Baking rights for delegate %s at cycle %s\n%v", delegate, cycle, string(respBytes))
 	return nil
 }
+
+// BakingRight is an entry of `GET /chains/<chain>/blocks/<block_id>/helpers/baking_rights`
+type BakingRight struct {
+	Level         int64      `json:"level"`
+	Delegate      string     `json:"delegate"`
+	Priority      int64      `json:"priority"`
+	EstimatedTime *Timestamp `json:"estimated_time,omitempty"`
+}
+
+// EndorsingRight is an entry of `GET /chains/<chain>/blocks/<block_id>/helpers/endorsing_rights`
+type EndorsingRight struct {
+	Level         int64      `json:"level"`
+	Delegate      string     `json:"delegate"`
+	Slots         []int64    `json:"slots"`
+	EstimatedTime *Timestamp `json:"estimated_time,omitempty"`
+}
+
+// RightsQuery filters baking and endorsing rights, zero values are left out of the query
+type RightsQuery struct {
+	Delegates   []string
+	Levels      []int64
+	Cycles      []int64
+	MaxPriority int64
+	All         bool
+}
+
+func (q RightsQuery) values() query {
+	v := query{}.add("delegate", q.Delegates...).addInts("level", q.Levels...).addInts("cycle", q.Cycles...)
+	if q.MaxPriority > 0 {
+		v.addInts("max_priority", q.MaxPriority)
+	}
+	return v.flag("all", q.All)
+}
+
+// GetBakingRights calls GET /chains/<chain>/blocks/<block_id>/helpers/baking_rights
+func (rpc *RPC) GetBakingRights(ctx context.Context, blockID BlockID, query RightsQuery) ([]BakingRight, error) {
+	rights := []BakingRight{}
+	err := rpc.get(ctx, query.values().path(fmt.Sprintf("/chains/%s/blocks/%s/helpers/baking_rights", rpc.ChainAlias(ctx), blockID)), &rights)
+	return rights, err
+}
+
+// GetEndorsingRights calls GET /chains/<chain>/blocks/<block_id>/helpers/endorsing_rights
+func (rpc *RPC) GetEndorsingRights(ctx context.Context, blockID BlockID, query RightsQuery) ([]EndorsingRight, error) {
+	rights := []EndorsingRight{}
+	err := rpc.get(ctx, query.values().path(fmt.Sprintf("/chains/%s/blocks/%s/helpers/endorsing_rights", rpc.ChainAlias(ctx), blockID)), &rights)
+	return rights, err
+}
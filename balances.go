@@ -0,0 +1,64 @@
+package tgo
+
+// Kinds and freezer categories of balance updates
+const (
+	BalanceContract = "contract"
+	BalanceFreezer  = "freezer"
+
+	FreezerDeposits = "deposits"
+	FreezerFees     = "fees"
+	FreezerRewards  = "rewards"
+)
+
+// BalanceUpdate is a change of a spendable or frozen balance, as reported in block metadata
+// and operation receipts. Change is in mutez.
+type BalanceUpdate struct {
+	// Kind is BalanceContract for spendable balances or BalanceFreezer for frozen ones
+	Kind     string `json:"kind"`
+	Contract string `json:"contract,omitempty"`
+	// Category is one of the freezer categories for frozen balances
+	Category string `json:"category,omitempty"`
+	Delegate string `json:"delegate,omitempty"`
+	// Cycle is the cycle frozen funds belong to, Level replaces it in protocols before Athens
+	Cycle  int64 `json:"cycle,omitempty"`
+	Level  int64 `json:"level,omitempty"`
+	Change int64 `json:"change,string"`
+	// Origin tells whether the update comes from the block or a protocol migration, when given
+	Origin string `json:"origin,omitempty"`
+}
+
+// BalanceUpdates is a list of balance updates
+type BalanceUpdates []BalanceUpdate
+
+// Spendable returns the total change of the spendable balance of address
+func (u BalanceUpdates) Spendable(address string) int64 {
+	total := int64(0)
+	for _, b := range u {
+		if b.Kind == BalanceContract && b.Contract == address {
+			total += b.Change
+		}
+	}
+	return total
+}
+
+// Frozen returns the total change of the frozen balance of delegate in category, for any cycle
+func (u BalanceUpdates) Frozen(delegate, category string) int64 {
+	total := int64(0)
+	for _, b := range u {
+		if b.Kind == BalanceFreezer && b.Delegate == delegate && b.Category == category {
+			total += b.Change
+		}
+	}
+	return total
+}
+
+// Cycle returns the updates of frozen balances belonging to cycle
+func (u BalanceUpdates) Cycle(cycle int64) BalanceUpdates {
+	updates := BalanceUpdates{}
+	for _, b := range u {
+		if b.Kind == BalanceFreezer && b.Cycle == cycle {
+			updates = append(updates, b)
+		}
+	}
+	return updates
+}
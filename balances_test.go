@@ -0,0 +1,41 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestBalanceUpdates(t *testing.T) {
+	baker := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/metadata": map[string]interface{}{
+			"baker": baker,
+			"balance_updates": []map[string]interface{}{
+				{"kind": "contract", "contract": baker, "change": "-512000000"},
+				{"kind": "freezer", "category": "deposits", "delegate": baker, "cycle": 7, "change": "512000000"},
+				{"kind": "freezer", "category": "rewards", "delegate": baker, "cycle": 7, "change": "16000000"},
+				{"kind": "freezer", "category": "rewards", "delegate": baker, "cycle": 2, "change": "-40000000"},
+				{"kind": "freezer", "category": "fees", "delegate": baker, "cycle": 7, "change": "1420"},
+			},
+		},
+	})
+	metadata, err := client.GetBlockMetadata(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates := metadata.BalanceUpdates
+	if len(updates) != 5 || updates[1].Kind != tgo.BalanceFreezer || updates[1].Change != 512000000 {
+		t.Fatalf("unexpected updates %+v", updates)
+	}
+	if updates.Spendable(baker) != -512000000 {
+		t.Fatalf("unexpected spendable change %d", updates.Spendable(baker))
+	}
+	if updates.Frozen(baker, tgo.FreezerRewards) != -24000000 || updates.Frozen(baker, tgo.FreezerFees) != 1420 {
+		t.Fatal("unexpected frozen changes")
+	}
+	if cycle := updates.Cycle(7); len(cycle) != 3 || cycle.Frozen(baker, tgo.FreezerRewards) != 16000000 {
+		t.Fatalf("unexpected cycle updates %+v", cycle)
+	}
+}
@@ -0,0 +1,208 @@
+package tgo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultBandwidthRetention is how many samples of each series a BandwidthCollector keeps by default
+const defaultBandwidthRetention = 360
+
+// BandwidthSample is the traffic of the node or of a peer between two samples of its totals
+type BandwidthSample struct {
+	Time    time.Time     `json:"time"`
+	Elapsed time.Duration `json:"elapsed"`
+	Sent    int64         `json:"sent"`
+	Recv    int64         `json:"recv"`
+}
+
+// SendRate returns the bytes sent per second over the sample
+func (s BandwidthSample) SendRate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / s.Elapsed.Seconds()
+}
+
+// RecvRate returns the bytes received per second over the sample
+func (s BandwidthSample) RecvRate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Recv) / s.Elapsed.Seconds()
+}
+
+// bandwidthSeries holds the samples of the node or of a peer and the totals they start from
+type bandwidthSeries struct {
+	last      time.Time
+	sent      int64
+	recv      int64
+	samples   []BandwidthSample
+	collected bool
+}
+
+// add records the totals read at now, counters going backwards, such as the counters of a
+// peer which reconnected, being counted from zero
+func (s *bandwidthSeries) add(now time.Time, sent, recv int64, retention int) {
+	if !s.last.IsZero() {
+		sample := BandwidthSample{Time: now, Elapsed: now.Sub(s.last), Sent: sent - s.sent, Recv: recv - s.recv}
+		if sample.Sent < 0 || sample.Recv < 0 {
+			sample.Sent, sample.Recv = sent, recv
+		}
+		s.samples = append(s.samples, sample)
+		if len(s.samples) > retention {
+			s.samples = s.samples[len(s.samples)-retention:]
+		}
+	}
+	s.last, s.sent, s.recv = now, sent, recv
+}
+
+// BandwidthCollector periodically samples the totals of /network/stat and of the running peers,
+// which only ever increase, and keeps the traffic between samples as time series
+type BandwidthCollector struct {
+	rpc *RPC
+	// Interval is how often the totals are sampled, PollInterval of the client by default
+	Interval time.Duration
+	// Retention is how many samples of each series are kept, 360 by default
+	Retention int
+	// SkipPeers only samples the totals of the node
+	SkipPeers bool
+
+	mu    sync.Mutex
+	node  bandwidthSeries
+	peers map[PeerID]*bandwidthSeries
+}
+
+// NewBandwidthCollector returns a collector of the traffic of the node behind rpc
+func NewBandwidthCollector(rpc *RPC) *BandwidthCollector {
+	return &BandwidthCollector{rpc: rpc, peers: map[PeerID]*bandwidthSeries{}}
+}
+
+// Sample reads the totals of the node and of its running peers once, adding a sample to each
+// series but the new ones. The series of peers no longer running are dropped.
+func (c *BandwidthCollector) Sample(ctx context.Context) error {
+	stat, err := c.rpc.GetNetworkStat(ctx)
+	if err != nil {
+		return err
+	}
+	var peers []KnownPeer
+	if !c.SkipPeers {
+		if peers, err = c.rpc.GetPeers(ctx, "running"); err != nil {
+			return err
+		}
+	}
+	retention := c.Retention
+	if retention <= 0 {
+		retention = defaultBandwidthRetention
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.node.add(now, int64(stat.TotalSent), int64(stat.TotalRecv), retention)
+	for _, s := range c.peers {
+		s.collected = false
+	}
+	for _, p := range peers {
+		s, ok := c.peers[p.ID]
+		if !ok {
+			s = &bandwidthSeries{}
+			c.peers[p.ID] = s
+		}
+		s.add(now, int64(p.Stat.TotalSent), int64(p.Stat.TotalRecv), retention)
+		s.collected = true
+	}
+	for id, s := range c.peers {
+		if !s.collected {
+			delete(c.peers, id)
+		}
+	}
+	return nil
+}
+
+// Run samples the totals every interval until ctx is cancelled. The returned channel is closed
+// once ctx is cancelled and receives the failures of samples, as described in the package
+// documentation, and sampling goes on.
+func (c *BandwidthCollector) Run(ctx context.Context) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		interval := c.Interval
+		if interval <= 0 {
+			interval = c.rpc.PollInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := c.Sample(ctx); err != nil && ctx.Err() == nil {
+				reportError(errs, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return errs
+}
+
+// BandwidthRate is the traffic of the last sample of the node, when Peer is empty, or of a peer
+// in bytes per second
+type BandwidthRate struct {
+	Peer PeerID  `json:"peer,omitempty"`
+	Send float64 `json:"send"`
+	Recv float64 `json:"recv"`
+}
+
+// Rates returns the rates of the last samples of the node, first, and of the peers sorted, the
+// series without samples left out. They are meant to be exported as gauges, e.g. from the
+// Collect method of a prometheus.Collector, the metrics client being left to the caller.
+func (c *BandwidthCollector) Rates() []BandwidthRate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rates := []BandwidthRate{}
+	if n := len(c.node.samples); n > 0 {
+		last := c.node.samples[n-1]
+		rates = append(rates, BandwidthRate{Send: last.SendRate(), Recv: last.RecvRate()})
+	}
+	peers := []BandwidthRate{}
+	for id, s := range c.peers {
+		if n := len(s.samples); n > 0 {
+			last := s.samples[n-1]
+			peers = append(peers, BandwidthRate{Peer: id, Send: last.SendRate(), Recv: last.RecvRate()})
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Peer < peers[j].Peer })
+	return append(rates, peers...)
+}
+
+// Node returns the samples of the traffic of the node, oldest first
+func (c *BandwidthCollector) Node() []BandwidthSample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]BandwidthSample(nil), c.node.samples...)
+}
+
+// Peer returns the samples of the traffic with peer, oldest first
+func (c *BandwidthCollector) Peer(peer PeerID) []BandwidthSample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.peers[peer]; ok {
+		return append([]BandwidthSample(nil), s.samples...)
+	}
+	return nil
+}
+
+// Peers returns the peers sampled, sorted
+func (c *BandwidthCollector) Peers() []PeerID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	peers := make([]PeerID, 0, len(c.peers))
+	for id := range c.peers {
+		peers = append(peers, id)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i] < peers[j] })
+	return peers
+}
@@ -0,0 +1,58 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestBandwidthCollector(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/stat":  rawBody(`{"total_sent":"1000","total_recv":"2000","current_inflow":0,"current_outflow":0}`),
+		"GET /network/peers": rawBody(`[["idtA",{"state":"running","stat":{"total_sent":"100","total_recv":"200"}}]]`),
+	})
+	collector := tgo.NewBandwidthCollector(client)
+	if err := collector.Sample(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// the first sample only reads the totals the next ones start from
+	if samples := collector.Node(); len(samples) != 0 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	node.route("GET /network/stat", rawBody(`{"total_sent":"1500","total_recv":"2100"}`))
+	node.route("GET /network/peers", rawBody(`[
+		["idtA",{"state":"running","stat":{"total_sent":"50","total_recv":"20"}}],
+		["idtB",{"state":"running","stat":{"total_sent":"10","total_recv":"10"}}]
+	]`))
+	if err := collector.Sample(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if samples := collector.Node(); len(samples) != 1 || samples[0].Sent != 500 || samples[0].Recv != 100 || samples[0].SendRate() <= 0 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	// the counters of the peer went backwards as it reconnected
+	if samples := collector.Peer("idtA"); len(samples) != 1 || samples[0].Sent != 50 || samples[0].Recv != 20 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if peers := collector.Peers(); len(peers) != 2 || peers[1] != "idtB" || len(collector.Peer("idtB")) != 0 {
+		t.Fatalf("unexpected peers %v", peers)
+	}
+	if rates := collector.Rates(); len(rates) != 2 || rates[0].Peer != "" || rates[0].Send <= 0 || rates[1].Peer != "idtA" {
+		t.Fatalf("unexpected rates %+v", rates)
+	}
+
+	node.route("GET /network/peers", rawBody(`[]`))
+	collector.Retention = 1
+	if err := collector.Sample(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if samples := collector.Node(); len(samples) != 1 || samples[0].Sent != 0 || len(collector.Peers()) != 0 {
+		t.Fatalf("unexpected samples %+v and peers %v", samples, collector.Peers())
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if q := node.queries["GET /network/peers"]; len(q) != 3 || q[0] != "filter=running" {
+		t.Fatalf("unexpected queries %v", q)
+	}
+}
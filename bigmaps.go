@@ -0,0 +1,81 @@
+package tgo
+
+import "github.com/postables/TGo/micheline"
+
+// Actions of big map diffs
+const (
+	BigMapUpdate = "update"
+	BigMapRemove = "remove"
+	BigMapCopy   = "copy"
+	BigMapAlloc  = "alloc"
+)
+
+// BigMapDiff is a change made to big maps by an operation. Depending on Action:
+// update sets Key in BigMap to Value, or removes it when Value is nil, the diffs of protocols
+// before Babylon being updates without action;
+// remove deletes BigMap; copy duplicates SourceBigMap into DestinationBigMap;
+// alloc creates BigMap with the given key and value types.
+// Negative ids are temporary big maps that never reach the context.
+type BigMapDiff struct {
+	Action            string          `json:"action,omitempty"`
+	BigMap            int64           `json:"big_map,string,omitempty"`
+	KeyHash           string          `json:"key_hash,omitempty"`
+	Key               *micheline.Node `json:"key,omitempty"`
+	Value             *micheline.Node `json:"value,omitempty"`
+	SourceBigMap      int64           `json:"source_big_map,string,omitempty"`
+	DestinationBigMap int64           `json:"destination_big_map,string,omitempty"`
+	KeyType           *micheline.Node `json:"key_type,omitempty"`
+	ValueType         *micheline.Node `json:"value_type,omitempty"`
+}
+
+// Target returns the id of the big map the diff changes
+func (d BigMapDiff) Target() int64 {
+	if d.Action == BigMapCopy {
+		return d.DestinationBigMap
+	}
+	return d.BigMap
+}
+
+// IsUpdate reports whether the diff sets or removes a key
+func (d BigMapDiff) IsUpdate() bool {
+	return d.Action == BigMapUpdate || d.Action == ""
+}
+
+// IsKeyRemoval reports whether the diff removes a key
+func (d BigMapDiff) IsKeyRemoval() bool {
+	return d.IsUpdate() && d.Value == nil
+}
+
+// BigMapChanges are big map diffs grouped by the id of the big map they change, in the order
+// they were applied
+type BigMapChanges map[int64][]BigMapDiff
+
+// add appends the diffs of an applied result
+func (c BigMapChanges) add(r OperationResult) {
+	if r.Status != "applied" {
+		return
+	}
+	for _, d := range r.BigMapDiff {
+		c[d.Target()] = append(c[d.Target()], d)
+	}
+}
+
+// BigMapDiffs returns the big map changes applied by the content and its internal operations
+func (c AppliedContents) BigMapDiffs() BigMapChanges {
+	changes := BigMapChanges{}
+	for _, r := range c.Results() {
+		changes.add(r)
+	}
+	return changes
+}
+
+// BigMapDiffs returns the big map changes applied by all the contents of the operation
+func (op BlockOperation) BigMapDiffs() BigMapChanges {
+	changes := BigMapChanges{}
+	for _, c := range op.Contents {
+		for _, r := range c.Results() {
+			changes.add(r)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,64 @@
+package tgo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestBigMapDiffs(t *testing.T) {
+	src := `{"hash": "ooHash", "contents": [{
+		"kind": "transaction",
+		"metadata": {
+			"operation_result": {"status": "applied", "big_map_diff": [
+				{"action": "alloc", "big_map": "-1", "key_type": {"prim": "nat"}, "value_type": {"prim": "string"}},
+				{"action": "copy", "source_big_map": "12", "destination_big_map": "40"},
+				{"action": "update", "big_map": "40", "key_hash": "expru1", "key": {"int": "1"}, "value": {"string": "one"}},
+				{"action": "update", "big_map": "40", "key_hash": "expru2", "key": {"int": "2"}},
+				{"action": "remove", "big_map": "-1"}
+			]},
+			"internal_operation_results": [
+				{"kind": "transaction", "source": "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi", "nonce": 0,
+					"result": {"status": "applied", "big_map_diff": [{"action": "update", "big_map": "12", "key_hash": "expru3", "key": {"int": "3"}, "value": {"string": "three"}}]}},
+				{"kind": "transaction", "source": "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi", "nonce": 1,
+					"result": {"status": "backtracked", "big_map_diff": [{"action": "update", "big_map": "12", "key_hash": "expru4", "key": {"int": "4"}}]}}
+			]
+		}
+	}]}`
+	op := tgo.BlockOperation{}
+	if err := json.Unmarshal([]byte(src), &op); err != nil {
+		t.Fatal(err)
+	}
+	changes := op.BigMapDiffs()
+	if len(changes) != 3 || len(changes[-1]) != 2 || len(changes[40]) != 3 || len(changes[12]) != 1 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if alloc := changes[-1][0]; alloc.Action != tgo.BigMapAlloc || !alloc.KeyType.IsPrim("nat") || !alloc.ValueType.IsPrim("string") {
+		t.Fatalf("unexpected alloc %+v", alloc)
+	}
+	if copied := changes[40][0]; copied.Action != tgo.BigMapCopy || copied.SourceBigMap != 12 || copied.Target() != 40 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	if update := changes[40][1]; update.KeyHash != "expru1" || update.Value.Str != "one" || update.IsKeyRemoval() {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	if !changes[40][2].IsKeyRemoval() {
+		t.Fatal("expected a key removal")
+	}
+	if internal := changes[12][0]; internal.KeyHash != "expru3" {
+		t.Fatalf("unexpected internal diff %+v", internal)
+	}
+	if len(op.Contents[0].BigMapDiffs()[40]) != 3 {
+		t.Fatal("unexpected diffs for the content")
+	}
+
+	// the diffs of protocols before Babylon are updates without action
+	diff := tgo.BigMapDiff{}
+	if err := json.Unmarshal([]byte(`{"key_hash": "expru5", "key": {"string": "tz1"}}`), &diff); err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsUpdate() || !diff.IsKeyRemoval() {
+		t.Fatalf("expected a key removal %+v", diff)
+	}
+}
@@ -0,0 +1,71 @@
+package tgo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BlockID designates a block in the path of block-scoped RPCs: an alias such as head or
+// genesis, a block hash or a level, optionally followed by ~N to designate its Nth ancestor
+// or +N for its Nth successor
+type BlockID string
+
+// Block aliases resolved by the node, which also knows checkpoint, savepoint and caboose
+const (
+	Head    BlockID = "head"
+	Genesis BlockID = "genesis"
+)
+
+// LevelID returns the id of the block at level of the main chain
+func LevelID(level int64) BlockID {
+	return BlockID(strconv.FormatInt(level, 10))
+}
+
+// ParseBlockID validates s and returns it as a BlockID
+func ParseBlockID(s string) (BlockID, error) {
+	id := BlockID(s)
+	return id, id.Validate()
+}
+
+// Ancestor returns the id of the nth predecessor of the block
+func (id BlockID) Ancestor(n int64) BlockID {
+	if n == 0 {
+		return id
+	}
+	return BlockID(fmt.Sprintf("%s~%d", string(id), n))
+}
+
+// Validate returns an error when the id is neither an alias, a block hash nor a level, or
+// has an invalid offset
+func (id BlockID) Validate() error {
+	base := string(id)
+	if i := strings.IndexAny(base, "~+"); i >= 0 {
+		offset, err := strconv.ParseUint(base[i+1:], 10, 31)
+		if err != nil || base[i+1:] != strconv.FormatUint(offset, 10) {
+			return fmt.Errorf("invalid block id %q: invalid offset", string(id))
+		}
+		base = base[:i]
+	}
+	switch base {
+	case "head", "genesis", "checkpoint", "savepoint", "caboose":
+		return nil
+	}
+	if isBlockHash(BlockID(base)) {
+		if _, err := b58CheckDecode(base, prefixBlock); err != nil {
+			return fmt.Errorf("invalid block id %q: %w", string(id), err)
+		}
+		return nil
+	}
+	if level, err := strconv.ParseUint(base, 10, 31); err == nil && base == strconv.FormatUint(level, 10) {
+		return nil
+	}
+	return fmt.Errorf("invalid block id %q", string(id))
+}
+
+// String returns the path segment of the block, escaped so an invalid id cannot designate
+// another path of the node
+func (id BlockID) String() string {
+	return url.PathEscape(string(id))
+}
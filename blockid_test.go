@@ -0,0 +1,47 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestBlockID(t *testing.T) {
+	for _, valid := range []string{"head", "genesis", "checkpoint", "head~10", "head+0", "12", "0",
+		"BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2", "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2~3"} {
+		if _, err := tgo.ParseBlockID(valid); err != nil {
+			t.Fatalf("expected %s to be valid got %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "tail", "head~", "head~-1", "head~1~2", "-3", "012", "12.5",
+		"BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW3", "BLock"} {
+		if _, err := tgo.ParseBlockID(invalid); err == nil {
+			t.Fatalf("expected %q to be invalid", invalid)
+		}
+	}
+	if id := tgo.LevelID(42); id != "42" || id.Validate() != nil {
+		t.Fatalf("unexpected level id %s", id)
+	}
+	if id := tgo.Head.Ancestor(2); id != "head~2" || tgo.Head.Ancestor(0) != tgo.Head {
+		t.Fatalf("unexpected ancestor %s", id)
+	}
+	// ids are escaped in the paths they are formatted in
+	for id, segment := range map[tgo.BlockID]string{"head~2": "head~2", "head+1": "head+1", "x/../y": "x%2F..%2Fy", "head?x=1": "head%3Fx=1"} {
+		if id.String() != segment {
+			t.Fatalf("%s: expected the segment %s got %s", string(id), segment, id.String())
+		}
+	}
+
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head~2/header": map[string]interface{}{"level": 40},
+	})
+	if header, err := client.GetBlockHeader(context.Background(), tgo.Head.Ancestor(2)); err != nil || header.Level != 40 {
+		t.Fatalf("unexpected header %+v, %v", header, err)
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if len(node.bodies["GET /chains/main/blocks/head~2/header"]) != 1 {
+		t.Fatal("expected the ancestor path to be requested")
+	}
+}
@@ -0,0 +1,152 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BlockHeader holds the shell header of a block along with its hash
+type BlockHeader struct {
+	Protocol       ProtocolHash `json:"protocol"`
+	ChainID        ChainID      `json:"chain_id"`
+	Hash           BlockHash    `json:"hash"`
+	Level          int64        `json:"level"`
+	Proto          int64        `json:"proto"`
+	Predecessor    BlockHash    `json:"predecessor"`
+	Timestamp      Timestamp    `json:"timestamp"`
+	ValidationPass int64        `json:"validation_pass"`
+	OperationsHash string       `json:"operations_hash"`
+	Fitness        []string     `json:"fitness"`
+	Context        string       `json:"context"`
+	Priority       int64        `json:"priority"`
+	// ProofOfWorkNonce and SeedNonceHash are part of the protocol data signed by the baker
+	ProofOfWorkNonce string `json:"proof_of_work_nonce,omitempty"`
+	SeedNonceHash    string `json:"seed_nonce_hash,omitempty"`
+	Signature        string `json:"signature"`
+}
+
+// BlockOperation is an operation group as included in a block, with receipts
+type BlockOperation struct {
+	Protocol  ProtocolHash      `json:"protocol"`
+	ChainID   ChainID           `json:"chain_id"`
+	Hash      OperationHash     `json:"hash"`
+	Branch    BlockHash         `json:"branch"`
+	Contents  []AppliedContents `json:"contents"`
+	Signature string            `json:"signature"`
+}
+
+// BlockMetadata holds the response from `GET /chains/<chain>/blocks/<block_id>/metadata`
+type BlockMetadata struct {
+	Protocol     ProtocolHash `json:"protocol"`
+	NextProtocol ProtocolHash `json:"next_protocol"`
+	Baker        Address      `json:"baker"`
+	// Level is read from level_info and voting_period_info from Florence on
+	Level BlockLevel `json:"level"`
+	// BalanceUpdates are the rewards and deposits of the baker and the protocol migrations
+	BalanceUpdates BalanceUpdates `json:"balance_updates,omitempty"`
+}
+
+// CurrentLevel returns the level information of the block whichever protocol it belongs to
+func (m BlockMetadata) CurrentLevel() BlockLevel {
+	return m.Level
+}
+
+// BlockLevel locates a block within its cycle and voting period
+type BlockLevel struct {
+	Level                int64 `json:"level"`
+	LevelPosition        int64 `json:"level_position"`
+	Cycle                int64 `json:"cycle"`
+	CyclePosition        int64 `json:"cycle_position"`
+	VotingPeriod         int64 `json:"voting_period"`
+	VotingPeriodPosition int64 `json:"voting_period_position"`
+	ExpectedCommitment   bool  `json:"expected_commitment"`
+}
+
+// GetBlockHeader calls GET /chains/<chain>/blocks/<block_id>/header
+func (rpc *RPC) GetBlockHeader(ctx context.Context, blockID BlockID) (BlockHeader, error) {
+	if block, ok, err := rpc.cachedBlock(ctx, blockID); ok {
+		return block.Header, err
+	}
+	header := BlockHeader{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/header", rpc.ChainAlias(ctx), blockID), &header)
+	return header, err
+}
+
+// GetBlockMetadata calls GET /chains/<chain>/blocks/<block_id>/metadata
+func (rpc *RPC) GetBlockMetadata(ctx context.Context, blockID BlockID) (BlockMetadata, error) {
+	if block, ok, err := rpc.cachedBlock(ctx, blockID); ok {
+		return block.Metadata, err
+	}
+	metadata := BlockMetadata{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/metadata", rpc.ChainAlias(ctx), blockID), &metadata)
+	return metadata, err
+}
+
+// GetBlockOperations calls GET /chains/<chain>/blocks/<block_id>/operations, returning
+// the operations of the block grouped by validation pass
+func (rpc *RPC) GetBlockOperations(ctx context.Context, blockID BlockID) ([][]BlockOperation, error) {
+	if block, ok, err := rpc.cachedBlock(ctx, blockID); ok {
+		return block.Operations, err
+	}
+	ops := [][]BlockOperation{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/operations", rpc.ChainAlias(ctx), blockID), &ops)
+	return ops, err
+}
+
+// BlocksQuery selects the blocks listed by GetBlocks, zero values are left out of the query
+type BlocksQuery struct {
+	// Length is how many blocks are listed from each head, 1 when 0
+	Length int64
+	// Heads are the blocks the lists start from, the current head when empty
+	Heads []BlockHash
+	// MinDate leaves out the heads older than it
+	MinDate time.Time
+}
+
+// GetBlocks calls GET /chains/<chain>/blocks and returns a list of block hashes for each
+// head, starting with the head and going back through its predecessors
+func (rpc *RPC) GetBlocks(ctx context.Context, opts BlocksQuery) ([][]BlockHash, error) {
+	q := query{}
+	if opts.Length > 0 {
+		q.addInts("length", opts.Length)
+	}
+	for _, head := range opts.Heads {
+		q.add("head", string(head))
+	}
+	if !opts.MinDate.IsZero() {
+		q.addInts("min_date", opts.MinDate.Unix())
+	}
+	blocks := [][]BlockHash{}
+	err := rpc.get(ctx, q.path(fmt.Sprintf("/chains/%s/blocks", rpc.ChainAlias(ctx))), &blocks)
+	return blocks, err
+}
+
+// Block holds the response from `GET /chains/<chain>/blocks/<block_id>`
+type Block struct {
+	Protocol   ProtocolHash       `json:"protocol"`
+	ChainID    ChainID            `json:"chain_id"`
+	Hash       BlockHash          `json:"hash"`
+	Header     BlockHeader        `json:"header"`
+	Metadata   BlockMetadata      `json:"metadata"`
+	Operations [][]BlockOperation `json:"operations"`
+}
+
+// GetBlock calls GET /chains/<chain>/blocks/<block_id>, the hash, chain and protocol of the
+// block are copied to its header
+func (rpc *RPC) GetBlock(ctx context.Context, blockID BlockID) (Block, error) {
+	if block, ok, err := rpc.cachedBlock(ctx, blockID); ok {
+		return block, err
+	}
+	return rpc.fetchBlock(ctx, blockID)
+}
+
+// fetchBlock reads the block blockID from the node
+func (rpc *RPC) fetchBlock(ctx context.Context, blockID BlockID) (Block, error) {
+	block := Block{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s", rpc.ChainAlias(ctx), blockID), &block); err != nil {
+		return block, err
+	}
+	block.Header.Hash, block.Header.ChainID, block.Header.Protocol = block.Hash, block.ChainID, block.Protocol
+	return block, nil
+}
@@ -0,0 +1,59 @@
+// Package boltstore persists the responses cached by a tgo client in a bolt database
+package boltstore
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucket holds every cached response keyed by request path
+var bucket = []byte("responses")
+
+// Store is a tgo.CacheStore backed by a bolt database file
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the database at path, creating it if needed. A database can only be opened by one
+// process at a time, Open gives up after a second if another process holds it.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Get returns the response stored for key
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		// values are only valid during the transaction
+		if v := tx.Bucket(bucket).Get([]byte(key)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return value, value != nil, err
+}
+
+// Put stores value for key
+func (s *Store) Put(key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), value)
+	})
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
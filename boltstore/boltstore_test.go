@@ -0,0 +1,73 @@
+package boltstore_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/boltstore"
+)
+
+func TestStore(t *testing.T) {
+	hash := "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2"
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"level": 1, "hash": hash})
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "boltstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.db")
+
+	read := func(blockID string) {
+		store, err := boltstore.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		client := tgo.GenerateClient(server.URL, time.Second*5)
+		client.Store = store
+		for i := 0; i < 2; i++ {
+			if header, err := client.GetBlockHeader(context.Background(), tgo.BlockID(blockID)); err != nil || header.Level != 1 {
+				t.Fatalf("unexpected header %+v, %v", header, err)
+			}
+		}
+	}
+	read(hash)
+	// the response survives reopening the database
+	read(hash)
+	read("head")
+	mu.Lock()
+	defer mu.Unlock()
+	if n := requests["/chains/main/blocks/"+hash+"/header"]; n != 1 {
+		t.Fatalf("expected the header to be fetched once got %d", n)
+	}
+	if n := requests["/chains/main/blocks/head/header"]; n != 2 {
+		t.Fatalf("expected head to be fetched every time got %d", n)
+	}
+
+	store, err := boltstore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, ok, err := store.Get("missing"); ok || err != nil {
+		t.Fatalf("expected no value got %v, %v", ok, err)
+	}
+}
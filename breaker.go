@@ -0,0 +1,103 @@
+package tgo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// default settings of a CircuitBreaker
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned instead of calling a node whose circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open after repeated failures")
+
+// CircuitBreaker stops calls to a node after repeated failures. Once Threshold consecutive
+// requests failed with a network error or a 5xx status, requests fail immediately with
+// ErrCircuitOpen for Cooldown, after which a single trial request decides whether the circuit
+// closes again or stays open for another Cooldown. Failing over to another node is out of
+// scope, the breaker only guards the client it is set on. Services spreading requests over
+// several nodes can skip the nodes whose breaker is Open.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures opening the circuit, 5 by default
+	Threshold int
+	// Cooldown is how long the circuit stays open before a trial request, 30 seconds by default
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// trial is set while the request deciding whether to close the circuit runs
+	trial bool
+}
+
+// NewCircuitBreaker returns a closed circuit breaker, zero values use defaults
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Open reports whether calls are currently short-circuited
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open(time.Now())
+}
+
+// open reports whether requests are rejected at now, b.mu must be held
+func (b *CircuitBreaker) open(now time.Time) bool {
+	if b.failures < b.threshold() {
+		return false
+	}
+	return b.trial || now.Sub(b.openedAt) < b.cooldown()
+}
+
+// allow returns ErrCircuitOpen when the request must not be made, and whether the request is
+// the trial of a circuit that was open
+func (b *CircuitBreaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open(time.Now()) {
+		return false, ErrCircuitOpen
+	}
+	if b.failures >= b.threshold() {
+		b.trial = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// record updates the breaker with the outcome of a request allowed by allow. Requests which
+// neither succeeded nor failed, such as cancelled ones, only end the trial.
+func (b *CircuitBreaker) record(trial, failed, succeeded bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if trial {
+		b.trial = false
+	}
+	switch {
+	case succeeded:
+		b.failures = 0
+	case failed:
+		b.failures++
+		if b.failures >= b.threshold() {
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+func (b *CircuitBreaker) threshold() int {
+	if b.Threshold <= 0 {
+		return defaultBreakerThreshold
+	}
+	return b.Threshold
+}
+
+func (b *CircuitBreaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return defaultBreakerCooldown
+	}
+	return b.Cooldown
+}
@@ -0,0 +1,74 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	failing, requests := true, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if failing {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"level": 7})
+	}))
+	defer server.Close()
+	set := func(fail bool) int {
+		mu.Lock()
+		defer mu.Unlock()
+		failing = fail
+		return requests
+	}
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	client.Breaker = tgo.NewCircuitBreaker(2, time.Millisecond*50)
+	get := func() error {
+		_, err := client.GetBlockHeader(context.Background(), "head")
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := get(); err == nil || err == tgo.ErrCircuitOpen {
+			t.Fatalf("expected the node error got %v", err)
+		}
+	}
+	if err := get(); err != tgo.ErrCircuitOpen || !client.Breaker.Open() {
+		t.Fatalf("expected the circuit to be open got %v", err)
+	}
+	if n := set(true); n != 2 {
+		t.Fatalf("expected the open circuit to skip the node got %d requests", n)
+	}
+
+	// the trial request after the cooldown fails and the circuit opens again
+	time.Sleep(time.Millisecond * 60)
+	if err := get(); err == nil || err == tgo.ErrCircuitOpen {
+		t.Fatalf("expected the trial to reach the node got %v", err)
+	}
+	if err := get(); err != tgo.ErrCircuitOpen {
+		t.Fatalf("expected the circuit to open again got %v", err)
+	}
+
+	// a successful trial closes the circuit
+	set(false)
+	time.Sleep(time.Millisecond * 60)
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if client.Breaker.Open() {
+		t.Fatal("expected the circuit to be closed")
+	}
+}
@@ -0,0 +1,92 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// OperationBuilder accumulates operations from a single signer and sends them as one
+// operation group with sequential counters and a single signature
+type OperationBuilder struct {
+	rpc      *RPC
+	signer   Signer
+	contents []OperationContents
+}
+
+// NewOperationBuilder returns an empty builder for operations signed by signer
+func (rpc *RPC) NewOperationBuilder(signer Signer) *OperationBuilder {
+	return &OperationBuilder{rpc: rpc, signer: signer}
+}
+
+// Add appends arbitrary contents, limits and fee left empty are computed when sending
+func (b *OperationBuilder) Add(contents OperationContents) *OperationBuilder {
+	b.contents = append(b.contents, contents)
+	return b
+}
+
+// AddTransaction appends a transfer of amount mutez to destination
+func (b *OperationBuilder) AddTransaction(destination string, amount int64) *OperationBuilder {
+	return b.Add(OperationContents{
+		Kind:        "transaction",
+		Destination: destination,
+		Amount:      strconv.FormatInt(amount, 10),
+	})
+}
+
+// AddContractCall appends a call to entrypoint of contract
+func (b *OperationBuilder) AddContractCall(contract string, amount int64, entrypoint string, value micheline.Node) *OperationBuilder {
+	return b.Add(OperationContents{
+		Kind:        "transaction",
+		Destination: contract,
+		Amount:      strconv.FormatInt(amount, 10),
+		Parameters:  &Parameters{Entrypoint: entrypoint, Value: value},
+	})
+}
+
+// AddDelegation appends a delegation to delegate, an empty delegate withdraws the delegation
+func (b *OperationBuilder) AddDelegation(delegate string) *OperationBuilder {
+	return b.Add(delegationContents(delegate))
+}
+
+// Contents returns the operations added so far
+func (b *OperationBuilder) Contents() []OperationContents {
+	return b.contents
+}
+
+// Send simulates the accumulated operations to size their gas and storage limits,
+// then forges, signs and injects them as a single operation group
+func (b *OperationBuilder) Send(ctx context.Context) (string, error) {
+	if len(b.contents) == 0 {
+		return "", errors.New("no operations to send")
+	}
+	contents := make([]OperationContents, len(b.contents))
+	copy(contents, b.contents)
+	return b.rpc.sendOperation(ctx, b.signer, contents)
+}
+
+// DryRun prepares the accumulated operations as Send does, sizing their limits and fees,
+// and returns the operation group and the result of its simulation without signing or
+// injecting it
+func (b *OperationBuilder) DryRun(ctx context.Context) (Operation, []AppliedContents, error) {
+	if len(b.contents) == 0 {
+		return Operation{}, nil, errors.New("no operations to simulate")
+	}
+	contents := make([]OperationContents, len(b.contents))
+	copy(contents, b.contents)
+	// the simulation does not reserve counters, which would be given out again afterwards
+	op, err := b.rpc.prepareOperation(ctx, b.signer, contents, false)
+	if err != nil {
+		return Operation{}, nil, err
+	}
+	if err := b.rpc.sizeLimits(ctx, &op); err != nil {
+		return Operation{}, nil, err
+	}
+	if _, err := b.rpc.forgeWithFees(ctx, &op); err != nil {
+		return Operation{}, nil, err
+	}
+	simulated, err := b.rpc.SimulateOperation(ctx, op)
+	return op, simulated, err
+}
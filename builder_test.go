@@ -0,0 +1,115 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestOperationBuilder(t *testing.T) {
+	simulate := func(body []byte) interface{} {
+		req := struct {
+			Operation tgo.Operation `json:"operation"`
+		}{}
+		json.Unmarshal(body, &req)
+		contents := []interface{}{}
+		for _, c := range req.Operation.Contents {
+			contents = append(contents, map[string]interface{}{
+				"kind": c.Kind,
+				"metadata": map[string]interface{}{
+					"operation_result": map[string]interface{}{
+						"status":                         "applied",
+						"consumed_gas":                   "10207",
+						"allocated_destination_contract": c.Destination == "tz1Wpefz7KdEkVf2hXGMRKYymVjML9Zpi1r7",
+					},
+				},
+			})
+		}
+		return map[string]interface{}{"contents": contents}
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter":     "10",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav",
+		"GET /chains/main/blocks/head/hash":                           "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"GET /chains/main/chain_id":                                   "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_operation": simulate,
+		"POST /chains/main/blocks/head/helpers/forge/operations":      strings.Repeat("ab", 1000),
+		"POST /injection/operation":                                   "ooHash",
+	})
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := client.NewOperationBuilder(key)
+	for i := 0; i < 49; i++ {
+		builder.AddTransaction("tz1bhL4zwmLJvHJK5ejDDKdeatpqorvJdc2s", int64(i+1))
+	}
+	builder.AddTransaction("tz1Wpefz7KdEkVf2hXGMRKYymVjML9Zpi1r7", 50)
+	if _, err := builder.Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sims := node.bodies["POST /chains/main/blocks/head/helpers/scripts/run_operation"]
+	if len(sims) != 1 || !strings.Contains(sims[0], `"gas_limit":"208000"`) {
+		t.Fatalf("expected a single simulation within the block gas limit, got %d", len(sims))
+	}
+	forges := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	op := tgo.Operation{}
+	if err := json.Unmarshal([]byte(forges[len(forges)-1]), &op); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.Contents) != 50 {
+		t.Fatalf("expected 50 contents got %d", len(op.Contents))
+	}
+	for i, c := range op.Contents {
+		if c.Counter != fmt.Sprint(11+i) || c.GasLimit != "10307" {
+			t.Fatalf("unexpected contents %d: %+v", i, c)
+		}
+	}
+	if op.Contents[0].StorageLimit != "0" || op.Contents[49].StorageLimit != "257" {
+		t.Fatalf("unexpected storage limits %s %s", op.Contents[0].StorageLimit, op.Contents[49].StorageLimit)
+	}
+}
+
+func TestOperationBuilderDryRun(t *testing.T) {
+	simulated := rawBody(`{"contents":[{"kind":"transaction","metadata":{"operation_result":{"status":"applied","consumed_gas":"1427"}}}]}`)
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter":     "10",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav",
+		"GET /chains/main/blocks/head/hash":                           "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"GET /chains/main/chain_id":                                   "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_operation": simulated,
+		"POST /chains/main/blocks/head/helpers/forge/operations":      strings.Repeat("ab", 100),
+	})
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Counters = tgo.NewCounterManager(client)
+	// another sender holds counter 11
+	if first, err := client.Counters.Reserve(context.Background(), key.PublicKeyHash(), 1); err != nil || first != 11 {
+		t.Fatalf("unexpected reservation %d: %v", first, err)
+	}
+	builder := client.NewOperationBuilder(key).AddTransaction("tz1bhL4zwmLJvHJK5ejDDKdeatpqorvJdc2s", 1)
+	for i := 0; i < 2; i++ {
+		op, results, err := builder.DryRun(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the counter is not kept by a dry run
+		if len(results) != 1 || results[0].Gas() != 1427 || op.Contents[0].Counter != "11" || op.Contents[0].GasLimit != "1527" || op.Contents[0].Fee == "" {
+			t.Fatalf("unexpected dry run %+v %+v", op, results)
+		}
+	}
+	if len(node.bodies["POST /injection/operation"]) != 0 {
+		t.Fatal("dry run injected the operation")
+	}
+	// the dry runs neither reserved counters nor made the manager forget counter 11
+	if first, err := client.Counters.Reserve(context.Background(), key.PublicKeyHash(), 1); err != nil || first != 12 {
+		t.Fatalf("unexpected reservation %d: %v", first, err)
+	}
+}
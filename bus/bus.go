@@ -0,0 +1,71 @@
+// Package bus publishes chain events to a message bus for downstream processing. The clients
+// of the buses are left to the caller: a NATS connection is used through NATSConn, other buses
+// are plugged in by implementing Publisher, e.g. with a kafka.Writer of segmentio/kafka-go
+//
+//	heads, _ := rpc.MonitorHeads(ctx, "main")
+//	ops, _ := rpc.WatchOperations(ctx, "tz1...")
+//	peers, _ := rpc.MonitorNetworkLog(ctx)
+//	events := notify.Merge(ctx, notify.Heads(ctx, heads), notify.Operations(ctx, ops), notify.Peers(ctx, peers))
+//	errs := bus.NewForwarder(bus.NewNATS(conn)).Run(ctx, events)
+//
+//	kafkaPublisher := bus.PublisherFunc(func(ctx context.Context, topic string, data []byte) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Topic: topic, Value: data})
+//	})
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/postables/TGo/notify"
+)
+
+// DefaultPrefix is prepended to the type of the events to name the subject they are published
+// on, e.g. tezos.head
+const DefaultPrefix = "tezos."
+
+// Publisher publishes a message on a subject of a bus, a NATS subject or a Kafka topic
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// PublisherFunc publishes with a function
+type PublisherFunc func(ctx context.Context, subject string, data []byte) error
+
+// Publish calls f
+func (f PublisherFunc) Publish(ctx context.Context, subject string, data []byte) error {
+	return f(ctx, subject, data)
+}
+
+// Forwarder publishes events as JSON on subjects named after their type
+type Forwarder struct {
+	// Prefix is prepended to the type of the events to name their subject, DefaultPrefix by default
+	Prefix string
+
+	publisher Publisher
+}
+
+// NewForwarder returns a forwarder publishing with publisher
+func NewForwarder(publisher Publisher) *Forwarder {
+	return &Forwarder{Prefix: DefaultPrefix, publisher: publisher}
+}
+
+// Publish publishes event on the subject of its type
+func (f *Forwarder) Publish(ctx context.Context, event notify.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	subject := f.Prefix + event.Type
+	if err := f.publisher.Publish(ctx, subject, data); err != nil {
+		return fmt.Errorf("publishing on %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Run publishes the events until the channel is closed or ctx is cancelled, the failed
+// publications are reported as by notify.Handle
+func (f *Forwarder) Run(ctx context.Context, events <-chan notify.Event) <-chan error {
+	return notify.Handle(ctx, events, f.Publish)
+}
@@ -0,0 +1,74 @@
+package bus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/bus"
+	"github.com/postables/TGo/notify"
+)
+
+func TestForwarder(t *testing.T) {
+	subjects := []string{}
+	payloads := [][]byte{}
+	f := bus.NewForwarder(bus.PublisherFunc(func(ctx context.Context, subject string, data []byte) error {
+		if subject == "tezos.peer" {
+			return errors.New("unavailable")
+		}
+		subjects = append(subjects, subject)
+		payloads = append(payloads, data)
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan notify.Event, 3)
+	events <- notify.NewEvent(notify.EventHead, tgo.BlockHeader{Level: 3})
+	events <- notify.NewEvent(notify.EventPeer, tgo.NetworkEvent{Event: "too_few_connections"})
+	events <- notify.NewEvent(notify.EventOperation, tgo.AddressOperation{Address: "tz1a", Operation: "opA"})
+	close(events)
+	failures := 0
+	for err := range f.Run(ctx, events) {
+		if err.Error() != "publishing on tezos.peer: unavailable" {
+			t.Fatal(err)
+		}
+		failures++
+	}
+	if failures != 1 || len(subjects) != 2 || subjects[0] != "tezos.head" || subjects[1] != "tezos.operation" {
+		t.Fatalf("unexpected publications %v and %d failures", subjects, failures)
+	}
+	op := struct {
+		Type string
+		Data tgo.AddressOperation
+	}{}
+	if err := json.Unmarshal(payloads[1], &op); err != nil {
+		t.Fatal(err)
+	}
+	if op.Type != notify.EventOperation || op.Data.Address != "tz1a" || op.Data.Operation != "opA" {
+		t.Fatalf("unexpected payload %s", payloads[1])
+	}
+}
+
+// natsConn records the messages published like a NATS connection
+type natsConn struct {
+	subjects []string
+}
+
+func (c *natsConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	return nil
+}
+
+func TestNATS(t *testing.T) {
+	conn := &natsConn{}
+	f := bus.NewForwarder(bus.NewNATS(conn))
+	if err := f.Publish(context.Background(), notify.NewEvent(notify.EventHead, tgo.BlockHeader{Level: 3})); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.subjects) != 1 || conn.subjects[0] != "tezos.head" {
+		t.Fatalf("unexpected publications %v", conn.subjects)
+	}
+}
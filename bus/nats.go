@@ -0,0 +1,25 @@
+package bus
+
+import "context"
+
+// NATSConn is the method of a NATS connection, such as *nats.Conn of github.com/nats-io/nats.go,
+// used to publish, so the client is left to the caller
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATS publishes on a NATS connection
+type NATS struct {
+	Conn NATSConn
+}
+
+// NewNATS returns a publisher on conn
+func NewNATS(conn NATSConn) *NATS {
+	return &NATS{Conn: conn}
+}
+
+// Publish publishes data on subject, the connection buffers the message and flushes it in
+// the background
+func (p *NATS) Publish(ctx context.Context, subject string, data []byte) error {
+	return p.Conn.Publish(subject, data)
+}
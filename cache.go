@@ -0,0 +1,92 @@
+package tgo
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+)
+
+// LRU keeps the most recently used decoded blocks and constants, keyed by block hash, so
+// reading several parts of the same block or its constants repeatedly does not fetch and
+// decode them again. Values handed out are shared with the cache and must not be modified.
+type LRU struct {
+	size int
+
+	mu      sync.Mutex
+	entries *list.List
+	keys    map[string]*list.Element
+}
+
+// lruEntry is a cached value and its key
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+// NewLRU returns a cache holding at most size values
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = 1
+	}
+	return &LRU{size: size, entries: list.New(), keys: map[string]*list.Element{}}
+}
+
+// Get returns the value cached for key and marks it as recently used
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.keys[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// Add caches value for key, evicting the least recently used value when the cache is full
+func (c *LRU) Add(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.keys[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.entries.MoveToFront(e)
+		return
+	}
+	c.keys[key] = c.entries.PushFront(&lruEntry{key: key, value: value})
+	if c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.keys, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of values cached
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// isBlockHash reports whether blockID designates a block by hash, whose content never changes,
+// rather than by level or alias
+func isBlockHash(blockID BlockID) bool {
+	return len(blockID) == 51 && strings.HasPrefix(string(blockID), "B")
+}
+
+// cachedBlock returns the block blockID from rpc.Cache, fetching the whole block once when
+// it is designated by hash. ok is false when the block cannot be cached.
+func (rpc *RPC) cachedBlock(ctx context.Context, blockID BlockID) (block Block, ok bool, err error) {
+	if rpc.Cache == nil || !isBlockHash(blockID) {
+		return Block{}, false, nil
+	}
+	if v, found := rpc.Cache.Get("block/" + string(blockID)); found {
+		return v.(Block), true, nil
+	}
+	block, err = rpc.fetchBlock(ctx, blockID)
+	if err != nil {
+		return Block{}, true, err
+	}
+	rpc.Cache.Add("block/"+string(blockID), block)
+	return block, true, nil
+}
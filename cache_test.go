@@ -0,0 +1,77 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestLRU(t *testing.T) {
+	cache := tgo.NewLRU(2)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a got %v", v)
+	}
+	// b is the least recently used
+	cache.Add("c", 3)
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok || cache.Len() != 2 {
+		t.Fatal("expected a to be kept")
+	}
+}
+
+func TestBlockCache(t *testing.T) {
+	hash := "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2"
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/" + hash: map[string]interface{}{
+			"hash":       hash,
+			"chain_id":   "NetXdQprcVkpaWU",
+			"header":     map[string]interface{}{"level": 7, "predecessor": "BLpred"},
+			"metadata":   map[string]interface{}{"baker": "tz1baker"},
+			"operations": [][]interface{}{{map[string]interface{}{"hash": "opHash"}}},
+		},
+		"GET /chains/main/blocks/" + hash + "/context/constants": map[string]interface{}{"blocks_per_cycle": 4096},
+		"GET /chains/main/blocks/7/header":                       map[string]interface{}{"level": 7},
+	})
+	client.Cache = tgo.NewLRU(16)
+	ctx := context.Background()
+	header, err := client.GetBlockHeader(ctx, tgo.BlockID(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Level != 7 || header.Hash != tgo.BlockHash(hash) || header.ChainID != "NetXdQprcVkpaWU" {
+		t.Fatalf("unexpected header %+v", header)
+	}
+	metadata, err := client.GetBlockMetadata(ctx, tgo.BlockID(hash))
+	if err != nil || metadata.Baker != "tz1baker" {
+		t.Fatalf("unexpected metadata %+v, %v", metadata, err)
+	}
+	ops, err := client.GetBlockOperations(ctx, tgo.BlockID(hash))
+	if err != nil || ops[0][0].Hash != "opHash" {
+		t.Fatalf("unexpected operations %+v, %v", ops, err)
+	}
+	for i := 0; i < 2; i++ {
+		if constants, err := client.GetConstants(ctx, tgo.BlockID(hash)); err != nil || constants.BlocksPerCycle != 4096 {
+			t.Fatalf("unexpected constants %+v, %v", constants, err)
+		}
+		// blocks designated by level may change and are not cached
+		if _, err := client.GetBlockHeader(ctx, "7"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if n := len(node.queries["GET /chains/main/blocks/"+hash]); n != 1 {
+		t.Fatalf("expected the block to be fetched once got %d", n)
+	}
+	if n := len(node.queries["GET /chains/main/blocks/"+hash+"/context/constants"]); n != 1 {
+		t.Fatalf("expected the constants to be fetched once got %d", n)
+	}
+	if n := len(node.queries["GET /chains/main/blocks/7/header"]); n != 2 {
+		t.Fatalf("expected headers by level to be fetched every time got %d", n)
+	}
+}
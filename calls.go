@@ -0,0 +1,67 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// ContractCall is a call to an entrypoint of a smart contract
+type ContractCall struct {
+	Contract string
+	// Entrypoint defaults to "default"
+	Entrypoint string
+	// Amount is the mutez sent along with the call
+	Amount int64
+	Value  micheline.Node
+}
+
+// entrypoint returns the called entrypoint
+func (c ContractCall) entrypoint() string {
+	if c.Entrypoint == "" {
+		return "default"
+	}
+	return c.Entrypoint
+}
+
+// contents returns the transaction performing the call
+func (c ContractCall) contents() OperationContents {
+	return OperationContents{
+		Kind:        "transaction",
+		Destination: c.Contract,
+		Amount:      strconv.FormatInt(c.Amount, 10),
+		Parameters:  &Parameters{Entrypoint: c.entrypoint(), Value: c.Value},
+	}
+}
+
+// ValidateContractCall checks the value of call against the parameter type of its entrypoint at blockID
+func (rpc *RPC) ValidateContractCall(ctx context.Context, blockID BlockID, call ContractCall) error {
+	typ, err := rpc.GetContractEntrypoint(ctx, blockID, call.Contract, call.entrypoint())
+	if err != nil {
+		return err
+	}
+	if err := ValidateData(call.Value, typ); err != nil {
+		return fmt.Errorf("invalid parameter for %s of %s: %w", call.entrypoint(), call.Contract, err)
+	}
+	return nil
+}
+
+// CallContract validates call against the entrypoint type at head, then forges, signs and
+// injects it and returns the operation hash
+func (rpc *RPC) CallContract(ctx context.Context, signer Signer, call ContractCall) (string, error) {
+	if err := rpc.ValidateContractCall(ctx, "head", call); err != nil {
+		return "", err
+	}
+	return rpc.sendOperation(ctx, signer, []OperationContents{call.contents()})
+}
+
+// AddCall validates call against the entrypoint type at head and appends it to the builder
+func (b *OperationBuilder) AddCall(ctx context.Context, call ContractCall) error {
+	if err := b.rpc.ValidateContractCall(ctx, "head", call); err != nil {
+		return err
+	}
+	b.Add(call.contents())
+	return nil
+}
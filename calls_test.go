@@ -0,0 +1,100 @@
+package tgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+func TestValidateData(t *testing.T) {
+	for _, c := range []struct {
+		data, typ string
+		valid     bool
+	}{
+		{`Pair "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" (Pair "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi%transfer" 10)`, `pair address (pair address nat)`, true},
+		{`Pair "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" 10`, `pair address address nat`, true},
+		{`Pair "tz1nope" 10`, `pair address nat`, false},
+		{`Pair "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" -1`, `pair address nat`, false},
+		{`{ Elt "a" (Some True) }`, `map string (option bool)`, true},
+		{`{ Elt "a" 1 }`, `map string (option bool)`, false},
+		{`Right (Left "2019-09-26T10:59:51Z")`, `or unit (or timestamp nat)`, true},
+		{`Left Unit`, `or (unit %a) (nat %b)`, true},
+		{`Some 0x0002298c03ed7d454a101eb7022bc95f7e5f41ac78`, `option key_hash`, true},
+		{`"edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav"`, `key`, true},
+		{`{ DROP ; UNIT }`, `lambda unit unit`, true},
+		{`42`, `big_map nat nat`, true},
+		{`"foo"`, `bytes`, false},
+		{`{ 1 ; 2 }`, `list int`, true},
+		{`{ 1 ; "2" }`, `list int`, false},
+	} {
+		data, err := micheline.Parse(c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typ, err := micheline.Parse(c.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tgo.ValidateData(data, typ); (err == nil) != c.valid {
+			t.Errorf("validate %s as %s: %v", c.data, c.typ, err)
+		}
+	}
+}
+
+func TestCallContract(t *testing.T) {
+	contract := "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi"
+	receipt := map[string]interface{}{
+		"kind":     "transaction",
+		"metadata": map[string]interface{}{"operation_result": map[string]interface{}{"status": "applied", "consumed_gas": "20000"}},
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/" + contract + "/entrypoints": map[string]interface{}{
+			"entrypoints": map[string]interface{}{"increment": map[string]interface{}{"prim": "nat"}, "reset": map[string]interface{}{"prim": "unit"}},
+		},
+		"GET /chains/main/blocks/head/context/contracts/" + contract + "/entrypoints/increment":           map[string]interface{}{"prim": "nat"},
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter":     "1",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav",
+		"GET /chains/main/blocks/head/hash":                           "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"GET /chains/main/chain_id":                                   "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_operation": map[string]interface{}{"contents": []interface{}{receipt}},
+		"POST /chains/main/blocks/head/helpers/forge/operations":      strings.Repeat("ab", 100),
+		"POST /injection/operation":                                   "ooHash",
+	})
+	entrypoints, err := client.GetContractEntrypoints(context.Background(), "head", contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entrypoints.Entrypoints) != 2 || !entrypoints.Entrypoints["reset"].IsPrim("unit") {
+		t.Fatalf("unexpected entrypoints %+v", entrypoints)
+	}
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := tgo.ContractCall{Contract: contract, Entrypoint: "increment", Value: micheline.NewString("one")}
+	if _, err := client.CallContract(context.Background(), key, call); err == nil || !strings.Contains(err.Error(), "invalid parameter") {
+		t.Fatalf("expected a type error, got %v", err)
+	}
+	if _, ok := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]; ok {
+		t.Fatal("an invalid call must not be forged")
+	}
+	call.Value = micheline.NewInt(5)
+	hash, err := client.CallContract(context.Background(), key, call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forges := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	if hash != "ooHash" || !strings.Contains(forges[len(forges)-1], `"parameters":{"entrypoint":"increment","value":{"int":"5"}}`) {
+		t.Fatalf("unexpected call %s %s", hash, forges[len(forges)-1])
+	}
+	builder := client.NewOperationBuilder(key)
+	if err := builder.AddCall(context.Background(), tgo.ContractCall{Contract: contract, Entrypoint: "missing"}); err == nil {
+		t.Fatal("expected error for an unknown entrypoint")
+	}
+	if len(builder.Contents()) != 0 {
+		t.Fatal("an invalid call must not be added")
+	}
+}
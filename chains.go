@@ -1,41 +1,106 @@
 package tgo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"net/http"
 )
 
-func (rpc *RPC) GetChainID(chainAlias string) error {
-	url := fmt.Sprintf("%s/chains/%s/chain_id", rpc.URL, chainAlias)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
-		return err
+// chainKey is the context key of the chain set by WithChain
+type chainKey struct{}
+
+// WithChain returns a copy of ctx making the /chains/<chain>/... calls made with it target
+// chain, such as "test" or a chain id, instead of the default chain of the client
+func WithChain(ctx context.Context, chain string) context.Context {
+	return context.WithValue(ctx, chainKey{}, chain)
+}
+
+// ChainAlias returns the chain targeted by calls made with ctx, the chain set by WithChain,
+// else the Chain of the client, else "main"
+func (rpc *RPC) ChainAlias(ctx context.Context) string {
+	if chain, ok := ctx.Value(chainKey{}).(string); ok && chain != "" {
+		return chain
 	}
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if rpc.Chain != "" {
+		return rpc.Chain
 	}
-	fmt.Println(string(respBytes))
-	return nil
+	return "main"
+}
+
+// GetChainID calls GET /chains/<chain>/chain_id
+func (rpc *RPC) GetChainID(ctx context.Context, chainAlias string) (ChainID, error) {
+	var chainID ChainID
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/chain_id", chainAlias), &chainID)
+	return chainID, err
+}
+
+// Checkpoint holds the response from `GET /chains/<chain>/checkpoint`
+type Checkpoint struct {
+	Block       BlockHeader `json:"block"`
+	SavePoint   int64       `json:"save_point"`
+	Caboose     int64       `json:"caboose"`
+	HistoryMode string      `json:"history_mode"`
+}
+
+// GetCheckpoint calls GET /chains/<chain>/checkpoint
+func (rpc *RPC) GetCheckpoint(ctx context.Context, chainAlias string) (Checkpoint, error) {
+	checkpoint := Checkpoint{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/checkpoint", chainAlias), &checkpoint)
+	return checkpoint, err
+}
+
+// GetHeadBlock calls GET /chains/<chain>/blocks/head
+func (rpc *RPC) GetHeadBlock(chainAlias string) (Block, error) {
+	return rpc.GetBlock(WithChain(context.Background(), chainAlias), Head)
+}
+
+// InvalidBlock holds an entry of `GET /chains/<chain>/invalid_blocks`
+type InvalidBlock struct {
+	Block  BlockHash         `json:"block"`
+	Level  int64             `json:"level"`
+	Errors []json.RawMessage `json:"errors"`
+}
+
+// GetInvalidBlocks calls GET /chains/<chain>/invalid_blocks
+func (rpc *RPC) GetInvalidBlocks(ctx context.Context, chainAlias string) ([]InvalidBlock, error) {
+	blocks := []InvalidBlock{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/invalid_blocks", chainAlias), &blocks)
+	return blocks, err
+}
+
+// GetInvalidBlock calls GET /chains/<chain>/invalid_blocks/<block_hash>
+func (rpc *RPC) GetInvalidBlock(ctx context.Context, chainAlias string, blockHash BlockHash) (InvalidBlock, error) {
+	block := InvalidBlock{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/invalid_blocks/%s", chainAlias, blockHash), &block)
+	return block, err
 }
 
-func (rpc *RPC) GetHeadBlock(chainAlias string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("%s/chains/%s/blocks/head", rpc.URL, chainAlias)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
-		return nil, err
+// DeleteInvalidBlock calls DELETE /chains/<chain>/invalid_blocks/<block_hash>
+func (rpc *RPC) DeleteInvalidBlock(ctx context.Context, chainAlias string, blockHash BlockHash) error {
+	return rpc.do(ctx, http.MethodDelete, fmt.Sprintf("/chains/%s/invalid_blocks/%s", chainAlias, blockHash), nil, nil)
+}
+
+// CheckBootstrapped calls GET /chains/<chain>/is_bootstrapped and returns ErrNodeNotBootstrapped
+// while the node is still synchronizing the chain
+func (rpc *RPC) CheckBootstrapped(ctx context.Context, chainAlias string) error {
+	status := struct {
+		Bootstrapped bool `json:"bootstrapped"`
+	}{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/is_bootstrapped", chainAlias), &status); err != nil {
+		return err
 	}
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if !status.Bootstrapped {
+		return ErrNodeNotBootstrapped
 	}
-	m := make(map[string]interface{})
+	return nil
+}
 
-	err = json.Unmarshal(respBytes, &m)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Printf("%+v\n", m["hash"])
-	return m, nil
+// SetBootstrapped calls PATCH /chains/<chain> to force the bootstrapped state of the chain,
+// mostly useful for sandboxes and test networks
+func (rpc *RPC) SetBootstrapped(ctx context.Context, chainAlias string, bootstrapped bool) error {
+	body := struct {
+		Bootstrapped bool `json:"bootstrapped"`
+	}{bootstrapped}
+	return rpc.do(ctx, http.MethodPatch, fmt.Sprintf("/chains/%s", chainAlias), body, nil)
 }
@@ -1,6 +1,7 @@
 package tgo_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -10,10 +11,11 @@ import (
 
 func TestChains(t *testing.T) {
 	client := tgo.GenerateClient(tgo.RpcURL, time.Minute)
-	err := client.GetChainID("main")
+	chainID, err := client.GetChainID(context.Background(), "main")
 	if err != nil {
 		t.Fatal(err)
 	}
+	fmt.Println(chainID)
 
 	blockHeader, err := client.GetHeadBlock("main")
 	if err != nil {
@@ -21,3 +23,108 @@ func TestChains(t *testing.T) {
 	}
 	fmt.Printf("%+v\n", blockHeader)
 }
+
+func TestGetCheckpoint(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/chain_id":   "NetXdQprcVkpaWU",
+		"GET /chains/main/checkpoint": rawBody(`{"block":{"level":1024,"proto":4,"predecessor":"BLpred","timestamp":"2019-08-01T00:00:00Z","validation_pass":4,"operations_hash":"LLoa","fitness":["01","00"],"context":"CoV"},"save_point":1024,"caboose":0,"history_mode":"full"}`),
+	})
+	chainID, err := client.GetChainID(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainID != "NetXdQprcVkpaWU" {
+		t.Fatalf("unexpected chain id %s", chainID)
+	}
+	checkpoint, err := client.GetCheckpoint(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Block.Level != 1024 || checkpoint.SavePoint != 1024 || checkpoint.HistoryMode != "full" {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+}
+
+func TestInvalidBlocks(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/invalid_blocks":          rawBody(`[{"block":"BLbad","level":12,"errors":[{"kind":"permanent","id":"validator.invalid_block"}]}]`),
+		"GET /chains/main/invalid_blocks/BLbad":    rawBody(`{"block":"BLbad","level":12,"errors":[]}`),
+		"DELETE /chains/main/invalid_blocks/BLbad": rawBody(`{}`),
+	})
+	blocks, err := client.GetInvalidBlocks(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Block != "BLbad" || len(blocks[0].Errors) != 1 {
+		t.Fatalf("unexpected invalid blocks %+v", blocks)
+	}
+	block, err := client.GetInvalidBlock(context.Background(), "main", "BLbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Level != 12 {
+		t.Fatalf("unexpected invalid block %+v", block)
+	}
+	if err := client.DeleteInvalidBlock(context.Background(), "main", "BLbad"); err != nil {
+		t.Fatal(err)
+	}
+	if len(node.bodies["DELETE /chains/main/invalid_blocks/BLbad"]) != 1 {
+		t.Fatal("expected invalid block to be deleted")
+	}
+	if err := client.DeleteInvalidBlock(context.Background(), "main", "BLother"); err == nil {
+		t.Fatal("expected error for unknown block")
+	}
+}
+
+func TestSetBootstrapped(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"PATCH /chains/main": rawBody(`{}`),
+	})
+	if err := client.SetBootstrapped(context.Background(), "main", true); err != nil {
+		t.Fatal(err)
+	}
+	if body := node.bodies["PATCH /chains/main"][0]; body != `{"bootstrapped":true}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
+
+func TestCheckBootstrapped(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/is_bootstrapped": rawBody(`{"bootstrapped":false,"sync_state":"unsynced"}`),
+	})
+	if err := client.CheckBootstrapped(context.Background(), "main"); err != tgo.ErrNodeNotBootstrapped {
+		t.Fatalf("expected ErrNodeNotBootstrapped got %v", err)
+	}
+	node.route("GET /chains/main/is_bootstrapped", rawBody(`{"bootstrapped":true,"sync_state":"synced"}`))
+	if err := client.CheckBootstrapped(context.Background(), "main"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithChain(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header":  tgo.BlockHeader{Level: 1},
+		"GET /chains/test/blocks/head/header":  tgo.BlockHeader{Level: 2},
+		"GET /chains/NetXp/blocks/head/header": tgo.BlockHeader{Level: 3},
+	})
+	ctx := context.Background()
+	for _, c := range []struct {
+		chain string
+		ctx   context.Context
+		level int64
+	}{
+		{"", ctx, 1},
+		{"test", ctx, 2},
+		{"test", tgo.WithChain(ctx, "NetXp"), 3},
+		{"", tgo.WithChain(ctx, "test"), 2},
+	} {
+		client.Chain = c.chain
+		header, err := client.GetBlockHeader(c.ctx, tgo.Head)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Level != c.level {
+			t.Fatalf("chain %q: expected level %d got %d", client.ChainAlias(c.ctx), c.level, header.Level)
+		}
+	}
+}
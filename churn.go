@@ -0,0 +1,205 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// churn settings
+const (
+	// defaultChurnWindow is how long a ChurnTracker counts events by default
+	defaultChurnWindow = time.Hour
+	// maxChurnWindows is how many windows ChurnTracker.Windows splits the events into at most
+	maxChurnWindows = 10000
+)
+
+// ChurnKind is the kind of change of the connections of the node an event of /network/log counts as
+type ChurnKind string
+
+// Kinds of churn counted by a ChurnTracker
+const (
+	ChurnConnection    ChurnKind = "connection"
+	ChurnDisconnection ChurnKind = "disconnection"
+	ChurnRejection     ChurnKind = "rejection"
+)
+
+// churnKinds maps the events of /network/log about a peer to the churn they count as
+var churnKinds = map[string]ChurnKind{
+	"connection_established": ChurnConnection,
+	"disconnection":          ChurnDisconnection,
+	"external_disconnection": ChurnDisconnection,
+	"rejecting_request":      ChurnRejection,
+}
+
+// ChurnCounts holds the number of events of each kind
+type ChurnCounts struct {
+	Connections    int `json:"connections"`
+	Disconnections int `json:"disconnections"`
+	Rejections     int `json:"rejections"`
+}
+
+// Flakiness is the number of disconnections and rejections, by which peers are ranked
+func (c ChurnCounts) Flakiness() int {
+	return c.Disconnections + c.Rejections
+}
+
+// add counts an event of kind
+func (c *ChurnCounts) add(kind ChurnKind) {
+	switch kind {
+	case ChurnConnection:
+		c.Connections++
+	case ChurnDisconnection:
+		c.Disconnections++
+	case ChurnRejection:
+		c.Rejections++
+	}
+}
+
+// PeerChurn is the churn of the connections with a peer
+type PeerChurn struct {
+	PeerID PeerID `json:"peer_id"`
+	ChurnCounts
+}
+
+// ChurnWindow is the churn of all peers over a window of time starting at Start
+type ChurnWindow struct {
+	Start time.Time `json:"start"`
+	ChurnCounts
+}
+
+// churnEvent is an event counted by a ChurnTracker
+type churnEvent struct {
+	time time.Time
+	kind ChurnKind
+}
+
+// ChurnTracker counts the connections, disconnections and rejections of peers reported by
+// /network/log over a sliding window, to find the peers whose connections are the least stable
+type ChurnTracker struct {
+	// Window is how long events are counted, 1 hour by default
+	Window time.Duration
+
+	mu     sync.Mutex
+	events map[PeerID][]churnEvent
+}
+
+// NewChurnTracker returns a tracker counting events over window
+func NewChurnTracker(window time.Duration) *ChurnTracker {
+	return &ChurnTracker{Window: window, events: map[PeerID][]churnEvent{}}
+}
+
+// Track counts the events of the network log of the node behind rpc until ctx is cancelled,
+// returning the error that stopped the stream if any
+func (t *ChurnTracker) Track(ctx context.Context, rpc *RPC) error {
+	events, errs := rpc.MonitorNetworkLog(ctx)
+	for event := range events {
+		t.Observe(event)
+	}
+	return <-errs
+}
+
+// Observe counts event, events which are not about a peer or not a change of its connection
+// are ignored
+func (t *ChurnTracker) Observe(event NetworkEvent) {
+	kind, ok := churnKinds[event.Event]
+	if !ok || event.PeerID == "" {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events[event.PeerID] = append(t.prune(event.PeerID, now), churnEvent{time: now, kind: kind})
+}
+
+// prune forgets the events of peer older than the window and returns the remaining ones
+func (t *ChurnTracker) prune(peer PeerID, now time.Time) []churnEvent {
+	window := t.Window
+	if window <= 0 {
+		window = defaultChurnWindow
+	}
+	events := t.events[peer]
+	for len(events) > 0 && now.Sub(events[0].time) > window {
+		events = events[1:]
+	}
+	if len(events) == 0 {
+		delete(t.events, peer)
+	} else {
+		t.events[peer] = events
+	}
+	return events
+}
+
+// Peer returns the churn of peer within the window
+func (t *ChurnTracker) Peer(peer PeerID) PeerChurn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	churn := PeerChurn{PeerID: peer}
+	for _, e := range t.prune(peer, time.Now()) {
+		churn.add(e.kind)
+	}
+	return churn
+}
+
+// Flakiest returns at most n peers with disconnections or rejections within the window, the
+// flakiest first, all of them when n is 0
+func (t *ChurnTracker) Flakiest(n int) []PeerChurn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	peers := []PeerChurn{}
+	for id := range t.events {
+		churn := PeerChurn{PeerID: id}
+		for _, e := range t.prune(id, now) {
+			churn.add(e.kind)
+		}
+		if churn.Flakiness() > 0 {
+			peers = append(peers, churn)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Flakiness() != peers[j].Flakiness() {
+			return peers[i].Flakiness() > peers[j].Flakiness()
+		}
+		return peers[i].PeerID < peers[j].PeerID
+	})
+	if n > 0 && len(peers) > n {
+		peers = peers[:n]
+	}
+	return peers
+}
+
+// Windows splits the events of all peers within the window into consecutive windows of width,
+// the oldest first, the empty windows included. Widths which are not positive or would split
+// the events into more than 10000 windows are rejected.
+func (t *ChurnTracker) Windows(width time.Duration) ([]ChurnWindow, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("invalid churn window width %s", width)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	var all []churnEvent
+	for id := range t.events {
+		all = append(all, t.prune(id, now)...)
+	}
+	if len(all) == 0 {
+		return []ChurnWindow{}, nil
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].time.Before(all[j].time) })
+	start := all[0].time.Truncate(width)
+	n := now.Sub(start)/width + 1
+	if n > maxChurnWindows {
+		return nil, fmt.Errorf("churn window width %s splits the events into more than %d windows", width, maxChurnWindows)
+	}
+	windows := make([]ChurnWindow, n)
+	for i := range windows {
+		windows[i].Start = start.Add(time.Duration(i) * width)
+	}
+	for _, e := range all {
+		windows[int(e.time.Sub(start)/width)].add(e.kind)
+	}
+	return windows, nil
+}
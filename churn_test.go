@@ -0,0 +1,75 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestChurnTracker(t *testing.T) {
+	tracker := tgo.NewChurnTracker(time.Hour)
+	for _, e := range []tgo.NetworkEvent{
+		{Event: "connection_established", PeerID: "idtA"},
+		{Event: "disconnection", PeerID: "idtA"},
+		{Event: "connection_established", PeerID: "idtA"},
+		{Event: "external_disconnection", PeerID: "idtA"},
+		{Event: "rejecting_request", PeerID: "idtB"},
+		{Event: "connection_established", PeerID: "idtC"},
+		// events which are not about the connection of a peer are ignored
+		{Event: "too_few_connections"},
+		{Event: "gc_peer_ids", PeerID: "idtC"},
+	} {
+		tracker.Observe(e)
+	}
+	if churn := tracker.Peer("idtA"); churn.Connections != 2 || churn.Disconnections != 2 || churn.Flakiness() != 2 {
+		t.Fatalf("unexpected churn %+v", churn)
+	}
+	flakiest := tracker.Flakiest(0)
+	if len(flakiest) != 2 || flakiest[0].PeerID != "idtA" || flakiest[1].PeerID != "idtB" || flakiest[1].Rejections != 1 {
+		t.Fatalf("unexpected flakiest peers %+v", flakiest)
+	}
+	if flakiest := tracker.Flakiest(1); len(flakiest) != 1 {
+		t.Fatalf("unexpected flakiest peers %+v", flakiest)
+	}
+	windows, err := tracker.Windows(24 * time.Hour)
+	if err != nil || len(windows) != 1 || windows[0].Connections != 3 || windows[0].Disconnections != 2 || windows[0].Rejections != 1 {
+		t.Fatalf("unexpected windows %+v, %v", windows, err)
+	}
+	// widths splitting the events into too many windows are rejected
+	time.Sleep(time.Millisecond)
+	for _, width := range []time.Duration{0, -time.Second, time.Nanosecond} {
+		if _, err := tracker.Windows(width); err == nil {
+			t.Fatalf("width %s accepted", width)
+		}
+	}
+
+	// events older than the window are forgotten
+	tracker.Window = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if flakiest := tracker.Flakiest(0); len(flakiest) != 0 {
+		t.Fatalf("unexpected flakiest peers %+v", flakiest)
+	}
+	if windows, err := tracker.Windows(time.Second); err != nil || len(windows) != 0 {
+		t.Fatalf("unexpected windows %+v, %v", windows, err)
+	}
+}
+
+func TestChurnTrackerTrack(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/log": rawBody(`{"event":"disconnection","peer_id":"idtA"}`),
+	})
+	client.PollInterval = time.Millisecond
+	tracker := tgo.NewChurnTracker(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- tracker.Track(ctx, client) }()
+	for tracker.Peer("idtA").Disconnections == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,13 +1,56 @@
 package tgo
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 )
 
 type RPC struct {
 	URL    string
 	Client *http.Client
+	// PollInterval is how often helpers waiting on the chain poll the node
+	PollInterval time.Duration
+	// Counters, when set, allocates the counters of the manager operations sent through the
+	// client so concurrent senders sharing a source do not collide
+	Counters *CounterManager
+	// Coalesce makes concurrent GET requests for the same path share a single request to the
+	// node, each caller decoding the shared response
+	Coalesce bool
+	// Cache, when set, keeps the blocks and constants read by block hash, the header, metadata
+	// and operations of a block are then read with a single request for the whole block
+	Cache *LRU
+	// Store, when set, persists the responses of GET requests on blocks designated by hash.
+	// It is only an optimization, failures to read or write it fall back to the node.
+	Store CacheStore
+	// MaxResponseSize aborts reading responses whose decompressed body exceeds this many
+	// bytes with ErrResponseTooLarge, responses are not limited when 0. Monitoring streams
+	// are not limited.
+	MaxResponseSize int64
+	// Breaker, when set, stops requests to the node after repeated failures
+	Breaker *CircuitBreaker
+	// Strict fails decoding responses with fields unknown to the decoded types or missing
+	// fields not tagged omitempty, so test suites notice when a protocol changes the shape
+	// of responses instead of silently getting zero values
+	Strict bool
+	// Chain is the chain targeted by the /chains/<chain>/... calls unless overridden per call
+	// with WithChain, "main" when empty. Calls taking a chain argument use it instead.
+	Chain string
+	// Network, when set, is the network the node is expected to serve, see VerifyNetwork
+	Network *Network
+
+	flights *flightGroup
+	life    *lifecycle
+	// transport is the transport created by SetTransport, the only one Close closes
+	transport *http.Transport
 }
 
 func GenerateClient(rpcURL string, timeout time.Duration) *RPC {
@@ -16,5 +59,253 @@ func GenerateClient(rpcURL string, timeout time.Duration) *RPC {
 	client.Timeout = timeout
 	rpc.Client = client
 	rpc.URL = rpcURL
+	rpc.PollInterval = time.Second * 5
+	rpc.flights = newFlightGroup()
+	rpc.life = newLifecycle()
 	return &rpc
 }
+
+// ErrResponseTooLarge is returned when a response exceeds RPC.MaxResponseSize
+var ErrResponseTooLarge = errors.New("response exceeds the maximum size")
+
+// StatusError is returned when the node answers with a status other than 200 OK
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	// Errors are the errors reported by the node when the body is its JSON error list
+	Errors []NodeError
+}
+
+// NodeError is an entry of the error list returned by the node, e.g.
+// {"kind":"permanent","id":"proto.alpha.contract.balance_too_low"}
+type NodeError struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("expected status '200 OK' got %s: %s", e.Status, e.Body)
+}
+
+// get calls GET on path and decodes the JSON response into out, a body shared with coalesced
+// callers is decoded again for each of them, see flightGroup
+func (rpc *RPC) get(ctx context.Context, path string, out interface{}) error {
+	if !rpc.Coalesce && rpc.Store == nil {
+		return rpc.do(ctx, http.MethodGet, path, nil, out)
+	}
+	body, err := rpc.getBody(ctx, path)
+	if err != nil || out == nil {
+		return err
+	}
+	return rpc.unmarshal(path, body, out)
+}
+
+// getBody returns the body of GET path, from rpc.Store when the response never changes and
+// sharing the request with concurrent callers when rpc.Coalesce is set
+func (rpc *RPC) getBody(ctx context.Context, path string) ([]byte, error) {
+	stored := rpc.Store != nil && immutablePath(path)
+	if stored {
+		if body, ok, err := rpc.Store.Get(path); err == nil && ok {
+			return body, nil
+		}
+	}
+	fetch := func(ctx context.Context) ([]byte, error) {
+		return rpc.fetch(ctx, http.MethodGet, path, nil)
+	}
+	var body []byte
+	var err error
+	if rpc.Coalesce && rpc.flights != nil {
+		body, err = rpc.flights.do(ctx, path, fetch)
+	} else {
+		body, err = fetch(ctx)
+	}
+	if err == nil && stored {
+		rpc.Store.Put(path, body)
+	}
+	return body, err
+}
+
+// getArray calls GET on path and calls decode for every element of the JSON array returned,
+// so long lists are never held in memory at once
+func (rpc *RPC) getArray(ctx context.Context, path string, decode func(*json.Decoder) error) error {
+	resp, err := rpc.open(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decoder := rpc.newDecoder(resp.Body)
+	if t, err := decoder.Token(); err != nil {
+		return err
+	} else if t != json.Delim('[') {
+		return fmt.Errorf("expected array from %s got %v", path, t)
+	}
+	for decoder.More() {
+		if err := decode(decoder); err != nil {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// post calls POST on path with in encoded as JSON and decodes the response into out
+func (rpc *RPC) post(ctx context.Context, path string, in, out interface{}) error {
+	return rpc.do(ctx, http.MethodPost, path, in, out)
+}
+
+// do performs a request against the node and decodes the response into out as it is read,
+// out may be nil if the response body is not needed
+func (rpc *RPC) do(ctx context.Context, method, path string, in, out interface{}) error {
+	resp, err := rpc.open(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		// drain the body so the connection can be reused
+		_, err := io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	if rpc.Strict {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return rpc.unmarshal(path, body, out)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return err
+	}
+	// the decoder stops at the end of the value, the rest is drained so the connection can be
+	// reused
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// fetch performs a request against the node and returns the body of a 200 response
+func (rpc *RPC) fetch(ctx context.Context, method, path string, in interface{}) ([]byte, error) {
+	resp, err := rpc.open(ctx, method, path, in)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// open performs a request against the node and returns the response when its status is
+// 200, the caller must close its body
+func (rpc *RPC) open(ctx context.Context, method, path string, in interface{}) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, rpc.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := rpc.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body, err = rpc.responseBody(resp); err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkStatus returns a *StatusError holding the body of resp, which it closes, when the
+// status is not 200
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	defer resp.Body.Close()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(respBytes))}
+	errs := []NodeError{}
+	if json.Unmarshal(respBytes, &errs) == nil {
+		statusErr.Errors = errs
+	}
+	return statusErr
+}
+
+// send performs req through rpc.Breaker when set
+func (rpc *RPC) send(req *http.Request) (*http.Response, error) {
+	if rpc.Breaker == nil {
+		return rpc.Client.Do(req)
+	}
+	trial, err := rpc.Breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rpc.Client.Do(req)
+	cancelled := err != nil && req.Context().Err() != nil
+	failed := !cancelled && (err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	rpc.Breaker.record(trial, failed, err == nil && !failed)
+	return resp, err
+}
+
+// responseBody returns the body of resp decompressed and limited to MaxResponseSize
+func (rpc *RPC) responseBody(resp *http.Response) (io.ReadCloser, error) {
+	body := resp.Body
+	var r io.Reader = body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		r = gz
+		resp.Header.Del("Content-Encoding")
+		resp.ContentLength = -1
+	}
+	if rpc.MaxResponseSize > 0 {
+		if resp.ContentLength > rpc.MaxResponseSize {
+			body.Close()
+			return nil, ErrResponseTooLarge
+		}
+		r = &limitedReader{r: r, remaining: rpc.MaxResponseSize}
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, body}, nil
+}
+
+// limitedReader fails with ErrResponseTooLarge once more than remaining bytes are read
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// read one byte past the limit to tell a body of exactly the limit from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrResponseTooLarge
+	}
+	return n, err
+}
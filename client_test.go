@@ -0,0 +1,79 @@
+package tgo_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestCompressedAndLimitedResponses(t *testing.T) {
+	padding := strings.Repeat("x", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"level": 7, "hash": "BLhead", "context": "` + padding + `"}`
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			http.Error(w, "gzip expected", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(body))
+		gz.Close()
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+
+	header, err := client.GetBlockHeader(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Level != 7 || header.Hash != "BLhead" {
+		t.Fatalf("unexpected header %+v", header)
+	}
+
+	// the limit applies to the decompressed body
+	client.MaxResponseSize = 500
+	if _, err := client.GetBlockHeader(context.Background(), "head"); err != tgo.ErrResponseTooLarge {
+		t.Fatalf("expected ErrResponseTooLarge got %v", err)
+	}
+	client.MaxResponseSize = 2000
+	if _, err := client.GetBlockHeader(context.Background(), "head"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`[{"kind":"permanent","id":"proto.alpha.contract.balance_too_low","contract":"tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"}]`))
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	_, err := client.GetBlockHeader(context.Background(), "head")
+	statusErr, ok := err.(*tgo.StatusError)
+	if !ok || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected a status error got %v", err)
+	}
+	if len(statusErr.Errors) != 1 || statusErr.Errors[0].ID != "proto.alpha.contract.balance_too_low" || statusErr.Errors[0].Kind != "permanent" {
+		t.Fatalf("unexpected node errors %+v", statusErr.Errors)
+	}
+	if !errors.Is(err, tgo.ErrBalanceTooLow) || errors.Is(err, tgo.ErrCounterInThePast) || errors.Is(err, tgo.ErrNotFound) {
+		t.Fatalf("unexpected matches of %v", err)
+	}
+	if !errors.Is(fmt.Errorf("sending: %w", err), tgo.ErrBalanceTooLow) {
+		t.Fatal("expected the wrapped error to match")
+	}
+
+	// streams fail the same way
+	_, errs := client.MonitorHeads(context.Background(), "main")
+	if statusErr, ok := (<-errs).(*tgo.StatusError); !ok || len(statusErr.Errors) != 1 {
+		t.Fatalf("expected a status error from the stream got %v", statusErr)
+	}
+}
@@ -0,0 +1,69 @@
+// Command describegen writes the Go types of the requests and responses of the services
+// described by a node under a path of its RPC tree, e.g.
+//
+//	describegen -url http://localhost:8732 -path network -package tgo -out network_types.go
+//
+// The description can be read from a file saved from `GET /describe/<path>?recurse=yes`
+// with -in instead of a node.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/describegen"
+)
+
+func main() {
+	url := flag.String("url", "", "URL of the node to describe")
+	in := flag.String("in", "", "file holding the description, instead of a node")
+	path := flag.String("path", "", "path of the RPC tree to generate types for")
+	pkg := flag.String("package", "tgo", "package of the generated file")
+	out := flag.String("out", "", "file to write, standard output when empty")
+	flag.Parse()
+
+	description := tgo.Description{}
+	switch {
+	case *in != "":
+		b, err := ioutil.ReadFile(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &description); err != nil {
+			log.Fatal(err)
+		}
+	case *url != "":
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		var err error
+		if description, err = tgo.GenerateClient(*url, time.Minute).Describe(ctx, *path, true); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatal("describegen: one of -url or -in is required")
+	}
+
+	g := describegen.NewGenerator(*pkg)
+	for _, service := range describegen.Services(*path, description) {
+		if err := g.Add(service); err != nil {
+			log.Fatal(err)
+		}
+	}
+	source, err := g.Source()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
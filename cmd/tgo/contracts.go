@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+// queries runs the commands reading the context of block
+type queries struct {
+	rpc   *tgo.RPC
+	block tgo.BlockID
+	out   *output
+}
+
+// balance is the JSON of account balance
+type balance struct {
+	Address string `json:"address"`
+	Balance int64  `json:"balance"`
+}
+
+// accountInfo is the JSON of account info, the counter is only set for implicit accounts
+type accountInfo struct {
+	Address  string `json:"address"`
+	Balance  int64  `json:"balance"`
+	Delegate string `json:"delegate,omitempty"`
+	Counter  *int64 `json:"counter,omitempty"`
+}
+
+// value is the JSON of contract storage and bigmap get, the Micheline of a value
+type value struct {
+	Contract string         `json:"contract,omitempty"`
+	BigMap   *int64         `json:"big_map,omitempty"`
+	KeyHash  string         `json:"key_hash,omitempty"`
+	Value    micheline.Node `json:"value"`
+}
+
+// account runs the account command with its arguments
+func (q *queries) account(command string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("account %s takes an address", command)
+	}
+	address := tgo.Address(args[0])
+	if err := address.Validate(); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	switch command {
+	case "balance":
+		mutez, err := q.rpc.GetBalance(ctx, q.block, address.String())
+		if err != nil {
+			return err
+		}
+		return q.out.print(balance{Address: address.String(), Balance: mutez}, fields("address", address.String(), "balance", q.out.amount(mutez)))
+	case "info":
+		contract, err := q.rpc.GetContract(ctx, q.block, address.String())
+		if err != nil {
+			return err
+		}
+		info := accountInfo{Address: address.String(), Balance: contract.Balance, Delegate: contract.Delegate}
+		t := fields("address", info.Address, "balance", q.out.amount(info.Balance), "delegate", info.Delegate)
+		if address.Implicit() {
+			info.Counter = &contract.Counter
+			t.rows = append(t.rows, []string{"counter", strconv.FormatInt(contract.Counter, 10)})
+		}
+		return q.out.print(info, t)
+	}
+	return fmt.Errorf("unknown account command %q", command)
+}
+
+// contract runs the contract command with its arguments
+func (q *queries) contract(command string, args []string) error {
+	if command != "storage" {
+		return fmt.Errorf("unknown contract command %q", command)
+	}
+	if len(args) != 1 {
+		return errors.New("contract storage takes a contract address")
+	}
+	address := tgo.Address(args[0])
+	if err := address.Validate(); err != nil {
+		return err
+	}
+	storage, err := q.rpc.GetStorage(context.Background(), q.block, address.String())
+	if err != nil {
+		return err
+	}
+	return q.printValue(value{Contract: address.String(), Value: storage})
+}
+
+// bigMap runs the bigmap command with its arguments
+func (q *queries) bigMap(command string, args []string) error {
+	if command != "get" {
+		return fmt.Errorf("unknown bigmap command %q", command)
+	}
+	if len(args) != 2 && len(args) != 3 {
+		return errors.New("bigmap get takes a big map id followed by a key hash or by a key and its type")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid big map id %q", args[0])
+	}
+	keyHash := args[1]
+	if len(args) == 3 {
+		key, err := micheline.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid key: %w", err)
+		}
+		keyType, err := micheline.Parse(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid key type: %w", err)
+		}
+		if keyHash, err = tgo.BigMapKeyHash(key, keyType); err != nil {
+			return err
+		}
+	} else if !strings.HasPrefix(keyHash, "expr") {
+		return fmt.Errorf("invalid key hash %q, a key is followed by its type", keyHash)
+	}
+	v, found, err := q.rpc.GetBigMapValue(context.Background(), q.block, id, keyHash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s is not in big map %d", keyHash, id)
+	}
+	return q.printValue(value{BigMap: &id, KeyHash: keyHash, Value: v})
+}
+
+// printValue prints v with its value in Michelson, or as Micheline in JSON
+func (q *queries) printValue(v value) error {
+	if q.out.format == "table" {
+		_, err := fmt.Fprintln(q.out.w, micheline.Format(v.Value))
+		return err
+	}
+	t := table{header: []string{"contract", "value"}, rows: [][]string{{v.Contract, micheline.Format(v.Value)}}}
+	if v.BigMap != nil {
+		t = table{header: []string{"big_map", "key_hash", "value"}, rows: [][]string{{strconv.FormatInt(*v.BigMap, 10), v.KeyHash, micheline.Format(v.Value)}}}
+	}
+	return q.out.print(v, t)
+}
+
+// tez formats an amount of mutez in tez
+func tez(mutez int64) string {
+	sign := ""
+	if mutez < 0 {
+		sign, mutez = "-", -mutez
+	}
+	return fmt.Sprintf("%s%d.%06d tez", sign, mutez/1e6, mutez%1e6)
+}
@@ -0,0 +1,266 @@
+// Command tgo calls the RPC of a node from the command line, e.g.
+//
+//	tgo --node http://localhost:8732 network connections
+//
+// Every command prints its result as aligned columns by default, or as CSV or JSON with
+// --output csv or --output json. The JSON of a command keeps the same schema whatever the
+// result, the objects of the node as it returns them and the summaries of tgo with snake
+// case fields and amounts in mutez, so it can be piped to jq. The commands changing the node
+// without result print ok, {"ok": true} in JSON.
+//
+// The network commands manage the peers of the node:
+//
+//	network connections              list the connections of the node
+//	network peer <peer_id>           show a peer
+//	network ban-peer <peer_id>       ban a peer, unban-peer and trust-peer change its access the same way
+//	network ban-point <addr:port>    ban a point, unban-point and trust-point change its access the same way
+//	network clear-greylist           clear the greylist of the node
+//	network log                      stream the events of /network/log until interrupted
+//
+// The account, contract and bigmap commands read the context of the block given by --block:
+//
+//	account balance <address>        print the balance of an account
+//	account info <address>           print the balance, delegate and counter of an account
+//	contract storage <KT1>           print the storage of a contract, in Michelson as a table
+//	bigmap get <id> <key> <type>     print the value of a key of a big map, the key and its
+//	                                 type written in Michelson, e.g. '"tz1..."' address
+//	bigmap get <id> <expr...>        print the value of the key hash of a big map
+//
+// The transfer and delegate commands sign with the key of --from, an alias of the keys of
+// tezos-client or an address held by the remote signer of --signer. They take --dry-run to
+// only simulate the operation and --wait to wait for its inclusion:
+//
+//	transfer [flags] <destination> <amount>    transfer an amount of tez
+//	delegate [flags] <delegate>                set the delegate of --from, none to withdraw
+//
+// The monitor commands follow the streams of the node until interrupted, printing every
+// event as a row, or as a line of JSON:
+//
+//	monitor heads                    stream the new heads of the chain
+//	monitor mempool [flags]          stream the operations entering the mempool, the applied
+//	                                 ones unless selected with --refused, --branch-refused
+//	                                 and --branch-delayed
+//	monitor bootstrapped             wait for the node to be bootstrapped and print its head
+//
+// The rights and rewards commands report on a delegate for a cycle:
+//
+//	rights --delegate <address> --cycle <cycle>     list the baking and endorsing rights
+//	rewards --delegate <address> --cycle <cycle>    summarize the rewards, fees and deposits
+//
+// The flags of tgo, such as --node, go before the command. The flags of a command may come
+// before or after its arguments, the arguments following -- are never read as flags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func main() {
+	node := flag.String("node", "http://localhost:8732", "URL of the node")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of the requests")
+	chain := flag.String("chain", "main", "chain targeted by the commands")
+	block := flag.String("block", "head", "block whose context the account, contract and rights commands read")
+	format := flag.String("output", "table", "output format, table, csv or json")
+	asJSON := flag.Bool("json", false, "shorthand for --output json")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: tgo [flags] network|account|contract|bigmap|transfer|delegate|monitor|rights|rewards <command> [arguments]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !parsesFlags(args[0], args[1]) {
+		rest, err := positional(args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		args = append(args[:2:2], rest...)
+	}
+	if *asJSON {
+		*format = "json"
+	}
+	out, err := newOutput(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rpc := tgo.GenerateClient(*node, *timeout)
+	rpc.Chain = *chain
+	blockID, err := tgo.ParseBlockID(*block)
+	if err != nil {
+		log.Fatal(err)
+	}
+	q := &queries{rpc: rpc, block: blockID, out: out}
+	switch args[0] {
+	case "network":
+		err = network(rpc, out, args[1], args[2:])
+	case "account":
+		err = q.account(args[1], args[2:])
+	case "contract":
+		err = q.contract(args[1], args[2:])
+	case "bigmap":
+		err = q.bigMap(args[1], args[2:])
+	case "transfer":
+		err = newSender(rpc, out, "transfer").transfer(args[1:])
+	case "delegate":
+		err = newSender(rpc, out, "delegate").delegate(args[1:])
+	case "monitor":
+		err = monitor(rpc, out, args[1], args[2:])
+	case "rights":
+		err = newReporter(rpc, blockID, out, "rights").rights(args[1:])
+	case "rewards":
+		err = newReporter(rpc, blockID, out, "rewards").rewards(args[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parsesFlags reports whether the command parses flags of its own among its arguments
+func parsesFlags(command, subcommand string) bool {
+	switch command {
+	case "transfer", "delegate", "rights", "rewards":
+		return true
+	case "monitor":
+		return subcommand == "mempool"
+	}
+	return false
+}
+
+// isFlag reports whether arg is written as a flag, negative numbers being arguments
+func isFlag(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err != nil
+}
+
+// positional returns the arguments of a command without flags of its own, failing on the
+// flags of tgo given after the command that the flag package would leave among them
+func positional(args []string) ([]string, error) {
+	for i, arg := range args {
+		if arg == "--" {
+			return append(args[:i:i], args[i+1:]...), nil
+		}
+		if isFlag(arg) {
+			return nil, fmt.Errorf("flag %s must come before the command", arg)
+		}
+	}
+	return args, nil
+}
+
+// parseFlags parses the flags of args wherever they are, where the flag package stops at the
+// first argument, and returns the arguments in order. The arguments after -- are not parsed.
+func parseFlags(flags *flag.FlagSet, args []string) []string {
+	var rest []string
+	for {
+		flags.Parse(args)
+		left := flags.Args()
+		if len(left) == 0 {
+			return rest
+		}
+		if parsed := args[:len(args)-len(left)]; len(parsed) > 0 && parsed[len(parsed)-1] == "--" {
+			return append(rest, left...)
+		}
+		rest = append(rest, left[0])
+		args = left[1:]
+	}
+}
+
+// acls are the access policies set by the ban, unban and trust commands
+var acls = map[string]tgo.ACL{"ban": tgo.ACLBan, "unban": tgo.ACLOpen, "trust": tgo.ACLTrust}
+
+// network runs the network command with its arguments
+func network(rpc *tgo.RPC, out *output, command string, args []string) error {
+	arg := func() (string, error) {
+		if len(args) != 1 {
+			return "", fmt.Errorf("%s takes one argument", command)
+		}
+		return args[0], nil
+	}
+	switch command {
+	case "connections":
+		connections, err := rpc.GetConnections()
+		if err != nil {
+			return err
+		}
+		t := table{header: []string{"peer_id", "address", "incoming", "private", "versions"}}
+		for _, c := range connections {
+			versions := make([]string, len(c.Versions))
+			for i, v := range c.Versions {
+				versions[i] = fmt.Sprintf("%s %d.%d", v.Name, v.Major, v.Minor)
+			}
+			t.rows = append(t.rows, []string{c.PeerID.String(), c.IDPoint.String(), strconv.FormatBool(c.Incoming), strconv.FormatBool(c.Private), strings.Join(versions, " ")})
+		}
+		return out.print(connections, t)
+	case "peer":
+		peerID, err := arg()
+		if err != nil {
+			return err
+		}
+		peer, err := rpc.GetPeer(tgo.PeerID(peerID))
+		if err != nil {
+			return err
+		}
+		return out.print(peer, fields(
+			"peer_id", peerID,
+			"state", peer.State,
+			"trusted", strconv.FormatBool(peer.Trusted),
+			"score", strconv.FormatInt(int64(peer.Score), 10),
+			"reachable_at", peer.ReachableAt.String(),
+			"total_sent", strconv.FormatInt(int64(peer.Stat.TotalSent), 10),
+			"total_recv", strconv.FormatInt(int64(peer.Stat.TotalRecv), 10),
+		))
+	case "clear-greylist":
+		if err := rpc.ClearGreylist(); err != nil {
+			return err
+		}
+		return out.ok()
+	case "log":
+		return networkLog(rpc, out)
+	}
+	action := strings.SplitN(command, "-", 2)
+	acl, ok := acls[action[0]]
+	if !ok || len(action) != 2 {
+		return fmt.Errorf("unknown network command %q", command)
+	}
+	target, err := arg()
+	if err != nil {
+		return err
+	}
+	switch action[1] {
+	case "peer":
+		peerID := tgo.PeerID(target)
+		if err := peerID.Validate(); err != nil {
+			return err
+		}
+		if err := rpc.SetPeerACL(peerID, acl); err != nil {
+			return err
+		}
+		return out.ok()
+	case "point":
+		point, err := tgo.ParsePoint(target)
+		if err != nil {
+			return err
+		}
+		if err := rpc.SetPointACL(point, acl); err != nil {
+			return err
+		}
+		return out.ok()
+	}
+	return fmt.Errorf("unknown network command %q", command)
+}
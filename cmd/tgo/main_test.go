@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		from     string
+		wait     bool
+		expected []string
+	}{
+		{[]string{"tz1a", "1"}, "", false, []string{"tz1a", "1"}},
+		{[]string{"--from", "alice", "tz1a", "1"}, "alice", false, []string{"tz1a", "1"}},
+		{[]string{"tz1a", "--from", "alice", "1", "--wait"}, "alice", true, []string{"tz1a", "1"}},
+		{[]string{"tz1a", "1", "--from=alice"}, "alice", false, []string{"tz1a", "1"}},
+		{[]string{"--wait", "--", "tz1a", "--from"}, "", true, []string{"tz1a", "--from"}},
+	} {
+		flags := flag.NewFlagSet("transfer", flag.ContinueOnError)
+		from := flags.String("from", "", "")
+		wait := flags.Bool("wait", false, "")
+		rest := parseFlags(flags, test.args)
+		if *from != test.from || *wait != test.wait || !reflect.DeepEqual(rest, test.expected) {
+			t.Fatalf("%q: expected %q %v %q got %q %v %q", test.args, test.from, test.wait, test.expected, *from, *wait, rest)
+		}
+	}
+}
+
+func TestPositional(t *testing.T) {
+	for _, args := range [][]string{{"idtAZ3", "--node", "http://x"}, {"-json"}} {
+		if rest, err := positional(args); err == nil {
+			t.Fatalf("%q: expected an error got %q", args, rest)
+		}
+	}
+	for _, test := range []struct{ args, expected []string }{
+		{[]string{"idtAZ3"}, []string{"idtAZ3"}},
+		{[]string{"1", "-5", "int"}, []string{"1", "-5", "int"}},
+		{[]string{"--", "--node"}, []string{"--node"}},
+	} {
+		if rest, err := positional(test.args); err != nil || !reflect.DeepEqual(rest, test.expected) {
+			t.Fatalf("%q: expected %q got %q: %v", test.args, test.expected, rest, err)
+		}
+	}
+	if !parsesFlags("monitor", "mempool") || parsesFlags("monitor", "heads") || parsesFlags("network", "peer") {
+		t.Fatal("unexpected commands parsing their own flags")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tgo "github.com/postables/TGo"
+)
+
+// monitor runs tgo monitor heads|mempool|bootstrapped, printing every event as it comes
+// until interrupted
+func monitor(rpc *tgo.RPC, out *output, command string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// streams stay open for as long as they are followed
+	rpc.Client.Timeout = 0
+	switch command {
+	case "heads":
+		heads, errs := rpc.MonitorHeads(ctx, rpc.ChainAlias(ctx))
+		event := out.stream("level", "hash", "timestamp", "priority")
+		for head := range heads {
+			if err := event(head, strconv.FormatInt(head.Level, 10), head.Hash.String(), head.Timestamp.String(), strconv.FormatInt(head.Priority, 10)); err != nil {
+				return err
+			}
+		}
+		return <-errs
+	case "mempool":
+		flags := flag.NewFlagSet("mempool", flag.ExitOnError)
+		opts := tgo.MempoolMonitorOptions{}
+		flags.BoolVar(&opts.Applied, "applied", true, "stream the applied operations")
+		flags.BoolVar(&opts.Refused, "refused", false, "stream the refused operations")
+		flags.BoolVar(&opts.BranchRefused, "branch-refused", false, "stream the operations refused on the current branch")
+		flags.BoolVar(&opts.BranchDelayed, "branch-delayed", false, "stream the operations delayed on the current branch")
+		if len(parseFlags(flags, args)) != 0 {
+			return errors.New("monitor mempool takes no arguments")
+		}
+		ops, errs := rpc.MonitorMempoolOperations(ctx, rpc.ChainAlias(ctx), opts)
+		event := out.stream("hash", "kinds", "refused")
+		for op := range ops {
+			kinds := make([]string, len(op.Contents))
+			for i, c := range op.Contents {
+				kinds[i] = c.Kind
+			}
+			if err := event(op, op.Hash.String(), strings.Join(kinds, " "), strconv.FormatBool(len(op.Error) > 0)); err != nil {
+				return err
+			}
+		}
+		return <-errs
+	case "bootstrapped":
+		// the node ends the stream once it is bootstrapped
+		status, err := rpc.MonitorBootstrapped(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		return out.stream("block", "timestamp")(status, status.Block.String(), status.Timestamp.String())
+	}
+	return fmt.Errorf("unknown monitor command %q", command)
+}
+
+// networkLog runs tgo network log, printing the events of the network log until interrupted
+func networkLog(rpc *tgo.RPC, out *output) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	rpc.Client.Timeout = 0
+	events, errs := rpc.MonitorNetworkLog(ctx)
+	event := out.stream("event", "peer_id")
+	for e := range events {
+		if err := event(e, e.Event, e.PeerID.String()); err != nil {
+			return err
+		}
+	}
+	return <-errs
+}
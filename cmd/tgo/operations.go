@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tgo "github.com/postables/TGo"
+)
+
+// sender sends the operations of the transfer and delegate commands
+type sender struct {
+	rpc *tgo.RPC
+	out *output
+
+	flags         *flag.FlagSet
+	from          string
+	keystore      string
+	signerURL     string
+	dryRun        bool
+	wait          bool
+	confirmations int64
+}
+
+// newSender returns a sender parsing the flags of command
+func newSender(rpc *tgo.RPC, out *output, command string) *sender {
+	s := &sender{rpc: rpc, out: out, flags: flag.NewFlagSet(command, flag.ExitOnError)}
+	home, _ := os.UserHomeDir()
+	s.flags.StringVar(&s.from, "from", "", "alias of the key in the keystore, or its address with --signer")
+	s.flags.StringVar(&s.keystore, "keystore", filepath.Join(home, ".tezos-client"), "base directory of tezos-client holding the keys")
+	s.flags.StringVar(&s.signerURL, "signer", "", "URL of a remote signer holding the key of --from")
+	s.flags.BoolVar(&s.dryRun, "dry-run", false, "simulate the operation without injecting it")
+	s.flags.BoolVar(&s.wait, "wait", false, "wait for the operation to be included")
+	s.flags.Int64Var(&s.confirmations, "confirmations", 1, "blocks confirming the operation --wait waits for, its own block included")
+	return s
+}
+
+// sent is the JSON of an operation sent by transfer or delegate, the block and level are set
+// once --wait saw it included
+type sent struct {
+	Operation     string `json:"operation"`
+	Block         string `json:"block,omitempty"`
+	Level         int64  `json:"level,omitempty"`
+	Confirmations int64  `json:"confirmations,omitempty"`
+}
+
+// dryRun is the JSON of an operation simulated with --dry-run
+type dryRun struct {
+	Operation tgo.Operation         `json:"operation"`
+	Results   []tgo.AppliedContents `json:"results"`
+}
+
+// transfer runs tgo transfer [flags] <destination> <amount in tez>
+func (s *sender) transfer(args []string) error {
+	args = parseFlags(s.flags, args)
+	if len(args) != 2 {
+		return errors.New("transfer takes a destination and an amount in tez")
+	}
+	destination := tgo.Address(args[0])
+	if err := destination.Validate(); err != nil {
+		return err
+	}
+	amount, err := parseTez(args[1])
+	if err != nil {
+		return err
+	}
+	return s.send(func(b *tgo.OperationBuilder) {
+		b.AddTransaction(destination.String(), amount)
+	})
+}
+
+// delegate runs tgo delegate [flags] <delegate>, none withdrawing the delegation
+func (s *sender) delegate(args []string) error {
+	args = parseFlags(s.flags, args)
+	if len(args) != 1 {
+		return errors.New("delegate takes a delegate, or none to withdraw the delegation")
+	}
+	delegate := tgo.Address(args[0])
+	if delegate == "none" {
+		delegate = ""
+	} else if err := delegate.Validate(); err != nil {
+		return err
+	}
+	return s.send(func(b *tgo.OperationBuilder) {
+		b.AddDelegation(delegate.String())
+	})
+}
+
+// send builds the operation with build and simulates or sends it
+func (s *sender) send(build func(b *tgo.OperationBuilder)) error {
+	ctx := context.Background()
+	signer, err := s.signer(ctx)
+	if err != nil {
+		return err
+	}
+	builder := s.rpc.NewOperationBuilder(signer)
+	build(builder)
+	if s.dryRun {
+		op, results, err := builder.DryRun(ctx)
+		if err != nil {
+			return err
+		}
+		t := table{header: []string{"kind", "status", "fee", "gas_limit", "storage_limit"}}
+		for i, c := range op.Contents {
+			fee, _ := strconv.ParseInt(c.Fee, 10, 64)
+			t.rows = append(t.rows, []string{c.Kind, results[i].Metadata.OperationResult.Status, s.out.amount(fee), c.GasLimit, c.StorageLimit})
+		}
+		return s.out.print(dryRun{Operation: op, Results: results}, t)
+	}
+	hash, err := builder.Send(ctx)
+	if err != nil {
+		return err
+	}
+	result := sent{Operation: hash}
+	if s.wait {
+		inclusion, err := s.rpc.WaitForOperation(ctx, tgo.OperationHash(hash), s.confirmations)
+		if err != nil {
+			return err
+		}
+		result.Block, result.Level, result.Confirmations = inclusion.BlockHash.String(), inclusion.Level, inclusion.Confirmations
+	}
+	t := fields("operation", result.Operation)
+	if s.wait {
+		t.rows = append(t.rows, []string{"block", result.Block}, []string{"level", strconv.FormatInt(result.Level, 10)})
+	}
+	return s.out.print(result, t)
+}
+
+// signer returns the signer of --from, from the keystore or the remote signer
+func (s *sender) signer(ctx context.Context) (tgo.Signer, error) {
+	if s.from == "" {
+		return nil, errors.New("--from is required")
+	}
+	if s.signerURL != "" {
+		return tgo.NewRemoteSigner(ctx, s.signerURL, s.from)
+	}
+	keystore, err := tgo.OpenKeystore(s.keystore)
+	if err != nil {
+		return nil, err
+	}
+	return keystore.Signer(ctx, s.from)
+}
+
+// parseTez parses an amount of tez with up to 6 decimals and returns it in mutez
+func parseTez(s string) (int64, error) {
+	units, decimals := s, ""
+	if dot := strings.Index(s, "."); dot >= 0 {
+		units, decimals = s[:dot], s[dot+1:]
+	}
+	if units+decimals == "" || len(decimals) > 6 || strings.HasPrefix(units, "-") || strings.HasPrefix(units, "+") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	mutez, err := strconv.ParseInt(units+decimals+strings.Repeat("0", 6-len(decimals)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return mutez, nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestParseTez(t *testing.T) {
+	for s, expected := range map[string]int64{
+		"1":        1000000,
+		"0.5":      500000,
+		".5":       500000,
+		"12.":      12000000,
+		"1.000001": 1000001,
+		"0":        0,
+	} {
+		if mutez, err := parseTez(s); err != nil || mutez != expected {
+			t.Fatalf("%q: expected %d got %d: %v", s, expected, mutez, err)
+		}
+	}
+	for _, s := range []string{"", ".", "-1", "+1", "1.0000001", "1,5", "1e6", "tez"} {
+		if mutez, err := parseTez(s); err == nil {
+			t.Fatalf("%q: expected an error got %d", s, mutez)
+		}
+	}
+}
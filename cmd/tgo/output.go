@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// output prints the results of the commands in the format of --output: aligned columns for
+// table, CSV with a header row for csv and indented JSON for json. Streams are written one
+// line per event, as NDJSON for json.
+type output struct {
+	format string
+	w      io.Writer
+}
+
+// newOutput returns an output writing format to the standard output
+func newOutput(format string) (*output, error) {
+	switch format {
+	case "table", "csv", "json":
+		return &output{format: format, w: os.Stdout}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q, expected table, csv or json", format)
+}
+
+// table is the tabular form of a result, rows of the columns named by header
+type table struct {
+	header []string
+	rows   [][]string
+	// pairs tables list field and value pairs, printed without their header as a table
+	pairs bool
+}
+
+// fields returns a table of the field and value pairs, printed without header as a table
+func fields(pairs ...string) table {
+	t := table{header: []string{"field", "value"}, pairs: true}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		t.rows = append(t.rows, []string{pairs[i], pairs[i+1]})
+	}
+	return t
+}
+
+// print prints v as JSON, or t as a table or CSV
+func (o *output) print(v interface{}, t table) error {
+	switch o.format {
+	case "json":
+		encoder := json.NewEncoder(o.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "csv":
+		w := csv.NewWriter(o.w)
+		w.Write(t.header)
+		w.WriteAll(t.rows)
+		return w.Error()
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	if !t.pairs {
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.header, "\t")))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// ok prints the success of a command without result, as {"ok": true} in JSON
+func (o *output) ok() error {
+	return o.print(struct {
+		OK bool `json:"ok"`
+	}{true}, fields("ok", "true"))
+}
+
+// stream returns a function printing the events of a stream as they come, each as a line of
+// JSON or as a row of the columns named by header
+func (o *output) stream(header ...string) func(v interface{}, row ...string) error {
+	switch o.format {
+	case "json":
+		encoder := json.NewEncoder(o.w)
+		return func(v interface{}, _ ...string) error {
+			return encoder.Encode(v)
+		}
+	case "csv":
+		w := csv.NewWriter(o.w)
+		w.Write(header)
+		w.Flush()
+		return func(_ interface{}, row ...string) error {
+			w.Write(row)
+			w.Flush()
+			return w.Error()
+		}
+	}
+	fmt.Fprintln(o.w, strings.ToUpper(strings.Join(header, "  ")))
+	return func(_ interface{}, row ...string) error {
+		_, err := fmt.Fprintln(o.w, strings.Join(row, "  "))
+		return err
+	}
+}
+
+// amount formats mutez in tez as a table, and as a plain number of mutez in CSV
+func (o *output) amount(mutez int64) string {
+	if o.format == "table" {
+		return tez(mutez)
+	}
+	return strconv.FormatInt(mutez, 10)
+}
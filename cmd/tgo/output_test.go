@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOutputFormats(t *testing.T) {
+	result := struct {
+		Level int64  `json:"level"`
+		Hash  string `json:"hash"`
+	}{12, "BLhash"}
+	for format, expected := range map[string]string{
+		"table": "LEVEL  HASH\n12     BLhash\n",
+		"csv":   "level,hash\n12,BLhash\n",
+		"json":  "{\n  \"level\": 12,\n  \"hash\": \"BLhash\"\n}\n",
+	} {
+		out, err := newOutput(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		out.w = &b
+		if err := out.print(result, table{header: []string{"level", "hash"}, rows: [][]string{{"12", "BLhash"}}}); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Fatalf("%s: expected %q got %q", format, expected, b.String())
+		}
+	}
+	if _, err := newOutput("yaml"); err == nil {
+		t.Fatal("expected an unknown format error")
+	}
+}
+
+func TestOutputPairs(t *testing.T) {
+	out, _ := newOutput("table")
+	var b bytes.Buffer
+	out.w = &b
+	if err := out.print(nil, fields("state", "running", "score", "12")); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "state  running\nscore  12\n"; b.String() != expected {
+		t.Fatalf("expected %q got %q", expected, b.String())
+	}
+}
+
+func TestOutputOK(t *testing.T) {
+	for format, expected := range map[string]string{
+		"table": "ok  true\n",
+		"csv":   "field,value\nok,true\n",
+		"json":  "{\n  \"ok\": true\n}\n",
+	} {
+		out, _ := newOutput(format)
+		var b bytes.Buffer
+		out.w = &b
+		if err := out.ok(); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Fatalf("%s: expected %q got %q", format, expected, b.String())
+		}
+	}
+}
+
+func TestOutputStream(t *testing.T) {
+	for format, expected := range map[string]string{
+		"table": "EVENT  PEER_ID\nconnection_established  idtAZ3\ndisconnection  idtAZ3\n",
+		"csv":   "event,peer_id\nconnection_established,idtAZ3\ndisconnection,idtAZ3\n",
+		"json":  "{\"event\":\"connection_established\"}\n{\"event\":\"disconnection\"}\n",
+	} {
+		out, _ := newOutput(format)
+		var b bytes.Buffer
+		out.w = &b
+		event := out.stream("event", "peer_id")
+		for _, e := range []string{"connection_established", "disconnection"} {
+			if err := event(map[string]string{"event": e}, e, "idtAZ3"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if b.String() != expected {
+			t.Fatalf("%s: expected %q got %q", format, expected, b.String())
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	table, _ := newOutput("table")
+	csv, _ := newOutput("csv")
+	if table.amount(1500000) != tez(1500000) || csv.amount(1500000) != "1500000" {
+		t.Fatalf("unexpected amounts %q and %q", table.amount(1500000), csv.amount(1500000))
+	}
+}
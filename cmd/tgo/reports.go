@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/rewards"
+)
+
+// reporter runs the rights and rewards commands of a delegate for a cycle
+type reporter struct {
+	rpc   *tgo.RPC
+	block tgo.BlockID
+	out   *output
+
+	flags       *flag.FlagSet
+	delegate    string
+	cycle       int64
+	maxPriority int64
+}
+
+// newReporter returns a reporter parsing the flags of command
+func newReporter(rpc *tgo.RPC, block tgo.BlockID, out *output, command string) *reporter {
+	r := &reporter{rpc: rpc, block: block, out: out, flags: flag.NewFlagSet(command, flag.ExitOnError)}
+	r.flags.StringVar(&r.delegate, "delegate", "", "address of the delegate")
+	r.flags.Int64Var(&r.cycle, "cycle", -1, "cycle of the report")
+	r.flags.Int64Var(&r.maxPriority, "max-priority", 0, "lowest baking priority listed by rights, the node's default when 0")
+	return r
+}
+
+// parse parses the flags of the command and checks the delegate and cycle
+func (r *reporter) parse(args []string) error {
+	if len(parseFlags(r.flags, args)) != 0 {
+		return fmt.Errorf("%s takes no arguments", r.flags.Name())
+	}
+	if err := tgo.Address(r.delegate).Validate(); err != nil {
+		return fmt.Errorf("--delegate: %w", err)
+	}
+	if r.cycle < 0 {
+		return errors.New("--cycle is required")
+	}
+	return nil
+}
+
+// delegateRights is the JSON of the rights command
+type delegateRights struct {
+	Baking    []tgo.BakingRight    `json:"baking"`
+	Endorsing []tgo.EndorsingRight `json:"endorsing"`
+}
+
+// rights runs tgo rights --delegate <address> --cycle <cycle>, listing the baking and
+// endorsing rights of the delegate in the cycle
+func (r *reporter) rights(args []string) error {
+	if err := r.parse(args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	query := tgo.RightsQuery{Delegates: []string{r.delegate}, Cycles: []int64{r.cycle}, MaxPriority: r.maxPriority}
+	baking, err := r.rpc.GetBakingRights(ctx, r.block, query)
+	if err != nil {
+		return err
+	}
+	endorsing, err := r.rpc.GetEndorsingRights(ctx, r.block, query)
+	if err != nil {
+		return err
+	}
+	t := table{header: []string{"right", "level", "priority", "slots", "estimated_time"}}
+	for _, right := range baking {
+		t.rows = append(t.rows, []string{"baking", strconv.FormatInt(right.Level, 10), strconv.FormatInt(right.Priority, 10), "", estimatedTime(right.EstimatedTime)})
+	}
+	for _, right := range endorsing {
+		slots := make([]string, len(right.Slots))
+		for i, slot := range right.Slots {
+			slots[i] = strconv.FormatInt(slot, 10)
+		}
+		t.rows = append(t.rows, []string{"endorsing", strconv.FormatInt(right.Level, 10), "", strings.Join(slots, " "), estimatedTime(right.EstimatedTime)})
+	}
+	return r.out.print(delegateRights{Baking: baking, Endorsing: endorsing}, t)
+}
+
+// rewards runs tgo rewards --delegate <address> --cycle <cycle>, summarizing what the
+// delegate earned in the cycle
+func (r *reporter) rewards(args []string) error {
+	if err := r.parse(args); err != nil {
+		return err
+	}
+	summaries, err := rewards.Summarize(context.Background(), r.rpc, r.delegate, r.cycle)
+	if err != nil {
+		return err
+	}
+	if r.out.format == "csv" {
+		return rewards.WriteCSV(r.out.w, summaries)
+	}
+	s := summaries[0]
+	return r.out.print(s, fields(
+		"delegate", s.Delegate,
+		"cycle", strconv.FormatInt(s.Cycle, 10),
+		"blocks_baked", strconv.Itoa(s.BlocksBaked),
+		"endorsed_slots", fmt.Sprintf("%d/%d", s.EndorsedSlots, s.EndorsingSlots),
+		"baking_rewards", r.out.amount(s.BakingRewards),
+		"endorsement_rewards", r.out.amount(s.EndorsementRewards),
+		"rewards", r.out.amount(s.Rewards),
+		"fees", r.out.amount(s.Fees),
+		"deposits", r.out.amount(s.DepositsFrozen),
+		"unfrozen", strconv.FormatBool(s.Unfrozen),
+	))
+}
+
+// estimatedTime formats the estimated time of a right, empty for past levels
+func estimatedTime(t *tgo.Timestamp) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.String()
+}
@@ -0,0 +1,61 @@
+package tgo
+
+import (
+	"context"
+	"sync"
+)
+
+// flight is a GET request shared by the callers asking for the same path while it runs
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+	// aborted is set when the request failed because the context of the caller running it
+	// was cancelled, the other callers then run their own request
+	aborted bool
+}
+
+// flightGroup coalesces concurrent GET requests for the same path. Only the body is shared,
+// every caller decodes it on its own: callers of the same path may decode it into different
+// types, and they own the values they get back, which a shared decoded value would make them
+// modify under each other. Decoding costs little next to the round trip to the node saved.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: map[string]*flight{}}
+}
+
+// do returns the body fetched by fetch for path, joining the request already running for
+// path if any instead of starting another one
+func (g *flightGroup) do(ctx context.Context, path string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	for {
+		g.mu.Lock()
+		f, running := g.flights[path]
+		if !running {
+			f = &flight{done: make(chan struct{})}
+			g.flights[path] = f
+		}
+		g.mu.Unlock()
+
+		if !running {
+			f.body, f.err = fetch(ctx)
+			f.aborted = f.err != nil && ctx.Err() != nil
+			g.mu.Lock()
+			delete(g.flights, path)
+			g.mu.Unlock()
+			close(f.done)
+			return f.body, f.err
+		}
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !f.aborted {
+			return f.body, f.err
+		}
+	}
+}
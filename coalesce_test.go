@@ -0,0 +1,76 @@
+package tgo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestCoalesce(t *testing.T) {
+	release := make(chan struct{})
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/constants": func([]byte) interface{} {
+			<-release
+			return map[string]interface{}{"blocks_per_cycle": 4096}
+		},
+	})
+	client.Coalesce = true
+	requests := func() int {
+		node.mu.Lock()
+		defer node.mu.Unlock()
+		return len(node.queries["GET /chains/main/blocks/head/context/constants"])
+	}
+
+	var wg sync.WaitGroup
+	results := make([]tgo.Constants, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			constants, err := client.GetConstants(context.Background(), "head")
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = constants
+		}(i)
+	}
+	// let every caller join the request in flight
+	time.Sleep(time.Millisecond * 100)
+	close(release)
+	wg.Wait()
+	for _, c := range results {
+		if c.BlocksPerCycle != 4096 {
+			t.Fatalf("unexpected constants %+v", c)
+		}
+	}
+	if n := requests(); n != 1 {
+		t.Fatalf("expected a single request got %d", n)
+	}
+
+	// a caller whose context is cancelled does not fail the callers sharing its request
+	release = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := client.GetConstants(ctx, "head")
+		leaderDone <- err
+	}()
+	time.Sleep(time.Millisecond * 50)
+	followerDone := make(chan error)
+	go func() {
+		_, err := client.GetConstants(context.Background(), "head")
+		followerDone <- err
+	}()
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+	if err := <-leaderDone; err == nil {
+		t.Fatal("expected the cancelled caller to fail")
+	}
+	close(release)
+	if err := <-followerDone; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+package tgo
+
+import "context"
+
+// UserActivatedUpgrade is a protocol switch the node is configured to perform at a given level
+type UserActivatedUpgrade struct {
+	Level               int64        `json:"level"`
+	ReplacementProtocol ProtocolHash `json:"replacement_protocol"`
+}
+
+// UserActivatedProtocolOverride replaces a protocol by another as soon as it would activate
+type UserActivatedProtocolOverride struct {
+	ReplacedProtocol    ProtocolHash `json:"replaced_protocol"`
+	ReplacementProtocol ProtocolHash `json:"replacement_protocol"`
+}
+
+// GetUserActivatedUpgrades calls GET /config/network/user_activated_upgrades
+func (rpc *RPC) GetUserActivatedUpgrades(ctx context.Context) ([]UserActivatedUpgrade, error) {
+	upgrades := []UserActivatedUpgrade{}
+	err := rpc.get(ctx, "/config/network/user_activated_upgrades", &upgrades)
+	return upgrades, err
+}
+
+// GetUserActivatedProtocolOverrides calls GET /config/network/user_activated_protocol_overrides
+func (rpc *RPC) GetUserActivatedProtocolOverrides(ctx context.Context) ([]UserActivatedProtocolOverride, error) {
+	overrides := []UserActivatedProtocolOverride{}
+	err := rpc.get(ctx, "/config/network/user_activated_protocol_overrides", &overrides)
+	return overrides, err
+}
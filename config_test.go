@@ -0,0 +1,27 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserActivatedUpgrades(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /config/network/user_activated_upgrades":           rawBody(`[{"level":28082177,"replacement_protocol":"PsYLVpVvgbLhAhoqAkMFUo6gudkJ9weNXhUYCiLDzcUpFpkk8Wt"}]`),
+		"GET /config/network/user_activated_protocol_overrides": rawBody(`[{"replaced_protocol":"PsBABY5HQTSkA4297zNHfsZNKtxULfL18y95qb3m53QJiXGmrbU","replacement_protocol":"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"}]`),
+	})
+	upgrades, err := client.GetUserActivatedUpgrades(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upgrades) != 1 || upgrades[0].Level != 28082177 {
+		t.Fatalf("unexpected upgrades %+v", upgrades)
+	}
+	overrides, err := client.GetUserActivatedProtocolOverrides(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 1 || overrides[0].ReplacementProtocol != "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS" {
+		t.Fatalf("unexpected overrides %+v", overrides)
+	}
+}
@@ -0,0 +1,81 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/describegen"
+)
+
+// conformanceEndpoints are the endpoints whose documented fields the hand written types
+// must decode, with the fields decoded some other way
+var conformanceEndpoints = []struct {
+	path   string
+	value  interface{}
+	ignore []string
+}{
+	{"chains/main/blocks/head/header", tgo.BlockHeader{}, nil},
+	// level_info and voting_period_info are read into Level by BlockMetadata.UnmarshalJSON
+	{"chains/main/blocks/head/metadata", tgo.BlockMetadata{}, []string{"level_info", "voting_period_info"}},
+	{"chains/main/blocks/head/context/constants", tgo.Constants{}, nil},
+	{"chains/main/blocks/head/helpers/baking_rights", []tgo.BakingRight{}, nil},
+	{"chains/main/blocks/head/helpers/endorsing_rights", []tgo.EndorsingRight{}, nil},
+	{"chains/main/blocks/head/votes/ballots", tgo.Ballots{}, nil},
+	{"chains/main/checkpoint", tgo.Checkpoint{}, nil},
+	{"chains/main/mempool/pending_operations", tgo.PendingOperations{}, nil},
+	{"network/connections", []tgo.ConnectionsResponse{}, nil},
+}
+
+// TestConformance checks the types of the wrapped endpoints against the schemas described
+// by the node at TGO_CONFORMANCE_URL, it is skipped when the variable is not set
+func TestConformance(t *testing.T) {
+	url := os.Getenv("TGO_CONFORMANCE_URL")
+	if url == "" {
+		t.Skip("TGO_CONFORMANCE_URL is not set")
+	}
+	client := tgo.GenerateClient(url, time.Minute)
+	for _, endpoint := range conformanceEndpoints {
+		endpoint := endpoint
+		t.Run(endpoint.path, func(t *testing.T) {
+			description, err := client.Describe(context.Background(), endpoint.path, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if description.Static == nil || description.Static.GetService == nil || description.Static.GetService.Output == nil {
+				t.Fatalf("no GET service described at %s", endpoint.path)
+			}
+			typ := reflect.TypeOf(endpoint.value)
+			missing, err := describegen.MissingFields(description.Static.GetService.Output.JSONSchema, typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := conformanceDiff(typ, endpoint.path, missing, endpoint.ignore); diff != "" {
+				t.Errorf("fields documented by the node are not decoded:\n%s", diff)
+			}
+		})
+	}
+}
+
+// conformanceDiff lists the missing fields not ignored as lines added to typ by the schema of path
+func conformanceDiff(typ reflect.Type, path string, missing, ignore []string) string {
+	var b strings.Builder
+	for _, field := range missing {
+		ignored := false
+		for _, i := range ignore {
+			ignored = ignored || field == i || strings.HasPrefix(field, i+".")
+		}
+		if !ignored {
+			fmt.Fprintf(&b, "+ %s\n", field)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("--- %s\n+++ GET /%s\n%s", typ, path, b.String())
+}
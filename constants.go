@@ -0,0 +1,100 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Constants holds the protocol constants from `GET /chains/<chain>/blocks/<block_id>/context/constants`
+type Constants struct {
+	ProofOfWorkNonceSize         int64     `json:"proof_of_work_nonce_size"`
+	ProofOfWorkThreshold         int64     `json:"proof_of_work_threshold,string"`
+	NonceLength                  int64     `json:"nonce_length"`
+	MaxRevelationsPerBlock       int64     `json:"max_revelations_per_block"`
+	MaxOperationDataLength       int64     `json:"max_operation_data_length"`
+	MaxProposalsPerDelegate      int64     `json:"max_proposals_per_delegate"`
+	PreservedCycles              int64     `json:"preserved_cycles"`
+	BlocksPerCycle               int64     `json:"blocks_per_cycle"`
+	BlocksPerCommitment          int64     `json:"blocks_per_commitment"`
+	BlocksPerRollSnapshot        int64     `json:"blocks_per_roll_snapshot"`
+	BlocksPerVotingPeriod        int64     `json:"blocks_per_voting_period"`
+	TimeBetweenBlocks            []string  `json:"time_between_blocks"`
+	EndorsersPerBlock            int64     `json:"endorsers_per_block"`
+	HardGasLimitPerOperation     int64     `json:"hard_gas_limit_per_operation,string"`
+	HardGasLimitPerBlock         int64     `json:"hard_gas_limit_per_block,string"`
+	TokensPerRoll                int64     `json:"tokens_per_roll,string"`
+	SeedNonceRevelationTip       int64     `json:"seed_nonce_revelation_tip,string"`
+	OriginationSize              int64     `json:"origination_size"`
+	BlockSecurityDeposit         int64     `json:"block_security_deposit,string"`
+	EndorsementSecurityDeposit   int64     `json:"endorsement_security_deposit,string"`
+	BlockReward                  MutezList `json:"block_reward,omitempty"`
+	BakingRewardPerEndorsement   MutezList `json:"baking_reward_per_endorsement,omitempty"`
+	EndorsementReward            MutezList `json:"endorsement_reward"`
+	CostPerByte                  int64     `json:"cost_per_byte,string"`
+	HardStorageLimitPerOperation int64     `json:"hard_storage_limit_per_operation,string"`
+	MichelsonMaximumTypeSize     int64     `json:"michelson_maximum_type_size"`
+	// TestChainDuration is dropped by Florence
+	TestChainDuration int64 `json:"test_chain_duration,string,omitempty"`
+	// QuorumMin, QuorumMax and MinProposalQuorum are the bounds of the adaptive quorum
+	// introduced by Babylon, in hundredths of percent
+	QuorumMin         int64 `json:"quorum_min,omitempty"`
+	QuorumMax         int64 `json:"quorum_max,omitempty"`
+	MinProposalQuorum int64 `json:"min_proposal_quorum,omitempty"`
+	// InitialEndorsers and DelayPerMissingEndorsement set the minimal block delay introduced
+	// by Babylon
+	InitialEndorsers           int64 `json:"initial_endorsers,omitempty"`
+	DelayPerMissingEndorsement int64 `json:"delay_per_missing_endorsement,string,omitempty"`
+}
+
+// MutezList decodes reward constants given either as a single mutez string or,
+// since babylon, as a list of mutez strings indexed by priority
+type MutezList []int64
+
+// UnmarshalJSON accepts "123" as well as ["123", "456"]
+func (m *MutezList) UnmarshalJSON(b []byte) error {
+	values := []string{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		var single string
+		if err := json.Unmarshal(b, &single); err != nil {
+			return fmt.Errorf("expected mutez or list of mutez got %s", b)
+		}
+		values = []string{single}
+	}
+	list := make(MutezList, len(values))
+	for i, v := range values {
+		mutez, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		list[i] = mutez
+	}
+	*m = list
+	return nil
+}
+
+// MarshalJSON encodes the list as mutez strings
+func (m MutezList) MarshalJSON() ([]byte, error) {
+	values := make([]string, len(m))
+	for i, v := range m {
+		values[i] = strconv.FormatInt(v, 10)
+	}
+	return json.Marshal(values)
+}
+
+// GetConstants calls GET /chains/<chain>/blocks/<block_id>/context/constants
+func (rpc *RPC) GetConstants(ctx context.Context, blockID BlockID) (Constants, error) {
+	cacheable := rpc.Cache != nil && isBlockHash(blockID)
+	if cacheable {
+		if v, ok := rpc.Cache.Get("constants/" + string(blockID)); ok {
+			return v.(Constants), nil
+		}
+	}
+	constants := Constants{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/constants", rpc.ChainAlias(ctx), blockID), &constants)
+	if err == nil && cacheable {
+		rpc.Cache.Add("constants/"+string(blockID), constants)
+	}
+	return constants, err
+}
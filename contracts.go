@@ -0,0 +1,115 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// GetContracts calls GET /chains/<chain>/blocks/<block_id>/context/contracts, the list can be
+// very long on mainnet, see ForEachContract to avoid holding it in memory
+func (rpc *RPC) GetContracts(ctx context.Context, blockID BlockID) ([]string, error) {
+	contracts := []string{}
+	err := rpc.ForEachContract(ctx, blockID, func(address string) error {
+		contracts = append(contracts, address)
+		return nil
+	})
+	return contracts, err
+}
+
+// ForEachContract calls GET /chains/<chain>/blocks/<block_id>/context/contracts and calls fn with
+// every address as it is decoded, stopping at the first error returned by fn
+func (rpc *RPC) ForEachContract(ctx context.Context, blockID BlockID, fn func(address string) error) error {
+	return rpc.getArray(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts", rpc.ChainAlias(ctx), blockID), func(decoder *json.Decoder) error {
+		var address string
+		if err := decoder.Decode(&address); err != nil {
+			return err
+		}
+		return fn(address)
+	})
+}
+
+// GetBalance calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<address>/balance and returns mutez
+func (rpc *RPC) GetBalance(ctx context.Context, blockID BlockID, address string) (int64, error) {
+	var balance string
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s/balance", rpc.ChainAlias(ctx), blockID, address), &balance)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(balance, 10, 64)
+}
+
+// Contract holds the response from `GET /chains/<chain>/blocks/<block_id>/context/contracts/<address>`,
+// Script is only set for originated contracts and Counter for implicit accounts
+type Contract struct {
+	Balance  int64   `json:"balance,string"`
+	Delegate string  `json:"delegate,omitempty"`
+	Script   *Script `json:"script,omitempty"`
+	Counter  int64   `json:"counter,string,omitempty"`
+}
+
+// GetContract calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<address>
+func (rpc *RPC) GetContract(ctx context.Context, blockID BlockID, address string) (Contract, error) {
+	contract := Contract{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s", rpc.ChainAlias(ctx), blockID, address), &contract)
+	return contract, err
+}
+
+// GetStorage calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<contract>/storage
+func (rpc *RPC) GetStorage(ctx context.Context, blockID BlockID, contract string) (micheline.Node, error) {
+	storage := micheline.Node{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s/storage", rpc.ChainAlias(ctx), blockID, contract), &storage)
+	return storage, err
+}
+
+// GetScript calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<contract>/script
+func (rpc *RPC) GetScript(ctx context.Context, blockID BlockID, contract string) (*Script, error) {
+	script := &Script{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s/script", rpc.ChainAlias(ctx), blockID, contract), script)
+	if err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+// GetBigMapValue calls GET /chains/<chain>/blocks/<block_id>/context/big_maps/<big_map_id>/<key_hash>,
+// found is false when the key is not in the big map. See BigMapKeyHash to compute key hashes.
+func (rpc *RPC) GetBigMapValue(ctx context.Context, blockID BlockID, bigMapID int64, keyHash string) (value micheline.Node, found bool, err error) {
+	err = rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/big_maps/%d/%s", rpc.ChainAlias(ctx), blockID, bigMapID, keyHash), &value)
+	if errors.Is(err, ErrNotFound) {
+		return micheline.Node{}, false, nil
+	}
+	if err != nil {
+		return micheline.Node{}, false, err
+	}
+	return value, true, nil
+}
+
+// Entrypoints holds the response from `GET /chains/<chain>/blocks/<block_id>/context/contracts/<contract>/entrypoints`
+type Entrypoints struct {
+	Entrypoints map[string]micheline.Node `json:"entrypoints"`
+	// Unreachable lists the paths of or branches that no entrypoint leads to
+	Unreachable []json.RawMessage `json:"unreachable,omitempty"`
+}
+
+// GetContractEntrypoints calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<contract>/entrypoints
+func (rpc *RPC) GetContractEntrypoints(ctx context.Context, blockID BlockID, contract string) (*Entrypoints, error) {
+	entrypoints := &Entrypoints{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s/entrypoints", rpc.ChainAlias(ctx), blockID, contract), entrypoints)
+	if err != nil {
+		return nil, err
+	}
+	return entrypoints, nil
+}
+
+// GetContractEntrypoint calls GET /chains/<chain>/blocks/<block_id>/context/contracts/<contract>/entrypoints/<entrypoint>
+// and returns the parameter type of the entrypoint
+func (rpc *RPC) GetContractEntrypoint(ctx context.Context, blockID BlockID, contract, entrypoint string) (micheline.Node, error) {
+	typ := micheline.Node{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/contracts/%s/entrypoints/%s", rpc.ChainAlias(ctx), blockID, contract, entrypoint), &typ)
+	return typ, err
+}
@@ -0,0 +1,60 @@
+package tgo_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestForEachContract(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts": []string{"tz1a", "KT1b", "tz1c"},
+	})
+	contracts, err := client.GetContracts(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(contracts, []string{"tz1a", "KT1b", "tz1c"}) {
+		t.Fatalf("unexpected contracts %v", contracts)
+	}
+
+	stop := errors.New("stop")
+	seen := 0
+	err = client.ForEachContract(context.Background(), "head", func(address string) error {
+		seen++
+		if address == "KT1b" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || seen != 2 {
+		t.Fatalf("expected to stop at the second contract got %d, %v", seen, err)
+	}
+
+	node.route("GET /chains/main/blocks/head/context/contracts", map[string]interface{}{"not": "a list"})
+	if _, err := client.GetContracts(context.Background(), "head"); err == nil {
+		t.Fatal("expected error for a response other than an array")
+	}
+}
+
+func TestGetContract(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1a":         rawBody(`{"balance":"1500000","delegate":"tz1d","counter":"42"}`),
+		"GET /chains/main/blocks/head/context/contracts/KT1b":         rawBody(`{"balance":"0","script":{"code":[],"storage":{"int":"7"}}}`),
+		"GET /chains/main/blocks/head/context/contracts/KT1b/storage": rawBody(`{"int":"7"}`),
+	})
+	ctx := context.Background()
+	account, err := client.GetContract(ctx, "head", "tz1a")
+	if err != nil || account.Balance != 1500000 || account.Delegate != "tz1d" || account.Counter != 42 || account.Script != nil {
+		t.Fatalf("unexpected account %+v: %v", account, err)
+	}
+	contract, err := client.GetContract(ctx, "head", "KT1b")
+	if err != nil || contract.Script == nil || contract.Script.Storage.String() != "7" {
+		t.Fatalf("unexpected contract %+v: %v", contract, err)
+	}
+	storage, err := client.GetStorage(ctx, "head", "KT1b")
+	if err != nil || storage.String() != "7" {
+		t.Fatalf("unexpected storage %s: %v", storage, err)
+	}
+}
@@ -0,0 +1,122 @@
+package tgo
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// CounterManager allocates the counters of manager operations for concurrent senders. It
+// caches the counter of each source so operations sent concurrently from the same source get
+// sequential counters instead of all reading the same one from the chain.
+type CounterManager struct {
+	rpc *RPC
+
+	mu      sync.Mutex
+	sources map[string]*sourceCounter
+}
+
+// sourceCounter is the counter state of one source, its mutex serializes allocations
+type sourceCounter struct {
+	mu sync.Mutex
+	// known is unset until the counter is read from the chain, and again after a failure
+	known bool
+	// last is the last counter allocated, injected the highest counter successfully injected
+	last     int64
+	injected int64
+	// outstanding holds the last counter of the reservations neither confirmed nor released
+	outstanding map[int64]bool
+}
+
+// NewCounterManager returns a manager reading counters through rpc
+func NewCounterManager(rpc *RPC) *CounterManager {
+	return &CounterManager{rpc: rpc, sources: map[string]*sourceCounter{}}
+}
+
+func (m *CounterManager) source(address string) *sourceCounter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sources[address]
+	if !ok {
+		s = &sourceCounter{outstanding: map[int64]bool{}}
+		m.sources[address] = s
+	}
+	return s
+}
+
+// Reserve allocates n sequential counters to an operation of source and returns the first
+// one. The counter is read from the chain the first time and after a Reset, counters of
+// operations injected but not yet included, or reserved and not yet released, are never
+// given out again. The reservation must be settled with Confirm or Release.
+func (m *CounterManager) Reserve(ctx context.Context, source string, n int) (int64, error) {
+	s := m.source(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.known {
+		counter, err := m.rpc.GetCounter(ctx, source)
+		if err != nil {
+			return 0, err
+		}
+		if counter < s.injected {
+			counter = s.injected
+		}
+		for last := range s.outstanding {
+			if counter < last {
+				counter = last
+			}
+		}
+		s.last, s.known = counter, true
+	}
+	first := s.last + 1
+	s.last += int64(n)
+	s.outstanding[s.last] = true
+	return first, nil
+}
+
+// Confirm records that the operation of source ending with counter last was injected
+func (m *CounterManager) Confirm(source string, last int64) {
+	s := m.source(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.outstanding, last)
+	if last > s.injected {
+		s.injected = last
+	}
+}
+
+// Release gives back the reservation of source ending with counter last after its operation
+// failed and resets the cached counter, see Reset
+func (m *CounterManager) Release(source string, last int64) {
+	s := m.source(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.outstanding, last)
+	s.known = false
+}
+
+// Reset forgets the cached counter of source, the next reservation reconciles with the chain
+// so unused counters are reallocated and counters already consumed on chain are skipped,
+// without giving out the counters of reservations still outstanding
+func (m *CounterManager) Reset(source string) {
+	s := m.source(source)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.known = false
+}
+
+// settleCounters reports the outcome of sending op to the counter manager of rpc if any
+func (rpc *RPC) settleCounters(op Operation, err error) {
+	if rpc.Counters == nil || len(op.Contents) == 0 {
+		return
+	}
+	source := op.Contents[0].Source
+	last, parseErr := strconv.ParseInt(op.Contents[len(op.Contents)-1].Counter, 10, 64)
+	switch {
+	case parseErr != nil:
+		rpc.Counters.Reset(source)
+	case err != nil:
+		rpc.Counters.Release(source, last)
+	default:
+		rpc.Counters.Confirm(source, last)
+	}
+}
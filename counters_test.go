@@ -0,0 +1,100 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestCounterManager(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counterKey := "GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter"
+	node, client := newFakeNode(t, map[string]interface{}{
+		counterKey: "41",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": key.PublicKey(),
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 100),
+		"POST /injection/operation":                              "ooHash",
+	})
+	client.Counters = tgo.NewCounterManager(client)
+	forgedCounters := func() map[string]bool {
+		node.mu.Lock()
+		defer node.mu.Unlock()
+		counters := map[string]bool{}
+		for _, body := range node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"] {
+			op := tgo.Operation{}
+			if err := json.Unmarshal([]byte(body), &op); err != nil {
+				t.Fatal(err)
+			}
+			counters[op.Contents[0].Counter] = true
+		}
+		node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"] = nil
+		return counters
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ClearDelegate(context.Background(), key); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	counters := forgedCounters()
+	for _, c := range []string{"42", "43", "44", "45", "46"} {
+		if !counters[c] || len(counters) != 5 {
+			t.Fatalf("expected sequential counters got %v", counters)
+		}
+	}
+	node.mu.Lock()
+	reads := len(node.queries[counterKey])
+	node.mu.Unlock()
+	if reads != 1 {
+		t.Fatalf("expected the counter to be read once got %d reads", reads)
+	}
+
+	// a failed injection makes the next operation reconcile with the chain, skipping the
+	// counters of operations already injected
+	node.mu.Lock()
+	delete(node.routes, "POST /injection/operation")
+	node.mu.Unlock()
+	if _, err := client.ClearDelegate(context.Background(), key); err == nil {
+		t.Fatal("expected injection error")
+	}
+	node.route("POST /injection/operation", "ooHash")
+	if _, err := client.ClearDelegate(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if counters := forgedCounters(); !counters["47"] || len(counters) != 1 {
+		t.Fatalf("expected counter 47 to be reused got %v", counters)
+	}
+	node.route(counterKey, "50")
+	client.Counters.Reset("tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx")
+	if _, err := client.ClearDelegate(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if counters := forgedCounters(); !counters["51"] {
+		t.Fatalf("expected the chain counter to be used got %v", counters)
+	}
+
+	// a reset does not give out again the counters of reservations still outstanding
+	source := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	first, err := client.Counters.Reserve(context.Background(), source, 2)
+	if err != nil || first != 52 {
+		t.Fatalf("unexpected reservation %d: %v", first, err)
+	}
+	client.Counters.Reset(source)
+	if next, err := client.Counters.Reserve(context.Background(), source, 1); err != nil || next != 54 {
+		t.Fatalf("expected the outstanding counters to be skipped got %d: %v", next, err)
+	}
+}
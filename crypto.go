@@ -0,0 +1,94 @@
+package tgo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// base58 prefixes used by tezos to tag encoded keys, hashes and signatures
+var (
+	prefixTz1       = []byte{6, 161, 159}
+	prefixTz2       = []byte{6, 161, 161}
+	prefixTz3       = []byte{6, 161, 164}
+	prefixKT1       = []byte{2, 90, 121}
+	prefixEdpk      = []byte{13, 15, 37, 217}
+	prefixSppk      = []byte{3, 254, 226, 86}
+	prefixP2pk      = []byte{3, 178, 139, 127}
+	prefixEdsk      = []byte{43, 246, 78, 7}
+	prefixEdskSeed  = []byte{13, 15, 58, 7}
+	prefixEdsig     = []byte{9, 245, 205, 134, 18}
+	prefixSpsig     = []byte{13, 115, 101, 19, 63}
+	prefixP2sig     = []byte{54, 240, 44, 52}
+	prefixSig       = []byte{4, 130, 43}
+	prefixBlock     = []byte{1, 52}
+	prefixOperation = []byte{5, 116}
+	prefixChainID   = []byte{87, 82, 0}
+	prefixExpr      = []byte{13, 44, 64, 27}
+	prefixNonce     = []byte{69, 220, 169}
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// b58CheckEncode prefixes payload, appends a double sha256 checksum and base58 encodes it
+func b58CheckEncode(prefix, payload []byte) string {
+	data := append(append([]byte{}, prefix...), payload...)
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	data = append(data, second[:4]...)
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	out := []byte{}
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// b58CheckDecode decodes s, verifies its checksum and strips the expected prefix
+func b58CheckDecode(s string, prefix []byte) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range []byte(s) {
+		idx := bytes.IndexByte([]byte(base58Alphabet), c)
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+	data := n.Bytes()
+	for _, c := range []byte(s) {
+		if c != base58Alphabet[0] {
+			break
+		}
+		data = append([]byte{0}, data...)
+	}
+	if len(data) < 4+len(prefix) {
+		return nil, errors.New("base58 payload too short")
+	}
+	payload, checksum := data[:len(data)-4], data[len(data)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, errors.New("invalid base58 checksum")
+	}
+	if !bytes.HasPrefix(payload, prefix) {
+		return nil, fmt.Errorf("unexpected prefix for %s", s)
+	}
+	return payload[len(prefix):], nil
+}
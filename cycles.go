@@ -0,0 +1,154 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// CycleEra is a range of levels sharing the same number of blocks per cycle, a new era
+// starts whenever a protocol upgrade changes blocks_per_cycle
+type CycleEra struct {
+	FirstLevel     int64
+	FirstCycle     int64
+	BlocksPerCycle int64
+}
+
+// Cycles converts between levels and cycles across eras
+type Cycles struct {
+	eras []CycleEra
+}
+
+// NewCycles returns a converter for the given eras, the first era must start at cycle 0
+func NewCycles(eras ...CycleEra) (*Cycles, error) {
+	if len(eras) == 0 {
+		return nil, errors.New("at least one cycle era is required")
+	}
+	sorted := append([]CycleEra{}, eras...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FirstLevel < sorted[j].FirstLevel })
+	if sorted[0].FirstCycle != 0 {
+		return nil, errors.New("the first cycle era must start at cycle 0")
+	}
+	for _, era := range sorted {
+		if era.BlocksPerCycle <= 0 {
+			return nil, errors.New("blocks per cycle must be positive")
+		}
+	}
+	return &Cycles{eras: sorted}, nil
+}
+
+// Eras returns the eras known to the converter, oldest first
+func (c *Cycles) Eras() []CycleEra {
+	return append([]CycleEra{}, c.eras...)
+}
+
+// eraOfLevel returns the era containing level
+func (c *Cycles) eraOfLevel(level int64) CycleEra {
+	era := c.eras[0]
+	for _, e := range c.eras[1:] {
+		if level < e.FirstLevel {
+			break
+		}
+		era = e
+	}
+	return era
+}
+
+// eraOfCycle returns the era containing cycle
+func (c *Cycles) eraOfCycle(cycle int64) CycleEra {
+	era := c.eras[0]
+	for _, e := range c.eras[1:] {
+		if cycle < e.FirstCycle {
+			break
+		}
+		era = e
+	}
+	return era
+}
+
+// Cycle returns the cycle of level
+func (c *Cycles) Cycle(level int64) int64 {
+	era := c.eraOfLevel(level)
+	return era.FirstCycle + (level-era.FirstLevel)/era.BlocksPerCycle
+}
+
+// Position returns the position of level within its cycle, starting at 0
+func (c *Cycles) Position(level int64) int64 {
+	era := c.eraOfLevel(level)
+	return (level - era.FirstLevel) % era.BlocksPerCycle
+}
+
+// FirstLevel returns the first level of cycle
+func (c *Cycles) FirstLevel(cycle int64) int64 {
+	era := c.eraOfCycle(cycle)
+	return era.FirstLevel + (cycle-era.FirstCycle)*era.BlocksPerCycle
+}
+
+// LastLevel returns the last level of cycle
+func (c *Cycles) LastLevel(cycle int64) int64 {
+	era := c.eraOfCycle(cycle)
+	return c.FirstLevel(cycle) + era.BlocksPerCycle - 1
+}
+
+// GetCycles builds a level and cycle converter for the chain. The current era comes from the
+// constants at head, older eras are found by searching block metadata for the level where
+// the cycle arithmetic of the newer era stops holding.
+func (rpc *RPC) GetCycles(ctx context.Context) (*Cycles, error) {
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := rpc.GetBlockMetadata(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	eras := []CycleEra{}
+	anchor := metadata.CurrentLevel()
+	bpc := constants.BlocksPerCycle
+	for {
+		if bpc <= 0 {
+			return nil, errors.New("invalid blocks per cycle")
+		}
+		// the anchor cycle starts at this level, search the oldest cycle still aligned on bpc
+		anchorFirst := anchor.Level - anchor.CyclePosition
+		low, high := int64(0), anchor.Cycle
+		for low < high {
+			mid := (low + high) / 2
+			ok, err := rpc.cycleStartsAt(ctx, mid, anchorFirst-(anchor.Cycle-mid)*bpc)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				high = mid
+			} else {
+				low = mid + 1
+			}
+		}
+		era := CycleEra{FirstLevel: anchorFirst - (anchor.Cycle-low)*bpc, FirstCycle: low, BlocksPerCycle: bpc}
+		eras = append(eras, era)
+		if era.FirstCycle == 0 {
+			break
+		}
+		// the last block of the previous era tells its cycle length through its position
+		previous, err := rpc.GetBlockMetadata(ctx, LevelID(era.FirstLevel-1))
+		if err != nil {
+			return nil, err
+		}
+		anchor = previous.CurrentLevel()
+		bpc = anchor.CyclePosition + 1
+	}
+	return NewCycles(eras...)
+}
+
+// cycleStartsAt reports whether level is the first block of cycle
+func (rpc *RPC) cycleStartsAt(ctx context.Context, cycle, level int64) (bool, error) {
+	if level < 1 {
+		return false, nil
+	}
+	metadata, err := rpc.GetBlockMetadata(ctx, LevelID(level))
+	if err != nil {
+		return false, err
+	}
+	l := metadata.CurrentLevel()
+	return l.Cycle == cycle && l.CyclePosition == 0, nil
+}
@@ -0,0 +1,44 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestGetCycles(t *testing.T) {
+	// cycles 0 to 4 last 8 blocks, an upgrade doubled them from cycle 5 at level 41
+	level := func(l int64) tgo.BlockLevel {
+		if l < 41 {
+			return tgo.BlockLevel{Level: l, Cycle: (l - 1) / 8, CyclePosition: (l - 1) % 8}
+		}
+		return tgo.BlockLevel{Level: l, Cycle: 5 + (l-41)/16, CyclePosition: (l - 41) % 16}
+	}
+	routes := map[string]interface{}{
+		"GET /chains/main/blocks/head/context/constants": map[string]interface{}{"blocks_per_cycle": 16},
+		"GET /chains/main/blocks/head/metadata":          tgo.BlockMetadata{Protocol: "PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i", Level: level(100)},
+	}
+	for l := int64(1); l <= 100; l++ {
+		routes[fmt.Sprintf("GET /chains/main/blocks/%d/metadata", l)] = tgo.BlockMetadata{Level: level(l)}
+	}
+	_, client := newFakeNode(t, routes)
+	cycles, err := client.GetCycles(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tgo.CycleEra{{FirstLevel: 1, FirstCycle: 0, BlocksPerCycle: 8}, {FirstLevel: 41, FirstCycle: 5, BlocksPerCycle: 16}}
+	if !reflect.DeepEqual(cycles.Eras(), expected) {
+		t.Fatalf("unexpected eras %+v", cycles.Eras())
+	}
+	for l := int64(1); l <= 100; l++ {
+		if cycles.Cycle(l) != level(l).Cycle || cycles.Position(l) != level(l).CyclePosition {
+			t.Fatalf("unexpected cycle for level %d: %d %d", l, cycles.Cycle(l), cycles.Position(l))
+		}
+	}
+	if cycles.FirstLevel(4) != 33 || cycles.LastLevel(4) != 40 || cycles.FirstLevel(6) != 57 || cycles.LastLevel(6) != 72 {
+		t.Fatalf("unexpected cycle bounds")
+	}
+}
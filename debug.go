@@ -0,0 +1,137 @@
+package tgo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+// DebugTransport is an http.RoundTripper writing every request and response to Out, to
+// inspect what the node actually returns when it cannot be decoded. Gzip responses are
+// decompressed so their bodies are readable.
+type DebugTransport struct {
+	// Base performs the requests, http.DefaultTransport when nil
+	Base http.RoundTripper
+	Out  io.Writer
+	// MaxBody truncates the bodies written to this many bytes, bodies are written in full
+	// when 0. Streams are written once MaxBody bytes are read or when they are closed.
+	MaxBody int
+
+	mu sync.Mutex
+}
+
+// RoundTrip performs req through Base and writes the request line, headers and bodies to Out
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	label := req.Method + " " + req.URL.String()
+	dump, err := httputil.DumpRequestOut(req, false)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			body.Close()
+			dump = append(dump, t.truncate(b)...)
+		}
+	}
+	t.write(fmt.Sprintf(">>> %s\n%s\n", label, strings.TrimRight(string(dump), "\r\n")))
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		t.write(fmt.Sprintf("!!! %s: %s\n", label, err))
+		return nil, err
+	}
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{gz, resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	dump, err = httputil.DumpResponse(resp, false)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	t.write(fmt.Sprintf("<<< %s\n%s\n", label, strings.TrimRight(string(dump), "\r\n")))
+	resp.Body = &debugBody{ReadCloser: resp.Body, t: t, label: label}
+	return resp, nil
+}
+
+// truncate returns b cut to MaxBody bytes
+func (t *DebugTransport) truncate(b []byte) []byte {
+	if t.MaxBody > 0 && len(b) > t.MaxBody {
+		return append(b[:t.MaxBody:t.MaxBody], "... (truncated)"...)
+	}
+	return b
+}
+
+func (t *DebugTransport) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.Out, s)
+}
+
+// debugBody keeps the start of a response body as it is read and writes it once the body
+// ends, is closed or MaxBody bytes were read
+type debugBody struct {
+	io.ReadCloser
+	t       *DebugTransport
+	label   string
+	buf     bytes.Buffer
+	written bool
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.written {
+		b.buf.Write(p[:n])
+		if err != nil || (b.t.MaxBody > 0 && b.buf.Len() > b.t.MaxBody) {
+			b.flush()
+		}
+	}
+	return n, err
+}
+
+func (b *debugBody) Close() error {
+	b.flush()
+	return b.ReadCloser.Close()
+}
+
+func (b *debugBody) flush() {
+	if b.written {
+		return
+	}
+	b.written = true
+	b.t.write(fmt.Sprintf("<<< %s body\n%s\n", b.label, b.t.truncate(b.buf.Bytes())))
+	b.buf = bytes.Buffer{}
+}
+
+// SetDebug makes rpc write every request and response to out, bodies truncated to maxBody
+// bytes unless 0. The HTTP client is copied so clients sharing it are not affected.
+func (rpc *RPC) SetDebug(out io.Writer, maxBody int) {
+	client := http.Client{}
+	if rpc.Client != nil {
+		client = *rpc.Client
+	}
+	client.Transport = &DebugTransport{Base: client.Transport, Out: out, MaxBody: maxBody}
+	rpc.Client = &client
+}
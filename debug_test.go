@@ -0,0 +1,40 @@
+package tgo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestSetDebug(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header": rawBody(`{"hash":"BLhead","level":7,"protocol":"Pt24m4xiPbLDhVgVfABUjirbmda3yohdN82Sp1FeuXXt"}`),
+		"POST /chains/main/mempool/filter":    rawBody(`{}`),
+	})
+	out := &bytes.Buffer{}
+	client.SetDebug(out, 20)
+	if _, err := client.GetBlockHeader(context.Background(), "head"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetMempoolFilter(context.Background(), "main", tgo.MempoolFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	dump := out.String()
+	for _, expected := range []string{
+		"GET /chains/main/blocks/head/header HTTP/1.1",
+		"200 OK",
+		`{"hash":"BLhead","le... (truncated)`,
+		"POST /chains/main/mempool/filter HTTP/1.1",
+		"Content-Type: application/json",
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Fatalf("expected %q in the dump\n%s", expected, dump)
+		}
+	}
+	if strings.Contains(dump, "Pt24m4xi") {
+		t.Fatalf("expected the body to be truncated\n%s", dump)
+	}
+}
@@ -0,0 +1,92 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FrozenBalance is an entry of `GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>/frozen_balance_by_cycle`
+type FrozenBalance struct {
+	Cycle    int64 `json:"cycle"`
+	Deposits int64 `json:"deposits,string"`
+	Fees     int64 `json:"fees,string"`
+	Rewards  int64 `json:"rewards,string"`
+}
+
+// UnmarshalJSON also accepts the `deposit` field used before carthage
+func (f *FrozenBalance) UnmarshalJSON(b []byte) error {
+	type frozenBalance FrozenBalance
+	raw := struct {
+		frozenBalance
+		Deposit *int64 `json:"deposit,string"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*f = FrozenBalance(raw.frozenBalance)
+	if raw.Deposit != nil {
+		f.Deposits = *raw.Deposit
+	}
+	return nil
+}
+
+// DelegatesQuery filters the delegates listed by GetDelegates, all delegates are listed when
+// neither Active nor Inactive is set
+type DelegatesQuery struct {
+	Active   bool
+	Inactive bool
+}
+
+// GetDelegates calls GET /chains/<chain>/blocks/<block_id>/context/delegates
+func (rpc *RPC) GetDelegates(ctx context.Context, blockID BlockID, opts DelegatesQuery) ([]string, error) {
+	delegates := []string{}
+	path := query{}.flag("active", opts.Active).flag("inactive", opts.Inactive).
+		path(fmt.Sprintf("/chains/%s/blocks/%s/context/delegates", rpc.ChainAlias(ctx), blockID))
+	err := rpc.get(ctx, path, &delegates)
+	return delegates, err
+}
+
+// GetFrozenBalanceByCycle calls GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>/frozen_balance_by_cycle
+func (rpc *RPC) GetFrozenBalanceByCycle(ctx context.Context, blockID BlockID, delegate string) ([]FrozenBalance, error) {
+	balances := []FrozenBalance{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/delegates/%s/frozen_balance_by_cycle", rpc.ChainAlias(ctx), blockID, delegate), &balances)
+	return balances, err
+}
+
+// Delegate holds the response from `GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>`
+type Delegate struct {
+	Balance              int64           `json:"balance,string"`
+	FrozenBalance        int64           `json:"frozen_balance,string"`
+	FrozenBalanceByCycle []FrozenBalance `json:"frozen_balance_by_cycle"`
+	StakingBalance       int64           `json:"staking_balance,string"`
+	DelegatedContracts   []string        `json:"delegated_contracts"`
+	DelegatedBalance     int64           `json:"delegated_balance,string"`
+	Deactivated          bool            `json:"deactivated"`
+	GracePeriod          int64           `json:"grace_period"`
+}
+
+// GetDelegate calls GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>
+func (rpc *RPC) GetDelegate(ctx context.Context, blockID BlockID, delegate string) (Delegate, error) {
+	d := Delegate{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/delegates/%s", rpc.ChainAlias(ctx), blockID, delegate), &d)
+	return d, err
+}
+
+// GetDelegatedContracts calls GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>/delegated_contracts
+func (rpc *RPC) GetDelegatedContracts(ctx context.Context, blockID BlockID, delegate string) ([]string, error) {
+	contracts := []string{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/delegates/%s/delegated_contracts", rpc.ChainAlias(ctx), blockID, delegate), &contracts)
+	return contracts, err
+}
+
+// GetStakingBalance calls GET /chains/<chain>/blocks/<block_id>/context/delegates/<pkh>/staking_balance
+func (rpc *RPC) GetStakingBalance(ctx context.Context, blockID BlockID, delegate string) (int64, error) {
+	var balance string
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/delegates/%s/staking_balance", rpc.ChainAlias(ctx), blockID, delegate), &balance)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(balance, 10, 64)
+}
@@ -0,0 +1,29 @@
+package tgo
+
+import "context"
+
+// gas and storage limits for a delegation operation
+const (
+	delegationGasLimit     = "10100"
+	delegationStorageLimit = "0"
+)
+
+// SetDelegate delegates the balance of signer to delegate and returns the operation hash
+func (rpc *RPC) SetDelegate(ctx context.Context, signer Signer, delegate string) (string, error) {
+	return rpc.sendOperation(ctx, signer, []OperationContents{delegationContents(delegate)})
+}
+
+// ClearDelegate withdraws the delegation of signer and returns the operation hash
+func (rpc *RPC) ClearDelegate(ctx context.Context, signer Signer) (string, error) {
+	return rpc.sendOperation(ctx, signer, []OperationContents{delegationContents("")})
+}
+
+// delegationContents builds a delegation, an empty delegate removes the current one
+func delegationContents(delegate string) OperationContents {
+	return OperationContents{
+		Kind:         "delegation",
+		GasLimit:     delegationGasLimit,
+		StorageLimit: delegationStorageLimit,
+		Delegate:     delegate,
+	}
+}
@@ -0,0 +1,62 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestSetDelegate(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter":     "41",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": nil,
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 100),
+		"POST /injection/operation":                              "ooHash",
+	})
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := client.SetDelegate(context.Background(), key, "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "ooHash" {
+		t.Fatalf("unexpected operation hash %s", hash)
+	}
+	forges := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	op := tgo.Operation{}
+	if err := json.Unmarshal([]byte(forges[len(forges)-1]), &op); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.Contents) != 2 {
+		t.Fatalf("expected reveal and delegation got %+v", op.Contents)
+	}
+	reveal, c := op.Contents[0], op.Contents[1]
+	if reveal.Kind != "reveal" || reveal.Counter != "42" || reveal.PublicKey != key.PublicKey() {
+		t.Fatalf("unexpected reveal %+v", reveal)
+	}
+	if c.Kind != "delegation" || c.Counter != "43" || c.Delegate != "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" {
+		t.Fatalf("unexpected contents %+v", c)
+	}
+	if c.Fee != "1192" {
+		t.Fatalf("unexpected fee %s", c.Fee)
+	}
+
+	node.route("GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key", key.PublicKey())
+	if _, err := client.ClearDelegate(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	forges = node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	op = tgo.Operation{}
+	if err := json.Unmarshal([]byte(forges[len(forges)-1]), &op); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.Contents) != 1 || op.Contents[0].Delegate != "" || op.Contents[0].Counter != "42" {
+		t.Fatalf("unexpected contents %+v", op.Contents)
+	}
+}
@@ -0,0 +1,217 @@
+// Package deposits scans finalized blocks for the transactions crediting a set of watched addresses
+package deposits
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgo "github.com/postables/TGo"
+)
+
+// DefaultConfirmations is how many blocks must be baked on top of a block before it is scanned
+const DefaultConfirmations = 30
+
+// Deposit is an applied transaction crediting a watched address, Amount is in mutez
+type Deposit struct {
+	Address       string
+	Sender        string
+	Amount        int64
+	OperationHash tgo.OperationHash
+	BlockHash     tgo.BlockHash
+	Level         int64
+	// Internal is set for transfers emitted by a contract, Sender then being the contract
+	Internal bool
+}
+
+// Cursor persists the last level scanned so a scanner resumes where it stopped
+type Cursor interface {
+	// Load returns the last level saved, 0 if none was
+	Load() (int64, error)
+	Save(level int64) error
+}
+
+// FileCursor is a Cursor keeping the level in the file at the given path
+type FileCursor string
+
+// Load reads the level from the file, a missing file means no level was saved
+func (c FileCursor) Load() (int64, error) {
+	b, err := ioutil.ReadFile(string(c))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// Save replaces the file with level, writing a temporary file first so a crash cannot
+// leave a truncated cursor
+func (c FileCursor) Save(level int64) error {
+	tmp := string(c) + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatInt(level, 10)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, string(c))
+}
+
+// Scanner walks the blocks with enough confirmations in order and reports the deposits to
+// the watched addresses
+type Scanner struct {
+	rpc    *tgo.RPC
+	cursor Cursor
+	// Confirmations is how many blocks must follow a block before it is scanned,
+	// DefaultConfirmations when 0
+	Confirmations int64
+	// StartLevel is the first level scanned when the cursor holds no level, the scan starts
+	// at the first final block when 0
+	StartLevel int64
+
+	mu        sync.RWMutex
+	addresses map[string]bool
+}
+
+// NewScanner returns a scanner reading blocks through rpc, persisting its progress in cursor
+// and watching addresses
+func NewScanner(rpc *tgo.RPC, cursor Cursor, addresses ...string) *Scanner {
+	s := &Scanner{rpc: rpc, cursor: cursor, addresses: map[string]bool{}}
+	s.Watch(addresses...)
+	return s
+}
+
+// Watch adds addresses to the watched set, it can be called while the scanner runs
+func (s *Scanner) Watch(addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, address := range addresses {
+		s.addresses[address] = true
+	}
+}
+
+// Unwatch removes addresses from the watched set
+func (s *Scanner) Unwatch(addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, address := range addresses {
+		delete(s.addresses, address)
+	}
+}
+
+func (s *Scanner) watched(address string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.addresses[address]
+}
+
+// ScanLevel returns the deposits to the watched addresses in the block at level, including
+// transfers emitted by contracts. Failed and backtracked transactions are left out.
+func (s *Scanner) ScanLevel(ctx context.Context, level int64) ([]Deposit, error) {
+	blockID := tgo.LevelID(level)
+	header, err := s.rpc.GetBlockHeader(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	passes, err := s.rpc.GetBlockOperations(ctx, header.Hash.ID())
+	if err != nil {
+		return nil, err
+	}
+	deposits := []Deposit{}
+	add := func(op tgo.BlockOperation, sender, destination, amount string, result tgo.OperationResult, internal bool) error {
+		if result.Status != "applied" || !s.watched(destination) {
+			return nil
+		}
+		mutez, err := strconv.ParseInt(amount, 10, 64)
+		if err != nil {
+			return err
+		}
+		deposits = append(deposits, Deposit{Address: destination, Sender: sender, Amount: mutez,
+			OperationHash: op.Hash, BlockHash: header.Hash, Level: header.Level, Internal: internal})
+		return nil
+	}
+	for _, ops := range passes {
+		for _, op := range ops {
+			for _, c := range op.Contents {
+				if c.Kind == "transaction" {
+					if err := add(op, c.Source, c.Destination, c.Amount, c.Metadata.OperationResult, false); err != nil {
+						return nil, err
+					}
+				}
+				for _, internal := range c.Metadata.InternalOperationResults {
+					if internal.Kind == "transaction" {
+						if err := add(op, internal.Source, internal.Destination, internal.Amount, internal.Result, true); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+		}
+	}
+	return deposits, nil
+}
+
+// Run scans every block once it has enough confirmations, starting after the level held by
+// the cursor, and emits the deposits found until ctx is cancelled. The cursor is saved once
+// all the deposits of a level are delivered, so after a restart deposits are delivered at
+// least once. Both channels are closed once scanning stops. errs receives the failures met
+// while scanning, which are dropped while a previous failure has not been read and retried
+// on the next head, and the error that stopped the head stream if any, which is never dropped.
+func (s *Scanner) Run(ctx context.Context) (<-chan Deposit, <-chan error) {
+	found := make(chan Deposit)
+	errs := make(chan error, 1)
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+	confirmations := s.Confirmations
+	if confirmations <= 0 {
+		confirmations = DefaultConfirmations
+	}
+	go func() {
+		defer close(found)
+		defer close(errs)
+		last, err := s.cursor.Load()
+		if err != nil {
+			errs <- err
+			return
+		}
+		if last == 0 && s.StartLevel > 0 {
+			last = s.StartLevel - 1
+		}
+		heads, headErrs := s.rpc.MonitorHeads(ctx, s.rpc.ChainAlias(ctx))
+		for head := range heads {
+			final := head.Level - confirmations
+			if last == 0 && final > 0 {
+				last = final - 1
+			}
+			for level := last + 1; level <= final; level++ {
+				deposits, err := s.ScanLevel(ctx, level)
+				if err != nil {
+					report(err)
+					break
+				}
+				for _, d := range deposits {
+					select {
+					case found <- d:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if err := s.cursor.Save(level); err != nil {
+					report(err)
+					break
+				}
+				last = level
+			}
+		}
+		if err := <-headErrs; err != nil {
+			errs <- err
+		}
+	}()
+	return found, errs
+}
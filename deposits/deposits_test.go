@@ -0,0 +1,109 @@
+package deposits_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/deposits"
+)
+
+func TestScanner(t *testing.T) {
+	watched := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	transaction := func(source, destination, amount, status string, internal ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"kind": "transaction", "source": source, "destination": destination, "amount": amount,
+			"metadata": map[string]interface{}{
+				"operation_result":           map[string]interface{}{"status": status},
+				"internal_operation_results": internal,
+			},
+		}
+	}
+	routes := map[string]interface{}{
+		"/chains/main/blocks/7/header": map[string]interface{}{"level": 7, "hash": "BL7"},
+		"/chains/main/blocks/BL7/operations": [][]interface{}{{}, {}, {}, {
+			map[string]interface{}{"hash": "opDeposit", "contents": []interface{}{
+				transaction("tz1sender", watched, "1000", "applied"),
+				transaction("tz1sender", "tz1other", "5", "applied"),
+			}},
+			map[string]interface{}{"hash": "opFailed", "contents": []interface{}{transaction("tz1sender", watched, "2000", "failed")}},
+			map[string]interface{}{"hash": "opCall", "contents": []interface{}{transaction("tz1caller", "KT1contract", "0", "applied",
+				map[string]interface{}{"kind": "transaction", "source": "KT1contract", "destination": watched, "amount": "300", "result": map[string]interface{}{"status": "applied"}},
+			)}},
+		}},
+		"/chains/main/blocks/8/header":       map[string]interface{}{"level": 8, "hash": "BL8"},
+		"/chains/main/blocks/BL8/operations": [][]interface{}{{}, {}, {}, {}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/monitor/heads/main" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"level": 10, "hash": "BL10"})
+			return
+		}
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	client.PollInterval = time.Millisecond
+
+	dir, err := ioutil.TempDir("", "deposits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cursor := deposits.FileCursor(filepath.Join(dir, "cursor"))
+	if level, err := cursor.Load(); err != nil || level != 0 {
+		t.Fatalf("expected no level for a missing cursor got %d, %v", level, err)
+	}
+	if err := cursor.Save(6); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := deposits.NewScanner(client, cursor, watched)
+	scanner.Confirmations = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	found, errs := scanner.Run(ctx)
+	expected := []deposits.Deposit{
+		{Address: watched, Sender: "tz1sender", Amount: 1000, OperationHash: "opDeposit", BlockHash: "BL7", Level: 7},
+		{Address: watched, Sender: "KT1contract", Amount: 300, OperationHash: "opCall", BlockHash: "BL7", Level: 7, Internal: true},
+	}
+	for _, e := range expected {
+		if d := <-found; !reflect.DeepEqual(d, e) {
+			t.Fatalf("expected %+v got %+v", e, d)
+		}
+	}
+	// level 8 is saved once scanned, level 9 lacks confirmations
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		level, err := cursor.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if level == 8 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cursor stuck at %d", level)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	cancel()
+	for d := range found {
+		t.Fatalf("unexpected deposit %+v", d)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
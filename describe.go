@@ -0,0 +1,71 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Description is a node of the RPC service tree returned by `GET /describe`
+type Description struct {
+	Static  *StaticDescription `json:"static,omitempty"`
+	Dynamic json.RawMessage    `json:"dynamic,omitempty"`
+}
+
+// StaticDescription lists the services offered at a path and its sub directories
+type StaticDescription struct {
+	GetService    *ServiceDescription `json:"get_service,omitempty"`
+	PostService   *ServiceDescription `json:"post_service,omitempty"`
+	PutService    *ServiceDescription `json:"put_service,omitempty"`
+	DeleteService *ServiceDescription `json:"delete_service,omitempty"`
+	PatchService  *ServiceDescription `json:"patch_service,omitempty"`
+	Subdirs       *SubdirsDescription `json:"subdirs,omitempty"`
+}
+
+// ServiceDescription describes a single RPC, its query parameters and schemas
+type ServiceDescription struct {
+	Meth        string             `json:"meth"`
+	Path        []json.RawMessage  `json:"path"`
+	Description string             `json:"description,omitempty"`
+	Query       []json.RawMessage  `json:"query"`
+	Input       *SchemaDescription `json:"input,omitempty"`
+	Output      *SchemaDescription `json:"output,omitempty"`
+	Error       *SchemaDescription `json:"error,omitempty"`
+}
+
+// SchemaDescription holds the JSON and binary schemas of an RPC input or output
+type SchemaDescription struct {
+	JSONSchema   json.RawMessage `json:"json_schema"`
+	BinarySchema json.RawMessage `json:"binary_schema"`
+}
+
+// SubdirsDescription holds either fixed suffixes or a dynamic path argument below a path
+type SubdirsDescription struct {
+	Suffixes        []SuffixDescription         `json:"suffixes,omitempty"`
+	DynamicDispatch *DynamicDispatchDescription `json:"dynamic_dispatch,omitempty"`
+}
+
+// SuffixDescription is a fixed sub directory
+type SuffixDescription struct {
+	Name string      `json:"name"`
+	Tree Description `json:"tree"`
+}
+
+// DynamicDispatchDescription is a sub directory named by a path argument such as a block id
+type DynamicDispatchDescription struct {
+	Arg  json.RawMessage `json:"arg"`
+	Tree Description     `json:"tree"`
+}
+
+// Describe calls GET /describe/<path>, describing the services available under path.
+// With recurse the whole sub tree is described, otherwise only path itself. The command
+// cmd/describegen generates the Go types of the described services.
+func (rpc *RPC) Describe(ctx context.Context, path string, recurse bool) (Description, error) {
+	url := "/describe/" + strings.TrimPrefix(path, "/")
+	if recurse {
+		url += "?recurse=yes"
+	}
+	description := Description{}
+	err := rpc.get(ctx, url, &description)
+	return description, err
+}
@@ -0,0 +1,28 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /describe/network/connections": rawBody(`{"static":{"get_service":{"meth":"GET","path":["network","connections"],"description":"List the running P2P connection.","query":[],"output":{"json_schema":{"type":"array"},"binary_schema":{}}},"subdirs":{"dynamic_dispatch":{"arg":{"id":"peer_id","name":"peer_id"},"tree":{"static":{"get_service":{"meth":"GET","path":[],"query":[]},"delete_service":{"meth":"DELETE","path":[],"query":[{"name":"wait"}]}}}}}}}`),
+	})
+	description, err := client.Describe(context.Background(), "/network/connections", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	static := description.Static
+	if static == nil || static.GetService == nil || static.GetService.Output == nil || static.Subdirs == nil || static.Subdirs.DynamicDispatch == nil {
+		t.Fatalf("unexpected description %+v", description)
+	}
+	if peer := static.Subdirs.DynamicDispatch.Tree.Static; peer == nil || peer.DeleteService == nil || len(peer.DeleteService.Query) != 1 {
+		t.Fatalf("unexpected peer description %+v", static.Subdirs.DynamicDispatch.Tree)
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if q := node.queries["GET /describe/network/connections"][0]; q != "recurse=yes" {
+		t.Fatalf("unexpected query %s", q)
+	}
+}
@@ -0,0 +1,103 @@
+package describegen
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MissingFields returns the paths of the fields documented by the JSON schema that the Go
+// type t does not decode, e.g. metadata.level_info. Objects are compared with the JSON
+// names of the fields of structs, embedded structs included, and maps cover every field.
+// Fields of each alternative of oneOf and anyOf schemas are expected.
+func MissingFields(rawSchema json.RawMessage, t reflect.Type) ([]string, error) {
+	s := &schema{}
+	if err := json.Unmarshal(rawSchema, s); err != nil {
+		return nil, err
+	}
+	missing := map[string]bool{}
+	compare(s, t, "", s.Definitions, missing, map[string]bool{})
+	paths := make([]string, 0, len(missing))
+	for p := range missing {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// compare records in missing the fields of s not decoded by t below path. seen holds the
+// definitions being compared so recursive definitions are compared once.
+func compare(s *schema, t reflect.Type, path string, defs map[string]*schema, missing, seen map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if s.Ref != "" {
+		def := strings.TrimPrefix(s.Ref, "#/definitions/")
+		target, ok := defs[def]
+		key := def + " " + t.String()
+		if !ok || seen[key] {
+			return
+		}
+		seen[key] = true
+		compare(target, t, path, defs, missing, seen)
+		delete(seen, key)
+		return
+	}
+	for _, alternative := range append(append([]*schema{}, s.OneOf...), s.AnyOf...) {
+		compare(alternative, t, path, defs, missing, seen)
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := &schema{}
+		if json.Unmarshal(s.Items, items) == nil {
+			compare(items, t.Elem(), path+"[]", defs, missing, seen)
+		}
+	case reflect.Struct:
+		fields := jsonFields(t)
+		for name, property := range s.Properties {
+			field, ok := fields[strings.ToLower(name)]
+			if !ok {
+				missing[strings.TrimPrefix(path+"."+name, ".")] = true
+				continue
+			}
+			compare(property, field, path+"."+name, defs, missing, seen)
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of the struct t by lower case JSON name, as
+// encoding/json matches names regardless of case
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, ft := range jsonFields(embedded) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = ft
+					}
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,342 @@
+// Package describegen generates Go types from the JSON schemas of the services described by
+// `GET /describe`, so the responses of new protocols can be decoded with less code written by hand
+package describegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	tgo "github.com/postables/TGo"
+)
+
+// Service is an RPC found in a description tree
+type Service struct {
+	Method string
+	// Path is the path of the service with its arguments in brackets, e.g. /network/connections/<peer_id>
+	Path string
+	// Name is the Go name of the service, its method followed by its path in camel case
+	Name        string
+	Description string
+	Input       json.RawMessage
+	Output      json.RawMessage
+}
+
+// Services walks the description of the tree at root and returns its services sorted by path
+func Services(root string, description tgo.Description) []Service {
+	services := []Service{}
+	walk("/"+strings.Trim(root, "/"), description, &services)
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Path != services[j].Path {
+			return services[i].Path < services[j].Path
+		}
+		return services[i].Method < services[j].Method
+	})
+	return services
+}
+
+// walk appends the services of description at path and of its sub directories to services
+func walk(path string, description tgo.Description, services *[]Service) {
+	static := description.Static
+	if static == nil {
+		return
+	}
+	for _, s := range []*tgo.ServiceDescription{static.GetService, static.PostService, static.PutService, static.DeleteService, static.PatchService} {
+		if s == nil {
+			continue
+		}
+		service := Service{Method: s.Meth, Path: path, Name: serviceName(s.Meth, path), Description: s.Description}
+		if s.Input != nil {
+			service.Input = s.Input.JSONSchema
+		}
+		if s.Output != nil {
+			service.Output = s.Output.JSONSchema
+		}
+		*services = append(*services, service)
+	}
+	if static.Subdirs == nil {
+		return
+	}
+	for _, suffix := range static.Subdirs.Suffixes {
+		walk(strings.TrimSuffix(path, "/")+"/"+suffix.Name, suffix.Tree, services)
+	}
+	if dynamic := static.Subdirs.DynamicDispatch; dynamic != nil {
+		arg := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(dynamic.Arg, &arg); err != nil || arg.Name == "" {
+			arg.Name = "arg"
+		}
+		walk(strings.TrimSuffix(path, "/")+"/<"+arg.Name+">", dynamic.Tree, services)
+	}
+}
+
+// serviceName returns the Go name of the service at path, e.g. GetNetworkConnectionsPeerID
+func serviceName(method, path string) string {
+	name := camel(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		name += camel(strings.Trim(segment, "<>"))
+	}
+	return name
+}
+
+// initialisms are written upper case in Go names
+var initialisms = map[string]bool{"id": true, "url": true, "rpc": true, "json": true, "p2p": true, "ip": true, "api": true}
+
+// camel converts a snake case, kebab case or dotted name to an exported Go name
+func camel(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	name := ""
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			name += strings.ToUpper(w)
+			continue
+		}
+		name += strings.ToUpper(w[:1]) + w[1:]
+	}
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "N" + name
+	}
+	return name
+}
+
+// schema is the part of a JSON schema describegen understands
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 json.RawMessage    `json:"type"`
+	Title                string             `json:"title"`
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                json.RawMessage    `json:"items"`
+	OneOf                []*schema          `json:"oneOf"`
+	AnyOf                []*schema          `json:"anyOf"`
+	Definitions          map[string]*schema `json:"definitions"`
+}
+
+// types returns the JSON types allowed by the schema other than null
+func (s *schema) types() []string {
+	var one string
+	if json.Unmarshal(s.Type, &one) == nil {
+		return []string{one}
+	}
+	many := []string{}
+	json.Unmarshal(s.Type, &many)
+	types := []string{}
+	for _, t := range many {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Generator accumulates the Go types generated for the schemas of services
+type Generator struct {
+	// Package is the package of the generated file
+	Package string
+	// decls are the generated declarations by type name, names in the order they were declared
+	decls map[string]string
+	names []string
+	// definitions maps the definitions of the schema being generated to the names of their types
+	definitions map[string]string
+	rawJSON     bool
+}
+
+// NewGenerator returns a generator of types in pkg
+func NewGenerator(pkg string) *Generator {
+	return &Generator{Package: pkg, decls: map[string]string{}}
+}
+
+// Add generates the types of the input and output of service, named after the service
+// with a Request and Response suffix
+func (g *Generator) Add(service Service) error {
+	for _, part := range []struct {
+		raw    json.RawMessage
+		suffix string
+		doc    string
+	}{
+		{service.Input, "Request", "is the body of"},
+		{service.Output, "Response", "holds the response from"},
+	} {
+		if len(part.raw) == 0 {
+			continue
+		}
+		s := &schema{}
+		if err := json.Unmarshal(part.raw, s); err != nil {
+			return fmt.Errorf("%s %s: %w", service.Method, service.Path, err)
+		}
+		g.definitions = map[string]string{}
+		doc := fmt.Sprintf("%s `%s %s`", part.doc, service.Method, service.Path)
+		g.declare(service.Name+part.suffix, doc, s, s.Definitions)
+	}
+	return nil
+}
+
+// declare declares the type name for s and returns the name it was given, a number is
+// appended to names already declared with a different type
+func (g *Generator) declare(name, doc string, s *schema, defs map[string]*schema) string {
+	var body string
+	if s.isObject() && s.Ref == "" {
+		body = g.structType(name, s, defs)
+	} else {
+		body = g.goType(name, doc, s, defs)
+	}
+	base := name
+	for i := 2; ; i++ {
+		declared, ok := g.decls[name]
+		if !ok {
+			break
+		}
+		if strings.HasSuffix(declared, " "+body+"\n") {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.decls[name] = fmt.Sprintf("// %s %s\ntype %s %s\n", name, doc, name, body)
+	g.names = append(g.names, name)
+	return name
+}
+
+// goType returns the Go type of s, declaring the structs it needs. Objects are declared
+// as name, documented by doc.
+func (g *Generator) goType(name, doc string, s *schema, defs map[string]*schema) string {
+	if s.Ref != "" {
+		def := strings.TrimPrefix(s.Ref, "#/definitions/")
+		if declared, ok := g.definitions[def]; ok {
+			return declared
+		}
+		target, ok := defs[def]
+		if !ok {
+			return g.raw()
+		}
+		if !target.isObject() {
+			return g.goType(name, doc, target, defs)
+		}
+		// the name is reserved before declaring so recursive definitions refer to it
+		g.definitions[def] = camel(def)
+		declared := g.declare(camel(def), "is the "+def+" definition of the schema", target, defs)
+		g.definitions[def] = declared
+		return declared
+	}
+	alternatives := append(append([]*schema{}, s.OneOf...), s.AnyOf...)
+	if len(alternatives) > 0 {
+		// alternatives are only typed when they are all the same scalar
+		typ := scalar(alternatives[0], defs)
+		for _, alternative := range alternatives[1:] {
+			if scalar(alternative, defs) != typ {
+				return g.raw()
+			}
+		}
+		if typ == "" {
+			return g.raw()
+		}
+		return typ
+	}
+	if typ := scalar(s, defs); typ != "" {
+		return typ
+	}
+	types := s.types()
+	if len(types) != 1 {
+		return g.raw()
+	}
+	switch types[0] {
+	case "array":
+		items := &schema{}
+		if err := json.Unmarshal(s.Items, items); err != nil {
+			// tuples list a schema for each item
+			return "[]" + g.raw()
+		}
+		return "[]" + g.goType(name+"Item", "is an item of "+name, items, defs)
+	case "object":
+		if s.isObject() {
+			return g.declare(name, doc, s, defs)
+		}
+		values := &schema{}
+		if err := json.Unmarshal(s.AdditionalProperties, values); err == nil {
+			return "map[string]" + g.goType(name+"Value", "is a value of "+name, values, defs)
+		}
+		return "map[string]" + g.raw()
+	}
+	return g.raw()
+}
+
+// scalars are the Go types of the JSON scalar types
+var scalars = map[string]string{"string": "string", "integer": "int64", "number": "float64", "boolean": "bool"}
+
+// scalar returns the Go type of s if it is a scalar or a reference to one, "" otherwise
+func scalar(s *schema, defs map[string]*schema) string {
+	for i := 0; s.Ref != "" && i < len(defs); i++ {
+		target, ok := defs[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if !ok {
+			return ""
+		}
+		s = target
+	}
+	if len(s.OneOf) == 1 && len(s.AnyOf) == 0 {
+		return scalar(s.OneOf[0], defs)
+	}
+	if types := s.types(); len(types) == 1 && len(s.OneOf)+len(s.AnyOf) == 0 {
+		return scalars[types[0]]
+	}
+	return ""
+}
+
+// isObject reports whether s is an object with properties, which is declared as a struct
+func (s *schema) isObject() bool {
+	return len(s.Properties) > 0
+}
+
+// structType returns the struct type of the object s, declaring the types of its nested objects
+func (g *Generator) structType(name string, s *schema, defs map[string]*schema) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, k := range keys {
+		field := camel(k)
+		typ := g.goType(name+field, "is the "+k+" field of "+name, s.Properties[k], defs)
+		tag := k
+		if !required[k] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:\"%s\"`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// raw returns the type of values describegen cannot type
+func (g *Generator) raw() string {
+	g.rawJSON = true
+	return "json.RawMessage"
+}
+
+// Source returns the formatted Go source of the generated types
+func (g *Generator) Source() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by describegen from the /describe schema of the node; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.Package)
+	if g.rawJSON {
+		b.WriteString("import \"encoding/json\"\n\n")
+	}
+	for _, name := range g.names {
+		b.WriteString(g.decls[name])
+		b.WriteString("\n")
+	}
+	return format.Source(b.Bytes())
+}
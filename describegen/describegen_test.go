@@ -0,0 +1,117 @@
+package describegen_test
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/describegen"
+)
+
+// connections is the description of /network/connections trimmed to a few fields
+const connections = `{"static":{"get_service":{"meth":"GET","path":["network","connections"],"query":[],
+	"output":{"json_schema":{"type":"array","items":{"$ref":"#/definitions/connection"},
+		"definitions":{"connection":{"type":"object","properties":{
+			"incoming":{"type":"boolean"},
+			"peer_id":{"$ref":"#/definitions/Crypto_box.Public_key_hash"},
+			"id_point":{"type":"object","properties":{"addr":{"type":"string"},"port":{"type":"integer","minimum":0,"maximum":65535}},"required":["addr"]},
+			"versions":{"type":"array","items":{"type":"object","properties":{"name":{"type":"string"},"major":{"type":"integer"}},"required":["name","major"]}},
+			"metadata":{"oneOf":[{"type":"string"},{"type":"object","properties":{"invalid_utf8_string":{"type":"array"}}}]},
+			"score":{"type":"number"}},
+			"required":["incoming","peer_id","id_point"]},
+		"Crypto_box.Public_key_hash":{"title":"A Cryptobox public key ID","oneOf":[{"type":"string"}]}}},
+	"binary_schema":{}}},
+	"subdirs":{"dynamic_dispatch":{"arg":{"id":"peer_id","name":"peer_id"},"tree":{"static":{
+		"get_service":{"meth":"GET","path":[],"query":[],"output":{"json_schema":{"$ref":"#/definitions/connection",
+			"definitions":{"connection":{"type":"object","properties":{"incoming":{"type":"boolean"}}}}}}},
+		"delete_service":{"meth":"DELETE","path":[],"query":[{"name":"wait"}],"output":{"json_schema":{"type":"object","properties":{}}}}}}}}}}`
+
+func TestGenerate(t *testing.T) {
+	description := tgo.Description{}
+	if err := json.Unmarshal([]byte(connections), &description); err != nil {
+		t.Fatal(err)
+	}
+	services := describegen.Services("network/connections", description)
+	paths := []string{}
+	for _, s := range services {
+		paths = append(paths, s.Method+" "+s.Path+" "+s.Name)
+	}
+	if strings.Join(paths, ",") != "GET /network/connections GetNetworkConnections,"+
+		"DELETE /network/connections/<peer_id> DeleteNetworkConnectionsPeerID,GET /network/connections/<peer_id> GetNetworkConnectionsPeerID" {
+		t.Fatalf("unexpected services %v", paths)
+	}
+	g := describegen.NewGenerator("tgo")
+	for _, s := range services {
+		if err := g.Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source, err := g.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "generated.go", source, 0); err != nil {
+		t.Fatalf("%v\n%s", err, source)
+	}
+	for _, expected := range []string{
+		"type GetNetworkConnectionsResponse []Connection\n",
+		"type Connection struct {",
+		"\tIDPoint  ConnectionIDPoint        `json:\"id_point\"`",
+		"\tPeerID   string                   `json:\"peer_id\"`",
+		"\tMetadata json.RawMessage          `json:\"metadata,omitempty\"`",
+		"\tVersions []ConnectionVersionsItem `json:\"versions,omitempty\"`",
+		"type ConnectionIDPoint struct {\n\tAddr string `json:\"addr\"`\n\tPort int64  `json:\"port,omitempty\"`\n}",
+		// the connection of a single peer has another shape and gets another name
+		"type GetNetworkConnectionsPeerIDResponse Connection2\n",
+		"type DeleteNetworkConnectionsPeerIDResponse map[string]json.RawMessage\n",
+	} {
+		if !strings.Contains(string(source), expected) {
+			t.Fatalf("expected %q in\n%s", expected, source)
+		}
+	}
+	// alternatives that are not all the same scalar are left raw without declaring their objects
+	if strings.Contains(string(source), "ConnectionMetadata") {
+		t.Fatalf("unexpected alternative type in\n%s", source)
+	}
+}
+
+func TestMissingFields(t *testing.T) {
+	type point struct {
+		Addr string `json:"addr"`
+	}
+	type connection struct {
+		Incoming bool `json:"incoming"`
+		// PeerID has no tag, encoding/json would only match peerid
+		PeerID   string
+		IDPoint  *point `json:"id_point"`
+		Metadata map[string]interface{}
+	}
+	var schema struct {
+		Output struct {
+			Schema json.RawMessage `json:"json_schema"`
+		} `json:"output"`
+	}
+	description := tgo.Description{}
+	if err := json.Unmarshal([]byte(connections), &description); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(description.Static.GetService)
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	missing, err := describegen.MissingFields(schema.Output.Schema, reflect.TypeOf([]connection{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(missing, ",") != "[].id_point.port,[].peer_id,[].score,[].versions" {
+		t.Fatalf("unexpected missing fields %v", missing)
+	}
+	missing, err = describegen.MissingFields(schema.Output.Schema, reflect.TypeOf([]tgo.ConnectionsResponse{}))
+	if err != nil || strings.Join(missing, ",") != "[].metadata,[].score" {
+		t.Fatalf("unexpected missing fields %v: %v", missing, err)
+	}
+}
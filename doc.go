@@ -0,0 +1,8 @@
+// Package tgo is a client of the RPC of Tezos nodes.
+//
+// The runners of the package, such as BandwidthCollector.Run or Follower.Follow, report the
+// failures they recover from on an error channel of capacity one without waiting for the
+// caller, a failure being dropped while the previous one has not been read. The error that
+// stops a runner, such as the failure of the stream it follows, is never dropped. The error
+// channel is closed once the runner stops and must be read until then.
+package tgo
@@ -0,0 +1,52 @@
+package tgo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+)
+
+// endorsementTag is the binary tag of endorsement contents
+const endorsementTag = 0x00
+
+// ForgeEndorsement forges locally an endorsement of the block branch at level and returns
+// the unsigned bytes as hex
+func ForgeEndorsement(branch BlockHash, level int64) (string, error) {
+	hash, err := b58CheckDecode(string(branch), prefixBlock)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch %s: %w", branch, err)
+	}
+	if level <= 0 || level > 1<<31-1 {
+		return "", fmt.Errorf("invalid level %d", level)
+	}
+	forged := appendUint32(append(hash, endorsementTag), uint32(level))
+	return hex.EncodeToString(forged), nil
+}
+
+// SignEndorsement signs a forged endorsement with the endorsement watermark of chainID,
+// returning the signature and the signed operation ready for injection
+func SignEndorsement(signer Signer, chainID ChainID, forgedHex string) (string, string, error) {
+	chain, err := b58CheckDecode(string(chainID), prefixChainID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid chain id %s: %w", chainID, err)
+	}
+	return signWatermarked(signer, append([]byte{endorsementPrefix}, chain...), forgedHex)
+}
+
+// Endorse injects an endorsement of the block blockID signed by signer and returns the
+// operation hash. The signer must have endorsing rights for the level of the block.
+func (rpc *RPC) Endorse(ctx context.Context, signer Signer, blockID BlockID) (string, error) {
+	header, err := rpc.GetBlockHeader(ctx, blockID)
+	if err != nil {
+		return "", err
+	}
+	forged, err := ForgeEndorsement(header.Hash, header.Level)
+	if err != nil {
+		return "", err
+	}
+	_, signed, err := SignEndorsement(signer, header.ChainID, forged)
+	if err != nil {
+		return "", err
+	}
+	return rpc.InjectOperation(ctx, signed)
+}
@@ -0,0 +1,50 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestEndorse(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header": map[string]interface{}{
+			"hash":     "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+			"chain_id": "NetXdQprcVkpaWU",
+			"level":    300,
+		},
+		"POST /injection/operation": "ooHash",
+	})
+	hash, err := client.Endorse(context.Background(), key, "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := "8fcf233671b6a04fcf679d2a381c2544ea6c1ea29ba6157776ed8424c7ccd00b" + "00" + "0000012c"
+	raw, _ := hex.DecodeString(forged)
+	signature, err := key.Sign(append([]byte{0x02, 0x7a, 0x06, 0xa7, 0x70}, raw...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, signed, err := tgo.SignEndorsement(key, "NetXdQprcVkpaWU", forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig != signature {
+		t.Fatalf("endorsement not signed with the chain watermark: %s", sig)
+	}
+	if injected := node.bodies["POST /injection/operation"][0]; hash != "ooHash" || injected != `"`+signed+`"` {
+		t.Fatalf("unexpected injected endorsement %s", injected)
+	}
+	if _, err := tgo.ForgeEndorsement("BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2", 0); err == nil {
+		t.Fatal("expected error for an invalid level")
+	}
+	if _, _, err := tgo.SignEndorsement(key, "NetXdQprcVkpaWX", forged); err == nil {
+		t.Fatal("expected error for an invalid chain id")
+	}
+}
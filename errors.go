@@ -0,0 +1,68 @@
+package tgo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors callers can test for with errors.Is, a *StatusError matches ErrNotFound on a 404 and
+// the errors mapped from the error IDs reported by the node
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrPeerNotFound        = errors.New("peer not found")
+	ErrNodeNotBootstrapped = errors.New("node is not bootstrapped")
+	ErrCounterInThePast    = errors.New("counter in the past")
+	ErrCounterInTheFuture  = errors.New("counter in the future")
+	ErrBalanceTooLow       = errors.New("balance too low")
+	ErrUnrevealedKey       = errors.New("public key of the source is not revealed")
+	ErrEmptyContract       = errors.New("empty implicit contract")
+	ErrGasExhausted        = errors.New("gas exhausted")
+	ErrStorageExhausted    = errors.New("storage exhausted")
+	ErrWrongNetwork        = errors.New("node serves another network")
+)
+
+// nodeErrors maps the error IDs of the node, without their protocol prefix, to the errors above
+var nodeErrors = map[string]error{
+	"contract.counter_in_the_past":     ErrCounterInThePast,
+	"contract.counter_in_the_future":   ErrCounterInTheFuture,
+	"contract.balance_too_low":         ErrBalanceTooLow,
+	"contract.unrevealed_key":          ErrUnrevealedKey,
+	"implicit.empty_implicit_contract": ErrEmptyContract,
+	"gas_exhausted.operation":          ErrGasExhausted,
+	"gas_exhausted.block":              ErrGasExhausted,
+	"storage_exhausted.operation":      ErrStorageExhausted,
+}
+
+// nodeError returns the error mapped from id, e.g. proto.005-PsBabyM1.contract.balance_too_low,
+// nil when the id is not mapped
+func nodeError(id string) error {
+	for suffix, err := range nodeErrors {
+		if id == suffix || strings.HasSuffix(id, "."+suffix) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Is reports whether the response matches target, see the errors of the package
+func (e *StatusError) Is(target error) bool {
+	if target == ErrNotFound && e.StatusCode == http.StatusNotFound {
+		return true
+	}
+	for _, nodeErr := range e.Errors {
+		if err := nodeError(nodeErr.ID); err != nil && err == target {
+			return true
+		}
+	}
+	return false
+}
+
+// reportError sends the failure a runner recovered from on errs without blocking, err is
+// dropped while the previous error has not been read, see the package documentation
+func reportError(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,80 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Estimation holds the suggested fee and limits for a single operation
+type Estimation struct {
+	Fee          int64
+	GasLimit     int64
+	StorageLimit int64
+}
+
+// Estimate simulates contents sent by signer through run_operation and returns for each of
+// them the gas and storage they need and the minimal fee covering their size and gas. Fees
+// and limits already set are kept, missing counters are fetched from the chain. A reveal is
+// simulated first when the key of signer is not yet known to the chain, as sendOperation
+// would send one, its estimation is not returned.
+func (rpc *RPC) Estimate(ctx context.Context, signer Signer, contents []OperationContents) ([]Estimation, error) {
+	if len(contents) == 0 {
+		return nil, errors.New("no operations to estimate")
+	}
+	managerKey, err := rpc.GetManagerKey(ctx, signer.PublicKeyHash())
+	if err != nil {
+		return nil, err
+	}
+	op := Operation{Contents: append([]OperationContents(nil), contents...)}
+	if managerKey == "" && contents[0].Kind != "reveal" {
+		op.Contents = append([]OperationContents{{
+			Kind:         "reveal",
+			GasLimit:     revealGasLimit,
+			StorageLimit: revealStorageLimit,
+			PublicKey:    signer.PublicKey(),
+		}}, op.Contents...)
+	}
+	var counter int64
+	for i := range op.Contents {
+		c := &op.Contents[i]
+		c.Source = signer.PublicKeyHash()
+		if c.Counter == "" {
+			if counter == 0 {
+				if counter, err = rpc.GetCounter(ctx, c.Source); err != nil {
+					return nil, err
+				}
+			}
+			counter++
+			c.Counter = strconv.FormatInt(counter, 10)
+		} else if counter, err = strconv.ParseInt(c.Counter, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid counter %q: %w", c.Counter, err)
+		}
+	}
+	branch, err := rpc.GetHeadHash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op.Branch = branch
+	if err := rpc.sizeLimits(ctx, &op); err != nil {
+		return nil, err
+	}
+	if _, err := rpc.forgeWithFees(ctx, &op); err != nil {
+		return nil, err
+	}
+	estimations := make([]Estimation, len(op.Contents))
+	for i, c := range op.Contents {
+		e := &estimations[i]
+		if e.Fee, err = strconv.ParseInt(c.Fee, 10, 64); err != nil {
+			return nil, err
+		}
+		if e.GasLimit, err = strconv.ParseInt(c.GasLimit, 10, 64); err != nil {
+			return nil, err
+		}
+		if e.StorageLimit, err = strconv.ParseInt(c.StorageLimit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return estimations[len(op.Contents)-len(contents):], nil
+}
@@ -0,0 +1,84 @@
+package tgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestEstimate(t *testing.T) {
+	receipt := func(kind, gas, storage string) map[string]interface{} {
+		return map[string]interface{}{
+			"kind": kind,
+			"metadata": map[string]interface{}{
+				"operation_result": map[string]interface{}{
+					"status":                 "applied",
+					"consumed_gas":           gas,
+					"paid_storage_size_diff": storage,
+				},
+			},
+		}
+	}
+	routes := map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter":     "7",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav",
+		"GET /chains/main/blocks/head/hash":                           "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"GET /chains/main/chain_id":                                   "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_operation": map[string]interface{}{"contents": []interface{}{receipt("transaction", "10207", "67")}},
+		"POST /chains/main/blocks/head/helpers/forge/operations":      strings.Repeat("ab", 100),
+	}
+	node, client := newFakeNode(t, routes)
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transaction := tgo.OperationContents{
+		Kind:        "transaction",
+		Destination: "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi",
+		Amount:      "0",
+	}
+	estimations, err := client.Estimate(context.Background(), key, []tgo.OperationContents{transaction})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estimations) != 1 {
+		t.Fatalf("expected 1 estimation got %d", len(estimations))
+	}
+	if e := estimations[0]; e.GasLimit != 10307 || e.StorageLimit != 67 || e.Fee != 1295 {
+		t.Fatalf("unexpected estimation %+v", e)
+	}
+	if sim := node.bodies["POST /chains/main/blocks/head/helpers/scripts/run_operation"][0]; !strings.Contains(sim, `"counter":"8"`) || strings.Contains(sim, "reveal") {
+		t.Fatalf("expected counter to be fetched, got %s", sim)
+	}
+
+	// fees and limits set by the caller are kept
+	set := transaction
+	set.Fee, set.GasLimit, set.StorageLimit = "2000", "15000", "100"
+	if estimations, err = client.Estimate(context.Background(), key, []tgo.OperationContents{set}); err != nil {
+		t.Fatal(err)
+	}
+	if e := estimations[0]; e.Fee != 2000 || e.GasLimit != 15000 || e.StorageLimit != 100 {
+		t.Fatalf("unexpected estimation %+v", e)
+	}
+
+	// a reveal is simulated first for an unrevealed key
+	node.route("GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key", "")
+	node.route("POST /chains/main/blocks/head/helpers/scripts/run_operation", map[string]interface{}{"contents": []interface{}{receipt("reveal", "1000", "0"), receipt("transaction", "10207", "67")}})
+	if estimations, err = client.Estimate(context.Background(), key, []tgo.OperationContents{transaction}); err != nil {
+		t.Fatal(err)
+	}
+	if len(estimations) != 1 || estimations[0].GasLimit != 10307 || estimations[0].StorageLimit != 67 {
+		t.Fatalf("unexpected estimations %+v", estimations)
+	}
+	sims := node.bodies["POST /chains/main/blocks/head/helpers/scripts/run_operation"]
+	sim := sims[len(sims)-1]
+	if !strings.Contains(sim, `"kind":"reveal"`) || !strings.Contains(sim, `"counter":"9"`) {
+		t.Fatalf("expected a reveal to be simulated first, got %s", sim)
+	}
+
+	if _, err := client.Estimate(context.Background(), key, nil); err == nil {
+		t.Fatal("expected error for no operations")
+	}
+}
@@ -0,0 +1,96 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// InlinedHeader is a signed block header as carried by double baking evidence, the header
+// of GET /chains/<chain>/blocks/<block_id>/header without its hash, chain and protocol
+type InlinedHeader struct {
+	Level            int64     `json:"level"`
+	Proto            int64     `json:"proto"`
+	Predecessor      BlockHash `json:"predecessor"`
+	Timestamp        Timestamp `json:"timestamp"`
+	ValidationPass   int64     `json:"validation_pass"`
+	OperationsHash   string    `json:"operations_hash"`
+	Fitness          []string  `json:"fitness"`
+	Context          string    `json:"context"`
+	Priority         int64     `json:"priority"`
+	ProofOfWorkNonce string    `json:"proof_of_work_nonce"`
+	SeedNonceHash    string    `json:"seed_nonce_hash,omitempty"`
+	Signature        string    `json:"signature"`
+}
+
+// Inlined returns the header as included in double baking evidence
+func (h BlockHeader) Inlined() InlinedHeader {
+	return InlinedHeader{
+		Level:            h.Level,
+		Proto:            h.Proto,
+		Predecessor:      h.Predecessor,
+		Timestamp:        h.Timestamp,
+		ValidationPass:   h.ValidationPass,
+		OperationsHash:   h.OperationsHash,
+		Fitness:          h.Fitness,
+		Context:          h.Context,
+		Priority:         h.Priority,
+		ProofOfWorkNonce: h.ProofOfWorkNonce,
+		SeedNonceHash:    h.SeedNonceHash,
+		Signature:        h.Signature,
+	}
+}
+
+// InlinedEndorsement is a signed endorsement as carried by double endorsement evidence
+type InlinedEndorsement struct {
+	Branch     BlockHash         `json:"branch"`
+	Operations OperationContents `json:"operations"`
+	Signature  string            `json:"signature"`
+}
+
+// InlinedEndorsement returns the endorsement of op as included in double endorsement evidence
+func (op BlockOperation) InlinedEndorsement() (InlinedEndorsement, error) {
+	if len(op.Contents) != 1 || op.Contents[0].Kind != "endorsement" {
+		return InlinedEndorsement{}, fmt.Errorf("operation %s is not an endorsement", op.Hash)
+	}
+	return InlinedEndorsement{
+		Branch:     op.Branch,
+		Operations: OperationContents{Kind: "endorsement", Level: op.Contents[0].Level},
+		Signature:  op.Signature,
+	}, nil
+}
+
+// InjectDoubleBakingEvidence denounces the baker of two different blocks at the same level
+// and returns the operation hash
+func (rpc *RPC) InjectDoubleBakingEvidence(ctx context.Context, bh1, bh2 BlockHeader) (string, error) {
+	h1, h2 := bh1.Inlined(), bh2.Inlined()
+	if h1.Level != h2.Level {
+		return "", fmt.Errorf("headers are at different levels %d and %d", h1.Level, h2.Level)
+	}
+	if reflect.DeepEqual(h1, h2) {
+		return "", errors.New("headers are identical")
+	}
+	if h1.Signature == "" || h2.Signature == "" {
+		return "", errors.New("headers must be signed")
+	}
+	return rpc.sendUnsigned(ctx, []OperationContents{{Kind: "double_baking_evidence", BH1: &h1, BH2: &h2}})
+}
+
+// InjectDoubleEndorsementEvidence denounces the delegate of two different endorsements of the
+// same level and returns the operation hash
+func (rpc *RPC) InjectDoubleEndorsementEvidence(ctx context.Context, op1, op2 InlinedEndorsement) (string, error) {
+	if op1.Operations.Kind != "endorsement" || op2.Operations.Kind != "endorsement" {
+		return "", errors.New("evidence must consist of endorsements")
+	}
+	if op1.Operations.Level != op2.Operations.Level {
+		return "", fmt.Errorf("endorsements are at different levels %d and %d", op1.Operations.Level, op2.Operations.Level)
+	}
+	if op1.Branch == op2.Branch {
+		return "", errors.New("endorsements endorse the same block")
+	}
+	if op1.Signature == "" || op2.Signature == "" {
+		return "", errors.New("endorsements must be signed")
+	}
+	return rpc.sendUnsigned(ctx, []OperationContents{{Kind: "double_endorsement_evidence", Op1: &op1, Op2: &op2}})
+}
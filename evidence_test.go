@@ -0,0 +1,86 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func evidenceNode(t *testing.T) (*fakeNode, *tgo.RPC) {
+	return newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 40),
+		"POST /injection/operation":                              "ooHash",
+	})
+}
+
+func TestInjectDoubleBakingEvidence(t *testing.T) {
+	node, client := evidenceNode(t)
+	bh1 := tgo.BlockHeader{Hash: "BLa", ChainID: "NetXdQprcVkpaWU", Level: 100, Priority: 0, ProofOfWorkNonce: "00", Signature: "sigA"}
+	bh2 := bh1
+	bh2.Hash, bh2.Timestamp, bh2.Signature = "BLb", tgo.NewTimestamp(time.Date(2019, 1, 1, 0, 0, 30, 0, time.UTC)), "sigB"
+	if _, err := client.InjectDoubleBakingEvidence(context.Background(), bh1, bh1); err == nil {
+		t.Fatal("expected error for identical headers")
+	}
+	bh3 := bh2
+	bh3.Level = 101
+	if _, err := client.InjectDoubleBakingEvidence(context.Background(), bh1, bh3); err == nil {
+		t.Fatal("expected error for headers at different levels")
+	}
+	hash, err := client.InjectDoubleBakingEvidence(context.Background(), bh1, bh2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]
+	if hash != "ooHash" || strings.Contains(body, "chain_id") || strings.Contains(body, `"hash"`) {
+		t.Fatalf("unexpected evidence %s", body)
+	}
+	forged := tgo.Operation{}
+	if err := json.Unmarshal([]byte(body), &forged); err != nil {
+		t.Fatal(err)
+	}
+	c := forged.Contents[0]
+	if c.Kind != "double_baking_evidence" || c.BH1.Signature != "sigA" || c.BH2.Signature != "sigB" || c.BH2.Level != 100 || c.BH2.Timestamp.String() != "2019-01-01T00:00:30Z" {
+		t.Fatalf("unexpected evidence %+v", c)
+	}
+}
+
+func TestInjectDoubleEndorsementEvidence(t *testing.T) {
+	node, client := evidenceNode(t)
+	ops := []tgo.BlockOperation{}
+	for _, branch := range []string{"BLa", "BLb"} {
+		op := tgo.BlockOperation{}
+		raw := `{"hash": "oo` + branch + `", "branch": "` + branch + `", "signature": "sig` + branch + `",
+			"contents": [{"kind": "endorsement", "level": 100, "metadata": {"delegate": "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", "slots": [1]}}]}`
+		if err := json.Unmarshal([]byte(raw), &op); err != nil {
+			t.Fatal(err)
+		}
+		ops = append(ops, op)
+	}
+	op1, err := ops[0].InlinedEndorsement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	op2, err := ops[1].InlinedEndorsement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.InjectDoubleEndorsementEvidence(context.Background(), op1, op1); err == nil {
+		t.Fatal("expected error for endorsements of the same block")
+	}
+	if _, err := client.InjectDoubleEndorsementEvidence(context.Background(), op1, op2); err != nil {
+		t.Fatal(err)
+	}
+	body := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]
+	want := `{"kind":"double_endorsement_evidence","op1":{"branch":"BLa","operations":{"kind":"endorsement","level":100},"signature":"sigBLa"}`
+	if !strings.Contains(body, want) {
+		t.Fatalf("unexpected evidence %s", body)
+	}
+	if _, err := (tgo.BlockOperation{Hash: "ooX"}).InlinedEndorsement(); err == nil {
+		t.Fatal("expected error for an operation that is not an endorsement")
+	}
+}
@@ -0,0 +1,59 @@
+package tgo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+// fakeNode serves canned JSON responses keyed by "METHOD /path" and records request bodies and queries
+type fakeNode struct {
+	*httptest.Server
+	mu      sync.Mutex
+	routes  map[string]interface{}
+	bodies  map[string][]string
+	queries map[string][]string
+}
+
+func newFakeNode(t *testing.T, routes map[string]interface{}) (*fakeNode, *tgo.RPC) {
+	node := &fakeNode{routes: routes, bodies: map[string][]string{}, queries: map[string][]string{}}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		node.mu.Lock()
+		node.bodies[key] = append(node.bodies[key], string(body))
+		node.queries[key] = append(node.queries[key], r.URL.RawQuery)
+		resp, ok := node.routes[key]
+		node.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if f, ok := resp.(func(body []byte) interface{}); ok {
+			resp = f(body)
+		}
+		if raw, ok := resp.(rawBody); ok {
+			w.Write([]byte(raw))
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(node.Close)
+	return node, tgo.GenerateClient(node.URL, time.Second*5)
+}
+
+// rawBody is written as is instead of being JSON encoded, e.g. for chunked streams
+type rawBody string
+
+// route replaces the response served for key
+func (n *fakeNode) route(key string, resp interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.routes[key] = resp
+}
@@ -0,0 +1,99 @@
+package tgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+// fixtures pairs the responses of testdata/fixtures with the route serving them and the
+// call decoding them, the responses are captured again from the node at TGO_FIXTURES_URL
+// when the variable is set
+var fixtures = []struct {
+	file  string
+	route string
+	call  func(ctx context.Context, rpc *tgo.RPC) (interface{}, error)
+}{
+	{"connections.json", "GET /network/connections", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetConnections()
+	}},
+	{"header.json", "GET /chains/main/blocks/head/header", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetBlockHeader(ctx, tgo.Head)
+	}},
+	{"metadata.json", "GET /chains/main/blocks/head/metadata", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetBlockMetadata(ctx, tgo.Head)
+	}},
+	{"operations.json", "GET /chains/main/blocks/head/operations", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetBlockOperations(ctx, tgo.Head)
+	}},
+	{"constants.json", "GET /chains/main/blocks/head/context/constants", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetConstants(ctx, tgo.Head)
+	}},
+	{"ballots.json", "GET /chains/main/blocks/head/votes/ballots", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetBallots(ctx, tgo.Head)
+	}},
+	{"baking_rights.json", "GET /chains/main/blocks/head/helpers/baking_rights", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetBakingRights(ctx, tgo.Head, tgo.RightsQuery{})
+	}},
+	{"endorsing_rights.json", "GET /chains/main/blocks/head/helpers/endorsing_rights", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetEndorsingRights(ctx, tgo.Head, tgo.RightsQuery{})
+	}},
+	{"delegate.json", "GET /chains/main/blocks/head/context/delegates/tz1NortRftucvAkD1J58L32EhSVrQEWJCEnB", func(ctx context.Context, rpc *tgo.RPC) (interface{}, error) {
+		return rpc.GetDelegate(ctx, tgo.Head, "tz1NortRftucvAkD1J58L32EhSVrQEWJCEnB")
+	}},
+}
+
+func TestFixtures(t *testing.T) {
+	url := os.Getenv("TGO_FIXTURES_URL")
+	for _, fixture := range fixtures {
+		t.Run(fixture.file, func(t *testing.T) {
+			name := filepath.Join("testdata", "fixtures", fixture.file)
+			if url != "" {
+				if err := captureFixture(url+strings.TrimPrefix(fixture.route, "GET "), name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			body, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, client := newFakeNode(t, map[string]interface{}{fixture.route: rawBody(body)})
+			// a typo in a tag fails as a missing field instead of decoding to zero
+			client.Strict = true
+			if _, err := fixture.call(context.Background(), client); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// captureFixture writes the response to a GET of url to the fixture name, indented
+func captureFixture(url, name string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("capturing %s: %s: %s", url, resp.Status, body)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return err
+	}
+	indented.WriteByte('\n')
+	return ioutil.WriteFile(name, indented.Bytes(), 0644)
+}
@@ -0,0 +1,105 @@
+package tgo
+
+import (
+	"context"
+	"sync"
+)
+
+// NodeHead is the head reported by one node of a fleet
+type NodeHead struct {
+	URL   string
+	Level int64
+	Hash  BlockHash
+	// Lag is how many levels the node is behind the highest head of the fleet
+	Lag int64
+	// Forked is set when the head is not on the chain of the highest head
+	Forked bool
+	// Err is set when the head of the node could not be read
+	Err error
+}
+
+// FleetHeads compares the heads of several nodes
+type FleetHeads struct {
+	Heads []NodeHead
+	// Highest is the head of the node with the highest level, ties resolved by node order
+	Highest NodeHead
+}
+
+// Lagging returns the nodes more than maxLag levels behind the highest head, unreachable
+// nodes and nodes on another branch
+func (f FleetHeads) Lagging(maxLag int64) []NodeHead {
+	lagging := []NodeHead{}
+	for _, h := range f.Heads {
+		if h.Err != nil || h.Forked || h.Lag > maxLag {
+			lagging = append(lagging, h)
+		}
+	}
+	return lagging
+}
+
+// Diverged reports whether any reachable node is on another branch than the highest head
+func (f FleetHeads) Diverged() bool {
+	for _, h := range f.Heads {
+		if h.Forked {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareHeads reads the heads of nodes concurrently and compares them with the highest one.
+// Heads behind the highest are checked against the block of the highest node at their level,
+// so a lagging node is told apart from a node stuck on another branch.
+func CompareHeads(ctx context.Context, nodes ...*RPC) FleetHeads {
+	heads := make([]NodeHead, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *RPC) {
+			defer wg.Done()
+			header, err := node.GetBlockHeader(ctx, "head")
+			heads[i] = NodeHead{URL: node.URL, Level: header.Level, Hash: header.Hash, Err: err}
+		}(i, node)
+	}
+	wg.Wait()
+
+	highest := -1
+	for i, h := range heads {
+		if h.Err == nil && (highest < 0 || h.Level > heads[highest].Level) {
+			highest = i
+		}
+	}
+	if highest < 0 {
+		return FleetHeads{Heads: heads}
+	}
+	reference := nodes[highest]
+	canonical := map[int64]BlockHash{heads[highest].Level: heads[highest].Hash}
+	var mu sync.Mutex
+	for i := range heads {
+		if heads[i].Err != nil {
+			continue
+		}
+		heads[i].Lag = heads[highest].Level - heads[i].Level
+		wg.Add(1)
+		go func(h *NodeHead) {
+			defer wg.Done()
+			mu.Lock()
+			hash, known := canonical[h.Level]
+			mu.Unlock()
+			if !known {
+				header, err := reference.GetBlockHeader(ctx, LevelID(h.Level))
+				if err != nil {
+					// the highest node cannot tell, the lag alone is reported
+					return
+				}
+				hash = header.Hash
+				mu.Lock()
+				canonical[h.Level] = hash
+				mu.Unlock()
+			}
+			h.Forked = hash != h.Hash
+		}(&heads[i])
+	}
+	wg.Wait()
+	return FleetHeads{Heads: heads, Highest: heads[highest]}
+}
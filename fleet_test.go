@@ -0,0 +1,40 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestCompareHeads(t *testing.T) {
+	header := func(hash string, level int) map[string]interface{} {
+		return map[string]interface{}{"hash": hash, "level": level}
+	}
+	_, leader := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header": header("BL302", 302),
+		"GET /chains/main/blocks/300/header":  header("BL300", 300),
+	})
+	_, synced := newFakeNode(t, map[string]interface{}{"GET /chains/main/blocks/head/header": header("BL302", 302)})
+	_, lagging := newFakeNode(t, map[string]interface{}{"GET /chains/main/blocks/head/header": header("BL300", 300)})
+	_, forked := newFakeNode(t, map[string]interface{}{"GET /chains/main/blocks/head/header": header("BL300b", 300)})
+	_, down := newFakeNode(t, map[string]interface{}{})
+
+	fleet := tgo.CompareHeads(context.Background(), lagging, leader, synced, forked, down)
+	if fleet.Highest.URL != leader.URL || fleet.Highest.Level != 302 {
+		t.Fatalf("unexpected highest head %+v", fleet.Highest)
+	}
+	h := fleet.Heads
+	if h[0].Lag != 2 || h[0].Forked || h[1].Lag != 0 || h[2].Forked || h[3].Lag != 2 || !h[3].Forked || h[4].Err == nil {
+		t.Fatalf("unexpected heads %+v", h)
+	}
+	if !fleet.Diverged() {
+		t.Fatal("expected the fleet to have diverged")
+	}
+	if lagging := fleet.Lagging(2); len(lagging) != 2 || lagging[0].URL != forked.URL || lagging[1].URL != down.URL {
+		t.Fatalf("unexpected lagging nodes %+v", lagging)
+	}
+	if lagging := fleet.Lagging(1); len(lagging) != 3 {
+		t.Fatalf("unexpected lagging nodes %+v", lagging)
+	}
+}
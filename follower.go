@@ -0,0 +1,143 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+)
+
+// Kinds of events emitted by a Follower
+const (
+	BlockApplied    = "apply"
+	BlockRolledBack = "rollback"
+)
+
+// defaultFollowerDepth is how many blocks of the canonical chain a Follower remembers by default
+const defaultFollowerDepth = 128
+
+// ChainEvent is a block entering or leaving the canonical chain
+type ChainEvent struct {
+	Kind  string
+	Block BlockHeader
+}
+
+// Follower tracks the canonical chain from the heads of the node. When a head is not built on
+// the previous one, the blocks of the abandoned branch are rolled back down to the common
+// ancestor before the blocks of the new branch are applied, so state derived from the applied
+// blocks can be kept consistent.
+type Follower struct {
+	rpc *RPC
+	// Depth is how many blocks are remembered to find the common ancestor of a reorganization,
+	// 128 by default
+	Depth int
+
+	chain  []BlockHeader
+	hashes map[BlockHash]int
+}
+
+// NewFollower returns a follower reading heads and blocks through rpc
+func NewFollower(rpc *RPC) *Follower {
+	return &Follower{rpc: rpc, hashes: map[BlockHash]int{}}
+}
+
+// Tip returns the last block applied, false if none was
+func (f *Follower) Tip() (BlockHeader, bool) {
+	if len(f.chain) == 0 {
+		return BlockHeader{}, false
+	}
+	return f.chain[len(f.chain)-1], true
+}
+
+// Handle moves the canonical chain to head and returns the events doing so: rollbacks from the
+// previous tip down to the common ancestor then applications up to head, filling in the blocks
+// skipped between heads. When the common ancestor is older than the blocks remembered, an
+// error is returned along with the application of head alone, state must then be rebuilt.
+// Handle is not safe for concurrent use, Follow calls it for every head.
+func (f *Follower) Handle(ctx context.Context, head BlockHeader) ([]ChainEvent, error) {
+	if len(f.chain) == 0 {
+		f.reset(head)
+		return []ChainEvent{{Kind: BlockApplied, Block: head}}, nil
+	}
+	// walk the branch of head back to a remembered block
+	branch := []BlockHeader{}
+	ancestor, known := f.hashes[head.Hash]
+	for cur := head; !known; {
+		branch = append(branch, cur)
+		if cur.Level <= f.chain[0].Level {
+			err := fmt.Errorf("no common ancestor with head %s within the last %d blocks", head.Hash, len(f.chain))
+			f.reset(head)
+			return []ChainEvent{{Kind: BlockApplied, Block: head}}, err
+		}
+		if ancestor, known = f.hashes[cur.Predecessor]; known {
+			break
+		}
+		predecessor, err := f.rpc.GetBlockHeader(ctx, cur.Predecessor.ID())
+		if err != nil {
+			return nil, err
+		}
+		cur = predecessor
+	}
+	events := []ChainEvent{}
+	for i := len(f.chain) - 1; i > ancestor; i-- {
+		events = append(events, ChainEvent{Kind: BlockRolledBack, Block: f.chain[i]})
+		delete(f.hashes, f.chain[i].Hash)
+	}
+	f.chain = f.chain[:ancestor+1]
+	for i := len(branch) - 1; i >= 0; i-- {
+		events = append(events, ChainEvent{Kind: BlockApplied, Block: branch[i]})
+		f.push(branch[i])
+	}
+	return events, nil
+}
+
+// Follow applies every head of the node and emits the resulting events until ctx is cancelled.
+// Both channels are closed once following stops. errs receives the failures of Handle and the
+// error that stopped the head stream if any, as described in the package documentation.
+func (f *Follower) Follow(ctx context.Context) (<-chan ChainEvent, <-chan error) {
+	events := make(chan ChainEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		heads, headErrs := f.rpc.MonitorHeads(ctx, f.rpc.ChainAlias(ctx))
+		for head := range heads {
+			found, err := f.Handle(ctx, head)
+			if err != nil {
+				reportError(errs, err)
+			}
+			for _, e := range found {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		if err := <-headErrs; err != nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
+
+// reset forgets the chain and starts again from head
+func (f *Follower) reset(head BlockHeader) {
+	f.chain, f.hashes = nil, map[BlockHash]int{}
+	f.push(head)
+}
+
+// push appends block to the chain, forgetting the oldest blocks beyond Depth
+func (f *Follower) push(block BlockHeader) {
+	depth := f.Depth
+	if depth <= 0 {
+		depth = defaultFollowerDepth
+	}
+	f.chain = append(f.chain, block)
+	f.hashes[block.Hash] = len(f.chain) - 1
+	if drop := len(f.chain) - depth; drop > 0 {
+		f.chain = append([]BlockHeader{}, f.chain[drop:]...)
+		f.hashes = map[BlockHash]int{}
+		for i, b := range f.chain {
+			f.hashes[b.Hash] = i
+		}
+	}
+}
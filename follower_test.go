@@ -0,0 +1,74 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestFollower(t *testing.T) {
+	header := func(hash, predecessor string, level int64) tgo.BlockHeader {
+		return tgo.BlockHeader{Hash: tgo.BlockHash(hash), Predecessor: tgo.BlockHash(predecessor), Level: level}
+	}
+	headers := map[string]tgo.BlockHeader{
+		"BL1":  header("BL1", "BL0", 1),
+		"BL2":  header("BL2", "BL1", 2),
+		"BL3":  header("BL3", "BL2", 3),
+		"BL4":  header("BL4", "BL3", 4),
+		"BL3b": header("BL3b", "BL2", 3),
+		"BL4b": header("BL4b", "BL3b", 4),
+		"BL5b": header("BL5b", "BL4b", 5),
+	}
+	routes := map[string]interface{}{}
+	for hash, h := range headers {
+		routes["GET /chains/main/blocks/"+hash+"/header"] = h
+	}
+	_, client := newFakeNode(t, routes)
+	follower := tgo.NewFollower(client)
+	follower.Depth = 4
+	describe := func(events []tgo.ChainEvent) string {
+		s := ""
+		for _, e := range events {
+			s += fmt.Sprintf("%s %s ", e.Kind, e.Block.Hash)
+		}
+		return s
+	}
+	steps := []struct {
+		head     string
+		expected string
+	}{
+		{"BL1", "apply BL1 "},
+		// skipped blocks are filled in
+		{"BL3", "apply BL2 apply BL3 "},
+		{"BL3", ""},
+		{"BL4", "apply BL4 "},
+		// the new branch forks after BL2
+		{"BL5b", "rollback BL4 rollback BL3 apply BL3b apply BL4b apply BL5b "},
+		// back to an older head of the chain
+		{"BL4b", "rollback BL5b "},
+	}
+	for _, step := range steps {
+		events, err := follower.Handle(context.Background(), headers[step.head])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := describe(events); got != step.expected {
+			t.Fatalf("head %s: expected %q got %q", step.head, step.expected, got)
+		}
+	}
+	if tip, ok := follower.Tip(); !ok || tip.Hash != "BL4b" {
+		t.Fatalf("unexpected tip %+v", tip)
+	}
+
+	// only BL4b and BL5b are remembered, BL4 forks below them
+	follower.Depth = 2
+	if _, err := follower.Handle(context.Background(), headers["BL5b"]); err != nil {
+		t.Fatal(err)
+	}
+	events, err := follower.Handle(context.Background(), headers["BL4"])
+	if err == nil || describe(events) != "apply BL4 " {
+		t.Fatalf("expected error and a restart from BL4 got %q, %v", describe(events), err)
+	}
+}
@@ -0,0 +1,161 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Ballots accepted by the ballot operation
+const (
+	BallotYay  = "yay"
+	BallotNay  = "nay"
+	BallotPass = "pass"
+)
+
+// Kinds of voting periods
+const (
+	PeriodProposal      = "proposal"
+	PeriodTestingVote   = "testing_vote"
+	PeriodTesting       = "testing"
+	PeriodPromotionVote = "promotion_vote"
+)
+
+// maxProposalsPerDelegate is the number of proposals a delegate may upvote in a proposal period
+const maxProposalsPerDelegate = 20
+
+// Ballots holds the response from `GET /chains/<chain>/blocks/<block_id>/votes/ballots`, in rolls
+type Ballots struct {
+	Yay  int64 `json:"yay"`
+	Nay  int64 `json:"nay"`
+	Pass int64 `json:"pass"`
+}
+
+// VotingPeriod is the voting period operations injected on top of a block are checked against
+type VotingPeriod struct {
+	Index int64
+	Kind  string
+	// Position is the position of the block within the period, starting at 0
+	Position int64
+}
+
+// GetCurrentPeriodKind calls GET /chains/<chain>/blocks/<block_id>/votes/current_period_kind
+func (rpc *RPC) GetCurrentPeriodKind(ctx context.Context, blockID BlockID) (string, error) {
+	var kind string
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/votes/current_period_kind", rpc.ChainAlias(ctx), blockID), &kind)
+	return kind, err
+}
+
+// GetCurrentProposal calls GET /chains/<chain>/blocks/<block_id>/votes/current_proposal,
+// the proposal is empty outside of the voting periods
+func (rpc *RPC) GetCurrentProposal(ctx context.Context, blockID BlockID) (ProtocolHash, error) {
+	var proposal *ProtocolHash
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/votes/current_proposal", rpc.ChainAlias(ctx), blockID), &proposal); err != nil {
+		return "", err
+	}
+	if proposal == nil {
+		return "", nil
+	}
+	return *proposal, nil
+}
+
+// GetProposals calls GET /chains/<chain>/blocks/<block_id>/votes/proposals and returns the rolls
+// supporting each proposal
+func (rpc *RPC) GetProposals(ctx context.Context, blockID BlockID) (map[ProtocolHash]int64, error) {
+	pairs := [][2]interface{}{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/votes/proposals", rpc.ChainAlias(ctx), blockID), &pairs); err != nil {
+		return nil, err
+	}
+	proposals := make(map[ProtocolHash]int64, len(pairs))
+	for _, p := range pairs {
+		hash, ok := p[0].(string)
+		rolls, ok2 := p[1].(float64)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid proposal %v", p)
+		}
+		proposals[ProtocolHash(hash)] = int64(rolls)
+	}
+	return proposals, nil
+}
+
+// GetBallots calls GET /chains/<chain>/blocks/<block_id>/votes/ballots
+func (rpc *RPC) GetBallots(ctx context.Context, blockID BlockID) (Ballots, error) {
+	ballots := Ballots{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/votes/ballots", rpc.ChainAlias(ctx), blockID), &ballots)
+	return ballots, err
+}
+
+// GetVotingPeriod returns the voting period at head. An error is returned when head is the last
+// block of its period, as operations injected then would land in the next period.
+func (rpc *RPC) GetVotingPeriod(ctx context.Context) (VotingPeriod, error) {
+	metadata, err := rpc.GetBlockMetadata(ctx, "head")
+	if err != nil {
+		return VotingPeriod{}, err
+	}
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return VotingPeriod{}, err
+	}
+	level := metadata.CurrentLevel()
+	if constants.BlocksPerVotingPeriod > 0 && level.VotingPeriodPosition >= constants.BlocksPerVotingPeriod-1 {
+		return VotingPeriod{}, fmt.Errorf("voting period %d ends at head, retry in the next period", level.VotingPeriod)
+	}
+	kind, err := rpc.GetCurrentPeriodKind(ctx, "head")
+	if err != nil {
+		return VotingPeriod{}, err
+	}
+	return VotingPeriod{Index: level.VotingPeriod, Kind: kind, Position: level.VotingPeriodPosition}, nil
+}
+
+// SubmitProposals injects a proposals operation upvoting proposals on behalf of signer and
+// returns the operation hash. The current period must be a proposal period.
+func (rpc *RPC) SubmitProposals(ctx context.Context, signer Signer, proposals ...ProtocolHash) (string, error) {
+	if len(proposals) == 0 || len(proposals) > maxProposalsPerDelegate {
+		return "", fmt.Errorf("between 1 and %d proposals can be submitted", maxProposalsPerDelegate)
+	}
+	period, err := rpc.GetVotingPeriod(ctx)
+	if err != nil {
+		return "", err
+	}
+	if period.Kind != PeriodProposal {
+		return "", fmt.Errorf("proposals cannot be submitted during a %s period", period.Kind)
+	}
+	return rpc.sendSigned(ctx, signer, []OperationContents{{
+		Kind:      "proposals",
+		Source:    signer.PublicKeyHash(),
+		Period:    &period.Index,
+		Proposals: proposals,
+	}})
+}
+
+// SubmitBallot injects the ballot of signer on proposal and returns the operation hash. The
+// current period must be a vote on proposal.
+func (rpc *RPC) SubmitBallot(ctx context.Context, signer Signer, proposal ProtocolHash, ballot string) (string, error) {
+	if ballot != BallotYay && ballot != BallotNay && ballot != BallotPass {
+		return "", fmt.Errorf("invalid ballot %q", ballot)
+	}
+	period, err := rpc.GetVotingPeriod(ctx)
+	if err != nil {
+		return "", err
+	}
+	if period.Kind != PeriodTestingVote && period.Kind != PeriodPromotionVote {
+		return "", fmt.Errorf("ballots cannot be cast during a %s period", period.Kind)
+	}
+	current, err := rpc.GetCurrentProposal(ctx, "head")
+	if err != nil {
+		return "", err
+	}
+	if current == "" {
+		return "", errors.New("no proposal is being voted on")
+	}
+	if current != proposal {
+		return "", fmt.Errorf("the proposal being voted on is %s", current)
+	}
+	return rpc.sendSigned(ctx, signer, []OperationContents{{
+		Kind:     "ballot",
+		Source:   signer.PublicKeyHash(),
+		Period:   &period.Index,
+		Proposal: proposal,
+		Ballot:   ballot,
+	}})
+}
@@ -0,0 +1,90 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func governanceNode(t *testing.T, kind string, position int64) (*fakeNode, *tgo.RPC) {
+	return newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/metadata":                  map[string]interface{}{"level": map[string]interface{}{"level": 1000, "voting_period": 3, "voting_period_position": position}},
+		"GET /chains/main/blocks/head/context/constants":         map[string]interface{}{"blocks_per_voting_period": 32768},
+		"GET /chains/main/blocks/head/votes/current_period_kind": kind,
+		"GET /chains/main/blocks/head/votes/current_proposal":    "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS",
+		"GET /chains/main/blocks/head/votes/proposals":           [][]interface{}{{"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS", 5000}},
+		"GET /chains/main/blocks/head/votes/ballots":             map[string]interface{}{"yay": 10, "nay": 2, "pass": 1},
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 60),
+		"POST /injection/operation":                              "ooHash",
+	})
+}
+
+func TestSubmitProposals(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, client := governanceNode(t, tgo.PeriodProposal, 10)
+	proposals, err := client.GetProposals(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposals["PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"] != 5000 {
+		t.Fatalf("unexpected proposals %v", proposals)
+	}
+	hash, err := client.SubmitProposals(context.Background(), key, "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := tgo.Operation{}
+	if err := json.Unmarshal([]byte(node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]), &forged); err != nil {
+		t.Fatal(err)
+	}
+	c := forged.Contents[0]
+	if hash != "ooHash" || c.Kind != "proposals" || c.Source != key.PublicKeyHash() || c.Period == nil || *c.Period != 3 || c.Fee != "" || len(c.Proposals) != 1 {
+		t.Fatalf("unexpected proposals operation %+v", c)
+	}
+	if _, err := client.SubmitBallot(context.Background(), key, "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS", tgo.BallotYay); err == nil {
+		t.Fatal("expected error for a ballot during a proposal period")
+	}
+	if _, err := client.SubmitProposals(context.Background(), key); err == nil {
+		t.Fatal("expected error without proposals")
+	}
+
+	_, client = governanceNode(t, tgo.PeriodProposal, 32767)
+	if _, err := client.SubmitProposals(context.Background(), key, "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"); err == nil {
+		t.Fatal("expected error at the end of the period")
+	}
+}
+
+func TestSubmitBallot(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, client := governanceNode(t, tgo.PeriodPromotionVote, 0)
+	ballots, err := client.GetBallots(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ballots != (tgo.Ballots{Yay: 10, Nay: 2, Pass: 1}) {
+		t.Fatalf("unexpected ballots %+v", ballots)
+	}
+	if _, err := client.SubmitBallot(context.Background(), key, "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS", "maybe"); err == nil {
+		t.Fatal("expected error for an invalid ballot")
+	}
+	if _, err := client.SubmitBallot(context.Background(), key, "PtOtherProposal", tgo.BallotNay); err == nil {
+		t.Fatal("expected error for a proposal not being voted on")
+	}
+	if _, err := client.SubmitBallot(context.Background(), key, "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS", tgo.BallotPass); err != nil {
+		t.Fatal(err)
+	}
+	body := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]
+	if !strings.Contains(body, `"kind":"ballot","source":"`+key.PublicKeyHash()+`"`) || !strings.Contains(body, `"period":3,"proposal":"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS","ballot":"pass"`) {
+		t.Fatalf("unexpected ballot %s", body)
+	}
+}
@@ -0,0 +1,79 @@
+package tgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Identifiers of the chain, encoded as base58check strings. They are decoded from and encoded
+// to JSON strings as is, Validate checks their prefix and checksum.
+type (
+	// Address is an implicit account (tz1, tz2, tz3) or an originated contract (KT1)
+	Address string
+	// BlockHash designates a block, e.g. BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2
+	BlockHash string
+	// OperationHash designates an operation group
+	OperationHash string
+	// ProtocolHash designates a protocol
+	ProtocolHash string
+	// ChainID designates a chain, e.g. NetXdQprcVkpaWU for mainnet
+	ChainID string
+)
+
+// prefixProtocol tags protocol hashes
+var prefixProtocol = []byte{2, 170}
+
+// validateHash returns an error when s is not a base58check string with prefix
+func validateHash(kind, s string, prefix []byte) error {
+	if _, err := b58CheckDecode(s, prefix); err != nil {
+		return fmt.Errorf("invalid %s %q: %w", kind, s, err)
+	}
+	return nil
+}
+
+// Validate returns an error when the address is not a valid tz1, tz2, tz3 or KT1 address
+func (a Address) Validate() error {
+	prefixes := map[string][]byte{"tz1": prefixTz1, "tz2": prefixTz2, "tz3": prefixTz3, "KT1": prefixKT1}
+	var prefix []byte
+	if len(a) >= 3 {
+		prefix = prefixes[string(a)[:3]]
+	}
+	if prefix == nil {
+		return fmt.Errorf("invalid address %q: unknown prefix", string(a))
+	}
+	return validateHash("address", string(a), prefix)
+}
+
+// Implicit reports whether the address is an implicit account rather than a contract
+func (a Address) Implicit() bool {
+	return strings.HasPrefix(string(a), "tz")
+}
+
+func (a Address) String() string { return string(a) }
+
+// Validate returns an error when the hash is not a valid block hash
+func (h BlockHash) Validate() error { return validateHash("block hash", string(h), prefixBlock) }
+
+// ID returns the block id designating the block of the hash
+func (h BlockHash) ID() BlockID { return BlockID(h) }
+
+func (h BlockHash) String() string { return string(h) }
+
+// Validate returns an error when the hash is not a valid operation hash
+func (h OperationHash) Validate() error {
+	return validateHash("operation hash", string(h), prefixOperation)
+}
+
+func (h OperationHash) String() string { return string(h) }
+
+// Validate returns an error when the hash is not a valid protocol hash
+func (h ProtocolHash) Validate() error {
+	return validateHash("protocol hash", string(h), prefixProtocol)
+}
+
+func (h ProtocolHash) String() string { return string(h) }
+
+// Validate returns an error when the id is not a valid chain id
+func (c ChainID) Validate() error { return validateHash("chain id", string(c), prefixChainID) }
+
+func (c ChainID) String() string { return string(c) }
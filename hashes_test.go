@@ -0,0 +1,42 @@
+package tgo_test
+
+import (
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestHashesValidate(t *testing.T) {
+	valid := []interface{ Validate() error }{
+		tgo.Address("tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"),
+		tgo.Address("KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi"),
+		tgo.BlockHash("BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2"),
+		tgo.ProtocolHash("PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"),
+		tgo.ChainID("NetXdQprcVkpaWU"),
+	}
+	for _, v := range valid {
+		if err := v.Validate(); err != nil {
+			t.Errorf("%v: %v", v, err)
+		}
+	}
+	invalid := []interface{ Validate() error }{
+		tgo.Address(""),
+		tgo.Address("tz4KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"),
+		tgo.Address("tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSy"),
+		tgo.BlockHash("NetXdQprcVkpaWU"),
+		tgo.ProtocolHash("BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2"),
+		tgo.ChainID("main"),
+		tgo.OperationHash("oo1"),
+	}
+	for _, v := range invalid {
+		if err := v.Validate(); err == nil {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+	if !tgo.Address("tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx").Implicit() || tgo.Address("KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi").Implicit() {
+		t.Fatal("unexpected Implicit")
+	}
+	if id := tgo.BlockHash("BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2").ID(); id.Validate() != nil {
+		t.Fatalf("unexpected block id %s", id)
+	}
+}
@@ -0,0 +1,149 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// default thresholds of Health checks
+const (
+	defaultMaxHeadAge       = 3 * time.Minute
+	defaultBootstrapTimeout = 2 * time.Second
+)
+
+// HealthOptions sets the thresholds a node must meet to be healthy, zero values use defaults
+type HealthOptions struct {
+	// MaxHeadAge is how old the timestamp of head may be, 3 minutes by default
+	MaxHeadAge time.Duration
+	// MinPeers is the number of connections required, 1 by default
+	MinPeers int
+	// MaxBacklog is the number of requests the prevalidator may have pending, unchecked when 0
+	MaxBacklog int
+	// BootstrapTimeout bounds the wait on /monitor/bootstrapped for nodes without
+	// /chains/<chain>/is_bootstrapped, 2 seconds by default
+	BootstrapTimeout time.Duration
+}
+
+// Health is the status of a node as seen by services depending on it
+type Health struct {
+	Bootstrapped bool          `json:"bootstrapped"`
+	SyncState    string        `json:"sync_state,omitempty"`
+	Head         BlockHeader   `json:"head"`
+	HeadAge      time.Duration `json:"head_age"`
+	Peers        int           `json:"peers"`
+	Backlog      int           `json:"prevalidator_backlog"`
+	// Problems lists why the node is unhealthy, it is empty for a healthy node
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Healthy reports whether the node met every threshold
+func (h Health) Healthy() bool {
+	return len(h.Problems) == 0
+}
+
+// Health checks that the node is bootstrapped, that its head is recent, that it is connected
+// to enough peers and that its prevalidator keeps up. An error is only returned when the head
+// cannot be read, failing checks are reported as problems.
+func (rpc *RPC) Health(ctx context.Context, opts HealthOptions) (Health, error) {
+	if opts.MaxHeadAge <= 0 {
+		opts.MaxHeadAge = defaultMaxHeadAge
+	}
+	if opts.MinPeers <= 0 {
+		opts.MinPeers = 1
+	}
+	if opts.BootstrapTimeout <= 0 {
+		opts.BootstrapTimeout = defaultBootstrapTimeout
+	}
+	head, err := rpc.GetBlockHeader(ctx, "head")
+	if err != nil {
+		return Health{}, err
+	}
+	health := Health{Head: head}
+	problem := func(format string, args ...interface{}) {
+		health.Problems = append(health.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if err := rpc.healthBootstrapped(ctx, &health, opts.BootstrapTimeout); err != nil {
+		problem("bootstrap status unavailable: %v", err)
+	} else if !health.Bootstrapped {
+		problem("node is not bootstrapped")
+	}
+
+	if head.Timestamp.IsZero() {
+		problem("head has no timestamp")
+	} else {
+		health.HeadAge = time.Since(head.Timestamp.Time)
+		if health.HeadAge > opts.MaxHeadAge {
+			problem("head %d is %s old", head.Level, health.HeadAge.Round(time.Second))
+		}
+	}
+
+	connections := []ConnectionsResponse{}
+	if err := rpc.get(ctx, "/network/connections", &connections); err != nil {
+		problem("connections unavailable: %v", err)
+	} else {
+		health.Peers = len(connections)
+		if health.Peers < opts.MinPeers {
+			problem("%d peers connected, %d required", health.Peers, opts.MinPeers)
+		}
+	}
+
+	if opts.MaxBacklog > 0 {
+		if worker, err := rpc.GetPrevalidatorWorker(ctx, head.ChainID); err != nil {
+			problem("prevalidator unavailable: %v", err)
+		} else {
+			health.Backlog = len(worker.PendingRequests)
+			if health.Backlog > opts.MaxBacklog {
+				problem("prevalidator has %d pending requests", health.Backlog)
+			}
+		}
+	}
+	return health, nil
+}
+
+// healthBootstrapped sets the bootstrap status of health from GET /chains/<chain>/is_bootstrapped,
+// falling back to GET /monitor/bootstrapped on nodes lacking it
+func (rpc *RPC) healthBootstrapped(ctx context.Context, health *Health, timeout time.Duration) error {
+	status := struct {
+		Bootstrapped bool   `json:"bootstrapped"`
+		SyncState    string `json:"sync_state"`
+	}{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/is_bootstrapped", rpc.ChainAlias(ctx)), &status)
+	if err == nil {
+		health.Bootstrapped, health.SyncState = status.Bootstrapped, status.SyncState
+		return nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err := rpc.MonitorBootstrapped(waitCtx); err != nil {
+		if errors.Is(err, ErrNodeNotBootstrapped) && ctx.Err() == nil {
+			return nil
+		}
+		return err
+	}
+	health.Bootstrapped = true
+	return nil
+}
+
+// HealthHandler serves the health of the node as JSON, with status 200 when it is healthy and
+// 503 otherwise, for use as a readiness probe
+func HealthHandler(rpc *RPC, opts HealthOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health, err := rpc.Health(r.Context(), opts)
+		if err != nil {
+			health.Problems = append(health.Problems, fmt.Sprintf("node unreachable: %v", err))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !health.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(health)
+	})
+}
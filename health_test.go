@@ -0,0 +1,60 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestHealth(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header":        map[string]interface{}{"hash": "BLa", "chain_id": "NetXdQprcVkpaWU", "level": 300, "timestamp": time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+		"GET /chains/main/is_bootstrapped":           map[string]interface{}{"bootstrapped": true, "sync_state": "synced"},
+		"GET /network/connections":                   []map[string]interface{}{{"peer_id": "idA"}, {"peer_id": "idB"}},
+		"GET /workers/prevalidators/NetXdQprcVkpaWU": map[string]interface{}{"status": map[string]interface{}{"phase": "running"}, "pending_requests": []interface{}{map[string]interface{}{}, map[string]interface{}{}}},
+	})
+	health, err := client.Health(context.Background(), tgo.HealthOptions{MaxBacklog: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.Healthy() || !health.Bootstrapped || health.SyncState != "synced" || health.Peers != 2 || health.Backlog != 2 {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	health, err = client.Health(context.Background(), tgo.HealthOptions{MaxHeadAge: time.Second, MinPeers: 3, MaxBacklog: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Healthy() || len(health.Problems) != 3 {
+		t.Fatalf("expected head age, peers and backlog problems, got %v", health.Problems)
+	}
+
+	// nodes without is_bootstrapped are checked through the bootstrapped stream
+	node.mu.Lock()
+	delete(node.routes, "GET /chains/main/is_bootstrapped")
+	node.mu.Unlock()
+	node.route("GET /monitor/bootstrapped", rawBody(`{"block":"BLa","timestamp":"2019-09-01T12:00:00Z"}`))
+	if health, err = client.Health(context.Background(), tgo.HealthOptions{}); err != nil || !health.Healthy() {
+		t.Fatalf("unexpected health %+v: %v", health, err)
+	}
+
+	server := httptest.NewServer(tgo.HealthHandler(client, tgo.HealthOptions{MinPeers: 3}))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	served := tgo.Health{}
+	if err := json.NewDecoder(resp.Body).Decode(&served); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || served.Peers != 2 || len(served.Problems) != 1 {
+		t.Fatalf("unexpected readiness response %d %+v", resp.StatusCode, served)
+	}
+}
@@ -0,0 +1,131 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultSamplerConcurrency is how many levels a BalanceSampler reads at once by default
+const defaultSamplerConcurrency = 8
+
+// BalancePoint is the balance of an address at a level, in mutez
+type BalancePoint struct {
+	Level     int64
+	Timestamp time.Time
+	Balance   int64
+}
+
+// BalanceSampler reads the balances of addresses at past levels concurrently, remembering the
+// blocks and balances already read so overlapping ranges are only fetched once
+type BalanceSampler struct {
+	rpc *RPC
+	// Concurrency is how many levels are read at once, 8 by default
+	Concurrency int
+
+	mu       sync.Mutex
+	headers  map[int64]BlockHeader
+	balances map[string]map[int64]int64
+}
+
+// NewBalanceSampler returns a sampler reading blocks and balances through rpc
+func NewBalanceSampler(rpc *RPC) *BalanceSampler {
+	return &BalanceSampler{rpc: rpc, headers: map[int64]BlockHeader{}, balances: map[string]map[int64]int64{}}
+}
+
+// History returns the balance of address every step levels from first to last, last being
+// always sampled. Levels at which a contract did not exist yet have a zero balance.
+func (s *BalanceSampler) History(ctx context.Context, address string, first, last, step int64) ([]BalancePoint, error) {
+	if first > last || first < 0 {
+		return nil, fmt.Errorf("invalid level range %d to %d", first, last)
+	}
+	if step <= 0 {
+		step = 1
+	}
+	levels := []int64{}
+	for level := first; level < last; level += step {
+		levels = append(levels, level)
+	}
+	levels = append(levels, last)
+
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSamplerConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	points := make([]BalancePoint, len(levels))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < concurrency && i < len(levels); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				point, err := s.sample(ctx, address, levels[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				points[i] = point
+			}
+		}()
+	}
+feed:
+	for i := range levels {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// sample returns the balance of address at level, from the cache when possible
+func (s *BalanceSampler) sample(ctx context.Context, address string, level int64) (BalancePoint, error) {
+	s.mu.Lock()
+	header, knownHeader := s.headers[level]
+	balance, knownBalance := s.balances[address][level]
+	s.mu.Unlock()
+	if !knownHeader {
+		var err error
+		if header, err = s.rpc.GetBlockHeader(ctx, LevelID(level)); err != nil {
+			return BalancePoint{}, err
+		}
+	}
+	if !knownBalance {
+		var err error
+		balance, err = s.rpc.GetBalance(ctx, header.Hash.ID(), address)
+		if errors.Is(err, ErrNotFound) {
+			// the contract was not originated yet
+			balance, err = 0, nil
+		}
+		if err != nil {
+			return BalancePoint{}, err
+		}
+	}
+	s.mu.Lock()
+	s.headers[level] = header
+	if s.balances[address] == nil {
+		s.balances[address] = map[int64]int64{}
+	}
+	s.balances[address][level] = balance
+	s.mu.Unlock()
+	return BalancePoint{Level: level, Timestamp: header.Timestamp.Time, Balance: balance}, nil
+}
@@ -0,0 +1,57 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestBalanceHistory(t *testing.T) {
+	routes := map[string]interface{}{}
+	for level := int64(10); level <= 14; level++ {
+		timestamp := time.Date(2020, 1, 1, 0, int(level), 0, 0, time.UTC).Format(time.RFC3339)
+		routes[fmt.Sprintf("GET /chains/main/blocks/%d/header", level)] = map[string]interface{}{"level": level, "hash": fmt.Sprintf("BL%d", level), "timestamp": timestamp}
+		// the contract is originated at level 12
+		if level >= 12 {
+			routes[fmt.Sprintf("GET /chains/main/blocks/BL%d/context/contracts/KT1contract/balance", level)] = fmt.Sprint(level * 100)
+		}
+	}
+	node, client := newFakeNode(t, routes)
+	sampler := tgo.NewBalanceSampler(client)
+	sampler.Concurrency = 2
+	history, err := sampler.History(context.Background(), "KT1contract", 10, 14, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tgo.BalancePoint{
+		{Level: 10, Timestamp: time.Date(2020, 1, 1, 0, 10, 0, 0, time.UTC), Balance: 0},
+		{Level: 13, Timestamp: time.Date(2020, 1, 1, 0, 13, 0, 0, time.UTC), Balance: 1300},
+		{Level: 14, Timestamp: time.Date(2020, 1, 1, 0, 14, 0, 0, time.UTC), Balance: 1400},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("unexpected history %+v", history)
+	}
+	for i := range expected {
+		if history[i].Level != expected[i].Level || !history[i].Timestamp.Equal(expected[i].Timestamp) || history[i].Balance != expected[i].Balance {
+			t.Fatalf("expected %+v got %+v", expected[i], history[i])
+		}
+	}
+
+	// sampled levels are served from the cache
+	if _, err := sampler.History(context.Background(), "KT1contract", 13, 14, 1); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	requests := len(node.queries["GET /chains/main/blocks/13/header"]) + len(node.queries["GET /chains/main/blocks/BL14/context/contracts/KT1contract/balance"])
+	node.mu.Unlock()
+	if requests != 2 {
+		t.Fatalf("expected cached samples got %d requests", requests)
+	}
+
+	if _, err := sampler.History(context.Background(), "KT1contract", 14, 16, 1); err == nil {
+		t.Fatal("expected error for a level beyond head")
+	}
+}
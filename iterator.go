@@ -0,0 +1,97 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// blockRetries is how many times ForEachBlock retries fetching a block after a transient failure
+const blockRetries = 3
+
+// blockResult is the outcome of fetching one block
+type blockResult struct {
+	block Block
+	err   error
+}
+
+// ForEachBlock fetches the blocks from level from to level to, up to workers at once, and
+// calls fn with each of them in increasing level order. Transient failures, network errors
+// and 5xx responses, are retried every PollInterval. Iteration stops at the first error
+// returned by fn or fetching a block, which is returned.
+func (rpc *RPC) ForEachBlock(ctx context.Context, from, to int64, workers int, fn func(Block) error) error {
+	if from > to {
+		return fmt.Errorf("invalid level range %d to %d", from, to)
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// pending holds the results in level order, its capacity bounds how far fetching runs
+	// ahead of fn
+	pending := make(chan chan blockResult, workers)
+	slots := make(chan struct{}, workers)
+	go func() {
+		defer close(pending)
+		for level := from; level <= to; level++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			result := make(chan blockResult, 1)
+			go func(level int64) {
+				defer func() { <-slots }()
+				block, err := rpc.getBlockRetrying(ctx, level)
+				result <- blockResult{block, err}
+			}(level)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for result := range pending {
+		r := <-result
+		if r.err != nil {
+			return r.err
+		}
+		if err := fn(r.block); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// getBlockRetrying fetches the block at level, retrying transient failures
+func (rpc *RPC) getBlockRetrying(ctx context.Context, level int64) (Block, error) {
+	for attempt := 0; ; attempt++ {
+		block, err := rpc.GetBlock(ctx, LevelID(level))
+		if err == nil || attempt == blockRetries || !transient(err) {
+			return block, err
+		}
+		select {
+		case <-ctx.Done():
+			return Block{}, ctx.Err()
+		case <-time.After(rpc.PollInterval):
+		}
+	}
+}
+
+// transient reports whether a request failing with err may succeed when retried, only network
+// errors and 5xx responses are, decoding errors and cancellations are not
+func transient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,99 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestForEachBlock(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var level int64
+		if _, err := fmt.Sscanf(r.URL.Path, "/chains/main/blocks/%d", &level); err != nil || (level > 8 && level != 20) {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		hits[r.URL.Path]++
+		first := hits[r.URL.Path] == 1
+		mu.Unlock()
+		if level == 20 {
+			w.Write([]byte(`{"hash":`))
+			return
+		}
+		if level == 3 && first {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		// later levels answer first
+		time.Sleep(time.Duration(10-level) * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"hash":   fmt.Sprintf("BL%d", level),
+			"header": map[string]interface{}{"level": level},
+		})
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	client.PollInterval = time.Millisecond
+
+	levels := []int64{}
+	err := client.ForEachBlock(context.Background(), 1, 8, 3, func(b tgo.Block) error {
+		if string(b.Header.Hash) != fmt.Sprintf("BL%d", b.Header.Level) {
+			t.Fatalf("unexpected block %+v", b)
+		}
+		levels = append(levels, b.Header.Level)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(levels) != "[1 2 3 4 5 6 7 8]" {
+		t.Fatalf("unexpected order %v", levels)
+	}
+	mu.Lock()
+	retries := hits["/chains/main/blocks/3"]
+	mu.Unlock()
+	if retries != 2 {
+		t.Fatalf("expected the transient failure to be retried once got %d requests", retries)
+	}
+
+	stop := errors.New("stop")
+	levels = nil
+	err = client.ForEachBlock(context.Background(), 1, 8, 3, func(b tgo.Block) error {
+		levels = append(levels, b.Header.Level)
+		if b.Header.Level == 4 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(levels) != 4 {
+		t.Fatalf("expected iteration to stop at level 4 got %v, %v", levels, err)
+	}
+
+	// missing blocks are not retried
+	err = client.ForEachBlock(context.Background(), 7, 10, 2, func(tgo.Block) error { return nil })
+	if statusErr, ok := err.(*tgo.StatusError); !ok || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected not found error got %v", err)
+	}
+
+	// malformed blocks are not retried either
+	if err = client.ForEachBlock(context.Background(), 20, 20, 1, func(tgo.Block) error { return nil }); err == nil {
+		t.Fatal("expected a decoding error")
+	}
+	mu.Lock()
+	retries = hits["/chains/main/blocks/20"]
+	mu.Unlock()
+	if retries != 1 {
+		t.Fatalf("expected the malformed block not to be retried got %d requests", retries)
+	}
+}
@@ -0,0 +1,243 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// PeerAction is what a PeerJanitor does to the peers breaking its policy
+type PeerAction string
+
+// Actions of a PeerJanitor, a disconnected peer may connect again while a banned peer is
+// blacklisted until its ban is lifted with SetPeerACL
+const (
+	PeerDisconnect PeerAction = "disconnect"
+	PeerBan        PeerAction = "ban"
+)
+
+// default policy settings
+const (
+	defaultDisconnectWindow = time.Hour
+)
+
+// PeerPolicy sets which peers a PeerJanitor acts upon, zero values disable a rule. Trusted
+// peers are never acted upon.
+type PeerPolicy struct {
+	// MinScore is the score below which peers are acted upon, unchecked when 0
+	MinScore int64
+	// MaxDisconnects is how many disconnections of a peer reported by /network/log are
+	// tolerated within DisconnectWindow, unchecked when 0
+	MaxDisconnects int
+	// DisconnectWindow is how far back disconnections are counted, 1 hour by default
+	DisconnectWindow time.Duration
+	// MaxSilence is how long ago peers may have last been seen, unchecked when 0
+	MaxSilence time.Duration
+	// Action is taken on the peers breaking the policy, PeerDisconnect by default, which
+	// only applies to running peers
+	Action PeerAction
+	// Exempt lists peers never acted upon on top of the trusted ones
+	Exempt []PeerID
+}
+
+// PeerVerdict is a peer breaking the policy of a PeerJanitor and the action taken
+type PeerVerdict struct {
+	Time    time.Time  `json:"time"`
+	PeerID  PeerID     `json:"peer_id"`
+	Action  PeerAction `json:"action"`
+	Reasons []string   `json:"reasons"`
+	// DryRun is set when the action was only reported
+	DryRun bool `json:"dry_run,omitempty"`
+	// Error is the failure of the action if any
+	Error string `json:"error,omitempty"`
+}
+
+// PeerJanitor periodically inspects the peers of the node and disconnects or bans those
+// breaking its policy, those scoring too low, disconnecting too often or gone silent
+type PeerJanitor struct {
+	rpc    *RPC
+	Policy PeerPolicy
+	// Interval is how often peers are inspected, PollInterval of the client by default
+	Interval time.Duration
+	// DryRun reports the verdicts without acting upon them
+	DryRun bool
+	// Audit receives every verdict as a line of JSON when set
+	Audit io.Writer
+
+	mu          sync.Mutex
+	disconnects map[PeerID][]time.Time
+	banned      map[PeerID]bool
+}
+
+// NewPeerJanitor returns a janitor of the peers of the node behind rpc enforcing policy
+func NewPeerJanitor(rpc *RPC, policy PeerPolicy) *PeerJanitor {
+	return &PeerJanitor{rpc: rpc, Policy: policy, disconnects: map[PeerID][]time.Time{}, banned: map[PeerID]bool{}}
+}
+
+// Run sweeps the peers every interval until ctx is cancelled, counting the disconnections
+// reported by /network/log in between when the policy limits them. Both channels are closed
+// once ctx is cancelled, errs receives the failures of sweeps, as described in the package
+// documentation, and sweeping goes on. The error that stopped the network log is never dropped,
+// disconnections are no longer counted then.
+func (j *PeerJanitor) Run(ctx context.Context) (<-chan PeerVerdict, <-chan error) {
+	verdicts := make(chan PeerVerdict)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+	if j.Policy.MaxDisconnects > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			events, logErrs := j.rpc.MonitorNetworkLog(ctx)
+			for event := range events {
+				j.Observe(event)
+			}
+			if err := <-logErrs; err != nil {
+				errs <- err
+			}
+		}()
+	}
+	go func() {
+		defer close(errs)
+		defer close(verdicts)
+		defer wg.Wait()
+		interval := j.Interval
+		if interval <= 0 {
+			interval = j.rpc.PollInterval
+		}
+		for {
+			found, err := j.Sweep(ctx)
+			if err != nil {
+				reportError(errs, err)
+			}
+			for _, v := range found {
+				select {
+				case verdicts <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return verdicts, errs
+}
+
+// Observe counts the disconnection reported by event, for janitors fed with the network log
+// by the caller instead of Run
+func (j *PeerJanitor) Observe(event NetworkEvent) {
+	if event.PeerID == "" || churnKinds[event.Event] != ChurnDisconnection {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.disconnects[event.PeerID] = append(j.disconnects[event.PeerID], time.Now())
+}
+
+// recentDisconnects returns how many disconnections of peer were observed within the window,
+// forgetting the older ones
+func (j *PeerJanitor) recentDisconnects(peer PeerID, window time.Duration) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	times := j.disconnects[peer]
+	for len(times) > 0 && time.Since(times[0]) > window {
+		times = times[1:]
+	}
+	if len(times) == 0 {
+		delete(j.disconnects, peer)
+	} else {
+		j.disconnects[peer] = times
+	}
+	return len(times)
+}
+
+// Sweep inspects the peers once and acts upon those breaking the policy, unless DryRun is set.
+// Peers already banned by the janitor, or reported as such in a dry run, are not reported
+// again. The failures of actions are reported in the verdicts, an error is only returned when
+// the peers cannot be listed.
+func (j *PeerJanitor) Sweep(ctx context.Context) ([]PeerVerdict, error) {
+	policy := j.Policy
+	if policy.Action == "" {
+		policy.Action = PeerDisconnect
+	}
+	if policy.DisconnectWindow <= 0 {
+		policy.DisconnectWindow = defaultDisconnectWindow
+	}
+	var states []string
+	if policy.Action == PeerDisconnect {
+		states = []string{"running"}
+	}
+	peers, err := j.rpc.GetPeers(ctx, states...)
+	if err != nil {
+		return nil, err
+	}
+	exempt := make(map[PeerID]bool, len(policy.Exempt))
+	for _, p := range policy.Exempt {
+		exempt[p] = true
+	}
+
+	verdicts := []PeerVerdict{}
+	for _, peer := range peers {
+		if peer.Trusted || exempt[peer.ID] {
+			continue
+		}
+		var reasons []string
+		if policy.MinScore != 0 && int64(peer.Score) < policy.MinScore {
+			reasons = append(reasons, fmt.Sprintf("score %d below %d", peer.Score, policy.MinScore))
+		}
+		if policy.MaxDisconnects > 0 {
+			if n := j.recentDisconnects(peer.ID, policy.DisconnectWindow); n > policy.MaxDisconnects {
+				reasons = append(reasons, fmt.Sprintf("%d disconnections within %s", n, policy.DisconnectWindow))
+			}
+		}
+		if seen := peer.LastSeen.Timestamp; policy.MaxSilence > 0 && !seen.IsZero() && time.Since(seen.Time) > policy.MaxSilence {
+			reasons = append(reasons, fmt.Sprintf("last seen %s ago", time.Since(seen.Time).Round(time.Second)))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		j.mu.Lock()
+		banned := j.banned[peer.ID]
+		j.mu.Unlock()
+		if policy.Action == PeerBan && banned {
+			continue
+		}
+
+		verdict := PeerVerdict{Time: time.Now(), PeerID: peer.ID, Action: policy.Action, Reasons: reasons, DryRun: j.DryRun}
+		if !j.DryRun {
+			if err := j.act(peer.ID, policy.Action); err != nil {
+				verdict.Error = err.Error()
+			}
+		}
+		if policy.Action == PeerBan && verdict.Error == "" {
+			j.mu.Lock()
+			j.banned[peer.ID] = true
+			j.mu.Unlock()
+		}
+		if j.Audit != nil {
+			if b, err := json.Marshal(verdict); err == nil {
+				j.Audit.Write(append(b, '\n'))
+			}
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts, nil
+}
+
+// act takes action on peer
+func (j *PeerJanitor) act(peer PeerID, action PeerAction) error {
+	switch action {
+	case PeerDisconnect:
+		return j.rpc.RemovePeer(peer, false)
+	case PeerBan:
+		return j.rpc.SetPeerACL(peer, ACLBan)
+	default:
+		return fmt.Errorf("unknown peer action %q", action)
+	}
+}
@@ -0,0 +1,104 @@
+package tgo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestPeerJanitor(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/peers": rawBody(`[
+			["idtLow", {"score":-10,"trusted":false,"state":"running"}],
+			["idtTrusted", {"score":-10,"trusted":true,"state":"running"}],
+			["idtFlaky", {"score":5,"state":"running"}],
+			["idtSilent", {"score":5,"state":"running","last_seen":{"addr":"1.2.3.4","port":9732,"Timestamp":"2019-09-01T12:00:00Z"}}]
+		]`),
+		"DELETE /network/connections/idtLow":    rawBody(`{}`),
+		"DELETE /network/connections/idtSilent": rawBody(`{}`),
+		"PATCH /network/peers/idtLow":           rawBody(`{}`),
+	})
+	janitor := tgo.NewPeerJanitor(client, tgo.PeerPolicy{MinScore: -5, MaxDisconnects: 1, MaxSilence: time.Hour})
+	audit := &bytes.Buffer{}
+	janitor.Audit = audit
+	for i := 0; i < 2; i++ {
+		janitor.Observe(tgo.NetworkEvent{Event: "disconnection", PeerID: "idtFlaky"})
+	}
+	janitor.Observe(tgo.NetworkEvent{Event: "connection_established", PeerID: "idtFlaky"})
+
+	verdicts, err := janitor.Sweep(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verdicts) != 3 || verdicts[0].PeerID != "idtLow" || verdicts[1].PeerID != "idtFlaky" || verdicts[2].PeerID != "idtSilent" {
+		t.Fatalf("unexpected verdicts %+v", verdicts)
+	}
+	// the flaky peer cannot be disconnected by the node, which is reported in its verdict
+	if verdicts[0].Error != "" || verdicts[1].Error == "" || !strings.HasPrefix(verdicts[2].Reasons[0], "last seen") {
+		t.Fatalf("unexpected verdicts %+v", verdicts)
+	}
+	if lines := strings.Split(strings.TrimSpace(audit.String()), "\n"); len(lines) != 3 || !strings.Contains(lines[0], `"reasons":["score -10 below -5"]`) {
+		t.Fatalf("unexpected audit log %s", audit)
+	}
+	node.mu.Lock()
+	if q := node.queries["GET /network/peers"]; len(q) != 1 || q[0] != "filter=running" {
+		t.Fatalf("unexpected queries %v", q)
+	}
+	node.mu.Unlock()
+
+	// banned peers are reported once and dry runs leave the peers alone
+	janitor.Policy.Action = tgo.PeerBan
+	janitor.Policy.MaxDisconnects, janitor.Policy.MaxSilence = 0, 0
+	if verdicts, err := janitor.Sweep(context.Background()); err != nil || len(verdicts) != 1 || verdicts[0].Error != "" {
+		t.Fatalf("unexpected verdicts %+v: %v", verdicts, err)
+	}
+	if verdicts, err := janitor.Sweep(context.Background()); err != nil || len(verdicts) != 0 {
+		t.Fatalf("unexpected verdicts %+v: %v", verdicts, err)
+	}
+	janitor.Policy.Action = tgo.PeerDisconnect
+	janitor.DryRun = true
+	if verdicts, err := janitor.Sweep(context.Background()); err != nil || len(verdicts) != 1 || !verdicts[0].DryRun {
+		t.Fatalf("unexpected verdicts %+v: %v", verdicts, err)
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if n := len(node.bodies["DELETE /network/connections/idtLow"]); n != 1 {
+		t.Fatalf("expected the peer to be disconnected once, got %d", n)
+	}
+	if n := len(node.bodies["PATCH /network/peers/idtLow"]); n != 1 {
+		t.Fatalf("expected the peer to be banned once, got %d", n)
+	}
+}
+
+func TestPeerJanitorRunLogFailure(t *testing.T) {
+	// sweeps keep failing while the network log is missing
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/peers": rawBody(`{`),
+	})
+	janitor := tgo.NewPeerJanitor(client, tgo.PeerPolicy{MaxDisconnects: 1})
+	janitor.Interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	verdicts, errs := janitor.Run(ctx)
+	go func() {
+		for range verdicts {
+		}
+	}()
+	timeout := time.After(5 * time.Second)
+	for logFailed := false; !logFailed; {
+		select {
+		case err := <-errs:
+			var statusErr *tgo.StatusError
+			logFailed = errors.As(err, &statusErr)
+		case <-timeout:
+			t.Fatal("expected the failure of the network log not to be dropped")
+		}
+	}
+	cancel()
+	for range errs {
+	}
+}
@@ -0,0 +1,75 @@
+package tgo
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+)
+
+// Signer is anything able to sign forged operations on behalf of an implicit account
+type Signer interface {
+	// PublicKeyHash returns the tz address of the signer
+	PublicKeyHash() string
+	// PublicKey returns the base58 encoded public key of the signer
+	PublicKey() string
+	// Sign signs the watermarked bytes and returns the base58 encoded signature
+	Sign(message []byte) (string, error)
+}
+
+// Key is an in-memory ed25519 signer
+type Key struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewKeyFromSecret parses an unencrypted edsk secret key, either as a 32 byte seed or a 64 byte secret key
+func NewKeyFromSecret(secret string) (*Key, error) {
+	if !strings.HasPrefix(secret, "edsk") {
+		return nil, fmt.Errorf("unsupported secret key %s, only edsk keys are supported", secret)
+	}
+	if seed, err := b58CheckDecode(secret, prefixEdskSeed); err == nil && len(seed) == ed25519.SeedSize {
+		return &Key{privateKey: ed25519.NewKeyFromSeed(seed)}, nil
+	}
+	sk, err := b58CheckDecode(secret, prefixEdsk)
+	if err != nil {
+		return nil, err
+	}
+	if len(sk) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid secret key length %d", len(sk))
+	}
+	return &Key{privateKey: ed25519.NewKeyFromSeed(sk[:ed25519.SeedSize])}, nil
+}
+
+// PublicKeyHash returns the tz1 address of the key
+func (k *Key) PublicKeyHash() string {
+	pk := k.privateKey.Public().(ed25519.PublicKey)
+	return b58CheckEncode(prefixTz1, blake2b(pk, 20))
+}
+
+// PublicKey returns the edpk encoded public key
+func (k *Key) PublicKey() string {
+	pk := k.privateKey.Public().(ed25519.PublicKey)
+	return b58CheckEncode(prefixEdpk, pk)
+}
+
+// Sign signs the blake2b digest of message and returns an edsig signature
+func (k *Key) Sign(message []byte) (string, error) {
+	sig := ed25519.Sign(k.privateKey, blake2b(message, 32))
+	return b58CheckEncode(prefixEdsig, sig), nil
+}
+
+// verifySignature checks a signature of message by the edpk public key, the signature being
+// either an edsig or the generic sig the node returns for operations it relays
+func verifySignature(publicKey, signature string, message []byte) (bool, error) {
+	pk, err := b58CheckDecode(publicKey, prefixEdpk)
+	if err != nil {
+		return false, fmt.Errorf("unsupported public key %s: %w", publicKey, err)
+	}
+	if len(pk) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid public key length %d", len(pk))
+	}
+	sig, err := encodeSignature(signature)
+	if err != nil {
+		return false, err
+	}
+	return ed25519.Verify(pk, blake2b(message, 32), sig), nil
+}
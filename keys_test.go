@@ -0,0 +1,23 @@
+package tgo_test
+
+import (
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestNewKeyFromSecret(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.PublicKey() != "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav" {
+		t.Fatalf("unexpected public key %s", key.PublicKey())
+	}
+	if key.PublicKeyHash() != "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" {
+		t.Fatalf("unexpected public key hash %s", key.PublicKeyHash())
+	}
+	if _, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsi"); err == nil {
+		t.Fatal("expected checksum error")
+	}
+}
@@ -0,0 +1,68 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Keystore reads the keys saved by tezos-client in the secret_keys file of its base
+// directory, e.g. ~/.tezos-client
+type Keystore struct {
+	// secretKeys are the secret keys or their locations by alias
+	secretKeys map[string]string
+}
+
+// OpenKeystore reads the secret keys of the tezos-client base directory dir
+func OpenKeystore(dir string) (*Keystore, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "secret_keys"))
+	if err != nil {
+		return nil, err
+	}
+	entries := []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}{}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("invalid secret keys in %s: %w", dir, err)
+	}
+	k := &Keystore{secretKeys: map[string]string{}}
+	for _, e := range entries {
+		k.secretKeys[e.Name] = e.Value
+	}
+	return k, nil
+}
+
+// Aliases returns the aliases of the keys in the keystore, sorted
+func (k *Keystore) Aliases() []string {
+	aliases := make([]string, 0, len(k.secretKeys))
+	for alias := range k.secretKeys {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Signer returns the signer of the key saved as alias. Unencrypted keys are signed with in
+// memory, keys held by a remote signer over HTTP through it. Encrypted keys are not supported.
+func (k *Keystore) Signer(ctx context.Context, alias string) (Signer, error) {
+	value, ok := k.secretKeys[alias]
+	if !ok {
+		return nil, fmt.Errorf("no key named %s", alias)
+	}
+	switch {
+	case strings.HasPrefix(value, "unencrypted:"):
+		return NewKeyFromSecret(strings.TrimPrefix(value, "unencrypted:"))
+	case strings.HasPrefix(value, "http://"), strings.HasPrefix(value, "https://"):
+		// remote keys are located by the URL of the signer followed by the address
+		slash := strings.LastIndex(value, "/")
+		return NewRemoteSigner(ctx, value[:slash], value[slash+1:])
+	case strings.HasPrefix(value, "encrypted:"):
+		return nil, fmt.Errorf("key %s is encrypted, which is not supported, use a remote signer", alias)
+	}
+	return nil, fmt.Errorf("unsupported location of key %s: %s", alias, strings.SplitN(value, ":", 2)[0])
+}
@@ -0,0 +1,106 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+const bootstrap1 = "edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh"
+
+func TestKeystore(t *testing.T) {
+	key, err := tgo.NewKeyFromSecret(bootstrap1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// signer serves the key of bootstrap1 as tezos-signer does
+	signer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/keys/"+key.PublicKeyHash() {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(map[string]string{"public_key": key.PublicKey()})
+			return
+		}
+		var message string
+		json.NewDecoder(r.Body).Decode(&message)
+		b, _ := hex.DecodeString(message)
+		signature, _ := key.Sign(b)
+		json.NewEncoder(w).Encode(map[string]string{"signature": signature})
+	}))
+	defer signer.Close()
+
+	dir := t.TempDir()
+	secretKeys := fmt.Sprintf(`[{"name":"alice","value":"unencrypted:%s"},{"name":"bob","value":"%s/%s"},{"name":"carol","value":"encrypted:edesk1"}]`,
+		bootstrap1, signer.URL, key.PublicKeyHash())
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret_keys"), []byte(secretKeys), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keystore, err := tgo.OpenKeystore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aliases := keystore.Aliases(); !reflect.DeepEqual(aliases, []string{"alice", "bob", "carol"}) {
+		t.Fatalf("unexpected aliases %v", aliases)
+	}
+	ctx := context.Background()
+	expected, _ := key.Sign([]byte{3, 1, 2})
+	for _, alias := range []string{"alice", "bob"} {
+		s, err := keystore.Signer(ctx, alias)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := s.Sign([]byte{3, 1, 2})
+		if err != nil || signature != expected || s.PublicKeyHash() != key.PublicKeyHash() || s.PublicKey() != key.PublicKey() {
+			t.Fatalf("%s: unexpected signer %s %s: %v", alias, s.PublicKeyHash(), signature, err)
+		}
+	}
+	for _, alias := range []string{"carol", "dave"} {
+		if _, err := keystore.Signer(ctx, alias); err == nil {
+			t.Fatalf("expected error for %s", alias)
+		}
+	}
+	if _, err := tgo.NewRemoteSigner(ctx, signer.URL, "tz1Wpefz7KdEkVf2hXGMRKYymVjML9Zpi1r7"); err == nil {
+		t.Fatal("expected error for a key unknown to the signer")
+	}
+}
+
+func TestSignOperationRemoteSigner(t *testing.T) {
+	raw := strings.Repeat("01", 64)
+	for _, signature := range []string{
+		"spsig15wbDdvxnr9unLT3q5LjePYyQs1r1x88gdPQ3C71WpJ3L5axTsyitCZp8Gtx4vcpnhNTFHXPvUJDsWosVBDSjWjNYHTpZN",
+		"p2sigMS8jfRGQy7os6i47eP7mzLNtRtWCyurRW2veoQ918pcSZCvbgxSAMGNj6GPExkY2bepXPuhpq6p9cnghtz9FZtbwgy5Tn",
+	} {
+		// signer holds a tz2 or tz3 key, signing with secp256k1 or p256
+		signer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				json.NewEncoder(w).Encode(map[string]string{"public_key": "sppk7bMuoa8w2LSKz3XEuPsKx1WavsMLCWgbWG9CZNAsJg9eTmkXRPd"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"signature": signature})
+		}))
+		remote, err := tgo.NewRemoteSigner(context.Background(), signer.URL, "tz2BFTyPeYRzxd5aiBchbXN3WCZhx7BqbMBq")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, signed, err := tgo.SignOperation(remote, "abcd")
+		signer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig != signature || signed != "abcd"+raw {
+			t.Fatalf("unexpected signed operation %s %s", sig, signed)
+		}
+	}
+}
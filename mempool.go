@@ -0,0 +1,153 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// MempoolOperation is an operation waiting in the mempool of a node
+type MempoolOperation struct {
+	Hash      OperationHash       `json:"hash"`
+	Protocol  ProtocolHash        `json:"protocol,omitempty"`
+	Branch    BlockHash           `json:"branch"`
+	Contents  []OperationContents `json:"contents"`
+	Signature string              `json:"signature,omitempty"`
+	// Error holds the reasons the operation was refused or delayed
+	Error []json.RawMessage `json:"error,omitempty"`
+}
+
+// PendingOperations holds the response from `GET /chains/<chain>/mempool/pending_operations`
+type PendingOperations struct {
+	Applied       []MempoolOperation
+	Refused       []MempoolOperation
+	BranchRefused []MempoolOperation
+	BranchDelayed []MempoolOperation
+	Unprocessed   []MempoolOperation
+}
+
+// UnmarshalJSON decodes the mempool buckets, all of them except applied are lists of
+// [hash, operation] pairs
+func (p *PendingOperations) UnmarshalJSON(b []byte) error {
+	raw := struct {
+		Applied       []MempoolOperation  `json:"applied"`
+		Refused       []mempoolHashedPair `json:"refused"`
+		BranchRefused []mempoolHashedPair `json:"branch_refused"`
+		BranchDelayed []mempoolHashedPair `json:"branch_delayed"`
+		Unprocessed   []mempoolHashedPair `json:"unprocessed"`
+	}{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	p.Applied = raw.Applied
+	p.Refused = unpairMempoolOperations(raw.Refused)
+	p.BranchRefused = unpairMempoolOperations(raw.BranchRefused)
+	p.BranchDelayed = unpairMempoolOperations(raw.BranchDelayed)
+	p.Unprocessed = unpairMempoolOperations(raw.Unprocessed)
+	return nil
+}
+
+// mempoolHashedPair is a [hash, operation] pair
+type mempoolHashedPair MempoolOperation
+
+func (m *mempoolHashedPair) UnmarshalJSON(b []byte) error {
+	pair := []json.RawMessage{}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected [hash, operation] pair got %s", b)
+	}
+	op := MempoolOperation{}
+	if err := json.Unmarshal(pair[1], &op); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(pair[0], &op.Hash); err != nil {
+		return err
+	}
+	*m = mempoolHashedPair(op)
+	return nil
+}
+
+func unpairMempoolOperations(pairs []mempoolHashedPair) []MempoolOperation {
+	ops := make([]MempoolOperation, len(pairs))
+	for i, p := range pairs {
+		ops[i] = MempoolOperation(p)
+	}
+	return ops
+}
+
+// GetMempoolPendingOperations calls GET /chains/<chain>/mempool/pending_operations
+func (rpc *RPC) GetMempoolPendingOperations(ctx context.Context, chain string) (PendingOperations, error) {
+	pending := PendingOperations{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/mempool/pending_operations", chain), &pending)
+	return pending, err
+}
+
+// MempoolFilter holds the fee thresholds applied by the prevalidator, the per gas unit
+// and per byte minimums are rationals given as [numerator, denominator]
+type MempoolFilter struct {
+	MinimalFees              int64     `json:"minimal_fees,string"`
+	MinimalNanotezPerGasUnit [2]string `json:"minimal_nanotez_per_gas_unit"`
+	MinimalNanotezPerByte    [2]string `json:"minimal_nanotez_per_byte"`
+	AllowScriptFailure       bool      `json:"allow_script_failure"`
+}
+
+// GetMempoolFilter calls GET /chains/<chain>/mempool/filter
+func (rpc *RPC) GetMempoolFilter(ctx context.Context, chain string) (MempoolFilter, error) {
+	filter := MempoolFilter{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/mempool/filter", chain), &filter)
+	return filter, err
+}
+
+// SetMempoolFilter calls POST /chains/<chain>/mempool/filter
+func (rpc *RPC) SetMempoolFilter(ctx context.Context, chain string, filter MempoolFilter) error {
+	return rpc.post(ctx, fmt.Sprintf("/chains/%s/mempool/filter", chain), filter, nil)
+}
+
+// MempoolMonitorOptions selects the mempool classifications streamed by MonitorMempoolOperations
+type MempoolMonitorOptions struct {
+	Applied       bool
+	Refused       bool
+	BranchRefused bool
+	BranchDelayed bool
+}
+
+// MonitorMempoolOperations calls GET /chains/<chain>/mempool/monitor_operations and streams
+// operations as they enter the mempool. The node ends the stream on every new head, it is
+// reopened until ctx is cancelled. Both channels are closed once streaming stops, errs
+// receives the error that stopped it if any.
+func (rpc *RPC) MonitorMempoolOperations(ctx context.Context, chain string, opts MempoolMonitorOptions) (<-chan MempoolOperation, <-chan error) {
+	ops := make(chan MempoolOperation)
+	errs := make(chan error, 1)
+	path := query{}.
+		addBool("applied", opts.Applied).
+		addBool("refused", opts.Refused).
+		addBool("branch_refused", opts.BranchRefused).
+		addBool("branch_delayed", opts.BranchDelayed).
+		path(fmt.Sprintf("/chains/%s/mempool/monitor_operations", chain))
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(ops)
+		defer close(errs)
+		err := rpc.monitor(ctx, path, func(decoder *json.Decoder) error {
+			batch := []MempoolOperation{}
+			if err := decoder.Decode(&batch); err != nil {
+				return err
+			}
+			for _, op := range batch {
+				select {
+				case ops <- op:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return ops, errs
+}
@@ -0,0 +1,92 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestGetMempoolPendingOperations(t *testing.T) {
+	pending := json.RawMessage(`{
+		"applied": [{"hash": "ooApplied", "branch": "BLock", "contents": [{"kind": "transaction", "amount": "10"}], "signature": "sigA"}],
+		"refused": [["ooRefused", {"protocol": "Pt", "branch": "BLock", "contents": [{"kind": "delegation"}], "signature": "sigR", "error": [{"kind": "temporary", "id": "proto.counter_in_the_past"}]}]],
+		"branch_refused": [],
+		"branch_delayed": [["ooDelayed", {"protocol": "Pt", "branch": "BLock", "contents": [], "error": []}]],
+		"unprocessed": [["ooUnprocessed", {"protocol": "Pt", "branch": "BLock", "contents": []}]]
+	}`)
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/mempool/pending_operations": pending,
+	})
+	ops, err := client.GetMempoolPendingOperations(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops.Applied) != 1 || ops.Applied[0].Hash != "ooApplied" || ops.Applied[0].Contents[0].Amount != "10" {
+		t.Fatalf("unexpected applied %+v", ops.Applied)
+	}
+	if len(ops.Refused) != 1 || ops.Refused[0].Hash != "ooRefused" || len(ops.Refused[0].Error) != 1 {
+		t.Fatalf("unexpected refused %+v", ops.Refused)
+	}
+	if len(ops.BranchRefused) != 0 || len(ops.BranchDelayed) != 1 || ops.Unprocessed[0].Hash != "ooUnprocessed" {
+		t.Fatalf("unexpected buckets %+v", ops)
+	}
+}
+
+func TestMonitorMempoolOperations(t *testing.T) {
+	streams := 0
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/mempool/monitor_operations": func([]byte) interface{} {
+			streams++
+			return []tgo.MempoolOperation{{Hash: tgo.OperationHash(fmt.Sprintf("oo%d", streams))}}
+		},
+	})
+	client.PollInterval = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	ops, errs := client.MonitorMempoolOperations(ctx, "main", tgo.MempoolMonitorOptions{Applied: true, BranchDelayed: true})
+	for _, expected := range []string{"oo1", "oo2", "oo3"} {
+		if op := <-ops; string(op.Hash) != expected {
+			t.Fatalf("expected %s got %s", expected, op.Hash)
+		}
+	}
+	// the stream ended by the node is reopened PollInterval later
+	if elapsed := time.Since(start); elapsed < 2*client.PollInterval {
+		t.Fatalf("expected the stream to be reopened after PollInterval, took %s", elapsed)
+	}
+	cancel()
+	for range ops {
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if q := node.queries["GET /chains/main/mempool/monitor_operations"][0]; q != "applied=true&branch_delayed=true&branch_refused=false&refused=false" {
+		t.Fatalf("unexpected query %s", q)
+	}
+}
+
+func TestMempoolFilter(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/mempool/filter":  json.RawMessage(`{"minimal_fees":"100","minimal_nanotez_per_gas_unit":["100","1"],"minimal_nanotez_per_byte":["1000","1"],"allow_script_failure":true}`),
+		"POST /chains/main/mempool/filter": map[string]interface{}{},
+	})
+	filter, err := client.GetMempoolFilter(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.MinimalFees != 100 || filter.MinimalNanotezPerGasUnit != [2]string{"100", "1"} || !filter.AllowScriptFailure {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	filter.MinimalFees = 0
+	if err := client.SetMempoolFilter(context.Background(), "main", filter); err != nil {
+		t.Fatal(err)
+	}
+	if body := node.bodies["POST /chains/main/mempool/filter"][0]; body != `{"minimal_fees":"0","minimal_nanotez_per_gas_unit":["100","1"],"minimal_nanotez_per_byte":["1000","1"],"allow_script_failure":true}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+}
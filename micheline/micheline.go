@@ -0,0 +1,250 @@
+// Package micheline models Micheline expressions, the generic syntax of Michelson code and data
+package micheline
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Type is the kind of a Micheline node
+type Type int
+
+// Micheline node types
+const (
+	IntNode Type = iota
+	StringNode
+	BytesNode
+	PrimNode
+	SeqNode
+)
+
+func (t Type) String() string {
+	switch t {
+	case IntNode:
+		return "int"
+	case StringNode:
+		return "string"
+	case BytesNode:
+		return "bytes"
+	case PrimNode:
+		return "prim"
+	case SeqNode:
+		return "seq"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// Node is a Micheline expression. Only the fields matching Type are meaningful, Args holds
+// the arguments of a primitive or the elements of a sequence.
+type Node struct {
+	Type   Type
+	Int    *big.Int
+	Str    string
+	Bytes  []byte
+	Prim   string
+	Args   []Node
+	Annots []string
+}
+
+// NewInt returns an int node
+func NewInt(i int64) Node {
+	return Node{Type: IntNode, Int: big.NewInt(i)}
+}
+
+// NewBigInt returns an int node for an arbitrary precision integer
+func NewBigInt(i *big.Int) Node {
+	return Node{Type: IntNode, Int: new(big.Int).Set(i)}
+}
+
+// NewString returns a string node
+func NewString(s string) Node {
+	return Node{Type: StringNode, Str: s}
+}
+
+// NewBytes returns a bytes node
+func NewBytes(b []byte) Node {
+	return Node{Type: BytesNode, Bytes: b}
+}
+
+// NewPrim returns a primitive application such as Pair or DUP
+func NewPrim(prim string, args ...Node) Node {
+	return Node{Type: PrimNode, Prim: prim, Args: args}
+}
+
+// NewSeq returns a sequence of nodes
+func NewSeq(nodes ...Node) Node {
+	if nodes == nil {
+		nodes = []Node{}
+	}
+	return Node{Type: SeqNode, Args: nodes}
+}
+
+// WithAnnots returns a copy of the node carrying the given annotations, e.g. "%to" or ":amount"
+func (n Node) WithAnnots(annots ...string) Node {
+	n.Annots = annots
+	return n
+}
+
+// annot returns the first annotation starting with prefix, without the prefix
+func (n Node) annot(prefix string) string {
+	for _, a := range n.Annots {
+		if strings.HasPrefix(a, prefix) {
+			return a[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// FieldAnnot returns the field annotation (%name) of the node
+func (n Node) FieldAnnot() string {
+	return n.annot("%")
+}
+
+// TypeAnnot returns the type annotation (:name) of the node
+func (n Node) TypeAnnot() string {
+	return n.annot(":")
+}
+
+// VarAnnot returns the variable annotation (@name) of the node
+func (n Node) VarAnnot() string {
+	return n.annot("@")
+}
+
+// IsPrim reports whether the node is an application of prim
+func (n Node) IsPrim(prim string) bool {
+	return n.Type == PrimNode && n.Prim == prim
+}
+
+// Equal reports whether both nodes are the same expression, annotations included
+func (n Node) Equal(other Node) bool {
+	if n.Type != other.Type || len(n.Args) != len(other.Args) || len(n.Annots) != len(other.Annots) {
+		return false
+	}
+	for i := range n.Annots {
+		if n.Annots[i] != other.Annots[i] {
+			return false
+		}
+	}
+	if !n.sameValue(other) {
+		return false
+	}
+	for i := range n.Args {
+		if !n.Args[i].Equal(other.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameValue compares the literal value or primitive name of nodes of the same type
+func (n Node) sameValue(other Node) bool {
+	switch n.Type {
+	case IntNode:
+		if n.Int == nil || other.Int == nil {
+			return n.Int == other.Int
+		}
+		return n.Int.Cmp(other.Int) == 0
+	case StringNode:
+		return n.Str == other.Str
+	case BytesNode:
+		return bytes.Equal(n.Bytes, other.Bytes)
+	case PrimNode:
+		return n.Prim == other.Prim
+	}
+	return true
+}
+
+// jsonNode is the JSON encoding of a node
+type jsonNode struct {
+	Int    *string           `json:"int,omitempty"`
+	String *string           `json:"string,omitempty"`
+	Bytes  *string           `json:"bytes,omitempty"`
+	Prim   *string           `json:"prim,omitempty"`
+	Args   []json.RawMessage `json:"args,omitempty"`
+	Annots []string          `json:"annots,omitempty"`
+}
+
+// MarshalJSON encodes the node as JSON Micheline
+func (n Node) MarshalJSON() ([]byte, error) {
+	switch n.Type {
+	case IntNode:
+		i := "0"
+		if n.Int != nil {
+			i = n.Int.String()
+		}
+		return json.Marshal(jsonNode{Int: &i})
+	case StringNode:
+		return json.Marshal(jsonNode{String: &n.Str})
+	case BytesNode:
+		b := hex.EncodeToString(n.Bytes)
+		return json.Marshal(jsonNode{Bytes: &b})
+	case PrimNode:
+		j := jsonNode{Prim: &n.Prim, Annots: n.Annots}
+		for _, arg := range n.Args {
+			b, err := arg.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			j.Args = append(j.Args, b)
+		}
+		return json.Marshal(j)
+	case SeqNode:
+		args := n.Args
+		if args == nil {
+			args = []Node{}
+		}
+		return json.Marshal(args)
+	}
+	return nil, fmt.Errorf("unknown micheline node type %d", n.Type)
+}
+
+// UnmarshalJSON decodes JSON Micheline
+func (n *Node) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		nodes := []Node{}
+		if err := json.Unmarshal(b, &nodes); err != nil {
+			return err
+		}
+		*n = NewSeq(nodes...)
+		return nil
+	}
+	j := jsonNode{}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	switch {
+	case j.Int != nil:
+		i, ok := new(big.Int).SetString(*j.Int, 10)
+		if !ok {
+			return fmt.Errorf("invalid micheline int %q", *j.Int)
+		}
+		*n = Node{Type: IntNode, Int: i}
+	case j.String != nil:
+		*n = NewString(*j.String)
+	case j.Bytes != nil:
+		raw, err := hex.DecodeString(*j.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid micheline bytes %q: %w", *j.Bytes, err)
+		}
+		*n = NewBytes(raw)
+	case j.Prim != nil:
+		prim := NewPrim(*j.Prim)
+		for _, raw := range j.Args {
+			arg := Node{}
+			if err := arg.UnmarshalJSON(raw); err != nil {
+				return err
+			}
+			prim.Args = append(prim.Args, arg)
+		}
+		prim.Annots = j.Annots
+		*n = prim
+	default:
+		return fmt.Errorf("invalid micheline expression %s", b)
+	}
+	return nil
+}
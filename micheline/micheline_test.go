@@ -0,0 +1,74 @@
+package micheline_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/postables/TGo/micheline"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	src := `[{"prim":"parameter","args":[{"prim":"or","args":[{"prim":"nat","annots":["%increment"]},{"prim":"unit","annots":["%reset"]}]}]},{"prim":"storage","args":[{"prim":"pair","args":[{"int":"-123456789012345678901234567890"},{"string":"hello"},{"bytes":"0a0b"}]}]},{"prim":"code","args":[[]]}]`
+	node := micheline.Node{}
+	if err := json.Unmarshal([]byte(src), &node); err != nil {
+		t.Fatal(err)
+	}
+	if node.Type != micheline.SeqNode || len(node.Args) != 3 {
+		t.Fatalf("unexpected node %+v", node)
+	}
+	storage, ok := node.Get(1, 0)
+	if !ok || !storage.IsPrim("pair") || storage.Args[0].Int.String() != "-123456789012345678901234567890" || storage.Args[2].Bytes[1] != 0x0b {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+	out, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != src {
+		t.Fatalf("round trip mismatch\n%s\n%s", out, src)
+	}
+	for _, invalid := range []string{`{"int":"1.5"}`, `{"bytes":"zz"}`, `{"foo":1}`} {
+		if err := json.Unmarshal([]byte(invalid), &node); err == nil {
+			t.Fatalf("expected error for %s", invalid)
+		}
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	param := micheline.NewPrim("or",
+		micheline.NewPrim("nat").WithAnnots("%increment"),
+		micheline.NewPrim("pair",
+			micheline.NewPrim("address").WithAnnots("%to", ":owner"),
+			micheline.NewPrim("nat").WithAnnots("%value"),
+		).WithAnnots("%transfer"),
+	)
+	paths := [][]int{}
+	param.Walk(func(path []int, node micheline.Node) bool {
+		paths = append(paths, append([]int{}, path...))
+		return !node.IsPrim("pair")
+	})
+	if !reflect.DeepEqual(paths, [][]int{{}, {0}, {1}}) {
+		t.Fatalf("unexpected walk %v", paths)
+	}
+	if nats := param.Find(func(n micheline.Node) bool { return n.IsPrim("nat") }); len(nats) != 2 {
+		t.Fatalf("expected 2 nat got %d", len(nats))
+	}
+	to, ok := param.FindAnnot("to")
+	if !ok || !to.IsPrim("address") || to.TypeAnnot() != "owner" {
+		t.Fatalf("unexpected %%to node %+v", to)
+	}
+	if _, ok := param.FindAnnot("missing"); ok {
+		t.Fatal("unexpected annotation found")
+	}
+	pattern := micheline.NewPrim("or", micheline.NewPrim(micheline.Wildcard), micheline.NewPrim("pair", micheline.NewPrim("address"), micheline.NewPrim(micheline.Wildcard)))
+	if !param.Match(pattern) {
+		t.Fatal("expected pattern to match")
+	}
+	if param.Match(micheline.NewPrim("or", micheline.NewPrim("int"), micheline.NewPrim(micheline.Wildcard))) {
+		t.Fatal("expected pattern not to match")
+	}
+	if !micheline.NewInt(5).Equal(micheline.NewInt(5)) || micheline.NewInt(5).Equal(micheline.NewString("5")) {
+		t.Fatal("unexpected equality")
+	}
+}
@@ -0,0 +1,118 @@
+package micheline
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/postables/TGo/zarith"
+)
+
+// PackPrefix is the tag prepended by PACK to serialized Michelson data
+const PackPrefix = 0x05
+
+// primitives lists Michelson primitives in the order of their binary codes
+var primitives = []string{
+	"parameter", "storage", "code", "False", "Elt", "Left", "None", "Pair", "Right", "Some",
+	"True", "Unit", "PACK", "UNPACK", "BLAKE2B", "SHA256", "SHA512", "ABS", "ADD", "AMOUNT",
+	"AND", "BALANCE", "CAR", "CDR", "CHECK_SIGNATURE", "COMPARE", "CONCAT", "CONS", "CREATE_ACCOUNT", "CREATE_CONTRACT",
+	"IMPLICIT_ACCOUNT", "DIP", "DROP", "DUP", "EDIV", "EMPTY_MAP", "EMPTY_SET", "EQ", "EXEC", "FAILWITH",
+	"GE", "GET", "GT", "HASH_KEY", "IF", "IF_CONS", "IF_LEFT", "IF_NONE", "INT", "LAMBDA",
+	"LE", "LEFT", "LOOP", "LSL", "LSR", "LT", "MAP", "MEM", "MUL", "NEG",
+	"NEQ", "NIL", "NONE", "NOT", "NOW", "OR", "PAIR", "PUSH", "RIGHT", "SIZE",
+	"SOME", "SOURCE", "SENDER", "SELF", "STEPS_TO_QUOTA", "SUB", "SWAP", "TRANSFER_TOKENS", "SET_DELEGATE", "UNIT",
+	"UPDATE", "XOR", "ITER", "LOOP_LEFT", "ADDRESS", "CONTRACT", "ISNAT", "CAST", "RENAME", "bool",
+	"contract", "int", "key", "key_hash", "lambda", "list", "map", "big_map", "nat", "option",
+	"or", "pair", "set", "signature", "string", "bytes", "mutez", "timestamp", "unit", "operation",
+	"address", "SLICE", "DIG", "DUG", "EMPTY_BIG_MAP", "APPLY", "chain_id", "CHAIN_ID",
+}
+
+// primCodes maps primitive names to their binary codes
+var primCodes = func() map[string]byte {
+	codes := make(map[string]byte, len(primitives))
+	for i, prim := range primitives {
+		codes[prim] = byte(i)
+	}
+	return codes
+}()
+
+// Pack serializes the node the way the PACK instruction does. The node must already be in
+// optimized form, e.g. addresses as bytes, for the result to match a packed typed value.
+func Pack(n Node) ([]byte, error) {
+	b, err := Encode(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{PackPrefix}, b...), nil
+}
+
+// Encode returns the binary encoding of the node used by PACK and by forged operations
+func Encode(n Node) ([]byte, error) {
+	return n.encode(nil)
+}
+
+func (n Node) encode(out []byte) ([]byte, error) {
+	switch n.Type {
+	case IntNode:
+		i := n.Int
+		if i == nil {
+			i = new(big.Int)
+		}
+		return zarith.AppendInt(append(out, 0), i), nil
+	case StringNode:
+		return appendSized(append(out, 1), []byte(n.Str)), nil
+	case BytesNode:
+		return appendSized(append(out, 10), n.Bytes), nil
+	case SeqNode:
+		var body []byte
+		for _, arg := range n.Args {
+			var err error
+			if body, err = arg.encode(body); err != nil {
+				return nil, err
+			}
+		}
+		return appendSized(append(out, 2), body), nil
+	case PrimNode:
+		code, ok := primCodes[n.Prim]
+		if !ok {
+			return nil, fmt.Errorf("unknown michelson primitive %q", n.Prim)
+		}
+		annots := len(n.Annots) > 0
+		if len(n.Args) > 2 {
+			var args []byte
+			for _, arg := range n.Args {
+				var err error
+				if args, err = arg.encode(args); err != nil {
+					return nil, err
+				}
+			}
+			out = appendSized(append(out, 9, code), args)
+			return appendSized(out, []byte(strings.Join(n.Annots, " "))), nil
+		}
+		// tags 3 to 8 encode primitives with zero, one or two arguments, odd ones without annotations
+		tag := byte(3 + 2*len(n.Args))
+		if annots {
+			tag++
+		}
+		out = append(out, tag, code)
+		for _, arg := range n.Args {
+			var err error
+			if out, err = arg.encode(out); err != nil {
+				return nil, err
+			}
+		}
+		if annots {
+			out = appendSized(out, []byte(strings.Join(n.Annots, " ")))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown micheline node type %d", n.Type)
+}
+
+// appendSized appends b preceded by its length on four bytes
+func appendSized(out, b []byte) []byte {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+	return append(append(out, size[:]...), b...)
+}
@@ -0,0 +1,41 @@
+package micheline_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/postables/TGo/micheline"
+)
+
+func TestPack(t *testing.T) {
+	big, _ := new(big.Int).SetString("-1000000000000000000000", 10)
+	for _, c := range []struct {
+		node micheline.Node
+		want string
+	}{
+		{micheline.NewInt(1), "050001"},
+		{micheline.NewInt(-1), "050041"},
+		{micheline.NewInt(64), "05008001"},
+		{micheline.NewBigInt(big), "0500c08080eabbf1d6c9ebd801"},
+		{micheline.NewString("foo"), "050100000003666f6f"},
+		{micheline.NewBytes([]byte{0xca, 0xfe}), "050a00000002cafe"},
+		{micheline.NewPrim("Unit"), "05030b"},
+		{micheline.NewPrim("Some", micheline.NewInt(1)), "0505090001"},
+		{micheline.NewPrim("Pair", micheline.NewInt(1), micheline.NewString("a")), "0507070001010000000161"},
+		{micheline.NewSeq(micheline.NewInt(1), micheline.NewInt(2)), "05020000000400010002"},
+		{micheline.NewPrim("nat").WithAnnots("%n"), "05046200000002256e"},
+		{micheline.NewPrim("pair", micheline.NewPrim("nat"), micheline.NewPrim("nat"), micheline.NewPrim("nat")), "0509650000000603620362036200000000"},
+	} {
+		b, err := micheline.Pack(c.node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(b); got != c.want {
+			t.Errorf("pack %s: got %s want %s", c.node, got, c.want)
+		}
+	}
+	if _, err := micheline.Pack(micheline.NewPrim("NOPE")); err == nil {
+		t.Fatal("expected error for unknown primitive")
+	}
+}
@@ -0,0 +1,305 @@
+package micheline
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// tokenKind identifies the lexical class of a token in Michelson source
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenInt
+	tokenString
+	tokenBytes
+	tokenIdent
+	tokenAnnot
+	tokenLBrace
+	tokenRBrace
+	tokenLParen
+	tokenRParen
+	tokenSemi
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lex splits Michelson source into tokens, dropping whitespace and comments
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at %d", i)
+			}
+			i += end + 4
+		case c == '{':
+			tokens = append(tokens, token{tokenLBrace, "{", i})
+			i++
+		case c == '}':
+			tokens = append(tokens, token{tokenRBrace, "}", i})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case c == ';':
+			tokens = append(tokens, token{tokenSemi, ";", i})
+			i++
+		case c == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if src[i] == '"' {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					switch src[i+1] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					case 'r':
+						sb.WriteByte('\r')
+					case 'b':
+						sb.WriteByte('\b')
+					case '"', '\\':
+						sb.WriteByte(src[i+1])
+					default:
+						return nil, fmt.Errorf("invalid escape \\%c at %d", src[i+1], i)
+					}
+					i += 2
+					continue
+				}
+				if src[i] == '\n' {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, token{tokenString, sb.String(), start})
+		case strings.HasPrefix(src[i:], "0x"):
+			start := i
+			i += 2
+			for i < len(src) && isHex(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenBytes, src[start+2 : i], start})
+		case c == '-' || isDigit(c):
+			start := i
+			i++
+			for i < len(src) && isDigit(src[i]) {
+				i++
+			}
+			if src[start:i] == "-" {
+				return nil, fmt.Errorf("invalid number at %d", start)
+			}
+			tokens = append(tokens, token{tokenInt, src[start:i], start})
+		case c == '@' || c == ':' || c == '%':
+			start := i
+			i++
+			for i < len(src) && isAnnotChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenAnnot, src[start:i], start})
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, src[start:i], start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(src)}), nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
+
+func isAnnotChar(c byte) bool {
+	return isIdentChar(c) || c == '@' || c == '%'
+}
+
+// parser is a recursive descent parser over Michelson tokens
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("expected %s at %d, got %q", what, t.pos, t.text)
+	}
+	return nil
+}
+
+// Parse parses Michelson concrete syntax, such as the content of a .tz file or a data
+// expression like `Pair 1 "foo"`. Several top level expressions separated by semicolons,
+// as in a contract file, are returned as a sequence.
+func Parse(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return Node{}, err
+	}
+	p := &parser{tokens: tokens}
+	nodes, err := p.parseExprs(tokenEOF)
+	if err != nil {
+		return Node{}, err
+	}
+	if len(nodes) == 0 {
+		return Node{}, fmt.Errorf("empty michelson expression")
+	}
+	if len(nodes) == 1 && !p.sawSemi() {
+		return nodes[0], nil
+	}
+	return NewSeq(nodes...), nil
+}
+
+// sawSemi reports whether the top level expressions were separated by semicolons
+func (p *parser) sawSemi() bool {
+	depth := 0
+	for _, t := range p.tokens {
+		switch t.kind {
+		case tokenLBrace, tokenLParen:
+			depth++
+		case tokenRBrace, tokenRParen:
+			depth--
+		case tokenSemi:
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseExprs parses semicolon separated expressions until the closing token
+func (p *parser) parseExprs(closing tokenKind) ([]Node, error) {
+	nodes := []Node{}
+	for {
+		if p.peek().kind == closing {
+			return nodes, nil
+		}
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		switch t := p.peek(); t.kind {
+		case tokenSemi:
+			p.next()
+		case closing:
+		default:
+			return nil, fmt.Errorf("expected ';' at %d, got %q", t.pos, t.text)
+		}
+	}
+}
+
+// parseExpr parses an expression where a primitive may take annotations and arguments
+func (p *parser) parseExpr() (Node, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return p.parseArg()
+	}
+	p.next()
+	node := NewPrim(t.text)
+	for p.peek().kind == tokenAnnot {
+		node.Annots = append(node.Annots, p.next().text)
+	}
+	for {
+		switch p.peek().kind {
+		case tokenInt, tokenString, tokenBytes, tokenIdent, tokenLBrace, tokenLParen:
+			arg, err := p.parseArg()
+			if err != nil {
+				return Node{}, err
+			}
+			node.Args = append(node.Args, arg)
+		default:
+			return node, nil
+		}
+	}
+}
+
+// parseArg parses an argument position, where applications must be parenthesised
+func (p *parser) parseArg() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt:
+		i, ok := new(big.Int).SetString(t.text, 10)
+		if !ok {
+			return Node{}, fmt.Errorf("invalid int %q at %d", t.text, t.pos)
+		}
+		return Node{Type: IntNode, Int: i}, nil
+	case tokenString:
+		return NewString(t.text), nil
+	case tokenBytes:
+		b, err := hex.DecodeString(t.text)
+		if err != nil {
+			return Node{}, fmt.Errorf("invalid bytes at %d: %w", t.pos, err)
+		}
+		return NewBytes(b), nil
+	case tokenIdent:
+		return NewPrim(t.text), nil
+	case tokenLBrace:
+		nodes, err := p.parseExprs(tokenRBrace)
+		if err != nil {
+			return Node{}, err
+		}
+		return NewSeq(nodes...), p.expect(tokenRBrace, "'}'")
+	case tokenLParen:
+		node, err := p.parseExpr()
+		if err != nil {
+			return Node{}, err
+		}
+		return node, p.expect(tokenRParen, "')'")
+	}
+	return Node{}, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
@@ -0,0 +1,104 @@
+package micheline_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/postables/TGo/micheline"
+)
+
+const counterSource = `# a counter with a reset entrypoint
+parameter (or (nat %increment) (unit %reset)) ;
+storage (pair (nat %count) (string %label)) ;
+code { UNPAIR ;
+       IF_LEFT
+         { DIP { UNPAIR } ; ADD ; PAIR }
+         { DROP ; CDR ; PUSH nat 0 ; PAIR } ; /* reset keeps the label */
+       NIL operation ;
+       PAIR }`
+
+func TestParseScript(t *testing.T) {
+	node, err := micheline.Parse(counterSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Type != micheline.SeqNode || len(node.Args) != 3 {
+		t.Fatalf("unexpected node %v", node)
+	}
+	if !node.Args[0].IsPrim("parameter") || !node.Args[2].IsPrim("code") {
+		t.Fatalf("unexpected sections %v", node)
+	}
+	reset, ok := node.FindAnnot("reset")
+	if !ok || !reset.IsPrim("unit") {
+		t.Fatalf("unexpected reset entrypoint %v", reset)
+	}
+	code, _ := node.Get(2, 0)
+	if len(code.Args) != 4 || !code.Args[1].IsPrim("IF_LEFT") || len(code.Args[1].Args) != 2 {
+		t.Fatalf("unexpected code %v", code)
+	}
+	push, _ := code.Get(1, 1, 2)
+	if !push.Equal(micheline.NewPrim("PUSH", micheline.NewPrim("nat"), micheline.NewInt(0))) {
+		t.Fatalf("unexpected push %v", push)
+	}
+}
+
+func TestParseData(t *testing.T) {
+	node, err := micheline.Parse(`Pair -12 "a \"quoted\"\nline" 0xCAFE { Elt "k" (Some Unit) }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"prim":"Pair","args":[{"int":"-12"},{"string":"a \"quoted\"\nline"},{"bytes":"cafe"},[{"prim":"Elt","args":[{"string":"k"},{"prim":"Some","args":[{"prim":"Unit"}]}]}]]}`
+	if string(b) != want {
+		t.Fatalf("got %s", b)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`{ DUP`,
+		`"unterminated`,
+		`(Pair 1 2`,
+		`DUP DROP ; }`,
+		`/* open comment`,
+		`0xABC`,
+		`PUSH int -`,
+		`$`,
+	} {
+		if node, err := micheline.Parse(src); err == nil {
+			t.Errorf("expected error for %q, got %v", src, node)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	node, err := micheline.Parse(counterSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted := micheline.Format(node)
+	if !strings.HasPrefix(formatted, "parameter (or (nat %increment) (unit %reset)) ;\nstorage") {
+		t.Fatalf("unexpected script layout:\n%s", formatted)
+	}
+	for _, line := range strings.Split(formatted, "\n") {
+		if len(line) > 80 {
+			t.Fatalf("line too long: %q", line)
+		}
+	}
+	again, err := micheline.Parse(formatted)
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, formatted)
+	}
+	if !again.Equal(node) {
+		t.Fatalf("round trip changed the script:\n%s", formatted)
+	}
+	data := micheline.NewPrim("Pair", micheline.NewPrim("Some", micheline.NewString("x\ty")).WithAnnots("%a"), micheline.NewSeq())
+	if s := data.String(); s != `Pair (Some %a "x\ty") {}` {
+		t.Fatalf("unexpected string %s", s)
+	}
+}
@@ -0,0 +1,132 @@
+package micheline
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// lineWidth is the width under which nodes are printed on a single line
+const lineWidth = 80
+
+// scriptSections are the top level primitives of a contract file
+var scriptSections = map[string]bool{"parameter": true, "storage": true, "code": true, "view": true}
+
+// String returns the node in Michelson concrete syntax on a single line
+func (n Node) String() string {
+	return n.flat(false)
+}
+
+// Format pretty prints the node in Michelson concrete syntax, breaking sequences and long
+// applications over several lines. A sequence of parameter, storage and code sections is
+// printed as a contract file, without the enclosing braces, so that Parse reads it back.
+func Format(n Node) string {
+	if n.Type == SeqNode && len(n.Args) > 0 {
+		script := true
+		for _, section := range n.Args {
+			if section.Type != PrimNode || !scriptSections[section.Prim] {
+				script = false
+				break
+			}
+		}
+		if script {
+			sections := make([]string, len(n.Args))
+			for i, section := range n.Args {
+				sections[i] = section.pretty(0, false)
+			}
+			return strings.Join(sections, " ;\n") + " ;\n"
+		}
+	}
+	return n.pretty(0, false)
+}
+
+// flat prints the node on a single line, wrapping applications in parentheses when nested
+func (n Node) flat(nested bool) string {
+	switch n.Type {
+	case IntNode:
+		if n.Int == nil {
+			return "0"
+		}
+		return n.Int.String()
+	case StringNode:
+		return quote(n.Str)
+	case BytesNode:
+		return "0x" + hex.EncodeToString(n.Bytes)
+	case SeqNode:
+		if len(n.Args) == 0 {
+			return "{}"
+		}
+		elems := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			elems[i] = arg.flat(false)
+		}
+		return "{ " + strings.Join(elems, " ; ") + " }"
+	}
+	parts := append([]string{n.Prim}, n.Annots...)
+	for _, arg := range n.Args {
+		parts = append(parts, arg.flat(true))
+	}
+	s := strings.Join(parts, " ")
+	if nested && (len(n.Args) > 0 || len(n.Annots) > 0) {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// pretty prints the node starting at column indent, falling back to several lines when the
+// single line form does not fit
+func (n Node) pretty(indent int, nested bool) string {
+	s := n.flat(nested)
+	if indent+len(s) <= lineWidth || (n.Type != SeqNode && n.Type != PrimNode) {
+		return s
+	}
+	if n.Type == SeqNode {
+		elems := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			elems[i] = arg.pretty(indent+2, false)
+		}
+		return "{ " + strings.Join(elems, " ;\n"+strings.Repeat(" ", indent+2)) + " }"
+	}
+	if len(n.Args) == 0 {
+		return s
+	}
+	open := ""
+	if nested {
+		open = "("
+	}
+	head := open + strings.Join(append([]string{n.Prim}, n.Annots...), " ")
+	argIndent := indent + len(head) + 1
+	args := make([]string, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = arg.pretty(argIndent, true)
+	}
+	s = head + " " + strings.Join(args, "\n"+strings.Repeat(" ", argIndent))
+	if nested {
+		s += ")"
+	}
+	return s
+}
+
+// quote escapes a string literal the way Michelson expects
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\b':
+			sb.WriteString(`\b`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package micheline
+
+// Wildcard is a primitive name matching any node in patterns given to Match
+const Wildcard = "_"
+
+// Walk calls fn for the node and every node below it in depth first order. path holds the
+// argument indexes leading from the root to the visited node. Children of a node are skipped
+// when fn returns false.
+func (n Node) Walk(fn func(path []int, node Node) bool) {
+	n.walk(nil, fn)
+}
+
+func (n Node) walk(path []int, fn func([]int, Node) bool) {
+	if !fn(path, n) {
+		return
+	}
+	for i, arg := range n.Args {
+		arg.walk(append(path[:len(path):len(path)], i), fn)
+	}
+}
+
+// Find returns every node below and including n for which match returns true
+func (n Node) Find(match func(Node) bool) []Node {
+	found := []Node{}
+	n.Walk(func(_ []int, node Node) bool {
+		if match(node) {
+			found = append(found, node)
+		}
+		return true
+	})
+	return found
+}
+
+// FindAnnot returns the first node carrying the field annotation %name, or false if there is none
+func (n Node) FindAnnot(name string) (Node, bool) {
+	var result Node
+	found := false
+	n.Walk(func(_ []int, node Node) bool {
+		if found {
+			return false
+		}
+		if node.FieldAnnot() == name {
+			result, found = node, true
+			return false
+		}
+		return true
+	})
+	return result, found
+}
+
+// Get returns the node at path, as given to the Walk callback
+func (n Node) Get(path ...int) (Node, bool) {
+	for _, i := range path {
+		if i < 0 || i >= len(n.Args) {
+			return Node{}, false
+		}
+		n = n.Args[i]
+	}
+	return n, true
+}
+
+// Match reports whether n has the shape of pattern. A Wildcard primitive in the pattern
+// matches any node, annotations are only compared when the pattern has some.
+func (n Node) Match(pattern Node) bool {
+	if pattern.Type == PrimNode && pattern.Prim == Wildcard {
+		return true
+	}
+	if n.Type != pattern.Type || len(n.Args) != len(pattern.Args) {
+		return false
+	}
+	if len(pattern.Annots) > 0 {
+		if len(n.Annots) != len(pattern.Annots) {
+			return false
+		}
+		for i := range n.Annots {
+			if n.Annots[i] != pattern.Annots[i] {
+				return false
+			}
+		}
+	}
+	if !n.sameValue(pattern) {
+		return false
+	}
+	for i := range n.Args {
+		if !n.Args[i].Match(pattern.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
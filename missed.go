@@ -0,0 +1,158 @@
+package tgo
+
+import (
+	"context"
+	"strings"
+)
+
+// Kinds of missed rights reported by a MissedRightsMonitor
+const (
+	MissedBake        = "missed_bake"
+	MissedEndorsement = "missed_endorsement"
+)
+
+// MissedRight is an alert for a right of a watched delegate that was not used
+type MissedRight struct {
+	Kind     string
+	Delegate string
+	// Level is the level of the right, the endorsements of a level are included in the next block
+	Level int64
+	// BlockHash is the block at Level for missed bakes and the block at Level+1 for missed endorsements
+	BlockHash BlockHash
+	// Baker and Priority describe who baked the block in place of the delegate
+	Baker    string
+	Priority int64
+	// Slots are the endorsement slots left unfilled
+	Slots []int64
+}
+
+// MissedRightsMonitor compares the rights of delegates with the blocks of the chain and
+// reports priority 0 blocks not baked and endorsement slots not filled by them
+type MissedRightsMonitor struct {
+	rpc       *RPC
+	Delegates []string
+}
+
+// NewMissedRightsMonitor returns a monitor of the rights of delegates
+func NewMissedRightsMonitor(rpc *RPC, delegates ...string) *MissedRightsMonitor {
+	return &MissedRightsMonitor{rpc: rpc, Delegates: delegates}
+}
+
+// Check returns the rights missed by the delegates in blockID: the priority 0 right at its
+// level and the endorsing rights of the level before, whose endorsements it includes
+func (m *MissedRightsMonitor) Check(ctx context.Context, blockID BlockID) ([]MissedRight, error) {
+	header, err := m.rpc.GetBlockHeader(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := m.rpc.GetBlockMetadata(ctx, header.Hash.ID())
+	if err != nil {
+		return nil, err
+	}
+	missed := []MissedRight{}
+	baking, err := m.rpc.GetBakingRights(ctx, header.Hash.ID(), RightsQuery{Delegates: m.Delegates, Levels: []int64{header.Level}})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range baking {
+		if r.Priority == 0 && r.Delegate != string(metadata.Baker) {
+			missed = append(missed, MissedRight{
+				Kind:      MissedBake,
+				Delegate:  r.Delegate,
+				Level:     header.Level,
+				BlockHash: header.Hash,
+				Baker:     string(metadata.Baker),
+				Priority:  header.Priority,
+			})
+		}
+	}
+	if header.Level <= 1 {
+		return missed, nil
+	}
+	endorsing, err := m.rpc.GetEndorsingRights(ctx, header.Hash.ID(), RightsQuery{Delegates: m.Delegates, Levels: []int64{header.Level - 1}})
+	if err != nil {
+		return nil, err
+	}
+	if len(endorsing) == 0 {
+		return missed, nil
+	}
+	passes, err := m.rpc.GetBlockOperations(ctx, header.Hash.ID())
+	if err != nil {
+		return nil, err
+	}
+	endorsed := map[string]bool{}
+	if len(passes) > 0 {
+		for _, op := range passes[0] {
+			for _, c := range op.Contents {
+				if strings.HasPrefix(c.Kind, "endorsement") {
+					endorsed[c.Metadata.Delegate] = true
+				}
+			}
+		}
+	}
+	for _, r := range endorsing {
+		if !endorsed[r.Delegate] {
+			missed = append(missed, MissedRight{
+				Kind:      MissedEndorsement,
+				Delegate:  r.Delegate,
+				Level:     r.Level,
+				BlockHash: header.Hash,
+				Baker:     string(metadata.Baker),
+				Priority:  header.Priority,
+				Slots:     r.Slots,
+			})
+		}
+	}
+	return missed, nil
+}
+
+// Watch checks every new head, along with the levels skipped since the previous one, and
+// emits the rights missed by the delegates until ctx is cancelled. Both channels are closed
+// once watching stops. errs receives the failures of checks and the error that stopped the head
+// stream if any, as described in the package documentation.
+func (m *MissedRightsMonitor) Watch(ctx context.Context) (<-chan MissedRight, <-chan error) {
+	alerts := make(chan MissedRight)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(alerts)
+		defer close(errs)
+		heads, headErrs := m.rpc.MonitorHeads(ctx, m.rpc.ChainAlias(ctx))
+		last, lastHash := int64(0), BlockHash("")
+		for head := range heads {
+			if head.Hash == lastHash {
+				// the stream was reopened on the same head
+				continue
+			}
+			first := head.Level
+			if last > 0 && last < head.Level {
+				first = last + 1
+			}
+			for level := first; level <= head.Level; level++ {
+				blockID := head.Hash.ID()
+				if level < head.Level {
+					blockID = LevelID(level)
+				}
+				missed, err := m.Check(ctx, blockID)
+				if err != nil {
+					reportError(errs, err)
+					continue
+				}
+				for _, alert := range missed {
+					select {
+					case alerts <- alert:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			if head.Level > last {
+				last = head.Level
+			}
+			lastHash = head.Hash
+		}
+		if err := <-headErrs; err != nil {
+			errs <- err
+		}
+	}()
+	return alerts, errs
+}
@@ -0,0 +1,98 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+// missedRoutes serves blocks 299 to 301, the watched delegate misses the bake of 301 and the
+// endorsement of 300, the other delegate endorses 300
+func missedRoutes() map[string]interface{} {
+	const delegate, other = "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", "tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF"
+	routes := map[string]interface{}{}
+	for level := 299; level <= 301; level++ {
+		hash := fmt.Sprintf("BL%d", level)
+		header := map[string]interface{}{"hash": hash, "level": level, "priority": 0}
+		routes[fmt.Sprintf("GET /chains/main/blocks/%d/header", level)] = header
+		routes["GET /chains/main/blocks/"+hash+"/header"] = header
+		routes["GET /chains/main/blocks/"+hash+"/metadata"] = map[string]interface{}{"baker": delegate}
+		routes["GET /chains/main/blocks/"+hash+"/helpers/baking_rights"] = []interface{}{}
+		routes["GET /chains/main/blocks/"+hash+"/helpers/endorsing_rights"] = []interface{}{}
+	}
+	routes["GET /chains/main/blocks/301/header"] = map[string]interface{}{"hash": "BL301", "level": 301, "priority": 1}
+	routes["GET /chains/main/blocks/BL301/header"] = routes["GET /chains/main/blocks/301/header"]
+	routes["GET /chains/main/blocks/BL301/metadata"] = map[string]interface{}{"baker": other}
+	routes["GET /chains/main/blocks/BL301/helpers/baking_rights"] = []map[string]interface{}{{"level": 301, "delegate": delegate, "priority": 0}}
+	routes["GET /chains/main/blocks/BL301/helpers/endorsing_rights"] = []map[string]interface{}{
+		{"level": 300, "delegate": delegate, "slots": []int{1, 4}},
+		{"level": 300, "delegate": other, "slots": []int{2}},
+	}
+	routes["GET /chains/main/blocks/BL301/operations"] = [][]map[string]interface{}{{{
+		"hash": "ooE", "branch": "BL300",
+		"contents": []map[string]interface{}{{"kind": "endorsement", "level": 300, "metadata": map[string]interface{}{"delegate": other, "slots": []int{2}}}},
+	}}, {}, {}, {}}
+	return routes
+}
+
+func expectedMissed() []tgo.MissedRight {
+	return []tgo.MissedRight{
+		{Kind: tgo.MissedBake, Delegate: "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", Level: 301, BlockHash: "BL301", Baker: "tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF", Priority: 1},
+		{Kind: tgo.MissedEndorsement, Delegate: "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", Level: 300, BlockHash: "BL301", Baker: "tz1Kqidr4E3p3XWNST6x8urveTyGXktFLMHF", Priority: 1, Slots: []int64{1, 4}},
+	}
+}
+
+func TestMissedRightsCheck(t *testing.T) {
+	_, client := newFakeNode(t, missedRoutes())
+	monitor := tgo.NewMissedRightsMonitor(client, "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx")
+	missed, err := monitor.Check(context.Background(), "301")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(missed, expectedMissed()) {
+		t.Fatalf("unexpected missed rights %+v", missed)
+	}
+	if missed, err = monitor.Check(context.Background(), "300"); err != nil || len(missed) != 0 {
+		t.Fatalf("unexpected missed rights at 300 %+v: %v", missed, err)
+	}
+}
+
+func TestMissedRightsWatch(t *testing.T) {
+	routes := missedRoutes()
+	streams := 0
+	routes["GET /monitor/heads/main"] = func([]byte) interface{} {
+		streams++
+		if streams == 1 {
+			return rawBody(`{"hash":"BL299","level":299}`)
+		}
+		// level 300 is skipped by the stream and the head is repeated on reconnection
+		return rawBody(`{"hash":"BL301","level":301}`)
+	}
+	_, client := newFakeNode(t, routes)
+	client.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alerts, _ := tgo.NewMissedRightsMonitor(client, "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx").Watch(ctx)
+	got := []tgo.MissedRight{}
+	timeout := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case alert := <-alerts:
+			got = append(got, alert)
+		case <-timeout:
+			t.Fatalf("expected two alerts, got %+v", got)
+		}
+	}
+	if !reflect.DeepEqual(got, expectedMissed()) {
+		t.Fatalf("unexpected alerts %+v", got)
+	}
+	select {
+	case alert := <-alerts:
+		t.Fatalf("unexpected alert %+v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
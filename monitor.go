@@ -0,0 +1,319 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// stream calls GET on path and repeatedly hands a decoder over the chunked response to
+// read until it returns an error, io.EOF is returned once the node closes the stream.
+// The returned bool reports whether the stream had been established.
+func (rpc *RPC) stream(ctx context.Context, path string, read func(*json.Decoder) error) (bool, error) {
+	ctx, done := rpc.begin(ctx)
+	defer done()
+	req, err := http.NewRequest(http.MethodGet, rpc.URL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	// streams are long lived, only ctx bounds them
+	client := *rpc.Client
+	client.Timeout = 0
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	decoder := rpc.newDecoder(resp.Body)
+	for {
+		if err := read(decoder); err != nil {
+			return true, err
+		}
+	}
+}
+
+// maxReconnectBackoff bounds the wait between the attempts to reopen a dropped stream
+const maxReconnectBackoff = time.Minute
+
+// monitor keeps the stream at path open until ctx is cancelled, reopening it PollInterval
+// after the node closes or drops it. Failed attempts to reopen it are retried, doubling the
+// wait up to a minute, so a node restarting does not end the stream. It returns the error
+// preventing the stream from being opened the first time or decoded.
+func (rpc *RPC) monitor(ctx context.Context, path string, read func(*json.Decoder) error) error {
+	opened := false
+	wait := rpc.PollInterval
+	for {
+		established, err := rpc.stream(ctx, path, read)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !established && !opened {
+			return err
+		}
+		switch err.(type) {
+		case *json.SyntaxError, *json.UnmarshalTypeError:
+			return err
+		}
+		if established {
+			opened = true
+			wait = rpc.PollInterval
+		} else if wait *= 2; wait > maxReconnectBackoff {
+			wait = maxReconnectBackoff
+		}
+		// the node closed or dropped the stream, give it a moment before reconnecting so a node
+		// ending every stream at once is not flooded with requests
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// MonitorHeads calls GET /monitor/heads/<chain> and streams every new head of the chain,
+// reconnecting when the stream drops. Both channels are closed once ctx is cancelled,
+// errs receives the error that stopped the stream otherwise.
+func (rpc *RPC) MonitorHeads(ctx context.Context, chain string) (<-chan BlockHeader, <-chan error) {
+	heads := make(chan BlockHeader)
+	errs := make(chan error, 1)
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(heads)
+		defer close(errs)
+		err := rpc.monitor(ctx, fmt.Sprintf("/monitor/heads/%s", chain), func(decoder *json.Decoder) error {
+			head := BlockHeader{}
+			if err := decoder.Decode(&head); err != nil {
+				return err
+			}
+			select {
+			case heads <- head:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return heads, errs
+}
+
+// ValidBlocksOptions filters the blocks streamed by MonitorValidBlocks, empty filters match everything
+type ValidBlocksOptions struct {
+	Protocols     []string
+	NextProtocols []string
+	Chains        []string
+}
+
+// MonitorValidBlocks calls GET /monitor/valid_blocks and streams every block validated by
+// the node, including blocks that never become head such as the losing side of a reorg.
+// Both channels are closed once ctx is cancelled, errs receives the error that stopped the stream otherwise.
+func (rpc *RPC) MonitorValidBlocks(ctx context.Context, opts ValidBlocksOptions) (<-chan BlockHeader, <-chan error) {
+	path := query{}.add("protocol", opts.Protocols...).add("next_protocol", opts.NextProtocols...).
+		add("chain", opts.Chains...).path("/monitor/valid_blocks")
+	blocks := make(chan BlockHeader)
+	errs := make(chan error, 1)
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(blocks)
+		defer close(errs)
+		err := rpc.monitor(ctx, path, func(decoder *json.Decoder) error {
+			block := BlockHeader{}
+			if err := decoder.Decode(&block); err != nil {
+				return err
+			}
+			select {
+			case blocks <- block:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return blocks, errs
+}
+
+// MonitorProtocols calls GET /monitor/protocols and streams the hash of every protocol
+// the node learns about. Both channels are closed once ctx is cancelled, errs receives
+// the error that stopped the stream otherwise.
+func (rpc *RPC) MonitorProtocols(ctx context.Context) (<-chan ProtocolHash, <-chan error) {
+	protocols := make(chan ProtocolHash)
+	errs := make(chan error, 1)
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(protocols)
+		defer close(errs)
+		err := rpc.monitor(ctx, "/monitor/protocols", func(decoder *json.Decoder) error {
+			var protocol ProtocolHash
+			if err := decoder.Decode(&protocol); err != nil {
+				return err
+			}
+			select {
+			case protocols <- protocol:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return protocols, errs
+}
+
+// ActiveChain is an entry of `GET /monitor/active_chains`, either an active chain,
+// a test chain with its protocol and expiration or a chain being stopped
+type ActiveChain struct {
+	ChainID        ChainID      `json:"chain_id,omitempty"`
+	TestProtocol   ProtocolHash `json:"test_protocol,omitempty"`
+	ExpirationDate *Timestamp   `json:"expiration_date,omitempty"`
+	Stopping       string       `json:"stopping,omitempty"`
+}
+
+// errStopStream ends a stream once the wanted chunks have been read
+var errStopStream = errors.New("stream stopped")
+
+// GetActiveChains reads the first chunk of GET /monitor/active_chains, the chains currently active on the node
+func (rpc *RPC) GetActiveChains(ctx context.Context) ([]ActiveChain, error) {
+	chains := []ActiveChain{}
+	_, err := rpc.stream(ctx, "/monitor/active_chains", func(decoder *json.Decoder) error {
+		if err := decoder.Decode(&chains); err != nil {
+			return err
+		}
+		return errStopStream
+	})
+	if err != errStopStream {
+		return nil, err
+	}
+	return chains, nil
+}
+
+// MonitorActiveChains calls GET /monitor/active_chains and streams the set of active chains
+// every time it changes, e.g. when a test chain is activated. Both channels are closed once
+// ctx is cancelled, errs receives the error that stopped the stream otherwise.
+func (rpc *RPC) MonitorActiveChains(ctx context.Context) (<-chan []ActiveChain, <-chan error) {
+	updates := make(chan []ActiveChain)
+	errs := make(chan error, 1)
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(updates)
+		defer close(errs)
+		err := rpc.monitor(ctx, "/monitor/active_chains", func(decoder *json.Decoder) error {
+			chains := []ActiveChain{}
+			if err := decoder.Decode(&chains); err != nil {
+				return err
+			}
+			select {
+			case updates <- chains:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return updates, errs
+}
+
+// BootstrappedStatus holds a chunk of the response from `GET /monitor/bootstrapped`
+type BootstrappedStatus struct {
+	Block     BlockHash `json:"block"`
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// MonitorBootstrapped calls GET /monitor/bootstrapped and blocks until the node reports it
+// is bootstrapped, returning the head it was synchronised to. It returns ErrNodeNotBootstrapped
+// when ctx ends first.
+func (rpc *RPC) MonitorBootstrapped(ctx context.Context) (BootstrappedStatus, error) {
+	status := BootstrappedStatus{}
+	_, err := rpc.stream(ctx, "/monitor/bootstrapped", func(decoder *json.Decoder) error {
+		return decoder.Decode(&status)
+	})
+	if err != io.EOF {
+		if ctx.Err() != nil {
+			return BootstrappedStatus{}, fmt.Errorf("%w: %v", ErrNodeNotBootstrapped, ctx.Err())
+		}
+		return BootstrappedStatus{}, err
+	}
+	if status.Block == "" {
+		return BootstrappedStatus{}, errors.New("bootstrapped stream ended without reporting a block")
+	}
+	return status, nil
+}
+
+// NetworkEvent is an event of `GET /network/log`, a change of the connections or peers of the
+// node such as connection_established or peer disconnected
+type NetworkEvent struct {
+	Event string `json:"event"`
+	// PeerID is set for the events about a peer
+	PeerID PeerID `json:"peer_id,omitempty"`
+	// Raw is the event as sent by the node, the fields of which depend on the event
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the event and keeps it as Raw
+func (e *NetworkEvent) UnmarshalJSON(b []byte) error {
+	type event NetworkEvent
+	if err := json.Unmarshal(b, (*event)(e)); err != nil {
+		return err
+	}
+	e.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// MarshalJSON encodes the event as sent by the node
+func (e NetworkEvent) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	type event NetworkEvent
+	return json.Marshal(event(e))
+}
+
+// MonitorNetworkLog calls GET /network/log and streams the events of the network of the node,
+// reconnecting when the stream drops. Both channels are closed once ctx is cancelled, errs
+// receives the error that stopped the stream otherwise.
+func (rpc *RPC) MonitorNetworkLog(ctx context.Context) (<-chan NetworkEvent, <-chan error) {
+	events := make(chan NetworkEvent)
+	errs := make(chan error, 1)
+	ctx, done := rpc.begin(ctx)
+	go func() {
+		defer done()
+		defer close(events)
+		defer close(errs)
+		err := rpc.monitor(ctx, "/network/log", func(decoder *json.Decoder) error {
+			event := NetworkEvent{}
+			if err := decoder.Decode(&event); err != nil {
+				return err
+			}
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
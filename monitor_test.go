@@ -0,0 +1,187 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestMonitorBootstrapped(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /monitor/bootstrapped": rawBody(`{"block":"BLold","timestamp":"2018-08-01T00:00:00Z"}
+{"block":"BLhead","timestamp":"2018-09-01T00:00:00Z"}`),
+	})
+	status, err := client.MonitorBootstrapped(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Block != "BLhead" || status.Timestamp.String() != "2018-09-01T00:00:00Z" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestMonitorBootstrappedTimeout(t *testing.T) {
+	// the node reports its progress but never finishes bootstrapping
+	bootstrapping := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"block":"BLold","timestamp":"2018-08-01T00:00:00Z"}`))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer bootstrapping.Close()
+	client := tgo.GenerateClient(bootstrapping.URL, time.Second*5)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.MonitorBootstrapped(ctx); !errors.Is(err, tgo.ErrNodeNotBootstrapped) {
+		t.Fatalf("expected ErrNodeNotBootstrapped got %v", err)
+	}
+}
+
+func TestMonitorHeads(t *testing.T) {
+	streams := 0
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /monitor/heads/main": func([]byte) interface{} {
+			streams++
+			if streams == 1 {
+				// the first stream drops in the middle of a chunk
+				return rawBody(`{"hash":"BL1","level":1}
+{"hash":"BL2","lev`)
+			}
+			return rawBody(fmt.Sprintf(`{"hash":"BL%d","level":%d}`, streams+1, streams+1))
+		},
+	})
+	client.PollInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	heads, errs := client.MonitorHeads(ctx, "main")
+	for _, expected := range []int64{1, 3, 4} {
+		if head := <-heads; head.Level != expected || string(head.Hash) != fmt.Sprintf("BL%d", expected) {
+			t.Fatalf("expected level %d got %+v", expected, head)
+		}
+	}
+	cancel()
+	for range heads {
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	// a node restarting drops the stream then refuses to reopen it once
+	requests := 0
+	restarting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Write([]byte(`{"hash":"BL1","level":1}`))
+		case 2:
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"hash":"BL2","level":2}`))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer restarting.Close()
+	client = tgo.GenerateClient(restarting.URL, time.Second*5)
+	client.PollInterval = time.Millisecond
+	ctx, cancel = context.WithCancel(context.Background())
+	heads, errs = client.MonitorHeads(ctx, "main")
+	for _, expected := range []int64{1, 2} {
+		if head := <-heads; head.Level != expected {
+			t.Fatalf("expected level %d got %+v", expected, head)
+		}
+	}
+	cancel()
+	for range heads {
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	_, client = newFakeNode(t, map[string]interface{}{})
+	heads, errs = client.MonitorHeads(context.Background(), "main")
+	for range heads {
+	}
+	if err := <-errs; err == nil {
+		t.Fatal("expected error for missing endpoint")
+	}
+}
+
+func TestMonitorValidBlocks(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /monitor/valid_blocks": rawBody(`{"chain_id":"NetXdQprcVkpaWU","hash":"BLa","level":7}
+{"chain_id":"NetXdQprcVkpaWU","hash":"BLb","level":7}`),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blocks, _ := client.MonitorValidBlocks(ctx, tgo.ValidBlocksOptions{
+		Protocols: []string{"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"},
+		Chains:    []string{"main", "test"},
+	})
+	for _, expected := range []string{"BLa", "BLb"} {
+		if block := <-blocks; string(block.Hash) != expected || block.ChainID != "NetXdQprcVkpaWU" {
+			t.Fatalf("expected %s got %+v", expected, block)
+		}
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if q := node.queries["GET /monitor/valid_blocks"][0]; q != "chain=main&chain=test&protocol=PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS" {
+		t.Fatalf("unexpected query %s", q)
+	}
+}
+
+func TestActiveChains(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /monitor/active_chains": rawBody(`[{"chain_id":"NetXdQprcVkpaWU"}]
+[{"chain_id":"NetXdQprcVkpaWU"},{"chain_id":"NetXtest","test_protocol":"PtTest","expiration_date":"2019-01-01T00:00:00Z"}]`),
+		"GET /monitor/protocols": rawBody(`"PtOld" "PtNew"`),
+	})
+	chains, err := client.GetActiveChains(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != 1 || chains[0].ChainID != "NetXdQprcVkpaWU" {
+		t.Fatalf("unexpected chains %+v", chains)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, _ := client.MonitorActiveChains(ctx)
+	<-updates
+	if update := <-updates; len(update) != 2 || update[1].TestProtocol != "PtTest" {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	protocols, _ := client.MonitorProtocols(ctx)
+	if first, second := <-protocols, <-protocols; first != "PtOld" || second != "PtNew" {
+		t.Fatalf("unexpected protocols %s %s", first, second)
+	}
+}
+
+func TestMonitorNetworkLog(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/log": rawBody(`{"event":"too_few_connections"}
+{"event":"connection_established","id_point":"1.2.3.4:9732","direction":"outgoing","peer_id":"idrpUzAVLGQ5ahqWeCRjq8SuRWKJxh"}`),
+	})
+	client.PollInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := client.MonitorNetworkLog(ctx)
+	first, second := <-events, <-events
+	if first.Event != "too_few_connections" || second.Event != "connection_established" || second.PeerID != "idrpUzAVLGQ5ahqWeCRjq8SuRWKJxh" {
+		t.Fatalf("unexpected events %+v and %+v", first, second)
+	}
+	// the event is encoded back as sent by the node
+	b, err := json.Marshal(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"direction":"outgoing"`) {
+		t.Fatalf("unexpected encoding %s", b)
+	}
+}
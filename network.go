@@ -1,28 +1,25 @@
 package tgo
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"regexp"
+	"strconv"
 	"time"
 )
 
 // ConnectionsResponse holds the response from `GET /network/connections`
 type ConnectionsResponse struct {
-	Incoming bool   `json:"incoming"`
-	PeerID   string `json:"peer_id"`
-	IDPoint  struct {
-		Address string `json:"addr"`
-		Port    int64  `json:"port"`
-	} `json:"id_point"`
-	RemoteSocketPort int64 `json:"remote_socket_port"`
+	Incoming         bool   `json:"incoming"`
+	PeerID           PeerID `json:"peer_id"`
+	IDPoint          Point  `json:"id_point"`
+	RemoteSocketPort int64  `json:"remote_socket_port"`
 	Versions         []struct {
 		Name  string `json:"name"`
-		Major int64  `json:"magor"`
-		Minor int64  `json:"miner"`
+		Major int64  `json:"major"`
+		Minor int64  `json:"minor"`
 	} `json:"versions"`
 	Private       bool `json:"private"`
 	LocalMetadata struct {
@@ -37,17 +34,8 @@ type ConnectionsResponse struct {
 
 // GetConnections calls GET /network/connections
 func (rpc *RPC) GetConnections() ([]ConnectionsResponse, error) {
-	resp, err := rpc.Client.Get(fmt.Sprintf("%s/network/connections", rpc.URL))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	cp := []ConnectionsResponse{}
-	err = json.Unmarshal(respBytes, &cp)
+	err := rpc.get(context.Background(), "/network/connections", &cp)
 	if err != nil {
 		return nil, err
 	}
@@ -55,28 +43,19 @@ func (rpc *RPC) GetConnections() ([]ConnectionsResponse, error) {
 }
 
 // GetPeerID calls GET /network/connections/<peer_id>
-func (rpc *RPC) GetPeerID(peerID string) (ConnectionsResponse, error) {
-	resp, err := rpc.Client.Get(fmt.Sprintf("%s/network/connections/%s", rpc.URL, peerID))
-	if err != nil {
-		return ConnectionsResponse{}, err
-	}
-	defer resp.Body.Close()
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return ConnectionsResponse{}, err
-	}
+func (rpc *RPC) GetPeerID(peerID PeerID) (ConnectionsResponse, error) {
 	cp := ConnectionsResponse{}
-	err = json.Unmarshal(respBytes, &cp)
+	err := rpc.get(context.Background(), peerPath("/network/connections", peerID), &cp)
 	if err != nil {
-		return ConnectionsResponse{}, err
+		return ConnectionsResponse{}, peerError(peerID, err)
 	}
 	return cp, nil
 }
 
 // RemovePeers can be used to remove multiple peers at once
 // Calls DELETE /network/connections/<peer_id>
-func (rpc *RPC) RemovePeers(peers map[string]bool) ([]string, error) {
-	processedPeers := []string{}
+func (rpc *RPC) RemovePeers(peers map[PeerID]bool) ([]PeerID, error) {
+	processedPeers := []PeerID{}
 	for k, v := range peers {
 		err := rpc.RemovePeer(k, v)
 		if err != nil {
@@ -88,70 +67,62 @@ func (rpc *RPC) RemovePeers(peers map[string]bool) ([]string, error) {
 }
 
 // RemovePeer calls DELETE /network/connections/<peer_id>
-func (rpc *RPC) RemovePeer(peerID string, wait bool) error {
-	url := fmt.Sprintf("%s/network/connections/%s", rpc.URL, peerID)
-	if wait {
-		url = fmt.Sprintf("%s?wait", url)
-	}
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := rpc.Client.Do(req)
-	defer resp.Body.Close()
-	if resp.Status != "200 OK" {
-		return fmt.Errorf("expected status '200 OK' got %s", resp.Status)
+func (rpc *RPC) RemovePeer(peerID PeerID, wait bool) error {
+	path := query{}.flag("wait", wait).path(peerPath("/network/connections", peerID))
+	return peerError(peerID, rpc.do(context.Background(), http.MethodDelete, path, nil, nil))
+}
+
+// peerError wraps a not found error on peerID with ErrPeerNotFound
+func peerError(peerID PeerID, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return &peerNotFoundError{peerID: peerID, err: err}
 	}
-	return nil
+	return err
+}
+
+// peerNotFoundError is a not found error on a peer, matching ErrPeerNotFound as well as the
+// error it wraps
+type peerNotFoundError struct {
+	peerID PeerID
+	err    error
+}
+
+func (e *peerNotFoundError) Error() string {
+	return fmt.Sprintf("peer %s: %s: %s", e.peerID, ErrPeerNotFound, e.err)
+}
+
+func (e *peerNotFoundError) Unwrap() error { return e.err }
+
+// Is reports whether target is ErrPeerNotFound
+func (e *peerNotFoundError) Is(target error) bool { return target == ErrPeerNotFound }
+
+// ConnectPoint calls PUT /network/points/<point>, connecting the node to point and waiting
+// at most timeout for the connection to be established
+func (rpc *RPC) ConnectPoint(point Point, timeout time.Duration) error {
+	return rpc.connectPoint(context.Background(), point, timeout)
+}
+
+// connectPoint is ConnectPoint bound to ctx
+func (rpc *RPC) connectPoint(ctx context.Context, point Point, timeout time.Duration) error {
+	path := query{}.add("timeout", strconv.FormatFloat(timeout.Seconds(), 'g', -1, 64)).path(pointPath(point))
+	return rpc.do(ctx, http.MethodPut, path, nil, nil)
 }
 
 // ClearGreylist calls GET /network/greylist/clear
 func (rpc *RPC) ClearGreylist() error {
-	resp, err := rpc.Client.Get(fmt.Sprintf("%s/network/greylist/clear", rpc.URL))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.Status != "200 OK" {
-		return fmt.Errorf("expected status '200 OK' got %s", resp.Status)
-	}
-	return nil
+	return rpc.do(context.Background(), http.MethodGet, "/network/greylist/clear", nil, nil)
 }
 
-// GetNetworkLog calls GET /network/log
-// NOTE: Currently semi-bugged, closed after the first response
-func (rpc *RPC) GetNetworkLog(waitTime time.Duration) error {
-	url := fmt.Sprintf("%s/network/log", rpc.URL)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
-		return err
-	}
-	go func() {
-		time.Sleep(waitTime)
-		resp.Body.Close()
-	}()
-	//defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
-	token, err := decoder.Token()
-	if err != nil {
-		return err
-	}
-	if delim, ok := token.(json.Delim); !ok || delim != '{' {
-		return errors.New("expected object")
-	}
-	for decoder.More() {
-		_, err := decoder.Token()
-		if err != nil {
-			return err
-		}
-		var v interface{}
-		err = decoder.Decode(&v)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("%+v\n", v)
+// GetNetworkLog calls GET /network/log and returns the events logged by the node during waitTime
+func (rpc *RPC) GetNetworkLog(waitTime time.Duration) ([]NetworkEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTime)
+	defer cancel()
+	events, errs := rpc.MonitorNetworkLog(ctx)
+	var log []NetworkEvent
+	for event := range events {
+		log = append(log, event)
 	}
-	return nil
+	return log, <-errs
 }
 
 type NetworkPeers struct {
@@ -163,58 +134,25 @@ type NetworkPeers struct {
 		PrivateNode    bool `json:"private_node"`
 	} `json:"conn_metadata"`
 	State       string `json:"state"`
-	ReachableAt struct {
-		Addr string `json:"addr"`
-		Port int64  `json:"port"`
-	} `json:"reachable_at"`
-	Stat struct {
+	ReachableAt Point  `json:"reachable_at"`
+	Stat        struct {
 		TotalSent      int64 `json:"total_sent"`
 		TotalRecv      int64 `json:"total_recv"`
 		CurrentInflow  int64 `json:"current_inflow"`
 		CurrentOutflow int64 `json:"current_outflow"`
 	} `json:"stat"`
-	LastFailedConnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port"`
-		Timestamp int64
-	} `json:"last_failed_connection,omitempty"`
-	LastRejectedConnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port"`
-		Timestamp int64
-	} `json:"last_rejected_connection,omitempty"`
-	LastEstablishedConnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port"`
-		Timestamp int64
-	} `json:"last_established_connection,omitempty"`
-	LastDisconnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port"`
-		Timestamp int64
-	} `json:"last_disconnection,omitempty"`
-	LastSeen struct {
-		Addr      string `json:"addr"`
-		Port      string `json:"port"`
-		Timestamp int64
-	} `json:"last_seen,omitempty"`
-	LastMiss struct {
-		Addr      string `json:"addr"`
-		Port      string `json:"port"`
-		Timestamp int64
-	} `json:"last_miss,omitempty"`
+	LastFailedConnection      PointTime `json:"last_failed_connection,omitempty"`
+	LastRejectedConnection    PointTime `json:"last_rejected_connection,omitempty"`
+	LastEstablishedConnection PointTime `json:"last_established_connection,omitempty"`
+	LastDisconnection         PointTime `json:"last_disconnection,omitempty"`
+	LastSeen                  PointTime `json:"last_seen,omitempty"`
+	LastMiss                  PointTime `json:"last_miss,omitempty"`
 }
 
 // GetNetworkPeers calls GET /network/peers
-//TODO: implement filter
+// TODO: implement filter
 func (rpc *RPC) GetNetworkPeers() error {
-	url := fmt.Sprintf("%s/network/peers", rpc.URL)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	respBytes, err := ioutil.ReadAll(resp.Body)
+	respBytes, err := rpc.fetch(context.Background(), http.MethodGet, "/network/peers", nil)
 	if err != nil {
 		return err
 	}
@@ -235,74 +173,214 @@ func (rpc *RPC) GetNetworkPeers() error {
 	return nil
 }
 
+// NetworkStat holds the response from `GET /network/stat`, the bytes exchanged by the node or
+// with a peer in total and per second currently
+type NetworkStat struct {
+	TotalSent      Int64String `json:"total_sent"`
+	TotalRecv      Int64String `json:"total_recv"`
+	CurrentInflow  Int64String `json:"current_inflow"`
+	CurrentOutflow Int64String `json:"current_outflow"`
+}
+
+// GetNetworkStat calls GET /network/stat
+func (rpc *RPC) GetNetworkStat(ctx context.Context) (NetworkStat, error) {
+	stat := NetworkStat{}
+	err := rpc.get(ctx, "/network/stat", &stat)
+	return stat, err
+}
+
 type NetworkPeer struct {
-	Score        int64 `json:"score,string"`
-	Trusted      bool  `json:"trusted"`
+	Score        Int64String `json:"score"`
+	Trusted      bool        `json:"trusted"`
 	ConnMetadata struct {
 		DisableMempool bool `json:"disable_mempool"`
 		PrivateNode    bool `json:"private_node"`
 	} `json:"conn_metadata"`
-	State       string `json:"state"`
-	ReachableAt struct {
-		Addr string `json:"addr"`
-		Port int64  `json:"port"`
-	} `json:"reachable_at"`
-	Stat struct {
-		TotalSent      string `json:"total_sent"`
-		TotalRecv      string `json:"total_recv"`
-		CurrentInflow  int64  `json:"current_inflow,string"`
-		CurrentOutflow int64  `json:"current_outflow,string"`
-	} `json:"stat"`
-	LastFailedConnection struct {
-		Addr      string `json:"addr"`
-		Port      string `json:"port,omitempty"`
-		Timestamp int64
-	} `json:"last_failed_connection,omitempty"`
-	LastRejectedConnection []struct {
-		Addr string `json:"addr"`
-		Port string `json:"port,omitempty"`
-		//Timestamp int64
-	} `json:"last_rejected_connection,omitempty"`
-	LastEstablishedConnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port,omitempty"`
-		Timestamp int64
-	} `json:"last_established_connection,omitempty"`
-	LastDisconnection struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port,omitempty"`
-		Timestamp int64
-	} `json:"last_disconnection,omitempty"`
-	LastSeen struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port,omitempty"`
-		Timestamp int64
-	} `json:"last_seen,omitempty"`
-	LastMiss struct {
-		Addr      string `json:"addr"`
-		Port      int64  `json:"port,omitempty"`
-		Timestamp int64
-	} `json:"last_miss,omitempty"`
-}
-
-func (rpc *RPC) GetNetworkPeer(peerID string) error {
-	url := fmt.Sprintf("%s/network/peers/%s", rpc.URL, peerID)
-	resp, err := rpc.Client.Get(url)
-	if err != nil {
+	State                     string      `json:"state"`
+	ReachableAt               Point       `json:"reachable_at"`
+	Stat                      NetworkStat `json:"stat"`
+	LastFailedConnection      PointTime   `json:"last_failed_connection,omitempty"`
+	LastRejectedConnection    []Point     `json:"last_rejected_connection,omitempty"`
+	LastEstablishedConnection PointTime   `json:"last_established_connection,omitempty"`
+	LastDisconnection         PointTime   `json:"last_disconnection,omitempty"`
+	LastSeen                  PointTime   `json:"last_seen,omitempty"`
+	LastMiss                  PointTime   `json:"last_miss,omitempty"`
+}
+
+// GetNetworkPeer calls GET /network/peers/<peer_id>
+//
+// Deprecated: use GetPeer, which it calls.
+func (rpc *RPC) GetNetworkPeer(peerID PeerID) (NetworkPeer, error) {
+	return rpc.GetPeer(peerID)
+}
+
+// GetPeer calls GET /network/peers/<peer_id>
+func (rpc *RPC) GetPeer(peerID PeerID) (NetworkPeer, error) {
+	peer := NetworkPeer{}
+	if err := rpc.get(context.Background(), peerPath("/network/peers", peerID), &peer); err != nil {
+		return NetworkPeer{}, peerError(peerID, err)
+	}
+	return peer, nil
+}
+
+// KnownPeer is an entry of `GET /network/peers`, a peer known to the node with its id
+type KnownPeer struct {
+	ID PeerID
+	NetworkPeer
+}
+
+// UnmarshalJSON decodes the [peer_id, peer] pair sent by the node
+func (p *KnownPeer) UnmarshalJSON(b []byte) error {
+	pair := []json.RawMessage{}
+	if err := json.Unmarshal(b, &pair); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a peer id and a peer, got %d elements", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], &p.ID); err != nil {
 		return err
 	}
-	re := regexp.MustCompile(`(":\s*)([\d\.]+)(\s*[,}])`)
-	respBytes = re.ReplaceAll(respBytes, []byte(`$1"$2"$3`))
-	peer := NetworkPeer{}
-	err = json.Unmarshal(respBytes, &peer)
+	return json.Unmarshal(pair[1], &p.NetworkPeer)
+}
+
+// MarshalJSON encodes the peer as the [peer_id, peer] pair sent by the node
+func (p KnownPeer) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{p.ID, p.NetworkPeer})
+}
+
+// GetPeers calls GET /network/peers and returns the peers known to the node, only those in one
+// of states, such as running, accepted or disconnected, when given
+func (rpc *RPC) GetPeers(ctx context.Context, states ...string) ([]KnownPeer, error) {
+	peers := []KnownPeer{}
+	if err := rpc.get(ctx, query{}.add("filter", states...).path("/network/peers"), &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// PeerTime is when a peer was last involved in an event of a point, encoded by the node as a
+// [peer_id, timestamp] pair
+type PeerTime struct {
+	PeerID PeerID
+	Time   Timestamp
+}
+
+// UnmarshalJSON decodes the [peer_id, timestamp] pair sent by the node
+func (p *PeerTime) UnmarshalJSON(b []byte) error {
+	pair := []json.RawMessage{}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a peer id and a timestamp, got %d elements", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], &p.PeerID); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &p.Time)
+}
+
+// MarshalJSON encodes the [peer_id, timestamp] pair sent by the node
+func (p PeerTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{p.PeerID, p.Time})
+}
+
+// NetworkPoint holds the response from `GET /network/points/<point>`
+type NetworkPoint struct {
+	Trusted         bool       `json:"trusted"`
+	GreylistedUntil *Timestamp `json:"greylisted_until,omitempty"`
+	State           struct {
+		// EventKind is requested, accepted, running or disconnected
+		EventKind string `json:"event_kind"`
+		P2PPeerID PeerID `json:"p2p_peer_id,omitempty"`
+	} `json:"state"`
+	P2PPeerID                 PeerID     `json:"p2p_peer_id,omitempty"`
+	LastFailedConnection      *Timestamp `json:"last_failed_connection,omitempty"`
+	LastRejectedConnection    *PeerTime  `json:"last_rejected_connection,omitempty"`
+	LastEstablishedConnection *PeerTime  `json:"last_established_connection,omitempty"`
+	LastDisconnection         *PeerTime  `json:"last_disconnection,omitempty"`
+	LastSeen                  *PeerTime  `json:"last_seen,omitempty"`
+	LastMiss                  *Timestamp `json:"last_miss,omitempty"`
+}
+
+// KnownPoint is an entry of `GET /network/points`, a point known to the node
+type KnownPoint struct {
+	Point Point
+	NetworkPoint
+}
+
+// UnmarshalJSON decodes the ["addr:port", point] pair sent by the node
+func (p *KnownPoint) UnmarshalJSON(b []byte) error {
+	pair := []json.RawMessage{}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a point and its status, got %d elements", len(pair))
+	}
+	var s string
+	if err := json.Unmarshal(pair[0], &s); err != nil {
+		return err
+	}
+	point, err := ParsePoint(s)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%+v\n", peer)
+	p.Point = point
+	return json.Unmarshal(pair[1], &p.NetworkPoint)
+}
+
+// MarshalJSON encodes the point as the ["addr:port", point] pair sent by the node
+func (p KnownPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{p.Point.String(), p.NetworkPoint})
+}
+
+// GetPoints calls GET /network/points and returns the points known to the node, only those in
+// one of states, such as requested, accepted, running or disconnected, when given
+func (rpc *RPC) GetPoints(ctx context.Context, states ...string) ([]KnownPoint, error) {
+	points := []KnownPoint{}
+	if err := rpc.get(ctx, query{}.add("filter", states...).path("/network/points"), &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// ACL is the access policy of the node towards a peer or a point
+type ACL string
+
+// ACLs accepted by SetPeerACL and SetPointACL, ACLOpen lifts a ban or a trust
+const (
+	ACLOpen  ACL = "open"
+	ACLBan   ACL = "ban"
+	ACLTrust ACL = "trust"
+)
+
+// SetPeerACL calls PATCH /network/peers/<peer_id> to ban, trust or open the peer
+func (rpc *RPC) SetPeerACL(peerID PeerID, acl ACL) error {
+	return rpc.setPeerACL(context.Background(), peerID, acl)
+}
+
+// setPeerACL is SetPeerACL bound to ctx
+func (rpc *RPC) setPeerACL(ctx context.Context, peerID PeerID, acl ACL) error {
+	body := struct {
+		ACL ACL `json:"acl"`
+	}{acl}
+	if err := rpc.do(ctx, http.MethodPatch, peerPath("/network/peers", peerID), body, nil); err != nil {
+		return peerError(peerID, err)
+	}
 	return nil
 }
+
+// SetPointACL calls PATCH /network/points/<point> to ban, trust or open the point
+func (rpc *RPC) SetPointACL(point Point, acl ACL) error {
+	return rpc.setPointACL(context.Background(), point, acl)
+}
+
+// setPointACL is SetPointACL bound to ctx
+func (rpc *RPC) setPointACL(ctx context.Context, point Point, acl ACL) error {
+	body := struct {
+		ACL ACL `json:"acl"`
+	}{acl}
+	return rpc.do(ctx, http.MethodPatch, pointPath(point), body, nil)
+}
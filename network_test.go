@@ -1,7 +1,9 @@
 package tgo_test
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -18,3 +20,119 @@ func TestGetconnections(t *testing.T) {
 
 	fmt.Printf("%+v\n", connections)
 }
+
+func TestNetworkStatusErrors(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"DELETE /network/connections/idtAZ3": rawBody(`{}`),
+	})
+	if err := client.RemovePeer("idtAZ3", true); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	query := node.queries["DELETE /network/connections/idtAZ3"]
+	node.mu.Unlock()
+	if len(query) != 1 || query[0] != "wait" {
+		t.Fatalf("unexpected query %v", query)
+	}
+	// error bodies are reported instead of being decoded
+	for _, err := range []error{
+		func() error { _, err := client.GetConnections(); return err }(),
+		func() error { _, err := client.GetPeerID("idtMissing"); return err }(),
+		client.GetNetworkPeers(),
+		client.RemovePeer("idtMissing", false),
+		client.ClearGreylist(),
+	} {
+		var statusErr *tgo.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || !errors.Is(err, tgo.ErrNotFound) {
+			t.Fatalf("expected a not found status error got %v", err)
+		}
+	}
+	if err := client.RemovePeer("idtMissing", false); !errors.Is(err, tgo.ErrPeerNotFound) {
+		t.Fatalf("expected ErrPeerNotFound got %v", err)
+	}
+}
+
+func TestGetNetworkPeer(t *testing.T) {
+	const raw = `{"score":12,"trusted":false,"state":"running","reachable_at":{"addr":"1.2.3.4","port":9732},
+		"stat":{"total_sent":"1048576","total_recv":"2097152","current_inflow":512,"current_outflow":"256"},
+		"last_failed_connection":{"addr":"::ffff:10.0.0.1","port":"9732","timestamp":"2019-09-01T12:00:00Z"}}`
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/peers/idtAZ3": rawBody(raw),
+	})
+	peer, err := client.GetNetworkPeer("idtAZ3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peer.Score != 12 || peer.ReachableAt.Addr != "1.2.3.4" || peer.ReachableAt.Port != 9732 || peer.Stat.TotalRecv != 2097152 ||
+		peer.Stat.CurrentInflow != 512 || peer.Stat.CurrentOutflow != 256 || peer.LastFailedConnection.Point != (tgo.Point{Addr: "::ffff:10.0.0.1", Port: 9732}) ||
+		peer.LastFailedConnection.Timestamp.IsZero() {
+		t.Fatalf("unexpected peer %+v", peer)
+	}
+	if _, err := client.GetNetworkPeer("idtMissing"); !errors.Is(err, tgo.ErrPeerNotFound) {
+		t.Fatalf("expected ErrPeerNotFound got %v", err)
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	for s, expected := range map[string]tgo.Point{
+		"1.2.3.4:9732":            {Addr: "1.2.3.4", Port: 9732},
+		"[::ffff:10.0.0.1]:19732": {Addr: "::ffff:10.0.0.1", Port: 19732},
+	} {
+		point, err := tgo.ParsePoint(s)
+		if err != nil || point != expected || point.String() != s {
+			t.Fatalf("%s: unexpected point %v: %v", s, point, err)
+		}
+	}
+	for _, s := range []string{"1.2.3.4", "::1:9732", "1.2.3.4:0", "1.2.3.4:65536", "node:9732"} {
+		if _, err := tgo.ParsePoint(s); err == nil {
+			t.Fatalf("expected %s to be invalid", s)
+		}
+	}
+}
+
+func TestConnectPoint(t *testing.T) {
+	if err := tgo.PeerID("idqRfT3vC16rJyZRUEbn5W6fd8fM37").Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgo.PeerID("idtAZ3").Validate(); err == nil {
+		t.Fatal("expected idtAZ3 to be invalid")
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"PUT /network/points/[::1]:9732": rawBody(`{}`),
+	})
+	if err := client.ConnectPoint(tgo.Point{Addr: "::1", Port: 9732}, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	query := node.queries["PUT /network/points/[::1]:9732"]
+	node.mu.Unlock()
+	if len(query) != 1 || query[0] != "timeout=10" {
+		t.Fatalf("unexpected query %v", query)
+	}
+}
+
+func TestSetACL(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/peers/idtAZ3":          rawBody(`{"score":3,"trusted":true,"state":"running"}`),
+		"PATCH /network/peers/idtAZ3":        rawBody(`{}`),
+		"PATCH /network/points/1.2.3.4:9732": rawBody(`{}`),
+	})
+	if peer, err := client.GetPeer("idtAZ3"); err != nil || peer.Score != 3 || !peer.Trusted {
+		t.Fatalf("unexpected peer %+v: %v", peer, err)
+	}
+	if err := client.SetPeerACL("idtAZ3", tgo.ACLBan); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetPointACL(tgo.Point{Addr: "1.2.3.4", Port: 9732}, tgo.ACLOpen); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	peerBody, pointBody := node.bodies["PATCH /network/peers/idtAZ3"], node.bodies["PATCH /network/points/1.2.3.4:9732"]
+	node.mu.Unlock()
+	if len(peerBody) != 1 || peerBody[0] != `{"acl":"ban"}` || len(pointBody) != 1 || pointBody[0] != `{"acl":"open"}` {
+		t.Fatalf("unexpected bodies %v %v", peerBody, pointBody)
+	}
+	if err := client.SetPeerACL("idtMissing", tgo.ACLOpen); !errors.Is(err, tgo.ErrPeerNotFound) {
+		t.Fatalf("expected ErrPeerNotFound got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Network is a known public network with the chain id of its main chain and public nodes
+// serving its RPC
+type Network struct {
+	Name    string
+	ChainID ChainID
+	// RPCURLs are public nodes of the network, the first one is used by NewNetworkClient
+	RPCURLs []string
+}
+
+// Presets of mainnet and of the active testnets
+var (
+	Mainnet = Network{
+		Name:    "mainnet",
+		ChainID: "NetXdQprcVkpaWU",
+		RPCURLs: []string{"https://mainnet.api.tez.ie", "https://mainnet.smartpy.io", "https://rpc.tzbeta.net"},
+	}
+	Hangzhounet = Network{
+		Name:    "hangzhounet",
+		ChainID: "NetXZSsxBpMQeAT",
+		RPCURLs: []string{"https://hangzhounet.api.tez.ie", "https://hangzhounet.smartpy.io"},
+	}
+	Ithacanet = Network{
+		Name:    "ithacanet",
+		ChainID: "NetXnHfVqm9iesp",
+		RPCURLs: []string{"https://ithacanet.ecadinfra.com", "https://ithacanet.smartpy.io"},
+	}
+)
+
+// Networks are the presets by name
+var Networks = map[string]Network{
+	Mainnet.Name:     Mainnet,
+	Hangzhounet.Name: Hangzhounet,
+	Ithacanet.Name:   Ithacanet,
+}
+
+// NewNetworkClient returns a client of the first public node of network, whose Network is
+// set so VerifyNetwork checks the node serves it
+func NewNetworkClient(network Network, timeout time.Duration) *RPC {
+	url := ""
+	if len(network.RPCURLs) > 0 {
+		url = network.RPCURLs[0]
+	}
+	rpc := GenerateClient(url, timeout)
+	rpc.Network = &network
+	return rpc
+}
+
+// VerifyNetwork returns an error matching ErrWrongNetwork when the chain targeted by ctx
+// does not have the chain id of rpc.Network, so tooling configured for one network is not
+// pointed at another by mistake. It returns an error when rpc.Network is not set.
+func (rpc *RPC) VerifyNetwork(ctx context.Context) error {
+	if rpc.Network == nil {
+		return fmt.Errorf("no network to verify %s against", rpc.URL)
+	}
+	chainID, err := rpc.GetChainID(ctx, rpc.ChainAlias(ctx))
+	if err != nil {
+		return err
+	}
+	if ChainID(chainID) != rpc.Network.ChainID {
+		return fmt.Errorf("%w: %s serves chain %s, %s is %s", ErrWrongNetwork, rpc.URL, chainID, rpc.Network.Name, rpc.Network.ChainID)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package tgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestNetworks(t *testing.T) {
+	for name, network := range tgo.Networks {
+		if network.Name != name || network.ChainID.Validate() != nil || len(network.RPCURLs) == 0 {
+			t.Fatalf("invalid preset %s %+v", name, network)
+		}
+	}
+	if client := tgo.NewNetworkClient(tgo.Mainnet, time.Second); client.URL != "https://mainnet.api.tez.ie" || client.Network.Name != "mainnet" {
+		t.Fatalf("unexpected client of %s for %+v", client.URL, client.Network)
+	}
+}
+
+func TestVerifyNetwork(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/chain_id": tgo.Ithacanet.ChainID,
+	})
+	ctx := context.Background()
+	if err := client.VerifyNetwork(ctx); err == nil {
+		t.Fatal("expected error without a network")
+	}
+	client.Network = &tgo.Ithacanet
+	if err := client.VerifyNetwork(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client.Network = &tgo.Mainnet
+	if err := client.VerifyNetwork(ctx); !errors.Is(err, tgo.ErrWrongNetwork) {
+		t.Fatalf("expected a wrong network error, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package tgo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// nonceSize is the size in bytes of a seed nonce
+const nonceSize = 32
+
+// NonceStatus holds the response from `GET /chains/<chain>/blocks/<block_id>/context/nonces/<level>`.
+// Nonce is set once revealed, Hash while the commitment awaits its revelation, neither
+// once the nonce has been forgotten.
+type NonceStatus struct {
+	Nonce string `json:"nonce,omitempty"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+// GetNonce calls GET /chains/<chain>/blocks/<block_id>/context/nonces/<level>
+func (rpc *RPC) GetNonce(ctx context.Context, blockID BlockID, level int64) (NonceStatus, error) {
+	status := NonceStatus{}
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/nonces/%d", rpc.ChainAlias(ctx), blockID, level), &status)
+	return status, err
+}
+
+// NonceHash returns the commitment a baker publishes in the header of a block for the hex
+// encoded nonce
+func NonceHash(nonce string) (string, error) {
+	raw, err := hex.DecodeString(nonce)
+	if err != nil || len(raw) != nonceSize {
+		return "", fmt.Errorf("invalid nonce %q", nonce)
+	}
+	return b58CheckEncode(prefixNonce, blake2b(raw, 32)), nil
+}
+
+// RevealNonce injects a seed_nonce_revelation operation for the nonce committed at level
+// and returns the operation hash. Revelations are anonymous and injected unsigned.
+func (rpc *RPC) RevealNonce(ctx context.Context, level int64, nonce string) (string, error) {
+	if _, err := NonceHash(nonce); err != nil {
+		return "", err
+	}
+	return rpc.sendUnsigned(ctx, []OperationContents{{
+		Kind:  "seed_nonce_revelation",
+		Level: level,
+		Nonce: nonce,
+	}})
+}
+
+// committedNonce is a nonce waiting for the cycle following its commitment
+type committedNonce struct {
+	nonce string
+	// cycle is the cycle of the committing block, -1 until looked up
+	cycle int64
+}
+
+// NonceTracker keeps the nonces a baker committed to and reveals them during the cycle
+// following their commitment, as required to avoid losing the baking rewards
+type NonceTracker struct {
+	rpc    *RPC
+	mu     sync.Mutex
+	nonces map[int64]*committedNonce
+}
+
+// NewNonceTracker returns an empty tracker revealing nonces through rpc
+func NewNonceTracker(rpc *RPC) *NonceTracker {
+	return &NonceTracker{rpc: rpc, nonces: map[int64]*committedNonce{}}
+}
+
+// Commit records the hex encoded nonce whose hash was included in the block baked at level
+func (t *NonceTracker) Commit(level int64, nonce string) error {
+	if _, err := NonceHash(nonce); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nonces[level] = &committedNonce{nonce: nonce, cycle: -1}
+	return nil
+}
+
+// Pending returns the levels of the nonces not revealed yet, in increasing order
+func (t *NonceTracker) Pending() []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	levels := make([]int64, 0, len(t.nonces))
+	for level := range t.nonces {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels
+}
+
+// RevealDue reveals every nonce committed in the cycle before the cycle of head and returns
+// the hashes of the injected operations. Nonces already revealed, forgotten or whose revelation
+// window has passed are dropped, nonces failing to be revealed are kept for the next call.
+func (t *NonceTracker) RevealDue(ctx context.Context) ([]string, error) {
+	metadata, err := t.rpc.GetBlockMetadata(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	head := metadata.CurrentLevel()
+	hashes := []string{}
+	var firstErr error
+	for _, level := range t.Pending() {
+		t.mu.Lock()
+		committed, ok := t.nonces[level]
+		var nonce committedNonce
+		if ok {
+			nonce = *committed
+		}
+		t.mu.Unlock()
+		if !ok {
+			continue
+		}
+		hash, done, err := t.reveal(ctx, head, level, nonce)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("revealing nonce of level %d: %w", level, err)
+			}
+			continue
+		}
+		if hash != "" {
+			hashes = append(hashes, hash)
+		}
+		if done {
+			t.mu.Lock()
+			delete(t.nonces, level)
+			t.mu.Unlock()
+		}
+	}
+	return hashes, firstErr
+}
+
+// reveal injects the revelation of committed if it is due at head, done reports whether
+// the nonce can be forgotten by the tracker. The cycle looked up is recorded in the tracker.
+func (t *NonceTracker) reveal(ctx context.Context, head BlockLevel, level int64, committed committedNonce) (string, bool, error) {
+	if level >= head.Level {
+		return "", false, nil
+	}
+	if committed.cycle < 0 {
+		metadata, err := t.rpc.GetBlockMetadata(ctx, LevelID(level))
+		if err != nil {
+			return "", false, err
+		}
+		committed.cycle = metadata.CurrentLevel().Cycle
+		t.mu.Lock()
+		if stored, ok := t.nonces[level]; ok {
+			stored.cycle = committed.cycle
+		}
+		t.mu.Unlock()
+	}
+	switch {
+	case head.Cycle <= committed.cycle:
+		return "", false, nil
+	case head.Cycle > committed.cycle+1:
+		return "", true, nil
+	}
+	status, err := t.rpc.GetNonce(ctx, "head", level)
+	if err != nil {
+		return "", false, err
+	}
+	if status.Hash == "" {
+		return "", true, nil
+	}
+	hash, err := t.rpc.RevealNonce(ctx, level, committed.nonce)
+	if err != nil {
+		return "", false, err
+	}
+	return hash, true, nil
+}
+
+// Run reveals due nonces on every new head until ctx is cancelled or the head stream fails.
+// Failed revelations are retried on the following heads.
+func (t *NonceTracker) Run(ctx context.Context) error {
+	heads, errs := t.rpc.MonitorHeads(ctx, t.rpc.ChainAlias(ctx))
+	for range heads {
+		if len(t.Pending()) > 0 {
+			t.RevealDue(ctx)
+		}
+	}
+	return <-errs
+}
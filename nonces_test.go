@@ -0,0 +1,87 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestNonceHash(t *testing.T) {
+	hash, err := tgo.NonceHash(strings.Repeat("00", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "nceVGgxfspsx9F9ATYHRUZQtA5UCN8fM9utNQuWzzo8wU9dUKi9Kz" {
+		t.Fatalf("unexpected nonce hash %s", hash)
+	}
+	if _, err := tgo.NonceHash("00"); err == nil {
+		t.Fatal("expected error for a short nonce")
+	}
+}
+
+func TestNonceTracker(t *testing.T) {
+	level := func(level, cycle int64) map[string]interface{} {
+		return map[string]interface{}{"level": map[string]interface{}{"level": level, "cycle": cycle}}
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/metadata":                  level(300, 4),
+		"GET /chains/main/blocks/200/metadata":                   level(200, 2),
+		"GET /chains/main/blocks/250/metadata":                   level(250, 3),
+		"GET /chains/main/blocks/260/metadata":                   level(260, 3),
+		"GET /chains/main/blocks/290/metadata":                   level(290, 4),
+		"GET /chains/main/blocks/head/context/nonces/250":        map[string]interface{}{"hash": "nceVGgxfspsx9F9ATYHRUZQtA5UCN8fM9utNQuWzzo8wU9dUKi9Kz"},
+		"GET /chains/main/blocks/head/context/nonces/260":        map[string]interface{}{"nonce": strings.Repeat("11", 32)},
+		"GET /chains/main/blocks/head/hash":                      "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"POST /chains/main/blocks/head/helpers/forge/operations": strings.Repeat("ab", 40),
+		"POST /injection/operation":                              "ooHash",
+	})
+	tracker := tgo.NewNonceTracker(client)
+	for _, level := range []int64{200, 250, 260, 290, 310} {
+		if err := tracker.Commit(level, strings.Repeat("00", 32)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tracker.Commit(320, "zz"); err == nil {
+		t.Fatal("expected error for an invalid nonce")
+	}
+	hashes, err := tracker.RevealDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hashes, []string{"ooHash"}) {
+		t.Fatalf("unexpected revelations %v", hashes)
+	}
+	if pending := tracker.Pending(); !reflect.DeepEqual(pending, []int64{290, 310}) {
+		t.Fatalf("unexpected pending nonces %v", pending)
+	}
+	forged := tgo.Operation{}
+	if err := json.Unmarshal([]byte(node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"][0]), &forged); err != nil {
+		t.Fatal(err)
+	}
+	c := forged.Contents[0]
+	if c.Kind != "seed_nonce_revelation" || c.Level != 250 || c.Nonce != strings.Repeat("00", 32) {
+		t.Fatalf("unexpected revelation %+v", c)
+	}
+	if injected := node.bodies["POST /injection/operation"][0]; injected != `"`+strings.Repeat("ab", 40)+`"` {
+		t.Fatalf("expected an unsigned operation, got %s", injected)
+	}
+
+	// concurrent calls share the cycles looked up, which go test -race checks
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.RevealDue(context.Background())
+		}()
+	}
+	wg.Wait()
+	if pending := tracker.Pending(); !reflect.DeepEqual(pending, []int64{290, 310}) {
+		t.Fatalf("unexpected pending nonces %v", pending)
+	}
+}
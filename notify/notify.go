@@ -0,0 +1,343 @@
+// Package notify posts chain events to webhooks as JSON, signed with HMAC-SHA256 so the
+// receivers can check they come from the notifier
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/deposits"
+)
+
+// Types of the events built from the watchers
+const (
+	EventHead      = "head"
+	EventMempool   = "mempool"
+	EventDeposit   = "deposit"
+	EventOperation = "operation"
+	EventPeer      = "peer"
+)
+
+// Headers set on every delivery
+const (
+	// HeaderEvent holds the type of the event
+	HeaderEvent = "X-TGo-Event"
+	// HeaderDelivery holds the ID of the event, the same for every attempt
+	HeaderDelivery = "X-TGo-Delivery"
+	// HeaderSignature holds sha256= followed by the hex HMAC-SHA256 of the body keyed with the
+	// secret of the webhook, only set when the webhook has a secret
+	HeaderSignature = "X-TGo-Signature"
+)
+
+// default delivery settings
+const (
+	defaultRetries = 3
+	defaultBackoff = time.Second
+)
+
+// Webhook is a URL events are posted to
+type Webhook struct {
+	URL string
+	// Secret keys the HMAC signature of the payloads, which are not signed when empty
+	Secret string
+	// Events are the types of events delivered, every type when empty
+	Events []string
+}
+
+// wants reports whether the webhook subscribed to events of type t
+func (w Webhook) wants(t string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON payload posted to the webhooks
+type Event struct {
+	// ID identifies the event so receivers can drop the duplicates of retried deliveries
+	ID   string      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// NewEvent returns an event of type t carrying data, with a random ID
+func NewEvent(t string, data interface{}) Event {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return Event{ID: hex.EncodeToString(id), Type: t, Time: time.Now().UTC(), Data: data}
+}
+
+// Notifier posts events to webhooks, retrying failed deliveries
+type Notifier struct {
+	Client *http.Client
+	// Retries is how many times a failed delivery is retried, 3 by default, negative to never retry
+	Retries int
+	// Backoff is the wait before the first retry, doubled after each attempt, 1 second by default
+	Backoff time.Duration
+
+	webhooks []Webhook
+}
+
+// New returns a notifier posting to webhooks
+func New(webhooks ...Webhook) *Notifier {
+	return &Notifier{Client: &http.Client{Timeout: 30 * time.Second}, webhooks: webhooks}
+}
+
+// Notify posts event to every webhook subscribed to its type and returns the first delivery
+// that failed after its retries
+func (n *Notifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, w := range n.webhooks {
+		if !w.wants(event.Type) {
+			continue
+		}
+		if err := n.deliver(ctx, w, event, body); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// Run posts the events until the channel is closed or ctx is cancelled, the failed deliveries
+// are reported as by Handle
+func (n *Notifier) Run(ctx context.Context, events <-chan Event) <-chan error {
+	return Handle(ctx, events, n.Notify)
+}
+
+// Handle calls handle with each event until the channel is closed or ctx is cancelled. errs is
+// closed once handling stops and receives the failures of handle, which are dropped while a
+// previous failure has not been read.
+func Handle(ctx context.Context, events <-chan Event, handle func(context.Context, Event) error) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := handle(ctx, event); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return errs
+}
+
+// deliver posts body to w, retrying network errors, 429 and 5xx responses
+func (n *Notifier) deliver(ctx context.Context, w Webhook, event Event, body []byte) error {
+	retries, backoff := n.Retries, n.Backoff
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, w, event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= retries {
+			return fmt.Errorf("webhook %s: %w", w.URL, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff << uint(attempt)):
+		}
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (n *Notifier) post(ctx context.Context, w Webhook, event Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderDelivery, event.ID)
+	if w.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(w.Secret, body))
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	// the body is drained so the connection is reused
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// Sign returns the value of HeaderSignature for body, sha256= followed by the hex HMAC-SHA256
+// of body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature, the value of HeaderSignature, signs body with secret
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, body)))
+}
+
+// Deposit is the data of deposit events, amounts are in mutez
+type Deposit struct {
+	Address   string `json:"address"`
+	Sender    string `json:"sender"`
+	Amount    int64  `json:"amount"`
+	Operation string `json:"operation"`
+	Block     string `json:"block"`
+	Level     int64  `json:"level"`
+	Internal  bool   `json:"internal"`
+}
+
+// Heads returns the events of heads, whose data are the block headers. The adapters close
+// their events once their source is closed or ctx is cancelled.
+func Heads(ctx context.Context, heads <-chan tgo.BlockHeader) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for head := range heads {
+			if !send(ctx, events, NewEvent(EventHead, head)) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Mempool returns the events of the operations of a mempool monitor, whose data are the operations
+func Mempool(ctx context.Context, ops <-chan tgo.MempoolOperation) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for op := range ops {
+			if !send(ctx, events, NewEvent(EventMempool, op)) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Operations returns the events of the operations of watched addresses, whose data are the
+// tgo.AddressOperation
+func Operations(ctx context.Context, ops <-chan tgo.AddressOperation) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for op := range ops {
+			if !send(ctx, events, NewEvent(EventOperation, op)) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Peers returns the events of the network log, whose data are the events as sent by the node
+func Peers(ctx context.Context, log <-chan tgo.NetworkEvent) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for e := range log {
+			if !send(ctx, events, NewEvent(EventPeer, e)) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Deposits returns the events of the deposits of a scanner, whose data are Deposit
+func Deposits(ctx context.Context, found <-chan deposits.Deposit) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for d := range found {
+			event := NewEvent(EventDeposit, Deposit{
+				Address:   d.Address,
+				Sender:    d.Sender,
+				Amount:    d.Amount,
+				Operation: d.OperationHash.String(),
+				Block:     d.BlockHash.String(),
+				Level:     d.Level,
+				Internal:  d.Internal,
+			})
+			if !send(ctx, events, event) {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Merge returns the events of every source, closed once all the sources are closed or ctx is
+// cancelled
+func Merge(ctx context.Context, sources ...<-chan Event) <-chan Event {
+	events := make(chan Event)
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source <-chan Event) {
+			defer wg.Done()
+			for event := range source {
+				if !send(ctx, events, event) {
+					return
+				}
+			}
+		}(source)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+	return events
+}
+
+// send sends event unless ctx is cancelled first, and reports whether it was sent
+func send(ctx context.Context, events chan<- Event, event Event) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
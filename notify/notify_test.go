@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/notify"
+)
+
+// receiver records the deliveries of a webhook, answering with the statuses in turn then 200
+type receiver struct {
+	mu         sync.Mutex
+	statuses   []int
+	bodies     [][]byte
+	signatures []string
+	deliveries []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	r.signatures = append(r.signatures, req.Header.Get(notify.HeaderSignature))
+	r.deliveries = append(r.deliveries, req.Header.Get(notify.HeaderDelivery))
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+	}
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func TestNotify(t *testing.T) {
+	signed := &receiver{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests}}
+	deposits := &receiver{}
+	rejecting := &receiver{statuses: []int{http.StatusBadRequest}}
+	servers := []*httptest.Server{httptest.NewServer(signed), httptest.NewServer(deposits), httptest.NewServer(rejecting)}
+	for _, s := range servers {
+		defer s.Close()
+	}
+	n := notify.New(
+		notify.Webhook{URL: servers[0].URL, Secret: "s3cret"},
+		notify.Webhook{URL: servers[1].URL, Events: []string{notify.EventDeposit}},
+		notify.Webhook{URL: servers[2].URL, Events: []string{notify.EventHead}},
+	)
+	n.Backoff = time.Millisecond
+
+	event := notify.NewEvent(notify.EventHead, tgo.BlockHeader{Hash: "BLh", Level: 12})
+	err := n.Notify(context.Background(), event)
+	if err == nil {
+		t.Fatal("expected the rejected delivery to be reported")
+	}
+	// the signed webhook is retried until it accepts the event, the rejecting one is not
+	if signed.count() != 3 || rejecting.count() != 1 || deposits.count() != 0 {
+		t.Fatalf("unexpected deliveries %d, %d and %d", signed.count(), rejecting.count(), deposits.count())
+	}
+	for i, body := range signed.bodies {
+		if !notify.Verify("s3cret", body, signed.signatures[i]) || signed.deliveries[i] != event.ID {
+			t.Fatalf("unexpected signature %s of delivery %s", signed.signatures[i], signed.deliveries[i])
+		}
+	}
+	if notify.Verify("other", signed.bodies[0], signed.signatures[0]) {
+		t.Fatal("signature verified with the wrong secret")
+	}
+	got := struct {
+		Type string
+		Data tgo.BlockHeader
+	}{}
+	if err := json.Unmarshal(signed.bodies[2], &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != notify.EventHead || got.Data.Level != 12 {
+		t.Fatalf("unexpected payload %s", signed.bodies[2])
+	}
+	if rejecting.signatures[0] != "" {
+		t.Fatal("unexpected signature without secret")
+	}
+}
+
+func TestRun(t *testing.T) {
+	r := &receiver{}
+	s := httptest.NewServer(r)
+	defer s.Close()
+	n := notify.New(notify.Webhook{URL: s.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	heads := make(chan tgo.BlockHeader, 2)
+	heads <- tgo.BlockHeader{Level: 1}
+	heads <- tgo.BlockHeader{Level: 2}
+	close(heads)
+	peers := make(chan tgo.NetworkEvent, 1)
+	peers <- tgo.NetworkEvent{Event: "too_few_connections"}
+	close(peers)
+	errs := n.Run(ctx, notify.Merge(ctx, notify.Heads(ctx, heads), notify.Peers(ctx, peers)))
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if r.count() != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", r.count())
+	}
+}
@@ -0,0 +1,32 @@
+package tgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Int64String is an integer the node encodes either as a JSON number or as a string, as it
+// does for values that may exceed the precision of JSON numbers
+type Int64String int64
+
+// UnmarshalJSON accepts 123 as well as "123"
+func (i *Int64String) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("expected integer got %s", b)
+	}
+	*i = Int64String(v)
+	return nil
+}
+
+// MarshalJSON encodes the integer as a string
+func (i Int64String) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
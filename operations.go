@@ -0,0 +1,383 @@
+package tgo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// minimal fee parameters applied by the default baker mempool filter
+const (
+	minimalFeeMutez        = 100
+	minimalNanotezPerByte  = 1000
+	minimalNanotezPerGas   = 100
+	signatureSize          = 64
+	genericOperationPrefix = 0x03
+)
+
+// watermarks prefixing blocks and endorsements before signing, followed by the chain id
+const (
+	blockPrefix       = 0x01
+	endorsementPrefix = 0x02
+)
+
+// gas and storage limits for a reveal operation
+const (
+	revealGasLimit     = "10000"
+	revealStorageLimit = "0"
+)
+
+// limits used when simulating an operation before its real limits are known
+const (
+	hardGasLimitPerOperation     = 1040000
+	hardGasLimitPerBlock         = 10400000
+	hardStorageLimitPerOperation = "60000"
+)
+
+// gasSafetyMargin is added on top of simulated gas consumption
+const gasSafetyMargin = 100
+
+// originationBurnSize is the storage in bytes burnt for allocating a new contract
+const originationBurnSize = 257
+
+// Operation is a group of operations sharing a branch and a signature
+type Operation struct {
+	Branch    BlockHash           `json:"branch"`
+	Contents  []OperationContents `json:"contents"`
+	Signature string              `json:"signature,omitempty"`
+}
+
+// OperationContents is a single operation within an operation group
+type OperationContents struct {
+	Kind         string      `json:"kind"`
+	Source       string      `json:"source,omitempty"`
+	Fee          string      `json:"fee,omitempty"`
+	Counter      string      `json:"counter,omitempty"`
+	GasLimit     string      `json:"gas_limit,omitempty"`
+	StorageLimit string      `json:"storage_limit,omitempty"`
+	Delegate     string      `json:"delegate,omitempty"`
+	Amount       string      `json:"amount,omitempty"`
+	Destination  string      `json:"destination,omitempty"`
+	Parameters   *Parameters `json:"parameters,omitempty"`
+	PublicKey    string      `json:"public_key,omitempty"`
+	Balance      string      `json:"balance,omitempty"`
+	Script       *Script     `json:"script,omitempty"`
+	// Period, Proposals, Proposal and Ballot are set for governance operations
+	Period    *int64         `json:"period,omitempty"`
+	Proposals []ProtocolHash `json:"proposals,omitempty"`
+	Proposal  ProtocolHash   `json:"proposal,omitempty"`
+	Ballot    string         `json:"ballot,omitempty"`
+	// Pkh and Secret are set for account activations
+	Pkh    string `json:"pkh,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	// Level and Nonce are set for seed nonce revelations
+	Level int64  `json:"level,omitempty"`
+	Nonce string `json:"nonce,omitempty"`
+	// BH1 and BH2 are set for double baking evidence, Op1 and Op2 for double endorsement evidence
+	BH1 *InlinedHeader      `json:"bh1,omitempty"`
+	BH2 *InlinedHeader      `json:"bh2,omitempty"`
+	Op1 *InlinedEndorsement `json:"op1,omitempty"`
+	Op2 *InlinedEndorsement `json:"op2,omitempty"`
+}
+
+// Script holds the code and storage of a contract
+type Script struct {
+	Code    micheline.Node `json:"code"`
+	Storage micheline.Node `json:"storage"`
+}
+
+// Parameters holds the entrypoint and argument of a contract call
+type Parameters struct {
+	Entrypoint string         `json:"entrypoint"`
+	Value      micheline.Node `json:"value"`
+}
+
+// GetCounter calls GET /chains/<chain>/blocks/head/context/contracts/<address>/counter
+func (rpc *RPC) GetCounter(ctx context.Context, address string) (int64, error) {
+	var counter string
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/head/context/contracts/%s/counter", rpc.ChainAlias(ctx), address), &counter)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(counter, 10, 64)
+}
+
+// GetManagerKey calls GET /chains/<chain>/blocks/head/context/contracts/<address>/manager_key,
+// an empty key means the manager key has not been revealed yet
+func (rpc *RPC) GetManagerKey(ctx context.Context, address string) (string, error) {
+	var key string
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/head/context/contracts/%s/manager_key", rpc.ChainAlias(ctx), address), &key)
+	return key, err
+}
+
+// GetHeadHash calls GET /chains/<chain>/blocks/head/hash
+func (rpc *RPC) GetHeadHash(ctx context.Context) (BlockHash, error) {
+	var hash BlockHash
+	err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/head/hash", rpc.ChainAlias(ctx)), &hash)
+	return hash, err
+}
+
+// ForgeOperation calls POST /chains/<chain>/blocks/head/helpers/forge/operations and returns the forged bytes as hex
+func (rpc *RPC) ForgeOperation(ctx context.Context, op Operation) (string, error) {
+	var forged string
+	err := rpc.post(ctx, fmt.Sprintf("/chains/%s/blocks/head/helpers/forge/operations", rpc.ChainAlias(ctx)), op, &forged)
+	return forged, err
+}
+
+// InjectOperation calls POST /injection/operation and returns the operation hash
+func (rpc *RPC) InjectOperation(ctx context.Context, signedHex string) (string, error) {
+	var hash string
+	err := rpc.post(ctx, query{}.add("chain", rpc.ChainAlias(ctx)).path("/injection/operation"), signedHex, &hash)
+	return hash, err
+}
+
+// SimulateOperation calls POST /chains/<chain>/blocks/head/helpers/scripts/run_operation,
+// applying op on top of head without checking its signature
+func (rpc *RPC) SimulateOperation(ctx context.Context, op Operation) ([]AppliedContents, error) {
+	chainID, err := rpc.GetChainID(ctx, rpc.ChainAlias(ctx))
+	if err != nil {
+		return nil, err
+	}
+	op.Signature = b58CheckEncode(prefixEdsig, make([]byte, signatureSize))
+	req := struct {
+		Operation Operation `json:"operation"`
+		ChainID   ChainID   `json:"chain_id"`
+	}{op, chainID}
+	resp := struct {
+		Contents []AppliedContents `json:"contents"`
+	}{}
+	if err := rpc.post(ctx, fmt.Sprintf("/chains/%s/blocks/head/helpers/scripts/run_operation", rpc.ChainAlias(ctx)), req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Contents) != len(op.Contents) {
+		return nil, fmt.Errorf("expected %d simulated contents got %d", len(op.Contents), len(resp.Contents))
+	}
+	return resp.Contents, checkApplied(resp.Contents)
+}
+
+// PreapplyOperation calls POST /chains/<chain>/blocks/head/helpers/preapply/operations with a signed operation
+func (rpc *RPC) PreapplyOperation(ctx context.Context, op Operation) ([]AppliedContents, error) {
+	protocols := struct {
+		NextProtocol ProtocolHash `json:"next_protocol"`
+	}{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/head/protocols", rpc.ChainAlias(ctx)), &protocols); err != nil {
+		return nil, err
+	}
+	req := []struct {
+		Protocol ProtocolHash `json:"protocol"`
+		Operation
+	}{{protocols.NextProtocol, op}}
+	resp := []struct {
+		Contents []AppliedContents `json:"contents"`
+	}{}
+	if err := rpc.post(ctx, fmt.Sprintf("/chains/%s/blocks/head/helpers/preapply/operations", rpc.ChainAlias(ctx)), req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp) != 1 || len(resp[0].Contents) != len(op.Contents) {
+		return nil, fmt.Errorf("unexpected preapply response for %d contents", len(op.Contents))
+	}
+	return resp[0].Contents, checkApplied(resp[0].Contents)
+}
+
+// SignOperation signs forged operation bytes with the generic operation watermark,
+// returning the signature and the signed operation ready for injection
+func SignOperation(signer Signer, forgedHex string) (string, string, error) {
+	return signWatermarked(signer, []byte{genericOperationPrefix}, forgedHex)
+}
+
+// signWatermarked signs forged bytes prefixed with watermark, returning the signature and
+// the forged bytes followed by the raw signature, of any curve
+func signWatermarked(signer Signer, watermark []byte, forgedHex string) (string, string, error) {
+	forged, err := hex.DecodeString(forgedHex)
+	if err != nil {
+		return "", "", err
+	}
+	signature, err := signer.Sign(append(append([]byte{}, watermark...), forged...))
+	if err != nil {
+		return "", "", err
+	}
+	rawSig, err := encodeSignature(signature)
+	if err != nil {
+		return "", "", err
+	}
+	return signature, forgedHex + hex.EncodeToString(rawSig), nil
+}
+
+// MinimalFee returns the smallest fee in mutez accepted by default mempools for an
+// operation of the given forged size in bytes consuming gasLimit
+func MinimalFee(size int, gasLimit int64) int64 {
+	nanotez := int64(minimalFeeMutez)*1000 + int64(size)*minimalNanotezPerByte + gasLimit*minimalNanotezPerGas
+	return (nanotez + 999) / 1000
+}
+
+// sendOperation fills in counters, limits and fees for contents originating from signer,
+// then forges, signs and injects them as a single operation group
+func (rpc *RPC) sendOperation(ctx context.Context, signer Signer, contents []OperationContents) (hash string, err error) {
+	op, err := rpc.prepareOperation(ctx, signer, contents, true)
+	if err != nil {
+		return "", err
+	}
+	defer func() { rpc.settleCounters(op, err) }()
+	if err := rpc.sizeLimits(ctx, &op); err != nil {
+		return "", err
+	}
+	forged, err := rpc.forgeWithFees(ctx, &op)
+	if err != nil {
+		return "", err
+	}
+	_, signed, err := SignOperation(signer, forged)
+	if err != nil {
+		return "", err
+	}
+	return rpc.InjectOperation(ctx, signed)
+}
+
+// sendSigned forges contents that carry no fee or counter, such as governance operations,
+// on top of the current head, then signs them with signer and injects them
+func (rpc *RPC) sendSigned(ctx context.Context, signer Signer, contents []OperationContents) (string, error) {
+	branch, err := rpc.GetHeadHash(ctx)
+	if err != nil {
+		return "", err
+	}
+	forged, err := rpc.ForgeOperation(ctx, Operation{Branch: branch, Contents: contents})
+	if err != nil {
+		return "", err
+	}
+	_, signed, err := SignOperation(signer, forged)
+	if err != nil {
+		return "", err
+	}
+	return rpc.InjectOperation(ctx, signed)
+}
+
+// sendUnsigned forges anonymous contents, such as nonce revelations and evidences, on top of
+// the current head and injects them without a signature
+func (rpc *RPC) sendUnsigned(ctx context.Context, contents []OperationContents) (string, error) {
+	branch, err := rpc.GetHeadHash(ctx)
+	if err != nil {
+		return "", err
+	}
+	forged, err := rpc.ForgeOperation(ctx, Operation{Branch: branch, Contents: contents})
+	if err != nil {
+		return "", err
+	}
+	return rpc.InjectOperation(ctx, forged)
+}
+
+// prepareOperation sets the source and sequential counters of contents and wraps them
+// in an operation group on top of the current head, prepending a reveal if the
+// manager key of signer is not yet known to the chain. Counters come from rpc.Counters
+// when set and reserve is, the outcome of sending the operation must then be reported with
+// settleCounters. Operations only simulated read their counters from the chain instead.
+func (rpc *RPC) prepareOperation(ctx context.Context, signer Signer, contents []OperationContents, reserve bool) (Operation, error) {
+	managerKey, err := rpc.GetManagerKey(ctx, signer.PublicKeyHash())
+	if err != nil {
+		return Operation{}, err
+	}
+	if managerKey == "" && (len(contents) == 0 || contents[0].Kind != "reveal") {
+		contents = append([]OperationContents{{
+			Kind:         "reveal",
+			GasLimit:     revealGasLimit,
+			StorageLimit: revealStorageLimit,
+			PublicKey:    signer.PublicKey(),
+		}}, contents...)
+	}
+	branch, err := rpc.GetHeadHash(ctx)
+	if err != nil {
+		return Operation{}, err
+	}
+	var counter int64
+	if rpc.Counters != nil && reserve {
+		first, err := rpc.Counters.Reserve(ctx, signer.PublicKeyHash(), len(contents))
+		if err != nil {
+			return Operation{}, err
+		}
+		counter = first - 1
+	} else if counter, err = rpc.GetCounter(ctx, signer.PublicKeyHash()); err != nil {
+		return Operation{}, err
+	}
+	for i := range contents {
+		counter++
+		contents[i].Source = signer.PublicKeyHash()
+		contents[i].Counter = strconv.FormatInt(counter, 10)
+	}
+	return Operation{Branch: branch, Contents: contents}, nil
+}
+
+// sizeLimits simulates op and sets the gas and storage limits of every content left
+// empty to its simulated consumption, no simulation happens if all limits are set
+func (rpc *RPC) sizeLimits(ctx context.Context, op *Operation) error {
+	simGas := hardGasLimitPerBlock / len(op.Contents)
+	if simGas > hardGasLimitPerOperation {
+		simGas = hardGasLimitPerOperation
+	}
+	autoGas := make([]bool, len(op.Contents))
+	autoStorage := make([]bool, len(op.Contents))
+	simulate := false
+	for i := range op.Contents {
+		if op.Contents[i].GasLimit == "" {
+			autoGas[i], simulate = true, true
+			op.Contents[i].GasLimit = strconv.Itoa(simGas)
+		}
+		if op.Contents[i].StorageLimit == "" {
+			autoStorage[i], simulate = true, true
+			op.Contents[i].StorageLimit = hardStorageLimitPerOperation
+		}
+	}
+	if !simulate {
+		return nil
+	}
+	simulated, err := rpc.SimulateOperation(ctx, *op)
+	if err != nil {
+		return err
+	}
+	for i, c := range simulated {
+		if autoGas[i] {
+			op.Contents[i].GasLimit = strconv.FormatInt(c.Gas()+gasSafetyMargin, 10)
+		}
+		if autoStorage[i] {
+			op.Contents[i].StorageLimit = strconv.FormatInt(c.BurnedStorage(), 10)
+		}
+	}
+	return nil
+}
+
+// forgeWithFees forges op, raising the fee of every content without an explicit
+// fee until it covers the minimal fee for its share of the forged size
+func (rpc *RPC) forgeWithFees(ctx context.Context, op *Operation) (string, error) {
+	auto := make([]bool, len(op.Contents))
+	for i := range op.Contents {
+		if op.Contents[i].Fee == "" {
+			auto[i] = true
+			op.Contents[i].Fee = "0"
+		}
+	}
+	for {
+		forged, err := rpc.ForgeOperation(ctx, *op)
+		if err != nil {
+			return "", err
+		}
+		size := (len(forged)/2 + signatureSize) / len(op.Contents)
+		changed := false
+		for i := range op.Contents {
+			if !auto[i] {
+				continue
+			}
+			gas, err := strconv.ParseInt(op.Contents[i].GasLimit, 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid gas limit %q: %w", op.Contents[i].GasLimit, err)
+			}
+			fee := strconv.FormatInt(MinimalFee(size, gas), 10)
+			if fee != op.Contents[i].Fee {
+				op.Contents[i].Fee = fee
+				changed = true
+			}
+		}
+		if !changed {
+			return forged, nil
+		}
+	}
+}
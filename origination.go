@@ -0,0 +1,122 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// Origination describes a contract to deploy
+type Origination struct {
+	// Code is the contract code as JSON Micheline or Michelson source
+	Code string
+	// Storage is the initial storage as JSON Micheline or Michelson source
+	Storage string
+	// Balance is the amount in mutez transferred to the new contract
+	Balance int64
+	// Delegate optionally sets the delegate of the new contract
+	Delegate string
+}
+
+// Originate deploys a contract from signer, sizing gas and storage limits from a simulation.
+// It returns the operation hash and the KT1 address of the originated contract.
+func (rpc *RPC) Originate(ctx context.Context, signer Signer, o Origination) (hash string, contract string, err error) {
+	contents, err := originationContents(o)
+	if err != nil {
+		return "", "", err
+	}
+	op, err := rpc.prepareOperation(ctx, signer, []OperationContents{contents}, true)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { rpc.settleCounters(op, err) }()
+	if err := rpc.sizeLimits(ctx, &op); err != nil {
+		return "", "", err
+	}
+	forged, err := rpc.forgeWithFees(ctx, &op)
+	if err != nil {
+		return "", "", err
+	}
+	signature, signed, err := SignOperation(signer, forged)
+	if err != nil {
+		return "", "", err
+	}
+	op.Signature = signature
+	applied, err := rpc.PreapplyOperation(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	// the origination follows a reveal when the signer was not revealed yet
+	originated := applied[len(applied)-1].Metadata.OperationResult.OriginatedContracts
+	if len(originated) == 0 {
+		return "", "", errors.New("no contract originated in operation receipt")
+	}
+	hash, err = rpc.InjectOperation(ctx, signed)
+	if err != nil {
+		return "", "", err
+	}
+	return hash, originated[0], nil
+}
+
+// originationContents builds an origination with its limits left to be simulated
+func originationContents(o Origination) (OperationContents, error) {
+	code, err := parseMicheline(o.Code)
+	if err != nil {
+		return OperationContents{}, err
+	}
+	storage, err := parseMicheline(o.Storage)
+	if err != nil {
+		return OperationContents{}, err
+	}
+	return OperationContents{
+		Kind:     "origination",
+		Balance:  strconv.FormatInt(o.Balance, 10),
+		Delegate: o.Delegate,
+		Script:   &Script{Code: code, Storage: storage},
+	}, nil
+}
+
+// parseMicheline decodes a JSON Micheline expression or Michelson source such as a .tz file
+func parseMicheline(src string) (micheline.Node, error) {
+	node := micheline.Node{}
+	if isJSONMicheline(src) {
+		if err := json.Unmarshal([]byte(src), &node); err != nil {
+			return micheline.Node{}, fmt.Errorf("invalid JSON Micheline: %w", err)
+		}
+		return node, nil
+	}
+	node, err := micheline.Parse(src)
+	if err != nil {
+		return micheline.Node{}, fmt.Errorf("invalid michelson expression: %w", err)
+	}
+	return node, nil
+}
+
+// isJSONMicheline reports whether src is a JSON array or an object shaped as a Micheline node,
+// Michelson literals such as 42, "hi" or {} being valid JSON as well
+func isJSONMicheline(src string) bool {
+	src = strings.TrimSpace(src)
+	if !json.Valid([]byte(src)) {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(src, "["):
+		return true
+	case strings.HasPrefix(src, "{"):
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal([]byte(src), &fields); err != nil {
+			return false
+		}
+		for _, key := range []string{"prim", "int", "string", "bytes"} {
+			if _, ok := fields[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
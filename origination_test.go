@@ -0,0 +1,81 @@
+package tgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestOriginate(t *testing.T) {
+	receipt := map[string]interface{}{
+		"kind": "origination",
+		"metadata": map[string]interface{}{
+			"operation_result": map[string]interface{}{
+				"status":                 "applied",
+				"consumed_gas":           "11000",
+				"paid_storage_size_diff": "40",
+				"originated_contracts":   []string{"KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi"},
+			},
+		},
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/counter": "1",
+		"GET /chains/main/blocks/head/hash": "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+		"GET /chains/main/blocks/head/context/contracts/tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx/manager_key": "edpkuBknW28nW72KG6RoHtYW7p12T6GKc7nAbwYX5m8Wd9sDVC9yav",
+		"GET /chains/main/chain_id":                                   "NetXdQprcVkpaWU",
+		"GET /chains/main/blocks/head/protocols":                      map[string]string{"next_protocol": "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"},
+		"POST /chains/main/blocks/head/helpers/scripts/run_operation": map[string]interface{}{"contents": []interface{}{receipt}},
+		"POST /chains/main/blocks/head/helpers/preapply/operations":   []interface{}{map[string]interface{}{"contents": []interface{}{receipt}}},
+		"POST /chains/main/blocks/head/helpers/forge/operations":      strings.Repeat("ab", 100),
+		"POST /injection/operation":                                   "ooHash",
+	})
+	key, err := tgo.NewKeyFromSecret("edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, contract, err := client.Originate(context.Background(), key, tgo.Origination{
+		Code:    `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"unit"}]},{"prim":"code","args":[[{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}]`,
+		Storage: `{"prim":"Unit"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "ooHash" || contract != "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi" {
+		t.Fatalf("unexpected result %s %s", hash, contract)
+	}
+	forges := node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	if !strings.Contains(forges[len(forges)-1], `"gas_limit":"11100","storage_limit":"297"`) {
+		t.Fatalf("unexpected limits in %s", forges[len(forges)-1])
+	}
+	if _, _, err := client.Originate(context.Background(), key, tgo.Origination{
+		Code:    "parameter unit ;\nstorage unit ;\ncode { CDR ; NIL operation ; PAIR }",
+		Storage: "Unit",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	forges = node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+	if !strings.Contains(forges[len(forges)-1], `"script":{"code":[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"unit"}]},{"prim":"code","args":[[{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}],"storage":{"prim":"Unit"}}`) {
+		t.Fatalf("unexpected script in %s", forges[len(forges)-1])
+	}
+	// Michelson literals which are valid JSON as well are parsed as Michelson
+	for storage, expected := range map[string]string{
+		`42`:                `"storage":{"int":"42"}`,
+		`"hi"`:              `"storage":{"string":"hi"}`,
+		`{}`:                `"storage":[]`,
+		`{"int":"42"}`:      `"storage":{"int":"42"}`,
+		`[{"prim":"Unit"}]`: `"storage":[{"prim":"Unit"}]`,
+	} {
+		if _, _, err := client.Originate(context.Background(), key, tgo.Origination{Code: "parameter unit ; storage unit ; code { CDR ; NIL operation ; PAIR }", Storage: storage}); err != nil {
+			t.Fatalf("%s: %v", storage, err)
+		}
+		forges = node.bodies["POST /chains/main/blocks/head/helpers/forge/operations"]
+		if !strings.Contains(forges[len(forges)-1], expected) {
+			t.Fatalf("%s: expected %s in %s", storage, expected, forges[len(forges)-1])
+		}
+	}
+	if _, _, err := client.Originate(context.Background(), key, tgo.Origination{Code: "parameter unit; {", Storage: "Unit"}); err == nil {
+		t.Fatal("expected error for invalid code")
+	}
+}
@@ -0,0 +1,238 @@
+package tgo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// PackData serializes data of type typ the way the PACK instruction does. Values given in
+// readable form, such as base58 addresses or RFC3339 timestamps, are converted to their
+// optimized binary form first so the result matches what a contract computes.
+func PackData(data, typ micheline.Node) ([]byte, error) {
+	optimized, err := optimizeData(data, typ)
+	if err != nil {
+		return nil, err
+	}
+	return micheline.Pack(optimized)
+}
+
+// ScriptExprHash returns the expr... hash of packed data, the hash identifying big map keys
+func ScriptExprHash(packed []byte) string {
+	return b58CheckEncode(prefixExpr, blake2b(packed, 32))
+}
+
+// BigMapKeyHash returns the hash under which key, of type keyType, is stored in a big map
+func BigMapKeyHash(key, keyType micheline.Node) (string, error) {
+	packed, err := PackData(key, keyType)
+	if err != nil {
+		return "", err
+	}
+	return ScriptExprHash(packed), nil
+}
+
+// optimizeData rewrites data of type typ in the optimized form used by PACK
+func optimizeData(data, typ micheline.Node) (micheline.Node, error) {
+	if typ.Type != micheline.PrimNode {
+		return data, fmt.Errorf("invalid michelson type %s", typ)
+	}
+	switch typ.Prim {
+	case "address", "contract":
+		return optimizeString(data, encodeAddress)
+	case "key_hash":
+		return optimizeString(data, encodeKeyHash)
+	case "key":
+		return optimizeString(data, encodePublicKey)
+	case "signature":
+		return optimizeString(data, encodeSignature)
+	case "chain_id":
+		return optimizeString(data, func(s string) ([]byte, error) { return b58CheckDecode(s, prefixChainID) })
+	case "timestamp":
+		if data.Type != micheline.StringNode {
+			return data, nil
+		}
+		t, err := time.Parse(time.RFC3339, data.Str)
+		if err != nil {
+			return data, fmt.Errorf("invalid timestamp %q: %w", data.Str, err)
+		}
+		return micheline.NewInt(t.Unix()), nil
+	case "pair":
+		if len(typ.Args) > 2 {
+			typ = micheline.NewPrim("pair", typ.Args[0], micheline.NewPrim("pair", typ.Args[1:]...))
+		}
+		if !data.IsPrim("Pair") || len(typ.Args) != 2 || len(data.Args) < 2 {
+			return data, fmt.Errorf("expected Pair for type %s, got %s", typ, data)
+		}
+		if len(data.Args) > 2 {
+			data = micheline.NewPrim("Pair", data.Args[0], micheline.NewPrim("Pair", data.Args[1:]...))
+		}
+		return optimizeArgs(data, typ.Args[0], typ.Args[1])
+	case "option":
+		if data.IsPrim("Some") && len(typ.Args) == 1 {
+			return optimizeArgs(data, typ.Args[0])
+		}
+		return data, nil
+	case "or":
+		if len(typ.Args) != 2 {
+			return data, fmt.Errorf("invalid michelson type %s", typ)
+		}
+		switch {
+		case data.IsPrim("Left"):
+			return optimizeArgs(data, typ.Args[0])
+		case data.IsPrim("Right"):
+			return optimizeArgs(data, typ.Args[1])
+		}
+		return data, fmt.Errorf("expected Left or Right for type %s, got %s", typ, data)
+	case "list", "set":
+		if data.Type != micheline.SeqNode || len(typ.Args) != 1 {
+			return data, fmt.Errorf("expected a sequence for type %s, got %s", typ, data)
+		}
+		elems := make([]micheline.Node, len(data.Args))
+		for i, elem := range data.Args {
+			var err error
+			if elems[i], err = optimizeData(elem, typ.Args[0]); err != nil {
+				return data, err
+			}
+		}
+		return micheline.NewSeq(elems...), nil
+	case "map", "big_map":
+		if data.Type != micheline.SeqNode || len(typ.Args) != 2 {
+			return data, fmt.Errorf("expected a sequence for type %s, got %s", typ, data)
+		}
+		elems := make([]micheline.Node, len(data.Args))
+		for i, elt := range data.Args {
+			if !elt.IsPrim("Elt") {
+				return data, fmt.Errorf("expected Elt in map, got %s", elt)
+			}
+			var err error
+			if elems[i], err = optimizeArgs(elt, typ.Args[0], typ.Args[1]); err != nil {
+				return data, err
+			}
+		}
+		return micheline.NewSeq(elems...), nil
+	}
+	return data, nil
+}
+
+// optimizeArgs optimizes the arguments of a data constructor against their types
+func optimizeArgs(data micheline.Node, types ...micheline.Node) (micheline.Node, error) {
+	if len(data.Args) != len(types) {
+		return data, fmt.Errorf("unexpected arguments in %s", data)
+	}
+	args := make([]micheline.Node, len(data.Args))
+	for i := range data.Args {
+		var err error
+		if args[i], err = optimizeData(data.Args[i], types[i]); err != nil {
+			return data, err
+		}
+	}
+	return micheline.NewPrim(data.Prim, args...).WithAnnots(data.Annots...), nil
+}
+
+// optimizeString converts a base58 string value to bytes, values already in bytes are kept
+func optimizeString(data micheline.Node, encode func(string) ([]byte, error)) (micheline.Node, error) {
+	if data.Type != micheline.StringNode {
+		return data, nil
+	}
+	b, err := encode(data.Str)
+	if err != nil {
+		return data, err
+	}
+	return micheline.NewBytes(b), nil
+}
+
+// encodeKeyHash returns the binary form of a tz1, tz2 or tz3 address
+func encodeKeyHash(s string) ([]byte, error) {
+	for tag, prefix := range [][]byte{prefixTz1, prefixTz2, prefixTz3} {
+		if hash, err := b58CheckDecode(s, prefix); err == nil && len(hash) == 20 {
+			return append([]byte{byte(tag)}, hash...), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid key hash %q", s)
+}
+
+// encodeAddress returns the binary form of an implicit or originated address, followed by
+// the entrypoint when the address carries one
+func encodeAddress(s string) ([]byte, error) {
+	entrypoint := ""
+	if i := strings.IndexByte(s, '%'); i >= 0 {
+		s, entrypoint = s[:i], s[i+1:]
+	}
+	var b []byte
+	if hash, err := b58CheckDecode(s, prefixKT1); err == nil && len(hash) == 20 {
+		b = append(append([]byte{1}, hash...), 0)
+	} else {
+		keyHash, err := encodeKeyHash(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		b = append([]byte{0}, keyHash...)
+	}
+	if entrypoint != "" && entrypoint != "default" {
+		b = append(b, entrypoint...)
+	}
+	return b, nil
+}
+
+// encodePublicKey returns the binary form of an edpk, sppk or p2pk public key
+func encodePublicKey(s string) ([]byte, error) {
+	for tag, prefix := range [][]byte{prefixEdpk, prefixSppk, prefixP2pk} {
+		if key, err := b58CheckDecode(s, prefix); err == nil {
+			return append([]byte{byte(tag)}, key...), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid public key %q", s)
+}
+
+// encodeSignature returns the raw bytes of a signature of any curve
+func encodeSignature(s string) ([]byte, error) {
+	for _, prefix := range [][]byte{prefixEdsig, prefixSpsig, prefixP2sig, prefixSig} {
+		if sig, err := b58CheckDecode(s, prefix); err == nil && len(sig) == signatureSize {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid signature %q", s)
+}
+
+// decodeKeyHash returns the tz1, tz2 or tz3 address of a binary key hash
+func decodeKeyHash(b []byte) (string, error) {
+	prefixes := [][]byte{prefixTz1, prefixTz2, prefixTz3}
+	if len(b) != 21 || int(b[0]) >= len(prefixes) {
+		return "", fmt.Errorf("invalid key hash %x", b)
+	}
+	return b58CheckEncode(prefixes[b[0]], b[1:]), nil
+}
+
+// decodeAddress returns the base58 form of a binary address, with its entrypoint if any
+func decodeAddress(b []byte) (string, error) {
+	if len(b) < 22 {
+		return "", fmt.Errorf("invalid address %x", b)
+	}
+	var address string
+	switch b[0] {
+	case 0:
+		var err error
+		if address, err = decodeKeyHash(b[1:22]); err != nil {
+			return "", err
+		}
+	case 1:
+		address = b58CheckEncode(prefixKT1, b[1:21])
+	default:
+		return "", fmt.Errorf("invalid address %x", b)
+	}
+	if len(b) > 22 {
+		address += "%" + string(b[22:])
+	}
+	return address, nil
+}
+
+// decodePublicKey returns the base58 form of a binary public key
+func decodePublicKey(b []byte) (string, error) {
+	prefixes := [][]byte{prefixEdpk, prefixSppk, prefixP2pk}
+	if len(b) < 2 || int(b[0]) >= len(prefixes) {
+		return "", fmt.Errorf("invalid public key %x", b)
+	}
+	return b58CheckEncode(prefixes[b[0]], b[1:]), nil
+}
@@ -0,0 +1,52 @@
+package tgo_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+func TestPackData(t *testing.T) {
+	for _, c := range []struct {
+		data, typ string
+		want      string
+	}{
+		{`"tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"`, `address`, "050a00000016000002298c03ed7d454a101eb7022bc95f7e5f41ac78"},
+		{`"KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi%transfer"`, `address`, "050a0000001e011d23c1d3d2f8a4ea5e8784b8f7ecf2ad304c0fe6007472616e73666572"},
+		{`"tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"`, `key_hash`, "050a000000150002298c03ed7d454a101eb7022bc95f7e5f41ac78"},
+		{`"1970-01-01T00:01:00Z"`, `timestamp`, "05003c"},
+		{`Pair "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" (Some 0)`, `pair address (option nat)`, "0507070a00000016000002298c03ed7d454a101eb7022bc95f7e5f41ac7805090000"},
+		{`{ Elt "a" 1 }`, `map string int`, "05020000000a07040100000001610001"},
+	} {
+		data, err := micheline.Parse(c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typ, err := micheline.Parse(c.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packed, err := tgo.PackData(data, typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(packed); got != c.want {
+			t.Errorf("pack %s: got %s want %s", c.data, got, c.want)
+		}
+	}
+	if _, err := tgo.PackData(micheline.NewString("tz1nope"), micheline.NewPrim("address")); err == nil {
+		t.Fatal("expected error for invalid address")
+	}
+}
+
+func TestBigMapKeyHash(t *testing.T) {
+	hash, err := tgo.BigMapKeyHash(micheline.NewInt(0), micheline.NewPrim("nat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "exprtZBwZUeYYYfUs9B9Rg2ywHezVHnCCnmF9WsDQVrs582dSK63dC" {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+}
@@ -0,0 +1,115 @@
+package tgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// prefixPeerID tags the hashes of the public keys identifying peers
+var prefixPeerID = []byte{153, 103}
+
+// PeerID identifies a peer of the network by the hash of its public key
+type PeerID string
+
+// Validate returns an error when the id is not a valid peer id
+func (p PeerID) Validate() error { return validateHash("peer id", string(p), prefixPeerID) }
+
+func (p PeerID) String() string { return string(p) }
+
+// Point is the address and port a node listens on, written as addr:port with IPv6 addresses
+// enclosed in brackets, e.g. [::1]:9732
+type Point struct {
+	Addr string `json:"addr"`
+	Port uint16 `json:"port"`
+}
+
+// ParsePoint parses and validates a point written as addr:port or [addr]:port
+func ParsePoint(s string) (Point, error) {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid point %q: %w", s, err)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid point %q: invalid port %q", s, port)
+	}
+	point := Point{Addr: host, Port: uint16(n)}
+	if err := point.Validate(); err != nil {
+		return Point{}, err
+	}
+	return point, nil
+}
+
+// Validate returns an error when the address of the point is not an IP or its port is zero
+func (p Point) Validate() error {
+	if net.ParseIP(p.Addr) == nil {
+		return fmt.Errorf("invalid point %s: address is not an IP", p)
+	}
+	if p.Port == 0 {
+		return fmt.Errorf("invalid point %s: missing port", p)
+	}
+	return nil
+}
+
+func (p Point) String() string {
+	return net.JoinHostPort(p.Addr, strconv.FormatUint(uint64(p.Port), 10))
+}
+
+// UnmarshalJSON decodes the {"addr", "port"} object sent by the node, which quotes the port in
+// some responses
+func (p *Point) UnmarshalJSON(b []byte) error {
+	v := struct {
+		Addr string      `json:"addr"`
+		Port Int64String `json:"port"`
+	}{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.Port < 0 || v.Port > math.MaxUint16 {
+		return fmt.Errorf("invalid port %d", v.Port)
+	}
+	*p = Point{Addr: v.Addr, Port: uint16(v.Port)}
+	return nil
+}
+
+// PointTime is a point and the time of an event about it, such as the last connection of a peer
+type PointTime struct {
+	Point     Point
+	Timestamp Timestamp
+}
+
+// UnmarshalJSON decodes the {"addr", "port", "timestamp"} object sent by the node
+func (p *PointTime) UnmarshalJSON(b []byte) error {
+	v := struct {
+		Timestamp Timestamp `json:"timestamp"`
+	}{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Timestamp = v.Timestamp
+	return json.Unmarshal(b, &p.Point)
+}
+
+// MarshalJSON encodes the point and its time as the {"addr", "port", "timestamp"} object sent by
+// the node
+func (p PointTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Addr      string    `json:"addr"`
+		Port      uint16    `json:"port"`
+		Timestamp Timestamp `json:"timestamp"`
+	}{p.Point.Addr, p.Point.Port, p.Timestamp})
+}
+
+// peerPath returns the path of a peer under the resource base, e.g. /network/connections
+func peerPath(base string, peerID PeerID) string {
+	return base + "/" + url.PathEscape(string(peerID))
+}
+
+// pointPath returns the path of a point under /network/points
+func pointPath(point Point) string {
+	return "/network/points/" + url.PathEscape(point.String())
+}
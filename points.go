@@ -0,0 +1,147 @@
+package tgo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReadPoints reads one point written as addr:port or [addr]:port per line, ignoring blank lines
+// and comments starting with #
+func ReadPoints(r io.Reader) ([]Point, error) {
+	points := []Point{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		s := scanner.Text()
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s = s[:i]
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		point, err := ParsePoint(s)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		points = append(points, point)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// ReadPointsFile reads the points listed in the file name as ReadPoints does
+func ReadPointsFile(name string) ([]Point, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	points, err := ReadPoints(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return points, nil
+}
+
+// PointResult is the outcome of adding a point to the node
+type PointResult struct {
+	Point     Point
+	Connected bool
+	Trusted   bool
+	// Err holds the failures to connect to or to trust the point
+	Err error
+}
+
+// addPointsConcurrency is how many points AddTrustedPoints adds at once
+const addPointsConcurrency = 8
+
+// AddTrustedPoints connects the node to the points, a few at a time, waiting at most timeout
+// for each connection, and trusts them so the node keeps connecting to them. Points the node
+// could not connect to are trusted nonetheless, e.g. for nodes started before their peers.
+// The results are in the order of points, those not added before ctx is cancelled failing
+// with its error.
+func (rpc *RPC) AddTrustedPoints(ctx context.Context, points []Point, timeout time.Duration) []PointResult {
+	results := make([]PointResult, len(points))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < addPointsConcurrency && i < len(points); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = rpc.addTrustedPoint(ctx, points[i], timeout)
+			}
+		}()
+	}
+	fed := 0
+feed:
+	for ; fed < len(points); fed++ {
+		select {
+		case indexes <- fed:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	for i := fed; i < len(points); i++ {
+		results[i] = PointResult{Point: points[i], Err: ctx.Err()}
+	}
+	return results
+}
+
+// addTrustedPoint connects the node to point and trusts it
+func (rpc *RPC) addTrustedPoint(ctx context.Context, point Point, timeout time.Duration) PointResult {
+	result := PointResult{Point: point}
+	var errs []error
+	if err := rpc.connectPoint(ctx, point, timeout); err != nil {
+		errs = append(errs, fmt.Errorf("connect to %s: %w", point, err))
+	} else {
+		result.Connected = true
+	}
+	if ctx.Err() != nil {
+		result.Err = pointErrors(append(errs, ctx.Err()))
+		return result
+	}
+	if err := rpc.setPointACL(ctx, point, ACLTrust); err != nil {
+		errs = append(errs, fmt.Errorf("trust %s: %w", point, err))
+	} else {
+		result.Trusted = true
+	}
+	if len(errs) > 0 {
+		result.Err = pointErrors(errs)
+	}
+	return result
+}
+
+// pointErrors are the failures of adding a point, matching with errors.Is the targets any of
+// them matches
+type pointErrors []error
+
+// Error joins the messages of the failures
+func (e pointErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the failures matches target
+func (e pointErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package tgo_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestReadPoints(t *testing.T) {
+	points, err := tgo.ReadPoints(strings.NewReader("# bootstrap peers\n1.2.3.4:9732\n\n  [::1]:19732  # local\n"))
+	if err != nil || len(points) != 2 || points[0] != (tgo.Point{Addr: "1.2.3.4", Port: 9732}) || points[1] != (tgo.Point{Addr: "::1", Port: 19732}) {
+		t.Fatalf("unexpected points %v: %v", points, err)
+	}
+	name := filepath.Join(t.TempDir(), "peers")
+	if err := ioutil.WriteFile(name, []byte("1.2.3.4:9732\nnode:9732\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tgo.ReadPointsFile(name); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected an error on line 2 got %v", err)
+	}
+}
+
+func TestAddTrustedPoints(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"PUT /network/points/1.2.3.4:9732":   rawBody(`{}`),
+		"PATCH /network/points/1.2.3.4:9732": rawBody(`{}`),
+		"PATCH /network/points/5.6.7.8:9732": rawBody(`{}`),
+	})
+	points := []tgo.Point{{Addr: "1.2.3.4", Port: 9732}, {Addr: "5.6.7.8", Port: 9732}}
+	results := client.AddTrustedPoints(context.Background(), points, 5*time.Second)
+	if len(results) != 2 || results[0].Point != points[0] || !results[0].Connected || !results[0].Trusted || results[0].Err != nil {
+		t.Fatalf("unexpected result %+v", results[0])
+	}
+	// the point the node could not connect to is trusted nonetheless
+	if results[1].Connected || !results[1].Trusted || !errors.Is(results[1].Err, tgo.ErrNotFound) {
+		t.Fatalf("unexpected result %+v", results[1])
+	}
+	// nothing is added once ctx is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range client.AddTrustedPoints(ctx, points, 5*time.Second) {
+		if r.Connected || r.Trusted || !errors.Is(r.Err, context.Canceled) {
+			t.Fatalf("unexpected result %+v", r)
+		}
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if body := node.bodies["PATCH /network/points/5.6.7.8:9732"]; len(body) != 1 || body[0] != `{"acl":"trust"}` {
+		t.Fatalf("unexpected bodies %v", body)
+	}
+}
@@ -0,0 +1,97 @@
+package tgo
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// default probe settings
+const (
+	defaultProbeConcurrency = 16
+	defaultProbeTimeout     = 3 * time.Second
+)
+
+// ProbeOptions configures ProbePoints, zero values use defaults
+type ProbeOptions struct {
+	// Concurrency is how many points are probed at once, 16 by default
+	Concurrency int
+	// Timeout bounds each connection, 3 seconds by default
+	Timeout time.Duration
+	// States only probes the points in one of the states, such as running or disconnected
+	States []string
+}
+
+// PointLatency is a point known to the node annotated with the time taken to open a TCP
+// connection to it from the prober
+type PointLatency struct {
+	KnownPoint
+	RTT time.Duration
+	// Err is the failure to connect to the point, RTT is zero then
+	Err error
+}
+
+// ProbePoints lists the points known to the node and measures the time taken to open a TCP
+// connection to each of them from this host, which approximates the round trip time to the
+// point. The points are returned fastest first, the unreachable ones last. An error is only
+// returned when the points cannot be listed or ctx is cancelled.
+func (rpc *RPC) ProbePoints(ctx context.Context, opts ProbeOptions) ([]PointLatency, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultProbeConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultProbeTimeout
+	}
+	points, err := rpc.GetPoints(ctx, opts.States...)
+	if err != nil {
+		return nil, err
+	}
+	latencies := make([]PointLatency, len(points))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency && i < len(points); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				rtt, err := probePoint(ctx, points[i].Point, opts.Timeout)
+				latencies[i] = PointLatency{KnownPoint: points[i], RTT: rtt, Err: err}
+			}
+		}()
+	}
+feed:
+	for i := range points {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(latencies, func(i, j int) bool {
+		if (latencies[i].Err == nil) != (latencies[j].Err == nil) {
+			return latencies[i].Err == nil
+		}
+		return latencies[i].RTT < latencies[j].RTT
+	})
+	return latencies, nil
+}
+
+// probePoint returns the time taken to open a TCP connection to point
+func probePoint(ctx context.Context, point Point, timeout time.Duration) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", point.String())
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	return rtt, nil
+}
@@ -0,0 +1,54 @@
+package tgo_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestProbePoints(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	// a closed listener gives an address refusing connections
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/points": rawBody(fmt.Sprintf(`[
+			[%q,{"trusted":false,"state":{"event_kind":"disconnected"}}],
+			[%q,{"trusted":true,"state":{"event_kind":"running"}}]
+		]`, closed.Addr(), listener.Addr())),
+	})
+	latencies, err := client.ProbePoints(context.Background(), tgo.ProbeOptions{Concurrency: 1, States: []string{"running", "disconnected"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the reachable point comes first
+	if len(latencies) != 2 || latencies[0].Point.String() != listener.Addr().String() || latencies[0].Err != nil || latencies[0].RTT <= 0 || !latencies[0].Trusted {
+		t.Fatalf("unexpected latencies %+v", latencies)
+	}
+	if latencies[1].Point.String() != closed.Addr().String() || latencies[1].Err == nil || latencies[1].RTT != 0 {
+		t.Fatalf("unexpected latencies %+v", latencies)
+	}
+	node.mu.Lock()
+	q := node.queries["GET /network/points"]
+	node.mu.Unlock()
+	if len(q) != 1 || q[0] != "filter=running&filter=disconnected" {
+		t.Fatalf("unexpected queries %v", q)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ProbePoints(ctx, tgo.ProbeOptions{}); err == nil {
+		t.Fatal("expected the cancelled probe to fail")
+	}
+}
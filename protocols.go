@@ -0,0 +1,33 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+)
+
+// Protocol holds the response from `GET /protocols/<protocol_hash>`
+type Protocol struct {
+	ExpectedEnvVersion int64               `json:"expected_env_version"`
+	Components         []ProtocolComponent `json:"components"`
+}
+
+// ProtocolComponent is a source module of a protocol
+type ProtocolComponent struct {
+	Name           string `json:"name"`
+	Interface      string `json:"interface,omitempty"`
+	Implementation string `json:"implementation"`
+}
+
+// GetProtocols calls GET /protocols and returns the hashes of the protocols known to the node
+func (rpc *RPC) GetProtocols(ctx context.Context) ([]ProtocolHash, error) {
+	protocols := []ProtocolHash{}
+	err := rpc.get(ctx, "/protocols", &protocols)
+	return protocols, err
+}
+
+// GetProtocol calls GET /protocols/<protocol_hash>
+func (rpc *RPC) GetProtocol(ctx context.Context, protocolHash ProtocolHash) (Protocol, error) {
+	protocol := Protocol{}
+	err := rpc.get(ctx, fmt.Sprintf("/protocols/%s", protocolHash), &protocol)
+	return protocol, err
+}
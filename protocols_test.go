@@ -0,0 +1,27 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProtocols(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /protocols": []string{"ProtoGenesis", "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"},
+		"GET /protocols/PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS": rawBody(`{"expected_env_version":0,"components":[{"name":"Misc","interface":"(* misc *)","implementation":"let x = 1"},{"name":"Main","implementation":"let y = 2"}]}`),
+	})
+	protocols, err := client.GetProtocols(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(protocols) != 2 {
+		t.Fatalf("unexpected protocols %v", protocols)
+	}
+	protocol, err := client.GetProtocol(context.Background(), protocols[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(protocol.Components) != 2 || protocol.Components[0].Name != "Misc" || protocol.Components[1].Interface != "" {
+		t.Fatalf("unexpected protocol %+v", protocol)
+	}
+}
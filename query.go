@@ -0,0 +1,71 @@
+package tgo
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// query builds the query string of a request. Repeated parameters, such as several
+// delegate filters, are encoded once per value and flags, parameters without a value
+// such as ?active, are encoded without =.
+type query url.Values
+
+// add appends values to the parameter key
+func (q query) add(key string, values ...string) query {
+	q[key] = append(q[key], values...)
+	return q
+}
+
+// addInts appends values to the parameter key
+func (q query) addInts(key string, values ...int64) query {
+	for _, v := range values {
+		q.add(key, strconv.FormatInt(v, 10))
+	}
+	return q
+}
+
+// addBool appends true or false to the parameter key
+func (q query) addBool(key string, value bool) query {
+	return q.add(key, strconv.FormatBool(value))
+}
+
+// flag adds the flag key when on is set
+func (q query) flag(key string, on bool) query {
+	if on {
+		q[key] = []string{""}
+	}
+	return q
+}
+
+// encode encodes the parameters sorted by key
+func (q query) encode() string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range q[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			if v != "" {
+				b.WriteByte('=')
+				b.WriteString(url.QueryEscape(v))
+			}
+		}
+	}
+	return b.String()
+}
+
+// path returns path followed by the query, path alone when there are no parameters
+func (q query) path(path string) string {
+	if encoded := q.encode(); encoded != "" {
+		return path + "?" + encoded
+	}
+	return path
+}
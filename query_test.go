@@ -0,0 +1,54 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestListQueries(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks":                               [][]string{{"BLb", "BLa"}},
+		"GET /chains/main/blocks/head/context/delegates":        []string{"tz1a"},
+		"GET /chains/main/blocks/head/helpers/baking_rights":    []tgo.BakingRight{},
+		"GET /chains/main/blocks/head/helpers/endorsing_rights": []tgo.EndorsingRight{},
+	})
+	ctx := context.Background()
+	blocks, err := client.GetBlocks(ctx, tgo.BlocksQuery{Length: 2, Heads: []tgo.BlockHash{"BLb", "BLc"}, MinDate: time.Unix(1567339200, 0)})
+	if err != nil || len(blocks) != 1 || len(blocks[0]) != 2 || blocks[0][1] != "BLa" {
+		t.Fatalf("unexpected blocks %v: %v", blocks, err)
+	}
+	if _, err := client.GetBlocks(ctx, tgo.BlocksQuery{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetDelegates(ctx, tgo.Head, tgo.DelegatesQuery{Active: true}); err != nil {
+		t.Fatal(err)
+	}
+	query := tgo.RightsQuery{Delegates: []string{"tz1a", "tz1b"}, Cycles: []int64{7}, MaxPriority: 4, All: true}
+	if _, err := client.GetBakingRights(ctx, tgo.Head, query); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetEndorsingRights(ctx, tgo.Head, tgo.RightsQuery{}); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	for key, expected := range map[string][]string{
+		"GET /chains/main/blocks":                               {"head=BLb&head=BLc&length=2&min_date=1567339200", ""},
+		"GET /chains/main/blocks/head/context/delegates":        {"active"},
+		"GET /chains/main/blocks/head/helpers/baking_rights":    {"all&cycle=7&delegate=tz1a&delegate=tz1b&max_priority=4"},
+		"GET /chains/main/blocks/head/helpers/endorsing_rights": {""},
+	} {
+		got := node.queries[key]
+		if len(got) != len(expected) {
+			t.Fatalf("%s: unexpected queries %q", key, got)
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("%s: expected query %q got %q", key, expected[i], got[i])
+			}
+		}
+	}
+}
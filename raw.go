@@ -0,0 +1,34 @@
+package tgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Get calls GET on path, e.g. /chains/main/blocks/head/context/raw/json, and decodes the
+// JSON response into out, which may be nil or a *json.RawMessage. It goes through the same
+// transport, circuit breaker, cache and error handling as the wrapped calls, so endpoints
+// not wrapped by the library can be called without another client.
+func (rpc *RPC) Get(ctx context.Context, path string, out interface{}) error {
+	return rpc.get(ctx, rawPath(path), out)
+}
+
+// Post calls POST on path with in encoded as JSON and decodes the JSON response into out,
+// in and out may be nil
+func (rpc *RPC) Post(ctx context.Context, path string, in, out interface{}) error {
+	return rpc.do(ctx, http.MethodPost, rawPath(path), in, out)
+}
+
+// Delete calls DELETE on path and decodes the JSON response into out, which may be nil
+func (rpc *RPC) Delete(ctx context.Context, path string, out interface{}) error {
+	return rpc.do(ctx, http.MethodDelete, rawPath(path), nil, out)
+}
+
+// rawPath returns path relative to the URL of the node with a leading slash
+func rawPath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
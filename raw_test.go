@@ -0,0 +1,39 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestRawCalls(t *testing.T) {
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/raw/json/cycle/7": rawBody(`{"roll_snapshot":3}`),
+		"POST /injection/protocol":                              rawBody(`"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"`),
+		"DELETE /network/points/[::1]:9732":                     rawBody(`{}`),
+	})
+	ctx := context.Background()
+	var cycle json.RawMessage
+	if err := client.Get(ctx, "chains/main/blocks/head/context/raw/json/cycle/7", &cycle); err != nil || string(cycle) != `{"roll_snapshot":3}` {
+		t.Fatalf("unexpected cycle %s: %v", cycle, err)
+	}
+	var protocol tgo.ProtocolHash
+	if err := client.Post(ctx, "/injection/protocol", map[string]int{"expected_env_version": 0}, &protocol); err != nil || protocol.Validate() != nil {
+		t.Fatalf("unexpected protocol %s: %v", protocol, err)
+	}
+	if err := client.Delete(ctx, "/network/points/[::1]:9732", nil); err != nil {
+		t.Fatal(err)
+	}
+	node.mu.Lock()
+	body := node.bodies["POST /injection/protocol"]
+	node.mu.Unlock()
+	if len(body) != 1 || body[0] != `{"expected_env_version":0}` {
+		t.Fatalf("unexpected body %v", body)
+	}
+	if err := client.Get(ctx, "/unknown", nil); !errors.Is(err, tgo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound got %v", err)
+	}
+}
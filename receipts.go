@@ -0,0 +1,124 @@
+package tgo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// AppliedContents is an operation content along with its receipt
+type AppliedContents struct {
+	OperationContents
+	Metadata ContentsMetadata `json:"metadata"`
+}
+
+// ContentsMetadata is the receipt of an operation content
+type ContentsMetadata struct {
+	// OperationResult is set for manager operations only
+	OperationResult OperationResult `json:"operation_result,omitempty"`
+	// BalanceUpdates are the fee payments of the content
+	BalanceUpdates BalanceUpdates `json:"balance_updates,omitempty"`
+	// InternalOperationResults are the operations emitted by contracts during the call
+	InternalOperationResults []InternalOperationResult `json:"internal_operation_results,omitempty"`
+	// Delegate and Slots are set for endorsements
+	Delegate string  `json:"delegate,omitempty"`
+	Slots    []int64 `json:"slots,omitempty"`
+}
+
+// OperationResult is the outcome of applying a single manager operation
+type OperationResult struct {
+	// Status is applied, failed, backtracked or skipped
+	Status           string `json:"status"`
+	ConsumedGas      int64  `json:"consumed_gas,string,omitempty"`
+	ConsumedMilligas int64  `json:"consumed_milligas,string,omitempty"`
+	// StorageSize is the storage used by the called or originated contract
+	StorageSize                  int64             `json:"storage_size,string,omitempty"`
+	PaidStorageSizeDiff          int64             `json:"paid_storage_size_diff,string,omitempty"`
+	OriginatedContracts          []string          `json:"originated_contracts,omitempty"`
+	AllocatedDestinationContract bool              `json:"allocated_destination_contract,omitempty"`
+	Storage                      *micheline.Node   `json:"storage,omitempty"`
+	BalanceUpdates               BalanceUpdates    `json:"balance_updates,omitempty"`
+	BigMapDiff                   []BigMapDiff      `json:"big_map_diff,omitempty"`
+	Errors                       []json.RawMessage `json:"errors,omitempty"`
+}
+
+// Gas returns the gas consumed by the operation, rounding milligas up when only it is known
+func (r OperationResult) Gas() int64 {
+	if r.ConsumedGas == 0 && r.ConsumedMilligas > 0 {
+		return (r.ConsumedMilligas + 999) / 1000
+	}
+	return r.ConsumedGas
+}
+
+// BurnedStorage returns the bytes of storage paid for by the operation, including the
+// fixed size burned for each originated contract and allocated implicit account
+func (r OperationResult) BurnedStorage() int64 {
+	storage := r.PaidStorageSizeDiff + originationBurnSize*int64(len(r.OriginatedContracts))
+	if r.AllocatedDestinationContract {
+		storage += originationBurnSize
+	}
+	return storage
+}
+
+// InternalOperationResult is an operation emitted by a contract along with its result
+type InternalOperationResult struct {
+	Kind        string          `json:"kind"`
+	Source      string          `json:"source"`
+	Nonce       int64           `json:"nonce"`
+	Amount      string          `json:"amount,omitempty"`
+	Destination string          `json:"destination,omitempty"`
+	Parameters  *Parameters     `json:"parameters,omitempty"`
+	PublicKey   string          `json:"public_key,omitempty"`
+	Balance     string          `json:"balance,omitempty"`
+	Delegate    string          `json:"delegate,omitempty"`
+	Script      *Script         `json:"script,omitempty"`
+	Result      OperationResult `json:"result"`
+}
+
+// Results returns the result of the content followed by the results of its internal operations
+func (c AppliedContents) Results() []OperationResult {
+	results := []OperationResult{c.Metadata.OperationResult}
+	for _, internal := range c.Metadata.InternalOperationResults {
+		results = append(results, internal.Result)
+	}
+	return results
+}
+
+// Gas returns the gas consumed by the content and its internal operations
+func (c AppliedContents) Gas() int64 {
+	gas := int64(0)
+	for _, r := range c.Results() {
+		gas += r.Gas()
+	}
+	return gas
+}
+
+// BurnedStorage returns the storage paid for by the content and its internal operations
+func (c AppliedContents) BurnedStorage() int64 {
+	storage := int64(0)
+	for _, r := range c.Results() {
+		storage += r.BurnedStorage()
+	}
+	return storage
+}
+
+// Errors returns the errors reported by the content and its internal operations
+func (c AppliedContents) Errors() []json.RawMessage {
+	errs := []json.RawMessage{}
+	for _, r := range c.Results() {
+		errs = append(errs, r.Errors...)
+	}
+	return errs
+}
+
+// checkApplied returns an error describing the first operation that was not applied
+func checkApplied(contents []AppliedContents) error {
+	for i, c := range contents {
+		result := c.Metadata.OperationResult
+		if result.Status != "" && result.Status != "applied" {
+			return fmt.Errorf("operation %d (%s) %s: %s", i, c.Kind, result.Status, c.Errors())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package tgo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestAppliedContentsReceipt(t *testing.T) {
+	src := `{
+		"kind": "transaction", "source": "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", "destination": "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi",
+		"amount": "0", "parameters": {"entrypoint": "mint", "value": {"int": "3"}},
+		"metadata": {
+			"operation_result": {"status": "applied", "consumed_gas": "25000", "storage_size": "4000", "paid_storage_size_diff": "67",
+				"storage": {"prim": "Pair", "args": [{"int": "3"}, {"int": "12"}]}},
+			"internal_operation_results": [
+				{"kind": "origination", "source": "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi", "nonce": 0, "balance": "0",
+					"result": {"status": "applied", "consumed_milligas": "10500200", "originated_contracts": ["KT1NewNewNewNewNewNewNewNewNewNewNew"], "paid_storage_size_diff": "100"}},
+				{"kind": "transaction", "source": "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi", "nonce": 1, "amount": "10", "destination": "tz1other",
+					"result": {"status": "applied", "consumed_gas": "10207", "allocated_destination_contract": true}}
+			]
+		}
+	}`
+	c := tgo.AppliedContents{}
+	if err := json.Unmarshal([]byte(src), &c); err != nil {
+		t.Fatal(err)
+	}
+	result := c.Metadata.OperationResult
+	if result.ConsumedGas != 25000 || result.StorageSize != 4000 || result.PaidStorageSizeDiff != 67 || result.Storage.String() != "Pair 3 12" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	internal := c.Metadata.InternalOperationResults
+	if len(internal) != 2 || internal[0].Kind != "origination" || internal[1].Nonce != 1 || internal[1].Destination != "tz1other" {
+		t.Fatalf("unexpected internal results %+v", internal)
+	}
+	if internal[0].Result.Gas() != 10501 {
+		t.Fatalf("unexpected milligas rounding %d", internal[0].Result.Gas())
+	}
+	if c.Gas() != 25000+10501+10207 {
+		t.Fatalf("unexpected total gas %d", c.Gas())
+	}
+	if c.BurnedStorage() != 67+100+257+257 {
+		t.Fatalf("unexpected burned storage %d", c.BurnedStorage())
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"consumed_gas":"25000"`) {
+		t.Fatalf("numbers must be encoded as strings: %s", b)
+	}
+}
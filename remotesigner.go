@@ -0,0 +1,84 @@
+package tgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RemoteSigner signs through the HTTP API of a remote signer such as tezos-signer, which
+// keeps the secret key of PublicKeyHash
+type RemoteSigner struct {
+	// URL is the base URL of the signer, e.g. http://localhost:6732
+	URL    string
+	Client *http.Client
+
+	publicKeyHash string
+	publicKey     string
+}
+
+// NewRemoteSigner returns a signer for the key of publicKeyHash held by the signer at url,
+// reading its public key from the signer
+func NewRemoteSigner(ctx context.Context, url, publicKeyHash string) (*RemoteSigner, error) {
+	s := &RemoteSigner{URL: strings.TrimSuffix(url, "/"), Client: &http.Client{Timeout: 30 * time.Second}, publicKeyHash: publicKeyHash}
+	resp := struct {
+		PublicKey string `json:"public_key"`
+	}{}
+	if err := s.call(ctx, http.MethodGet, nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.PublicKey == "" {
+		return nil, fmt.Errorf("remote signer %s has no public key for %s", s.URL, publicKeyHash)
+	}
+	s.publicKey = resp.PublicKey
+	return s, nil
+}
+
+// PublicKeyHash returns the address of the remote key
+func (s *RemoteSigner) PublicKeyHash() string { return s.publicKeyHash }
+
+// PublicKey returns the public key of the remote key
+func (s *RemoteSigner) PublicKey() string { return s.publicKey }
+
+// Sign calls POST /keys/<public_key_hash> on the signer with the watermarked message
+func (s *RemoteSigner) Sign(message []byte) (string, error) {
+	resp := struct {
+		Signature string `json:"signature"`
+	}{}
+	if err := s.call(context.Background(), http.MethodPost, hex.EncodeToString(message), &resp); err != nil {
+		return "", err
+	}
+	return resp.Signature, nil
+}
+
+// call sends a request to /keys/<public_key_hash> with the JSON encoded body when not nil
+func (s *RemoteSigner) call(ctx context.Context, method string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.URL+"/keys/"+s.publicKeyHash, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp); err != nil {
+		return fmt.Errorf("remote signer %s: %w", s.URL, err)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,138 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+
+	tgo "github.com/postables/TGo"
+)
+
+// EndorsementLevel is the endorsing activity of a delegate at a level where it had rights
+type EndorsementLevel struct {
+	Level int64
+	Cycle int64
+	// Slots is the number of slots owned, Included how many of them made it into the next block
+	Slots    int64
+	Included int64
+}
+
+// EndorsementCycle aggregates the endorsing activity of a delegate over a cycle
+type EndorsementCycle struct {
+	Cycle    int64
+	Slots    int64
+	Included int64
+}
+
+// Reliability is the percentage of slots included, 100 when the delegate had no slot
+func (c EndorsementCycle) Reliability() float64 {
+	return reliability(c.Included, c.Slots)
+}
+
+// RollingReliability is the reliability over the window of levels ending at Level
+type RollingReliability struct {
+	Level       int64
+	Reliability float64
+}
+
+// EndorsementPerformance compares the endorsement slots owned by a delegate over a range of
+// levels with the slots actually included
+type EndorsementPerformance struct {
+	Delegate   string
+	FirstLevel int64
+	LastLevel  int64
+	// Levels holds the levels with rights in increasing order
+	Levels   []EndorsementLevel
+	Cycles   []EndorsementCycle
+	Slots    int64
+	Included int64
+}
+
+// Reliability is the percentage of slots included over the whole range
+func (p EndorsementPerformance) Reliability() float64 {
+	return reliability(p.Included, p.Slots)
+}
+
+// Rolling returns, for each level with rights, the reliability over the window of levels with
+// rights ending at it
+func (p EndorsementPerformance) Rolling(window int) []RollingReliability {
+	if window <= 0 {
+		window = 1
+	}
+	rolling := make([]RollingReliability, len(p.Levels))
+	var slots, included int64
+	for i, l := range p.Levels {
+		slots += l.Slots
+		included += l.Included
+		if i >= window {
+			slots -= p.Levels[i-window].Slots
+			included -= p.Levels[i-window].Included
+		}
+		rolling[i] = RollingReliability{Level: l.Level, Reliability: reliability(included, slots)}
+	}
+	return rolling
+}
+
+// Endorsements computes the endorsing performance of delegate from firstLevel to lastLevel.
+// Levels whose endorsements cannot be included yet, the head and beyond, are left out.
+func Endorsements(ctx context.Context, rpc *tgo.RPC, delegate string, firstLevel, lastLevel int64) (EndorsementPerformance, error) {
+	perf := EndorsementPerformance{Delegate: delegate, FirstLevel: firstLevel, LastLevel: lastLevel}
+	head, err := rpc.GetBlockHeader(ctx, "head")
+	if err != nil {
+		return perf, err
+	}
+	if lastLevel >= head.Level {
+		lastLevel = head.Level - 1
+	}
+	if firstLevel > lastLevel {
+		return perf, fmt.Errorf("no level with included endorsements between %d and %d", firstLevel, perf.LastLevel)
+	}
+	perf.LastLevel = lastLevel
+	query := tgo.RightsQuery{Delegates: []string{delegate}}
+	for level := firstLevel; level <= lastLevel; level++ {
+		query.Levels = append(query.Levels, level)
+	}
+	rights, err := rpc.GetEndorsingRights(ctx, "head", query)
+	if err != nil {
+		return perf, err
+	}
+	slots := map[int64]int64{}
+	for _, right := range rights {
+		if right.Delegate == delegate {
+			slots[right.Level] += int64(len(right.Slots))
+		}
+	}
+	blocks := newBlockCache(rpc, delegate)
+	for _, level := range sortedLevels(slots) {
+		// endorsements for a level are included in the next block
+		block, err := blocks.get(ctx, level+1)
+		if err != nil {
+			return perf, err
+		}
+		cycle := block.level.Cycle
+		if block.level.CyclePosition == 0 {
+			cycle--
+		}
+		included := block.endorsedSlots
+		if included > slots[level] {
+			included = slots[level]
+		}
+		perf.Levels = append(perf.Levels, EndorsementLevel{Level: level, Cycle: cycle, Slots: slots[level], Included: included})
+		perf.Slots += slots[level]
+		perf.Included += included
+		if n := len(perf.Cycles); n == 0 || perf.Cycles[n-1].Cycle != cycle {
+			perf.Cycles = append(perf.Cycles, EndorsementCycle{Cycle: cycle})
+		}
+		c := &perf.Cycles[len(perf.Cycles)-1]
+		c.Slots += slots[level]
+		c.Included += included
+	}
+	return perf, nil
+}
+
+// reliability is included as a percentage of slots
+func reliability(included, slots int64) float64 {
+	if slots == 0 {
+		return 100
+	}
+	return float64(included) * 100 / float64(slots)
+}
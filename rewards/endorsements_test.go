@@ -0,0 +1,90 @@
+package rewards_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/rewards"
+)
+
+func TestEndorsements(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	block := func(level, cycle, position int64, slots ...int64) map[string]interface{} {
+		return map[string]interface{}{
+			"header":   map[string]interface{}{"level": level},
+			"metadata": map[string]interface{}{"baker": "tz1other", "level": map[string]interface{}{"level": level, "cycle": cycle, "cycle_position": position}},
+			"operations": [][]interface{}{
+				{map[string]interface{}{"contents": []interface{}{endorsement(delegate, slots...)}}},
+			},
+		}
+	}
+	blocks := map[string]map[string]interface{}{
+		// level 63 is the last block of cycle 0, its endorsements are included in the first block of cycle 1
+		"64": block(64, 1, 0, 1, 2),
+		"65": block(65, 1, 1),
+		"66": block(66, 1, 2, 5),
+	}
+	routes := map[string]interface{}{
+		"/chains/main/blocks/head/header": map[string]interface{}{"level": 66},
+		"/chains/main/blocks/head/helpers/endorsing_rights": []map[string]interface{}{
+			{"level": 63, "delegate": delegate, "slots": []int64{1, 2}},
+			{"level": 64, "delegate": delegate, "slots": []int64{7}},
+			{"level": 65, "delegate": delegate, "slots": []int64{5, 9}},
+		},
+	}
+	for level, b := range blocks {
+		for part, resp := range b {
+			routes["/chains/main/blocks/"+level+"/"+part] = resp
+		}
+	}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chains/main/blocks/head/helpers/endorsing_rights" {
+			query = r.URL.RawQuery
+		}
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+
+	perf, err := rewards.Endorsements(context.Background(), client, delegate, 63, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "delegate="+delegate+"&level=63&level=64&level=65" {
+		t.Fatalf("unexpected rights query %s", query)
+	}
+	levels := []rewards.EndorsementLevel{
+		{Level: 63, Cycle: 0, Slots: 2, Included: 2},
+		{Level: 64, Cycle: 1, Slots: 1, Included: 0},
+		{Level: 65, Cycle: 1, Slots: 2, Included: 1},
+	}
+	if perf.LastLevel != 65 || !reflect.DeepEqual(perf.Levels, levels) {
+		t.Fatalf("unexpected levels %+v", perf)
+	}
+	cycles := []rewards.EndorsementCycle{{Cycle: 0, Slots: 2, Included: 2}, {Cycle: 1, Slots: 3, Included: 1}}
+	if !reflect.DeepEqual(perf.Cycles, cycles) || perf.Cycles[0].Reliability() != 100 {
+		t.Fatalf("unexpected cycles %+v", perf.Cycles)
+	}
+	if perf.Reliability() != 60 {
+		t.Fatalf("unexpected reliability %v", perf.Reliability())
+	}
+	rolling := []rewards.RollingReliability{{Level: 63, Reliability: 100}, {Level: 64, Reliability: 200.0 / 3}, {Level: 65, Reliability: 100.0 / 3}}
+	if got := perf.Rolling(2); !reflect.DeepEqual(got, rolling) {
+		t.Fatalf("unexpected rolling reliability %+v", got)
+	}
+	if _, err := rewards.Endorsements(context.Background(), client, delegate, 66, 70); err == nil {
+		t.Fatal("expected error for a range without included endorsements")
+	}
+}
@@ -0,0 +1,83 @@
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	tgo "github.com/postables/TGo"
+)
+
+// PayoutConfig controls how rewards are shared with delegators
+type PayoutConfig struct {
+	// FeePercent is the share of each delegator's gross reward kept by the baker, e.g. 10 for 10%
+	FeePercent float64
+	// MinimumPayout drops payouts below this amount in mutez
+	MinimumPayout int64
+}
+
+// Payout is what a delegator is owed for a cycle, amounts are in mutez
+type Payout struct {
+	Address string
+	// Balance is the delegator's balance at the snapshot block
+	Balance int64
+	// Share is Balance relative to the staking balance of the delegate
+	Share float64
+	Gross int64
+	Fee   int64
+	Net   int64
+}
+
+// Payouts splits rewards between the delegators of delegate proportionally to their balance at
+// snapshotBlock, the block whose balances produced the rolls of the rewarded cycle.
+// Payouts are sorted by decreasing amount and exclude the delegate itself.
+func Payouts(ctx context.Context, rpc *tgo.RPC, delegate string, snapshotBlock tgo.BlockID, rewards int64, config PayoutConfig) ([]Payout, error) {
+	staking, err := rpc.GetStakingBalance(ctx, snapshotBlock, delegate)
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := rpc.GetDelegatedContracts(ctx, snapshotBlock, delegate)
+	if err != nil {
+		return nil, err
+	}
+	payouts := []Payout{}
+	for _, address := range contracts {
+		if address == delegate {
+			continue
+		}
+		balance, err := rpc.GetBalance(ctx, snapshotBlock, address)
+		if err != nil {
+			return nil, err
+		}
+		payout := sharePayout(address, balance, staking, rewards, config.FeePercent)
+		if payout.Net < config.MinimumPayout || payout.Net <= 0 {
+			continue
+		}
+		payouts = append(payouts, payout)
+	}
+	sort.Slice(payouts, func(i, j int) bool { return payouts[i].Net > payouts[j].Net })
+	return payouts, nil
+}
+
+// sharePayout computes the payout of a balance out of the staking balance without overflowing
+func sharePayout(address string, balance, staking, rewards int64, feePercent float64) Payout {
+	payout := Payout{Address: address, Balance: balance}
+	if staking <= 0 {
+		return payout
+	}
+	gross := new(big.Int).Mul(big.NewInt(rewards), big.NewInt(balance))
+	gross.Quo(gross, big.NewInt(staking))
+	payout.Gross = gross.Int64()
+	payout.Share = float64(balance) / float64(staking)
+	payout.Fee = int64(float64(payout.Gross)*feePercent/100 + 0.5)
+	payout.Net = payout.Gross - payout.Fee
+	return payout
+}
+
+// AddPayouts appends a transaction per payout to builder so they can be sent as one operation group
+func AddPayouts(builder *tgo.OperationBuilder, payouts []Payout) *tgo.OperationBuilder {
+	for _, p := range payouts {
+		builder.AddTransaction(p.Address, p.Net)
+	}
+	return builder
+}
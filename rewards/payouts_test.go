@@ -0,0 +1,60 @@
+package rewards_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/rewards"
+)
+
+func TestPayouts(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	prefix := "/chains/main/blocks/1024/context/"
+	routes := map[string]interface{}{
+		prefix + "delegates/" + delegate + "/staking_balance":     "1000000000",
+		prefix + "delegates/" + delegate + "/delegated_contracts": []string{delegate, "KT1A", "KT1B"},
+		prefix + "contracts/KT1A/balance":                         "600000000",
+		prefix + "contracts/KT1B/balance":                         "300000000",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+
+	payouts, err := rewards.Payouts(context.Background(), client, delegate, "1024", 100000000, rewards.PayoutConfig{FeePercent: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payouts) != 2 {
+		t.Fatalf("expected 2 payouts got %+v", payouts)
+	}
+	if p := payouts[0]; p.Address != "KT1A" || p.Share != 0.6 || p.Gross != 60000000 || p.Fee != 6000000 || p.Net != 54000000 {
+		t.Fatalf("unexpected payout %+v", p)
+	}
+	if p := payouts[1]; p.Address != "KT1B" || p.Net != 27000000 {
+		t.Fatalf("unexpected payout %+v", p)
+	}
+
+	payouts, err = rewards.Payouts(context.Background(), client, delegate, "1024", 100000000, rewards.PayoutConfig{FeePercent: 10, MinimumPayout: 30000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payouts) != 1 || payouts[0].Address != "KT1A" {
+		t.Fatalf("expected small payouts to be dropped got %+v", payouts)
+	}
+	builder := rewards.AddPayouts(client.NewOperationBuilder(nil), payouts)
+	if contents := builder.Contents(); len(contents) != 1 || contents[0].Destination != "KT1A" || contents[0].Amount != "54000000" {
+		t.Fatalf("unexpected builder contents %+v", contents)
+	}
+}
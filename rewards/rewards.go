@@ -0,0 +1,267 @@
+// Package rewards computes what a delegate earned and lost during a cycle
+package rewards
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	tgo "github.com/postables/TGo"
+)
+
+// maxPriority is the lowest baking priority considered when looking for baked blocks
+const maxPriority = 8
+
+// Report summarises the baking and endorsing activity of a delegate for a cycle, amounts are in mutez
+type Report struct {
+	Delegate string
+	Cycle    int64
+
+	// BakingRights is the number of levels the delegate could bake at priority 0
+	BakingRights int
+	Baked        []BakedBlock
+	MissedBakes  []MissedBake
+
+	EndorsingSlots     int64
+	EndorsedSlots      int64
+	MissedEndorsements []MissedEndorsement
+
+	// PendingLevels are levels with rights that have not been reached yet
+	PendingLevels []int64
+
+	BakingRewards      int64
+	EndorsementRewards int64
+	Fees               int64
+
+	MissedBakingRewards      int64
+	MissedEndorsementRewards int64
+
+	// LostRewards and LostFees are the part of the earnings no longer frozen for the cycle,
+	// which happens when the delegate was denounced for double baking or endorsing
+	LostRewards int64
+	LostFees    int64
+}
+
+// BakedBlock is a block of the cycle baked by the delegate
+type BakedBlock struct {
+	Level    int64
+	Priority int64
+	Reward   int64
+	Fees     int64
+}
+
+// MissedBake is a level the delegate had priority 0 for but someone else baked
+type MissedBake struct {
+	Level        int64
+	BakedBy      string
+	Priority     int64
+	MissedReward int64
+}
+
+// MissedEndorsement is a level whose endorsement slots of the delegate were not included
+type MissedEndorsement struct {
+	Level        int64
+	Slots        int64
+	MissedReward int64
+}
+
+// Compute builds the report of delegate for cycle from its rights, the blocks of the cycle
+// and its frozen balances
+func Compute(ctx context.Context, rpc *tgo.RPC, delegate string, cycle int64) (Report, error) {
+	report := Report{Delegate: delegate, Cycle: cycle}
+	head, err := rpc.GetBlockHeader(ctx, "head")
+	if err != nil {
+		return report, err
+	}
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return report, err
+	}
+	query := tgo.RightsQuery{Delegates: []string{delegate}, Cycles: []int64{cycle}, MaxPriority: maxPriority}
+	bakingRights, err := rpc.GetBakingRights(ctx, "head", query)
+	if err != nil {
+		return report, err
+	}
+	endorsingRights, err := rpc.GetEndorsingRights(ctx, "head", query)
+	if err != nil {
+		return report, err
+	}
+
+	blocks := newBlockCache(rpc, delegate)
+	pending := map[int64]bool{}
+
+	// a delegate may hold several priorities at a level, only the best one matters
+	best := map[int64]int64{}
+	for _, right := range bakingRights {
+		if p, ok := best[right.Level]; !ok || right.Priority < p {
+			best[right.Level] = right.Priority
+		}
+	}
+	for _, level := range sortedLevels(best) {
+		if best[level] == 0 {
+			report.BakingRights++
+		}
+		if level > head.Level {
+			pending[level] = true
+			continue
+		}
+		block, err := blocks.get(ctx, level)
+		if err != nil {
+			return report, err
+		}
+		if block.baker == delegate {
+			reward := bakingReward(constants, block.priority, block.endorsements)
+			report.Baked = append(report.Baked, BakedBlock{Level: level, Priority: block.priority, Reward: reward, Fees: block.fees})
+			report.BakingRewards += reward
+			report.Fees += block.fees
+		} else if best[level] == 0 {
+			missed := bakingReward(constants, 0, block.endorsements)
+			report.MissedBakes = append(report.MissedBakes, MissedBake{Level: level, BakedBy: block.baker, Priority: block.priority, MissedReward: missed})
+			report.MissedBakingRewards += missed
+		}
+	}
+
+	for _, right := range endorsingRights {
+		slots := int64(len(right.Slots))
+		report.EndorsingSlots += slots
+		// endorsements for a level are included in the next block
+		if right.Level+1 > head.Level {
+			pending[right.Level] = true
+			continue
+		}
+		block, err := blocks.get(ctx, right.Level+1)
+		if err != nil {
+			return report, err
+		}
+		if block.endorsedSlots > 0 {
+			report.EndorsedSlots += block.endorsedSlots
+			report.EndorsementRewards += endorsementReward(constants, block.priority, block.endorsedSlots)
+			continue
+		}
+		missed := endorsementReward(constants, 0, slots)
+		report.MissedEndorsements = append(report.MissedEndorsements, MissedEndorsement{Level: right.Level, Slots: slots, MissedReward: missed})
+		report.MissedEndorsementRewards += missed
+	}
+	for level := range pending {
+		report.PendingLevels = append(report.PendingLevels, level)
+	}
+	sort.Slice(report.PendingLevels, func(i, j int) bool { return report.PendingLevels[i] < report.PendingLevels[j] })
+
+	frozen, err := rpc.GetFrozenBalanceByCycle(ctx, "head", delegate)
+	if err != nil {
+		return report, err
+	}
+	for _, f := range frozen {
+		if f.Cycle != cycle {
+			continue
+		}
+		if lost := report.BakingRewards + report.EndorsementRewards - f.Rewards; lost > 0 {
+			report.LostRewards = lost
+		}
+		if lost := report.Fees - f.Fees; lost > 0 {
+			report.LostFees = lost
+		}
+	}
+	return report, nil
+}
+
+// bakingReward is the reward for a block baked at priority including the given number of
+// endorsement slots, babylon rewards depend on both while earlier protocols paid a flat reward
+func bakingReward(constants tgo.Constants, priority, endorsements int64) int64 {
+	if len(constants.BakingRewardPerEndorsement) > 0 {
+		return rewardAt(constants.BakingRewardPerEndorsement, priority) * endorsements
+	}
+	return rewardAt(constants.BlockReward, 0)
+}
+
+// endorsementReward is the reward for slots included in a block baked at priority
+func endorsementReward(constants tgo.Constants, priority, slots int64) int64 {
+	if len(constants.EndorsementReward) > 1 {
+		return rewardAt(constants.EndorsementReward, priority) * slots
+	}
+	return rewardAt(constants.EndorsementReward, 0) / (priority + 1) * slots
+}
+
+// rewardAt returns the reward for priority, the last entry applies to every later priority
+func rewardAt(rewards tgo.MutezList, priority int64) int64 {
+	if len(rewards) == 0 {
+		return 0
+	}
+	if priority >= int64(len(rewards)) {
+		priority = int64(len(rewards)) - 1
+	}
+	return rewards[priority]
+}
+
+func sortedLevels(levels map[int64]int64) []int64 {
+	sorted := make([]int64, 0, len(levels))
+	for level := range levels {
+		sorted = append(sorted, level)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// blockSummary is what the report needs from a block
+type blockSummary struct {
+	baker         string
+	priority      int64
+	level         tgo.BlockLevel
+	fees          int64
+	endorsements  int64
+	endorsedSlots int64
+}
+
+// blockCache fetches each block once, rights at consecutive levels share blocks
+type blockCache struct {
+	rpc      *tgo.RPC
+	delegate string
+	blocks   map[int64]blockSummary
+}
+
+func newBlockCache(rpc *tgo.RPC, delegate string) *blockCache {
+	return &blockCache{rpc: rpc, delegate: delegate, blocks: map[int64]blockSummary{}}
+}
+
+func (c *blockCache) get(ctx context.Context, level int64) (blockSummary, error) {
+	if block, ok := c.blocks[level]; ok {
+		return block, nil
+	}
+	id := tgo.LevelID(level)
+	header, err := c.rpc.GetBlockHeader(ctx, id)
+	if err != nil {
+		return blockSummary{}, err
+	}
+	metadata, err := c.rpc.GetBlockMetadata(ctx, id)
+	if err != nil {
+		return blockSummary{}, err
+	}
+	passes, err := c.rpc.GetBlockOperations(ctx, id)
+	if err != nil {
+		return blockSummary{}, err
+	}
+	block := blockSummary{baker: string(metadata.Baker), priority: header.Priority, level: metadata.CurrentLevel()}
+	for _, ops := range passes {
+		for _, op := range ops {
+			for _, contents := range op.Contents {
+				if contents.Kind == "endorsement" {
+					slots := int64(len(contents.Metadata.Slots))
+					block.endorsements += slots
+					if contents.Metadata.Delegate == c.delegate {
+						block.endorsedSlots += slots
+					}
+					continue
+				}
+				if contents.Fee != "" {
+					fee, err := strconv.ParseInt(contents.Fee, 10, 64)
+					if err != nil {
+						return blockSummary{}, err
+					}
+					block.fees += fee
+				}
+			}
+		}
+	}
+	c.blocks[level] = block
+	return block, nil
+}
@@ -0,0 +1,86 @@
+package rewards_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/rewards"
+)
+
+func endorsement(delegate string, slots ...int64) map[string]interface{} {
+	return map[string]interface{}{"kind": "endorsement", "metadata": map[string]interface{}{"delegate": delegate, "slots": slots}}
+}
+
+func TestCompute(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	routes := map[string]interface{}{
+		"/chains/main/blocks/head/header":            map[string]interface{}{"level": 150},
+		"/chains/main/blocks/head/context/constants": map[string]interface{}{"baking_reward_per_endorsement": []string{"1250000", "187500"}, "endorsement_reward": []string{"1250000", "833333"}},
+		"/chains/main/blocks/head/helpers/baking_rights": []map[string]interface{}{
+			{"level": 100, "delegate": delegate, "priority": 0},
+			{"level": 100, "delegate": delegate, "priority": 3},
+			{"level": 101, "delegate": delegate, "priority": 0},
+			{"level": 200, "delegate": delegate, "priority": 0},
+		},
+		"/chains/main/blocks/head/helpers/endorsing_rights": []map[string]interface{}{
+			{"level": 100, "delegate": delegate, "slots": []int64{1, 2}},
+			{"level": 120, "delegate": delegate, "slots": []int64{3}},
+		},
+		"/chains/main/blocks/100/header":   map[string]interface{}{"level": 100, "priority": 0},
+		"/chains/main/blocks/100/metadata": map[string]interface{}{"baker": delegate},
+		"/chains/main/blocks/100/operations": [][]interface{}{
+			{map[string]interface{}{"contents": []interface{}{endorsement("tz1other", 0, 3, 4, 5, 6, 7, 8, 9, 10, 11)}}},
+			{}, {},
+			{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"kind": "transaction", "fee": "1000"}}}},
+		},
+		"/chains/main/blocks/101/header":   map[string]interface{}{"level": 101, "priority": 1},
+		"/chains/main/blocks/101/metadata": map[string]interface{}{"baker": "tz1other"},
+		"/chains/main/blocks/101/operations": [][]interface{}{
+			{map[string]interface{}{"contents": []interface{}{endorsement(delegate, 1, 2)}}, map[string]interface{}{"contents": []interface{}{endorsement("tz1other", make([]int64, 20)...)}}},
+		},
+		"/chains/main/blocks/121/header":     map[string]interface{}{"level": 121, "priority": 0},
+		"/chains/main/blocks/121/metadata":   map[string]interface{}{"baker": "tz1other"},
+		"/chains/main/blocks/121/operations": [][]interface{}{{}},
+		"/chains/main/blocks/head/context/delegates/" + delegate + "/frozen_balance_by_cycle": []map[string]interface{}{
+			{"cycle": 10, "deposits": "0", "fees": "1000", "rewards": "13666666"},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	report, err := rewards.Compute(context.Background(), tgo.GenerateClient(server.URL, time.Second*5), delegate, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.BakingRights != 3 || !reflect.DeepEqual(report.Baked, []rewards.BakedBlock{{Level: 100, Reward: 12500000, Fees: 1000}}) {
+		t.Fatalf("unexpected bakes %+v", report)
+	}
+	if !reflect.DeepEqual(report.MissedBakes, []rewards.MissedBake{{Level: 101, BakedBy: "tz1other", Priority: 1, MissedReward: 27500000}}) {
+		t.Fatalf("unexpected missed bakes %+v", report.MissedBakes)
+	}
+	if report.EndorsingSlots != 3 || report.EndorsedSlots != 2 || report.EndorsementRewards != 1666666 {
+		t.Fatalf("unexpected endorsements %+v", report)
+	}
+	if !reflect.DeepEqual(report.MissedEndorsements, []rewards.MissedEndorsement{{Level: 120, Slots: 1, MissedReward: 1250000}}) {
+		t.Fatalf("unexpected missed endorsements %+v", report.MissedEndorsements)
+	}
+	if !reflect.DeepEqual(report.PendingLevels, []int64{200}) {
+		t.Fatalf("unexpected pending levels %v", report.PendingLevels)
+	}
+	if report.LostRewards != 500000 || report.LostFees != 0 {
+		t.Fatalf("unexpected losses %d %d", report.LostRewards, report.LostFees)
+	}
+}
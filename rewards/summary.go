@@ -0,0 +1,133 @@
+package rewards
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	tgo "github.com/postables/TGo"
+)
+
+// CycleSummary is the accounting summary of a delegate for a cycle, amounts are in mutez
+type CycleSummary struct {
+	Delegate           string `json:"delegate"`
+	Cycle              int64  `json:"cycle"`
+	BlocksBaked        int    `json:"blocks_baked"`
+	EndorsingSlots     int64  `json:"endorsing_slots"`
+	EndorsedSlots      int64  `json:"endorsed_slots"`
+	Fees               int64  `json:"fees"`
+	BakingRewards      int64  `json:"baking_rewards"`
+	EndorsementRewards int64  `json:"endorsement_rewards"`
+	// Rewards is the total of baking and endorsement rewards
+	Rewards int64 `json:"rewards"`
+	// DepositsFrozen is the security deposit frozen for the cycle
+	DepositsFrozen int64 `json:"deposits_frozen"`
+	// Unfrozen is set once the frozen funds of the cycle have been released, at the end of
+	// cycle + preserved_cycles, the amounts released are then given by the Unfrozen fields
+	Unfrozen         bool  `json:"unfrozen"`
+	DepositsUnfrozen int64 `json:"deposits_unfrozen"`
+	RewardsUnfrozen  int64 `json:"rewards_unfrozen"`
+	FeesUnfrozen     int64 `json:"fees_unfrozen"`
+}
+
+// summaryHeader names the CSV columns written by WriteCSV
+var summaryHeader = []string{
+	"delegate", "cycle", "blocks_baked", "endorsing_slots", "endorsed_slots", "fees",
+	"baking_rewards", "endorsement_rewards", "rewards", "deposits_frozen",
+	"unfrozen", "deposits_unfrozen", "rewards_unfrozen", "fees_unfrozen",
+}
+
+// Summarize builds the summary of delegate for each of cycles from its report, its frozen
+// balances and the balance updates of the block releasing the funds of the cycle
+func Summarize(ctx context.Context, rpc *tgo.RPC, delegate string, cycles ...int64) ([]CycleSummary, error) {
+	metadata, err := rpc.GetBlockMetadata(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	head := metadata.CurrentLevel()
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	frozen, err := rpc.GetFrozenBalanceByCycle(ctx, "head", delegate)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]CycleSummary, 0, len(cycles))
+	for _, cycle := range cycles {
+		report, err := Compute(ctx, rpc, delegate, cycle)
+		if err != nil {
+			return nil, err
+		}
+		summary := CycleSummary{
+			Delegate:           delegate,
+			Cycle:              cycle,
+			BlocksBaked:        len(report.Baked),
+			EndorsingSlots:     report.EndorsingSlots,
+			EndorsedSlots:      report.EndorsedSlots,
+			Fees:               report.Fees,
+			BakingRewards:      report.BakingRewards,
+			EndorsementRewards: report.EndorsementRewards,
+			Rewards:            report.BakingRewards + report.EndorsementRewards,
+		}
+		for _, f := range frozen {
+			if f.Cycle == cycle {
+				summary.DepositsFrozen = f.Deposits
+			}
+		}
+		// frozen funds are released by the last block of cycle + preserved_cycles
+		release := head.Level - head.CyclePosition + (cycle+constants.PreservedCycles-head.Cycle+1)*constants.BlocksPerCycle - 1
+		if constants.BlocksPerCycle > 0 && release <= head.Level {
+			released, err := rpc.GetBlockMetadata(ctx, tgo.LevelID(release))
+			if err != nil {
+				return nil, err
+			}
+			updates := released.BalanceUpdates.Cycle(cycle)
+			summary.Unfrozen = true
+			summary.DepositsUnfrozen = -updates.Frozen(delegate, tgo.FreezerDeposits)
+			summary.RewardsUnfrozen = -updates.Frozen(delegate, tgo.FreezerRewards)
+			summary.FeesUnfrozen = -updates.Frozen(delegate, tgo.FreezerFees)
+			summary.DepositsFrozen = summary.DepositsUnfrozen
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// WriteJSON writes summaries as a JSON array
+func WriteJSON(w io.Writer, summaries []CycleSummary) error {
+	return json.NewEncoder(w).Encode(summaries)
+}
+
+// WriteCSV writes summaries as CSV with a header row
+func WriteCSV(w io.Writer, summaries []CycleSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(summaryHeader); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		record := []string{
+			s.Delegate,
+			strconv.FormatInt(s.Cycle, 10),
+			strconv.Itoa(s.BlocksBaked),
+			strconv.FormatInt(s.EndorsingSlots, 10),
+			strconv.FormatInt(s.EndorsedSlots, 10),
+			strconv.FormatInt(s.Fees, 10),
+			strconv.FormatInt(s.BakingRewards, 10),
+			strconv.FormatInt(s.EndorsementRewards, 10),
+			strconv.FormatInt(s.Rewards, 10),
+			strconv.FormatInt(s.DepositsFrozen, 10),
+			strconv.FormatBool(s.Unfrozen),
+			strconv.FormatInt(s.DepositsUnfrozen, 10),
+			strconv.FormatInt(s.RewardsUnfrozen, 10),
+			strconv.FormatInt(s.FeesUnfrozen, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,104 @@
+package rewards_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/rewards"
+)
+
+func TestSummarize(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	freezer := func(category string, cycle int64, change string) map[string]interface{} {
+		return map[string]interface{}{"kind": "freezer", "category": category, "delegate": delegate, "cycle": cycle, "change": change}
+	}
+	routes := map[string]interface{}{
+		"/chains/main/blocks/head/header":   map[string]interface{}{"level": 150},
+		"/chains/main/blocks/head/metadata": map[string]interface{}{"level": map[string]interface{}{"level": 150, "cycle": 18, "cycle_position": 6}},
+		"/chains/main/blocks/head/context/constants": map[string]interface{}{
+			"preserved_cycles": 2, "blocks_per_cycle": 8,
+			"baking_reward_per_endorsement": []string{"1250000", "187500"}, "endorsement_reward": []string{"1250000", "833333"},
+		},
+		"/chains/main/blocks/100/header":   map[string]interface{}{"level": 100, "priority": 0},
+		"/chains/main/blocks/100/metadata": map[string]interface{}{"baker": delegate},
+		"/chains/main/blocks/100/operations": [][]interface{}{
+			{map[string]interface{}{"contents": []interface{}{endorsement("tz1other", 0, 1)}}},
+			{}, {},
+			{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"kind": "transaction", "fee": "1000"}}}},
+		},
+		// the last block of cycle 12 releases the funds frozen for cycle 10
+		"/chains/main/blocks/103/metadata": map[string]interface{}{"balance_updates": []interface{}{
+			freezer("deposits", 10, "-512000000"),
+			freezer("rewards", 10, "-2500000"),
+			freezer("fees", 10, "-1000"),
+			freezer("deposits", 11, "-1"),
+		}},
+		"/chains/main/blocks/head/context/delegates/" + delegate + "/frozen_balance_by_cycle": []map[string]interface{}{
+			{"cycle": 17, "deposits": "64000000", "fees": "0", "rewards": "0"},
+		},
+		"/chains/main/blocks/head/helpers/endorsing_rights": []interface{}{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chains/main/blocks/head/helpers/baking_rights" {
+			rights := []interface{}{}
+			if r.URL.Query().Get("cycle") == "10" {
+				rights = append(rights, map[string]interface{}{"level": 100, "delegate": delegate, "priority": 0})
+			}
+			json.NewEncoder(w).Encode(rights)
+			return
+		}
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+
+	summaries, err := rewards.Summarize(context.Background(), client, delegate, 10, 17)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []rewards.CycleSummary{
+		{
+			Delegate: delegate, Cycle: 10, BlocksBaked: 1, Fees: 1000, BakingRewards: 2500000, Rewards: 2500000,
+			DepositsFrozen: 512000000, Unfrozen: true, DepositsUnfrozen: 512000000, RewardsUnfrozen: 2500000, FeesUnfrozen: 1000,
+		},
+		{Delegate: delegate, Cycle: 17, DepositsFrozen: 64000000},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Fatalf("unexpected summaries %+v", summaries)
+	}
+
+	var csv bytes.Buffer
+	if err := rewards.WriteCSV(&csv, summaries); err != nil {
+		t.Fatal(err)
+	}
+	expectedCSV := "delegate,cycle,blocks_baked,endorsing_slots,endorsed_slots,fees,baking_rewards,endorsement_rewards,rewards,deposits_frozen,unfrozen,deposits_unfrozen,rewards_unfrozen,fees_unfrozen\n" +
+		delegate + ",10,1,0,0,1000,2500000,0,2500000,512000000,true,512000000,2500000,1000\n" +
+		delegate + ",17,0,0,0,0,0,0,0,64000000,false,0,0,0\n"
+	if csv.String() != expectedCSV {
+		t.Fatalf("unexpected csv %q", csv.String())
+	}
+
+	var out bytes.Buffer
+	if err := rewards.WriteJSON(&out, summaries); err != nil {
+		t.Fatal(err)
+	}
+	decoded := []rewards.CycleSummary{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, summaries) {
+		t.Fatalf("unexpected json %s", out.String())
+	}
+}
@@ -0,0 +1,140 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Kinds of rights reported by a RightsWatcher
+const (
+	RightBaking    = "baking"
+	RightEndorsing = "endorsing"
+)
+
+// RightEvent announces an upcoming baking or endorsing right of a watched delegate
+type RightEvent struct {
+	Kind     string
+	Delegate string
+	Level    int64
+	// Priority is set for baking rights, Slots for endorsing rights
+	Priority int64
+	Slots    []int64
+	// EstimatedTime is when the node expects the block of the right to be baked
+	EstimatedTime time.Time
+}
+
+// key identifies the right of the event across refreshes
+func (e RightEvent) key() string {
+	return fmt.Sprintf("%s/%s/%d/%d", e.Kind, e.Delegate, e.Level, e.Priority)
+}
+
+// RightsWatcher periodically fetches the rights of delegates for the current and next cycles
+// and emits an event Lead before the estimated time of each of them
+type RightsWatcher struct {
+	rpc       *RPC
+	Delegates []string
+	// Lead is how long before the estimated time of a right its event is emitted
+	Lead time.Duration
+	// MaxPriority is the lowest baking priority watched, 0 watches priority 0 only
+	MaxPriority int64
+	// Refresh is how often rights are fetched again, PollInterval of the client by default
+	Refresh time.Duration
+}
+
+// NewRightsWatcher returns a watcher of the rights of delegates emitting events lead before each right
+func NewRightsWatcher(rpc *RPC, lead time.Duration, delegates ...string) *RightsWatcher {
+	return &RightsWatcher{rpc: rpc, Delegates: delegates, Lead: lead}
+}
+
+// Watch emits the upcoming rights of the delegates in order of estimated time until ctx is
+// cancelled. Rights whose estimated time has already passed are not reported. Both channels
+// are closed once ctx is cancelled, errs receives the failures of refreshes, as described in the
+// package documentation, and watching goes on with the rights already known.
+func (w *RightsWatcher) Watch(ctx context.Context) (<-chan RightEvent, <-chan error) {
+	events := make(chan RightEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		refresh := w.Refresh
+		if refresh <= 0 {
+			refresh = w.rpc.PollInterval
+		}
+		emitted := map[string]bool{}
+		pending := []RightEvent{}
+		nextRefresh := time.Now()
+		for {
+			if !time.Now().Before(nextRefresh) {
+				rights, err := w.fetch(ctx)
+				if err != nil {
+					reportError(errs, err)
+				} else {
+					pending = pending[:0]
+					for _, r := range rights {
+						if !emitted[r.key()] && r.EstimatedTime.After(time.Now()) {
+							pending = append(pending, r)
+						}
+					}
+					sort.SliceStable(pending, func(i, j int) bool { return pending[i].EstimatedTime.Before(pending[j].EstimatedTime) })
+				}
+				nextRefresh = time.Now().Add(refresh)
+			}
+			for len(pending) > 0 && !pending[0].EstimatedTime.Add(-w.Lead).After(time.Now()) {
+				select {
+				case events <- pending[0]:
+				case <-ctx.Done():
+					return
+				}
+				emitted[pending[0].key()] = true
+				pending = pending[1:]
+			}
+			wait := time.Until(nextRefresh)
+			if len(pending) > 0 {
+				if due := time.Until(pending[0].EstimatedTime.Add(-w.Lead)); due < wait {
+					wait = due
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+	return events, errs
+}
+
+// fetch returns the rights of the delegates for the cycle of head and the next one
+func (w *RightsWatcher) fetch(ctx context.Context) ([]RightEvent, error) {
+	metadata, err := w.rpc.GetBlockMetadata(ctx, "head")
+	if err != nil {
+		return nil, err
+	}
+	cycle := metadata.CurrentLevel().Cycle
+	query := RightsQuery{Delegates: w.Delegates, Cycles: []int64{cycle, cycle + 1}, MaxPriority: w.MaxPriority}
+	baking, err := w.rpc.GetBakingRights(ctx, "head", query)
+	if err != nil {
+		return nil, err
+	}
+	endorsing, err := w.rpc.GetEndorsingRights(ctx, "head", query)
+	if err != nil {
+		return nil, err
+	}
+	events := []RightEvent{}
+	for _, r := range baking {
+		if r.Priority > w.MaxPriority {
+			continue
+		}
+		if r.EstimatedTime != nil {
+			events = append(events, RightEvent{Kind: RightBaking, Delegate: r.Delegate, Level: r.Level, Priority: r.Priority, EstimatedTime: r.EstimatedTime.Time})
+		}
+	}
+	for _, r := range endorsing {
+		if r.EstimatedTime != nil {
+			events = append(events, RightEvent{Kind: RightEndorsing, Delegate: r.Delegate, Level: r.Level, Slots: r.Slots, EstimatedTime: r.EstimatedTime.Time})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,62 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestRightsWatcher(t *testing.T) {
+	at := func(d time.Duration) string { return time.Now().Add(d).UTC().Format(time.RFC3339Nano) }
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/metadata": map[string]interface{}{"level": map[string]interface{}{"level": 300, "cycle": 4}},
+		"GET /chains/main/blocks/head/helpers/baking_rights": []map[string]interface{}{
+			{"level": 299, "delegate": delegate, "priority": 0, "estimated_time": at(-time.Minute)},
+			{"level": 301, "delegate": delegate, "priority": 0, "estimated_time": at(100 * time.Millisecond)},
+			{"level": 302, "delegate": delegate, "priority": 2, "estimated_time": at(200 * time.Millisecond)},
+			{"level": 400, "delegate": delegate, "priority": 0, "estimated_time": at(2 * time.Hour)},
+		},
+		"GET /chains/main/blocks/head/helpers/endorsing_rights": []map[string]interface{}{
+			{"level": 300, "delegate": delegate, "slots": []int{3, 7}, "estimated_time": at(50 * time.Millisecond)},
+		},
+	})
+	client.PollInterval = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := tgo.NewRightsWatcher(client, time.Hour-time.Second, delegate)
+	events, _ := watcher.Watch(ctx)
+
+	// lead almost an hour: the rights within the next seconds are due at once, level 400 is not
+	got := []tgo.RightEvent{}
+	timeout := time.After(time.Second)
+	for len(got) < 2 {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-timeout:
+			t.Fatalf("expected two events, got %+v", got)
+		}
+	}
+	if got[0].Kind != tgo.RightEndorsing || got[0].Level != 300 || len(got[0].Slots) != 2 {
+		t.Fatalf("unexpected first event %+v", got[0])
+	}
+	if got[1].Kind != tgo.RightBaking || got[1].Level != 301 || got[1].Priority != 0 {
+		t.Fatalf("unexpected second event %+v", got[1])
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %+v", e)
+	case <-time.After(200 * time.Millisecond):
+	}
+	cancel()
+	for range events {
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if q := node.queries["GET /chains/main/blocks/head/helpers/baking_rights"][0]; q != "cycle=4&cycle=5&delegate="+delegate {
+		t.Fatalf("unexpected rights query %s", q)
+	}
+}
@@ -0,0 +1,121 @@
+package sandbox
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// BSON element types of the values of JSON documents
+const (
+	bsonDouble   = 0x01
+	bsonString   = 0x02
+	bsonDocument = 0x03
+	bsonArray    = 0x04
+	bsonBool     = 0x08
+	bsonNull     = 0x0a
+)
+
+// encodeParameters encodes v as the node expects protocol parameters: its JSON
+// representation as a BSON document, numbers being doubles
+func encodeParameters(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+	switch document := document.(type) {
+	case map[string]interface{}:
+		return bsonObject(document)
+	case []interface{}:
+		return bsonList(document)
+	}
+	return nil, fmt.Errorf("protocol parameters must be an object, got %s", raw)
+}
+
+// bsonObject encodes a JSON object as a document with its keys sorted
+func bsonObject(object map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	elements := []byte{}
+	for _, k := range keys {
+		element, err := bsonElement(k, object[k])
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element...)
+	}
+	return bsonDocumentOf(elements), nil
+}
+
+// bsonList encodes a JSON array as a document keyed by the indexes of its items
+func bsonList(items []interface{}) ([]byte, error) {
+	elements := []byte{}
+	for i, item := range items {
+		element, err := bsonElement(strconv.Itoa(i), item)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element...)
+	}
+	return bsonDocumentOf(elements), nil
+}
+
+// bsonDocumentOf wraps elements between the size of the document and its terminator
+func bsonDocumentOf(elements []byte) []byte {
+	document := make([]byte, 4, len(elements)+5)
+	binary.LittleEndian.PutUint32(document, uint32(len(elements)+5))
+	return append(append(document, elements...), 0x00)
+}
+
+// bsonElement encodes the value v of key in a document
+func bsonElement(key string, v interface{}) ([]byte, error) {
+	element := append([]byte{0}, key...)
+	element = append(element, 0x00)
+	switch v := v.(type) {
+	case nil:
+		element[0] = bsonNull
+	case bool:
+		element[0] = bsonBool
+		if v {
+			return append(element, 0x01), nil
+		}
+		element = append(element, 0x00)
+	case float64:
+		element[0] = bsonDouble
+		var raw [8]byte
+		binary.LittleEndian.PutUint64(raw[:], math.Float64bits(v))
+		element = append(element, raw[:]...)
+	case string:
+		element[0] = bsonString
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(v)+1))
+		element = append(append(append(element, size[:]...), v...), 0x00)
+	case map[string]interface{}:
+		element[0] = bsonDocument
+		document, err := bsonObject(v)
+		if err != nil {
+			return nil, err
+		}
+		element = append(element, document...)
+	case []interface{}:
+		element[0] = bsonArray
+		document, err := bsonList(v)
+		if err != nil {
+			return nil, err
+		}
+		element = append(element, document...)
+	default:
+		return nil, fmt.Errorf("unsupported value %v of %s", v, key)
+	}
+	return element, nil
+}
@@ -0,0 +1,254 @@
+// Package sandbox drives a node running a sandboxed chain: it activates a protocol with
+// custom parameters, bakes blocks on demand and moves time forward, so integration tests
+// of applications built on tgo can run against a local node entirely from Go
+package sandbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+// Secret keys of the activator and of the bootstrap accounts of the sandbox parameters
+// shipped with the node
+const (
+	ActivatorSecret  = "edsk31vznjHSSpGExDMHYASz45VZqXN4DPxvsa4hAyY8dHM28cZzp6"
+	Bootstrap1Secret = "edsk3gUfUPyBSfrS9CCgmCiQsTCHGkviBDusMxDJstFtojtc1zcpsh"
+	Bootstrap2Secret = "edsk39qAm1fiMjgmPkw1EgQYkMzkJezLNewd7PLNHTkr6w9XA2zdfo"
+	Bootstrap3Secret = "edsk4ArLQgBTLWG5FJmnGnT689VKoqhXwmDPBuGx3z4cvwU9MmrPZZ"
+	Bootstrap4Secret = "edsk2uqQB9AY4FvioK2YMdfmyMrer5R8mGFyuaLLFfSRo8EoyNdht3"
+	Bootstrap5Secret = "edsk4QLrcijEffxV31gGdN2HU7UpyJjA8drFoNcmnB28n89YjPNRFm"
+)
+
+// zeroSignature stands for the signature of headers before they are signed
+const zeroSignature = "edsigtXomBKi5CTRf5cjATJWSyaRvhfYNHqSUGrn4SdbYRcGwQrUGjzEfQDTuqHhuA8b2d8NarZjz8TRf65WkpQmo423BtomS8Q"
+
+// Sandbox bakes the blocks of a sandboxed chain through rpc
+type Sandbox struct {
+	rpc *tgo.RPC
+	// Activator signs the block activating the protocol, the key the node was started
+	// with in its sandbox configuration
+	Activator tgo.Signer
+	// Baker bakes the blocks at its best priority and must be a bootstrap delegate
+	Baker tgo.Signer
+	// Nonces are the nonces committed to by the baked blocks, revealed by the next blocks
+	Nonces *tgo.NonceTracker
+}
+
+// New returns a sandbox driving the node of rpc with the default activator key, baking
+// with the first bootstrap account
+func New(rpc *tgo.RPC) (*Sandbox, error) {
+	activator, err := tgo.NewKeyFromSecret(ActivatorSecret)
+	if err != nil {
+		return nil, err
+	}
+	baker, err := tgo.NewKeyFromSecret(Bootstrap1Secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Sandbox{rpc: rpc, Activator: activator, Baker: baker, Nonces: tgo.NewNonceTracker(rpc)}, nil
+}
+
+// Activation describes the activation of a protocol on top of the genesis block
+type Activation struct {
+	Protocol tgo.ProtocolHash
+	// Parameters are the protocol parameters, e.g. the bootstrap accounts and the constants,
+	// as a value encoding to a JSON object
+	Parameters interface{}
+	// Fitness defaults to ["01", "0000000000000001"]
+	Fitness []string
+	// Timestamp of the activation block, defaults to the time of the node. Activating in
+	// the past leaves room for FastForward.
+	Timestamp time.Time
+}
+
+// Activate signs with the activator key and injects the block activating the protocol on
+// top of the genesis block, returning its hash
+func (s *Sandbox) Activate(ctx context.Context, activation Activation) (tgo.BlockHash, error) {
+	parameters, err := encodeParameters(activation.Parameters)
+	if err != nil {
+		return "", fmt.Errorf("invalid protocol parameters: %w", err)
+	}
+	fitness := activation.Fitness
+	if fitness == nil {
+		fitness = []string{"01", "0000000000000001"}
+	}
+	chain := s.rpc.ChainAlias(ctx)
+	chainID, err := s.rpc.GetChainID(ctx, chain)
+	if err != nil {
+		return "", err
+	}
+	protocols := struct {
+		NextProtocol string `json:"next_protocol"`
+	}{}
+	if err := s.rpc.Get(ctx, fmt.Sprintf("/chains/%s/blocks/head/protocols", chain), &protocols); err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	if !activation.Timestamp.IsZero() {
+		query.Set("timestamp", strconv.FormatInt(activation.Timestamp.Unix(), 10))
+	}
+	type content struct {
+		Command    string           `json:"command"`
+		Hash       tgo.ProtocolHash `json:"hash"`
+		Fitness    []string         `json:"fitness"`
+		Parameters string           `json:"protocol_parameters"`
+	}
+	req := struct {
+		ProtocolData struct {
+			Protocol  string  `json:"protocol"`
+			Content   content `json:"content"`
+			Signature string  `json:"signature"`
+		} `json:"protocol_data"`
+		Operations [][]tgo.Operation `json:"operations"`
+	}{Operations: [][]tgo.Operation{}}
+	req.ProtocolData.Protocol = protocols.NextProtocol
+	req.ProtocolData.Content = content{"activate", activation.Protocol, fitness, hex.EncodeToString(parameters)}
+	req.ProtocolData.Signature = zeroSignature
+	resp := struct {
+		ShellHeader tgo.ShellHeader `json:"shell_header"`
+	}{}
+	path := fmt.Sprintf("/chains/%s/blocks/head/helpers/preapply/block", chain)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	if err := s.rpc.Post(ctx, path, req, &resp); err != nil {
+		return "", err
+	}
+	forged, err := tgo.ForgeActivationBlock(resp.ShellHeader, tgo.ActivationCommand{Protocol: activation.Protocol, Fitness: fitness, Parameters: parameters})
+	if err != nil {
+		return "", err
+	}
+	_, signed, err := tgo.SignBlockHeader(s.Activator, chainID, forged)
+	if err != nil {
+		return "", err
+	}
+	return s.rpc.InjectBlock(ctx, signed, [][]tgo.InjectableOperation{})
+}
+
+// Bake bakes a block on top of head at the time of the node, see BakeAt
+func (s *Sandbox) Bake(ctx context.Context) (tgo.BlockHash, error) {
+	return s.BakeAt(ctx, time.Time{})
+}
+
+// BakeAt reveals the nonces due and bakes a block at timestamp with the operations
+// applied in the mempool, at the best priority of the baker, and returns its hash. A zero
+// timestamp lets the node pick its time.
+func (s *Sandbox) BakeAt(ctx context.Context, timestamp time.Time) (tgo.BlockHash, error) {
+	if len(s.Nonces.Pending()) > 0 {
+		if _, err := s.Nonces.RevealDue(ctx); err != nil {
+			return "", err
+		}
+	}
+	right, err := s.right(ctx)
+	if err != nil {
+		return "", err
+	}
+	pending, err := s.rpc.GetMempoolPendingOperations(ctx, s.rpc.ChainAlias(ctx))
+	if err != nil {
+		return "", err
+	}
+	operations := [][]tgo.Operation{{}, {}, {}, {}}
+	for _, op := range pending.Applied {
+		if len(op.Contents) == 0 {
+			continue
+		}
+		pass := validationPass(op.Contents[0].Kind)
+		operations[pass] = append(operations[pass], tgo.Operation{Branch: op.Branch, Contents: op.Contents, Signature: op.Signature})
+	}
+	template := tgo.BlockTemplate{Priority: right.Priority, Operations: operations, Timestamp: timestamp}
+	constants, err := s.rpc.GetConstants(ctx, tgo.Head)
+	if err != nil {
+		return "", err
+	}
+	var nonce string
+	if constants.BlocksPerCommitment > 0 && right.Level%constants.BlocksPerCommitment == 0 {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		nonce = hex.EncodeToString(raw)
+		if template.SeedNonceHash, err = tgo.NonceHash(nonce); err != nil {
+			return "", err
+		}
+	}
+	hash, err := s.rpc.BakeBlock(ctx, s.Baker, template)
+	if err != nil {
+		return "", err
+	}
+	if nonce != "" {
+		if err := s.Nonces.Commit(right.Level, nonce); err != nil {
+			return hash, err
+		}
+	}
+	return hash, nil
+}
+
+// FastForward bakes blocks at the earliest time allowed by the protocol until the timestamp
+// of head moved forward by d, so time dependent code can be tested without waiting. Blocks
+// can not be baked ahead of the clock of the node: activating the protocol in the past
+// leaves room to move forward.
+func (s *Sandbox) FastForward(ctx context.Context, d time.Duration) error {
+	head, err := s.rpc.GetBlockHeader(ctx, tgo.Head)
+	if err != nil {
+		return err
+	}
+	target := head.Timestamp.Add(d)
+	for head.Timestamp.Before(target) {
+		right, err := s.right(ctx)
+		if err != nil {
+			return err
+		}
+		// a block without endorsements is valid last, it is valid whatever the mempool holds
+		var earliest tgo.Timestamp
+		path := fmt.Sprintf("/chains/%s/blocks/head/minimal_valid_time?priority=%d&endorsing_power=0", s.rpc.ChainAlias(ctx), right.Priority)
+		if err := s.rpc.Get(ctx, path, &earliest); err != nil {
+			return err
+		}
+		if _, err := s.BakeAt(ctx, earliest.Time); err != nil {
+			return err
+		}
+		if head, err = s.rpc.GetBlockHeader(ctx, tgo.Head); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// right returns the baking right of the baker with the best priority at the next level
+func (s *Sandbox) right(ctx context.Context) (tgo.BakingRight, error) {
+	rights, err := s.rpc.GetBakingRights(ctx, tgo.Head, tgo.RightsQuery{Delegates: []string{s.Baker.PublicKeyHash()}})
+	if err != nil {
+		return tgo.BakingRight{}, err
+	}
+	if len(rights) == 0 {
+		return tgo.BakingRight{}, errors.New("no baking right for " + s.Baker.PublicKeyHash() + " at the next level")
+	}
+	best := rights[0]
+	for _, r := range rights[1:] {
+		if r.Priority < best.Priority {
+			best = r
+		}
+	}
+	return best, nil
+}
+
+// validationPass returns the validation pass of the operations of kind
+func validationPass(kind string) int {
+	switch kind {
+	case "endorsement", "endorsement_with_slot":
+		return 0
+	case "proposals", "ballot":
+		return 1
+	case "seed_nonce_revelation", "double_endorsement_evidence", "double_baking_evidence", "activate_account":
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,152 @@
+package sandbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/sandbox"
+)
+
+const protocol = "PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS"
+
+// node fakes a sandboxed node whose head moves forward by a minute each injected block
+type node struct {
+	mu     sync.Mutex
+	level  int64
+	time   time.Time
+	bodies map[string][]string
+}
+
+func newNode(t *testing.T) (*node, *tgo.RPC) {
+	n := &node{level: 7, time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), bodies: map[string][]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		key := r.Method + " " + r.URL.Path
+		n.bodies[key] = append(n.bodies[key], string(body))
+		shell := tgo.ShellHeader{
+			Level:          n.level + 1,
+			Proto:          1,
+			Predecessor:    "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2",
+			Timestamp:      tgo.NewTimestamp(n.time.Add(time.Minute)),
+			ValidationPass: 4,
+			OperationsHash: "LLoZKi7YfF6zf8vpKTbstYfpJaDu8fMmnJShSvApkx7uaQ2rsAa4T",
+			Fitness:        []string{"01", "0000000000000001"},
+			Context:        "CoUtTZPbHbP2fb3hZ2xUa7WGKn9WRRaXqvxfAP1p5raecRUkyKjF",
+		}
+		var resp interface{}
+		switch key {
+		case "GET /chains/main/chain_id":
+			resp = "NetXdQprcVkpaWU"
+		case "GET /chains/main/blocks/head/protocols":
+			resp = map[string]string{"protocol": protocol, "next_protocol": protocol}
+		case "GET /chains/main/blocks/head/header":
+			resp = map[string]interface{}{"level": n.level, "timestamp": tgo.NewTimestamp(n.time)}
+		case "GET /chains/main/blocks/head/context/constants":
+			resp = map[string]interface{}{"blocks_per_commitment": 8, "proof_of_work_threshold": "-1"}
+		case "GET /chains/main/blocks/head/helpers/baking_rights":
+			resp = []map[string]interface{}{
+				{"level": n.level + 1, "delegate": "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", "priority": 2},
+				{"level": n.level + 1, "delegate": "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", "priority": 1},
+			}
+		case "GET /chains/main/blocks/head/minimal_valid_time":
+			resp = tgo.NewTimestamp(n.time.Add(time.Minute))
+		case "GET /chains/main/blocks/head/metadata", "GET /chains/main/blocks/8/metadata":
+			resp = map[string]interface{}{"level": map[string]interface{}{"level": n.level, "cycle": 0}}
+		case "GET /chains/main/mempool/pending_operations":
+			resp = map[string]interface{}{"applied": []map[string]interface{}{
+				{"hash": "opT", "branch": "BLb", "contents": []map[string]interface{}{{"kind": "transaction", "amount": "1"}}, "signature": "sigT"},
+				{"hash": "opE", "branch": "BLb", "contents": []map[string]interface{}{{"kind": "endorsement", "level": n.level}}, "signature": "sigE"},
+			}}
+		case "POST /chains/main/blocks/head/helpers/preapply/block":
+			resp = map[string]interface{}{"shell_header": shell, "operations": []interface{}{}}
+		case "POST /injection/block":
+			n.level++
+			n.time = n.time.Add(time.Minute)
+			resp = fmt.Sprintf("BLock%d", n.level)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return n, tgo.GenerateClient(server.URL, time.Second*5)
+}
+
+func TestActivate(t *testing.T) {
+	n, client := newNode(t)
+	s, err := sandbox.New(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := s.Activate(context.Background(), sandbox.Activation{
+		Protocol:   protocol,
+		Parameters: map[string]interface{}{"a": 1},
+		Timestamp:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil || hash != "BLock8" {
+		t.Fatalf("unexpected activation %s: %v", hash, err)
+	}
+	preapply := n.bodies["POST /chains/main/blocks/head/helpers/preapply/block"][0]
+	// {"a": 1} as a BSON document
+	if !strings.Contains(preapply, `"content":{"command":"activate","hash":"`+protocol+`","fitness":["01","0000000000000001"],"protocol_parameters":"10000000016100000000000000f03f00"}`) {
+		t.Fatalf("unexpected preapply request %s", preapply)
+	}
+	injection := struct {
+		Data string `json:"data"`
+	}{}
+	json.Unmarshal([]byte(n.bodies["POST /injection/block"][0]), &injection)
+	if !strings.HasSuffix(injection.Data[:len(injection.Data)-128], "10000000016100000000000000f03f00") {
+		t.Fatalf("unexpected injected block %s", injection.Data)
+	}
+	if _, err := s.Activate(context.Background(), sandbox.Activation{Protocol: protocol, Parameters: 1}); err == nil {
+		t.Fatal("expected error for parameters not encoding to an object")
+	}
+}
+
+func TestBakeAndFastForward(t *testing.T) {
+	n, client := newNode(t)
+	s, err := sandbox.New(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := s.Bake(context.Background())
+	if err != nil || hash != "BLock8" {
+		t.Fatalf("unexpected block %s: %v", hash, err)
+	}
+	preapply := struct {
+		ProtocolData tgo.BlockProtocolData `json:"protocol_data"`
+		Operations   [][]tgo.Operation     `json:"operations"`
+	}{}
+	if err := json.Unmarshal([]byte(n.bodies["POST /chains/main/blocks/head/helpers/preapply/block"][0]), &preapply); err != nil {
+		t.Fatal(err)
+	}
+	if preapply.ProtocolData.Priority != 1 || preapply.ProtocolData.SeedNonceHash == "" {
+		t.Fatalf("unexpected protocol data %+v", preapply.ProtocolData)
+	}
+	if len(preapply.Operations) != 4 || len(preapply.Operations[0]) != 1 || preapply.Operations[0][0].Signature != "sigE" || len(preapply.Operations[3]) != 1 || preapply.Operations[3][0].Signature != "sigT" {
+		t.Fatalf("operations not sorted by validation pass %+v", preapply.Operations)
+	}
+	if pending := s.Nonces.Pending(); len(pending) != 1 || pending[0] != 8 {
+		t.Fatalf("nonce of level 8 not committed %v", pending)
+	}
+	if err := s.FastForward(context.Background(), 3*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.level != 11 {
+		t.Fatalf("expected 3 blocks baked, head at level %d", n.level)
+	}
+}
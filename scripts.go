@@ -0,0 +1,48 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// RunCodeInput is the body of `POST /chains/<chain>/blocks/<block_id>/helpers/scripts/run_code`
+type RunCodeInput struct {
+	Script  micheline.Node `json:"script"`
+	Storage micheline.Node `json:"storage"`
+	Input   micheline.Node `json:"input"`
+	Amount  string         `json:"amount"`
+	// ChainID defaults to the id of the main chain
+	ChainID    ChainID `json:"chain_id"`
+	Source     string  `json:"source,omitempty"`
+	Payer      string  `json:"payer,omitempty"`
+	Entrypoint string  `json:"entrypoint,omitempty"`
+}
+
+// RunCodeResult holds the response from `POST /chains/<chain>/blocks/<block_id>/helpers/scripts/run_code`
+type RunCodeResult struct {
+	Storage micheline.Node `json:"storage"`
+	// Operations are the internal operations emitted by the script, they are not applied
+	Operations []OperationContents `json:"operations"`
+}
+
+// RunCode calls POST /chains/<chain>/blocks/<block_id>/helpers/scripts/run_code to run a script
+// against a storage and an input without touching the chain
+func (rpc *RPC) RunCode(ctx context.Context, blockID BlockID, input RunCodeInput) (*RunCodeResult, error) {
+	if input.Amount == "" {
+		input.Amount = "0"
+	}
+	if input.ChainID == "" {
+		chainID, err := rpc.GetChainID(ctx, rpc.ChainAlias(ctx))
+		if err != nil {
+			return nil, err
+		}
+		input.ChainID = chainID
+	}
+	result := &RunCodeResult{}
+	if err := rpc.post(ctx, fmt.Sprintf("/chains/%s/blocks/%s/helpers/scripts/run_code", rpc.ChainAlias(ctx), blockID), input, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
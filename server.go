@@ -0,0 +1,242 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAllowedPaths are the paths served by a Server without allowlist, the reads of the
+// blocks of the chains and of the protocols
+var DefaultAllowedPaths = []string{
+	"/version",
+	"/chains/*/chain_id",
+	"/chains/*/blocks",
+	"/chains/*/blocks/*",
+	"/chains/*/blocks/*/**",
+	"/protocols",
+	"/protocols/*",
+}
+
+// default server settings
+const (
+	defaultServerCacheSize = 1024
+	// clientsSweepPeriod is how often the rate limits of clients gone quiet are forgotten
+	clientsSweepPeriod = time.Minute
+)
+
+// ServerOptions configures a Server, zero values use defaults
+type ServerOptions struct {
+	// AllowedPaths are the patterns of the paths served, DefaultAllowedPaths when empty. A *
+	// segment matches any single segment and a trailing /** any number of segments below.
+	AllowedPaths []string
+	// CacheSize is how many responses of paths that never change, such as the reads of blocks
+	// by hash, are kept in memory, 1024 by default and none when negative
+	CacheSize int
+	// RateLimit is how many requests per second a client may make, unlimited when 0
+	RateLimit float64
+	// Burst is how many requests a client may make at once, RateLimit rounded up by default
+	Burst int
+	// TrustForwardedFor identifies clients by the first address of X-Forwarded-For, for
+	// servers behind a load balancer, instead of the address of the connection
+	TrustForwardedFor bool
+}
+
+// Server is a read-only reverse proxy serving the RPC paths of the node behind rpc over HTTP,
+// for an archive node to be exposed without its write endpoints. Only GET and HEAD requests on
+// allowed paths are served, through the transport, circuit breaker, store and coalescing of
+// rpc, the responses that never change being also cached in memory.
+type Server struct {
+	rpc   *RPC
+	opts  ServerOptions
+	cache *LRU
+
+	mu        sync.Mutex
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewServer returns a server proxying the node behind rpc
+func NewServer(rpc *RPC, opts ServerOptions) *Server {
+	if len(opts.AllowedPaths) == 0 {
+		opts.AllowedPaths = DefaultAllowedPaths
+	}
+	if opts.CacheSize == 0 {
+		opts.CacheSize = defaultServerCacheSize
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = int(math.Ceil(opts.RateLimit))
+	}
+	s := &Server{rpc: rpc, opts: opts, clients: map[string]*tokenBucket{}, lastSweep: time.Now()}
+	if opts.CacheSize > 0 {
+		s.cache = NewLRU(opts.CacheSize)
+	}
+	return s
+}
+
+// ServeHTTP serves the response of the node to r
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	clean := path.Clean("/" + r.URL.Path)
+	if !s.allowed(clean) {
+		http.Error(w, "path not allowed", http.StatusForbidden)
+		return
+	}
+	if wait, ok := s.limit(s.clientAddr(r)); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	p := (&url.URL{Path: clean}).EscapedPath()
+	if r.URL.RawQuery != "" {
+		p += "?" + r.URL.RawQuery
+	}
+	body, hit, err := s.fetch(r.Context(), p)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if hit {
+		w.Header().Set("X-Cache", "hit")
+	} else {
+		w.Header().Set("X-Cache", "miss")
+	}
+	if r.Method == http.MethodGet {
+		w.Write(body)
+	}
+}
+
+// fetch returns the body of GET p from the cache when it never changes, or from the node
+func (s *Server) fetch(ctx context.Context, p string) ([]byte, bool, error) {
+	cached := s.cache != nil && immutablePath(p)
+	if cached {
+		if body, ok := s.cache.Get(p); ok {
+			return body.([]byte), true, nil
+		}
+	}
+	body, err := s.rpc.getBody(ctx, p)
+	if err != nil {
+		return nil, false, err
+	}
+	if cached {
+		s.cache.Add(p, body)
+	}
+	return body, false, nil
+}
+
+// writeError relays the status and body of the errors of the node, and answers 502 or 503 when
+// it could not be reached
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	statusErr := &StatusError{}
+	switch {
+	case r.Context().Err() != nil:
+		// the client is gone
+	case errors.As(err, &statusErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusErr.StatusCode)
+		w.Write([]byte(statusErr.Body))
+	case errors.Is(err, ErrCircuitOpen):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+// allowed reports whether p matches one of the allowed patterns
+func (s *Server) allowed(p string) bool {
+	for _, pattern := range s.opts.AllowedPaths {
+		if matchPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether p matches pattern segment by segment, a trailing /** matching any
+// number of segments below
+func matchPath(pattern, p string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if patternSegments[len(patternSegments)-1] == "**" {
+		patternSegments = patternSegments[:len(patternSegments)-1]
+		if len(segments) <= len(patternSegments) {
+			return false
+		}
+		segments = segments[:len(patternSegments)]
+	}
+	if len(segments) != len(patternSegments) {
+		return false
+	}
+	for i, segment := range segments {
+		if ok, err := path.Match(patternSegments[i], segment); !ok || err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// clientAddr returns the address identifying the client of r for rate limiting
+func (s *Server) clientAddr(r *http.Request) string {
+	if s.opts.TrustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// tokenBucket holds the requests a client may still make, refilled at the rate limit
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limit takes a request from the bucket of client and returns how long to wait otherwise
+func (s *Server) limit(client string) (time.Duration, bool) {
+	if s.opts.RateLimit <= 0 {
+		return 0, true
+	}
+	now := time.Now()
+	burst := float64(s.opts.Burst)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) > clientsSweepPeriod {
+		// clients whose bucket refilled are forgotten, they start again with a full bucket
+		for addr, b := range s.clients {
+			if b.tokens+now.Sub(b.last).Seconds()*s.opts.RateLimit >= burst {
+				delete(s.clients, addr)
+			}
+		}
+		s.lastSweep = now
+	}
+	b, ok := s.clients[client]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		s.clients[client] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*s.opts.RateLimit)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / s.opts.RateLimit * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
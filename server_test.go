@@ -0,0 +1,65 @@
+package tgo_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestServer(t *testing.T) {
+	hash := "BLockGenesisGenesisGenesisGenesisGenesisf79b5d1CoW2"
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/" + hash + "/header": rawBody(`{"level":1}`),
+		"GET /chains/main/blocks/head/header":         rawBody(`{"level":9}`),
+		"GET /network/connections":                    rawBody(`[]`),
+	})
+	proxy := httptest.NewServer(tgo.NewServer(client, tgo.ServerOptions{RateLimit: 1, Burst: 4}))
+	defer proxy.Close()
+
+	get := func(method, path string) (*http.Response, string) {
+		req, err := http.NewRequest(method, proxy.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp, strings.TrimSpace(string(body))
+	}
+	for _, c := range []struct {
+		method, path string
+		status       int
+		body, cache  string
+	}{
+		{http.MethodGet, "/chains/main/blocks/" + hash + "/header", http.StatusOK, `{"level":1}`, "miss"},
+		// responses of blocks read by hash never change and are served from the cache
+		{http.MethodGet, "/chains/main/blocks/" + hash + "/header", http.StatusOK, `{"level":1}`, "hit"},
+		{http.MethodGet, "/chains/main/blocks/head/header?version=1", http.StatusOK, `{"level":9}`, "miss"},
+		{http.MethodPost, "/injection/operation", http.StatusMethodNotAllowed, "method not allowed", ""},
+		{http.MethodGet, "/network/connections", http.StatusForbidden, "path not allowed", ""},
+		{http.MethodGet, "/chains/main/blocks/../../network/connections", http.StatusForbidden, "path not allowed", ""},
+		{http.MethodGet, "/chains/main/blocks/head/missing", http.StatusNotFound, "404 page not found", ""},
+		// the burst of 4 requests is spent, refused requests not counting
+		{http.MethodGet, "/chains/main/blocks/head/header", http.StatusTooManyRequests, "rate limit exceeded", ""},
+	} {
+		resp, body := get(c.method, c.path)
+		if resp.StatusCode != c.status || body != c.body || resp.Header.Get("X-Cache") != c.cache {
+			t.Fatalf("%s %s: unexpected response %s %q, cache %q", c.method, c.path, resp.Status, body, resp.Header.Get("X-Cache"))
+		}
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if n := len(node.queries["GET /chains/main/blocks/"+hash+"/header"]); n != 1 {
+		t.Fatalf("expected the block to be read once, got %d", n)
+	}
+	if q := node.queries["GET /chains/main/blocks/head/header"]; len(q) != 1 || q[0] != "version=1" {
+		t.Fatalf("unexpected queries %v", q)
+	}
+}
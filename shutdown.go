@@ -0,0 +1,68 @@
+package tgo
+
+import (
+	"context"
+	"sync"
+)
+
+// lifecycle tracks the streams and goroutines of a client so Close can stop them
+type lifecycle struct {
+	mu      sync.Mutex
+	closed  bool
+	next    int
+	cancels map[int]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{cancels: map[int]context.CancelFunc{}}
+}
+
+// begin registers work running under ctx with the client. The returned context is cancelled
+// by Close, done must be called once the work ends. After Close the context is already
+// cancelled.
+func (rpc *RPC) begin(ctx context.Context) (context.Context, func()) {
+	if rpc.life == nil {
+		return ctx, func() {}
+	}
+	l := rpc.life
+	ctx, cancel := context.WithCancel(ctx)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		cancel()
+		return ctx, func() {}
+	}
+	id := l.next
+	l.next++
+	l.cancels[id] = cancel
+	l.wg.Add(1)
+	return ctx, func() {
+		l.mu.Lock()
+		delete(l.cancels, id)
+		l.mu.Unlock()
+		cancel()
+		l.wg.Done()
+	}
+}
+
+// Close cancels the monitoring streams of the client, waits for the goroutines they run to
+// return and closes the idle connections of the transport created by SetTransport. HTTP
+// clients and transports set by the caller, such as http.DefaultClient used by GenerateClient,
+// may be shared and are left open. Streams opened after Close end immediately. Close is safe
+// to call more than once.
+func (rpc *RPC) Close() error {
+	if l := rpc.life; l != nil {
+		l.mu.Lock()
+		l.closed = true
+		for _, cancel := range l.cancels {
+			cancel()
+		}
+		l.mu.Unlock()
+		l.wg.Wait()
+	}
+	if rpc.transport != nil {
+		rpc.transport.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package tgo_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hash":"BL1","level":1}` + "\n"))
+		w.(http.Flusher).Flush()
+		// hold the stream open until the client goes away
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+
+	heads, errs := client.MonitorHeads(context.Background(), "main")
+	if head := <-heads; head.Hash != "BL1" {
+		t.Fatalf("unexpected head %+v", head)
+	}
+	closed := make(chan error)
+	go func() { closed <- client.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Close did not return")
+	}
+	// the stream goroutine has returned, both channels are already closed
+	select {
+	case _, ok := <-heads:
+		if ok {
+			t.Fatal("expected heads to be closed")
+		}
+	default:
+		t.Fatal("expected heads to be closed once Close returns")
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	// streams opened after Close end immediately
+	heads, _ = client.MonitorHeads(context.Background(), "main")
+	for range heads {
+		t.Fatal("expected no head after Close")
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCloseIdleConnections(t *testing.T) {
+	closedConns := make(chan struct{}, 10)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"BL1"`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closedConns <- struct{}{}
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	// the idle connections of a shared client are left open
+	shared := &http.Client{Transport: &http.Transport{}}
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	client.Client = shared
+	if _, err := client.GetHeadHash(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+	select {
+	case <-closedConns:
+		t.Fatal("expected the connection of a shared client to be left open")
+	case <-time.After(50 * time.Millisecond):
+	}
+	shared.CloseIdleConnections()
+	<-closedConns
+
+	// the idle connections of the transport set by SetTransport are closed
+	client = tgo.GenerateClient(server.URL, time.Second*5)
+	client.SetTransport(tgo.TransportOptions{})
+	if _, err := client.GetHeadHash(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+	select {
+	case <-closedConns:
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the idle connection to be closed")
+	}
+}
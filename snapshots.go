@@ -0,0 +1,106 @@
+package tgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Snapshot locates the roll snapshot whose balances were used to compute the rights of a cycle
+type Snapshot struct {
+	Cycle     int64
+	Index     int64
+	Level     int64
+	BlockHash BlockHash
+}
+
+// cycleData holds the raw context data of a cycle from `GET .../context/raw/json/cycle/<cycle>`
+type cycleData struct {
+	RollSnapshot int64  `json:"roll_snapshot"`
+	RandomSeed   string `json:"random_seed"`
+}
+
+// GetSnapshot resolves the snapshot index of cycle from the raw context and the block it
+// designates. cycles converts levels across protocol eras, it is fetched when nil.
+func (rpc *RPC) GetSnapshot(ctx context.Context, cycles *Cycles, cycle int64) (Snapshot, error) {
+	if cycles == nil {
+		var err error
+		if cycles, err = rpc.GetCycles(ctx); err != nil {
+			return Snapshot{}, err
+		}
+	}
+	head, err := rpc.GetBlockHeader(ctx, "head")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	// the cycle data is only kept for a few cycles, read it from within the cycle when possible
+	blockID := Head
+	if first := cycles.FirstLevel(cycle); first <= head.Level {
+		blockID = LevelID(first)
+	}
+	constants, err := rpc.GetConstants(ctx, blockID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	data := cycleData{}
+	if err := rpc.get(ctx, fmt.Sprintf("/chains/%s/blocks/%s/context/raw/json/cycle/%d", rpc.ChainAlias(ctx), blockID, cycle), &data); err != nil {
+		return Snapshot{}, err
+	}
+	snapshotCycle := cycle - constants.PreservedCycles - 2
+	if snapshotCycle < 0 {
+		return Snapshot{}, fmt.Errorf("cycle %d uses the genesis rolls and has no snapshot", cycle)
+	}
+	level := cycles.FirstLevel(snapshotCycle) + (data.RollSnapshot+1)*constants.BlocksPerRollSnapshot - 1
+	header, err := rpc.GetBlockHeader(ctx, LevelID(level))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{Cycle: cycle, Index: data.RollSnapshot, Level: level, BlockHash: header.Hash}, nil
+}
+
+// Delegator is a contract delegating to a delegate and its balance, in mutez
+type Delegator struct {
+	Address string
+	Balance int64
+}
+
+// SnapshotDelegation is the delegation of a delegate at the roll snapshot of a cycle
+type SnapshotDelegation struct {
+	Snapshot       Snapshot
+	Delegate       string
+	StakingBalance int64
+	// Delegators are sorted by decreasing balance and exclude the delegate itself
+	Delegators []Delegator
+}
+
+// GetSnapshotDelegators lists the delegators of delegate and their balances at the snapshot
+// block of cycle, the balances its rights and rewards were computed from. cycles converts
+// levels across protocol eras, it is fetched when nil.
+func (rpc *RPC) GetSnapshotDelegators(ctx context.Context, cycles *Cycles, delegate string, cycle int64) (SnapshotDelegation, error) {
+	snapshot, err := rpc.GetSnapshot(ctx, cycles, cycle)
+	if err != nil {
+		return SnapshotDelegation{}, err
+	}
+	delegation := SnapshotDelegation{Snapshot: snapshot, Delegate: delegate, Delegators: []Delegator{}}
+	if delegation.StakingBalance, err = rpc.GetStakingBalance(ctx, snapshot.BlockHash.ID(), delegate); err != nil {
+		return delegation, err
+	}
+	contracts, err := rpc.GetDelegatedContracts(ctx, snapshot.BlockHash.ID(), delegate)
+	if err != nil {
+		return delegation, err
+	}
+	for _, address := range contracts {
+		if address == delegate {
+			continue
+		}
+		balance, err := rpc.GetBalance(ctx, snapshot.BlockHash.ID(), address)
+		if err != nil {
+			return delegation, err
+		}
+		delegation.Delegators = append(delegation.Delegators, Delegator{Address: address, Balance: balance})
+	}
+	sort.SliceStable(delegation.Delegators, func(i, j int) bool {
+		return delegation.Delegators[i].Balance > delegation.Delegators[j].Balance
+	})
+	return delegation, nil
+}
@@ -0,0 +1,59 @@
+package tgo_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestGetSnapshot(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header":                    map[string]interface{}{"level": 40000},
+		"GET /chains/main/blocks/36865/context/constants":        map[string]interface{}{"preserved_cycles": 5, "blocks_per_cycle": 4096, "blocks_per_roll_snapshot": 256},
+		"GET /chains/main/blocks/36865/context/raw/json/cycle/9": map[string]interface{}{"roll_snapshot": 12, "random_seed": "seed"},
+		"GET /chains/main/blocks/11520/header":                   map[string]interface{}{"level": 11520, "hash": "BLsnapshot"},
+	})
+	cycles, err := tgo.NewCycles(tgo.CycleEra{FirstLevel: 1, BlocksPerCycle: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := client.GetSnapshot(context.Background(), cycles, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cycle 9 uses a snapshot of cycle 2: 8193 + 13*256 - 1
+	if snapshot != (tgo.Snapshot{Cycle: 9, Index: 12, Level: 11520, BlockHash: "BLsnapshot"}) {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+}
+
+func TestGetSnapshotDelegators(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/header":                                                       map[string]interface{}{"level": 40000},
+		"GET /chains/main/blocks/36865/context/constants":                                           map[string]interface{}{"preserved_cycles": 5, "blocks_per_cycle": 4096, "blocks_per_roll_snapshot": 256},
+		"GET /chains/main/blocks/36865/context/raw/json/cycle/9":                                    map[string]interface{}{"roll_snapshot": 12, "random_seed": "seed"},
+		"GET /chains/main/blocks/11520/header":                                                      map[string]interface{}{"level": 11520, "hash": "BLsnapshot"},
+		"GET /chains/main/blocks/BLsnapshot/context/delegates/" + delegate + "/staking_balance":     "600",
+		"GET /chains/main/blocks/BLsnapshot/context/delegates/" + delegate + "/delegated_contracts": []string{"KT1small", delegate, "KT1large"},
+		"GET /chains/main/blocks/BLsnapshot/context/contracts/KT1small/balance":                     "100",
+		"GET /chains/main/blocks/BLsnapshot/context/contracts/KT1large/balance":                     "300",
+	})
+	cycles, err := tgo.NewCycles(tgo.CycleEra{FirstLevel: 1, BlocksPerCycle: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegation, err := client.GetSnapshotDelegators(context.Background(), cycles, delegate, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delegation.Snapshot.Level != 11520 || delegation.StakingBalance != 600 {
+		t.Fatalf("unexpected delegation %+v", delegation)
+	}
+	expected := []tgo.Delegator{{Address: "KT1large", Balance: 300}, {Address: "KT1small", Balance: 100}}
+	if !reflect.DeepEqual(delegation.Delegators, expected) {
+		t.Fatalf("unexpected delegators %+v", delegation.Delegators)
+	}
+}
@@ -0,0 +1,49 @@
+package tgo
+
+import "context"
+
+// GCStats holds the response from `GET /stats/gc`, the OCaml garbage collector counters of the node
+type GCStats struct {
+	MinorWords       float64 `json:"minor_words"`
+	PromotedWords    float64 `json:"promoted_words"`
+	MajorWords       float64 `json:"major_words"`
+	MinorCollections int64   `json:"minor_collections"`
+	MajorCollections int64   `json:"major_collections"`
+	HeapWords        int64   `json:"heap_words"`
+	HeapChunks       int64   `json:"heap_chunks"`
+	LiveWords        int64   `json:"live_words"`
+	LiveBlocks       int64   `json:"live_blocks"`
+	FreeWords        int64   `json:"free_words"`
+	FreeBlocks       int64   `json:"free_blocks"`
+	LargestFree      int64   `json:"largest_free"`
+	Fragments        int64   `json:"fragments"`
+	Compactions      int64   `json:"compactions"`
+	TopHeapWords     int64   `json:"top_heap_words"`
+	StackSize        int64   `json:"stack_size"`
+}
+
+// MemoryStats holds the response from `GET /stats/memory`, sizes are in pages of PageSize bytes
+type MemoryStats struct {
+	PageSize int64 `json:"page_size"`
+	Size     int64 `json:"size,string"`
+	Resident int64 `json:"resident,string"`
+	Shared   int64 `json:"shared,string"`
+	Text     int64 `json:"text,string"`
+	Lib      int64 `json:"lib,string"`
+	Data     int64 `json:"data,string"`
+	Dt       int64 `json:"dt,string"`
+}
+
+// GetStatsGC calls GET /stats/gc
+func (rpc *RPC) GetStatsGC(ctx context.Context) (GCStats, error) {
+	stats := GCStats{}
+	err := rpc.get(ctx, "/stats/gc", &stats)
+	return stats, err
+}
+
+// GetStatsMemory calls GET /stats/memory
+func (rpc *RPC) GetStatsMemory(ctx context.Context) (MemoryStats, error) {
+	stats := MemoryStats{}
+	err := rpc.get(ctx, "/stats/memory", &stats)
+	return stats, err
+}
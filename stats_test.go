@@ -0,0 +1,27 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /stats/gc":     rawBody(`{"minor_words":1.5e9,"promoted_words":2e8,"major_words":3e8,"minor_collections":1200,"major_collections":30,"heap_words":4000000,"heap_chunks":12,"live_words":3000000,"live_blocks":90000,"free_words":1000000,"free_blocks":100,"largest_free":5000,"fragments":4,"compactions":2,"top_heap_words":5000000,"stack_size":300}`),
+		"GET /stats/memory": rawBody(`{"page_size":4096,"size":"250000","resident":"120000","shared":"3000","text":"9000","lib":"0","data":"110000","dt":"0"}`),
+	})
+	gc, err := client.GetStatsGC(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gc.MinorCollections != 1200 || gc.MinorWords != 1.5e9 {
+		t.Fatalf("unexpected gc stats %+v", gc)
+	}
+	memory, err := client.GetStatsMemory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory.PageSize != 4096 || memory.Resident != 120000 {
+		t.Fatalf("unexpected memory stats %+v", memory)
+	}
+}
@@ -0,0 +1,29 @@
+package tgo
+
+import "strings"
+
+// CacheStore persists the raw responses of requests whose result never changes, such as
+// the parts of a block read by hash, so they survive restarts of the process
+type CacheStore interface {
+	// Get returns the value stored for key, ok is false when there is none
+	Get(key string) (value []byte, ok bool, err error)
+	Put(key string, value []byte) error
+}
+
+// immutablePath reports whether the response to GET path never changes, which is the case
+// for everything read from a block designated by hash on any chain
+func immutablePath(path string) bool {
+	if !strings.HasPrefix(path, "/chains/") {
+		return false
+	}
+	chain := strings.TrimPrefix(path, "/chains/")
+	i := strings.IndexByte(chain, '/')
+	if i < 0 || !strings.HasPrefix(chain[i:], "/blocks/") {
+		return false
+	}
+	blockID := strings.TrimPrefix(chain[i:], "/blocks/")
+	if i := strings.IndexAny(blockID, "/?"); i >= 0 {
+		blockID = blockID[:i]
+	}
+	return isBlockHash(BlockID(blockID))
+}
@@ -0,0 +1,160 @@
+package tgo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// newDecoder returns a decoder over r rejecting unknown fields when rpc.Strict is set
+func (rpc *RPC) newDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if rpc.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder
+}
+
+// unmarshal decodes the response to path into out, checking in strict mode that it has no
+// unknown field and no missing required field
+func (rpc *RPC) unmarshal(path string, body []byte, out interface{}) error {
+	if !rpc.Strict {
+		return json.Unmarshal(body, out)
+	}
+	if err := rpc.newDecoder(bytes.NewReader(body)).Decode(out); err != nil {
+		return fmt.Errorf("strict decoding of %s: %w", path, err)
+	}
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	if err := checkRequired(reflect.TypeOf(out), raw, ""); err != nil {
+		return fmt.Errorf("strict decoding of %s: %w", path, err)
+	}
+	return nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	strictShaperType    = reflect.TypeOf((*strictShaper)(nil)).Elem()
+)
+
+// strictShaper is implemented by types decoding themselves from one of several shapes, such as
+// block metadata changing with the protocol, to have their fields checked in strict mode
+type strictShaper interface {
+	// strictShape returns the type whose fields the decoded JSON value raw must have
+	strictShape(raw interface{}) reflect.Type
+}
+
+// checkRequired returns an error naming the first field of typ missing from the decoded JSON
+// value raw. Fields are required unless tagged omitempty, null values and types decoding
+// themselves are not inspected unless they implement strictShaper.
+func checkRequired(typ reflect.Type, raw interface{}, at string) error {
+	return checkFields(typ, raw, at, nil)
+}
+
+// checkFields checks raw as checkRequired does, skipping the fields named in shadowed which are
+// those of an embedded struct hidden by the fields of the struct embedding it
+func checkFields(typ reflect.Type, raw interface{}, at string, shadowed map[string]bool) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if raw == nil {
+		return nil
+	}
+	if reflect.PtrTo(typ).Implements(strictShaperType) {
+		typ = reflect.New(typ).Interface().(strictShaper).strictShape(raw)
+	}
+	if reflect.PtrTo(typ).Implements(jsonUnmarshalerType) || reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		values, _ := raw.([]interface{})
+		for i, v := range values {
+			if err := checkFields(typ.Elem(), v, fmt.Sprintf("%s[%d]", at, i), nil); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		values, _ := raw.(map[string]interface{})
+		for k, v := range values {
+			if err := checkFields(typ.Elem(), v, at+"."+k, nil); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		values, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		// fields of embedded structs are hidden by the fields of the same name of typ
+		names := map[string]bool{}
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); !field.Anonymous {
+				names[strings.ToLower(fieldName(field))] = true
+			}
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("json")
+			// the fields of embedded structs are decoded even when their type is unexported
+			if (field.PkgPath != "" && !field.Anonymous) || tag == "-" {
+				continue
+			}
+			name, opts := tag, ""
+			if i := strings.Index(tag, ","); i >= 0 {
+				name, opts = tag[:i], tag[i:]
+			}
+			if field.Anonymous && name == "" {
+				if err := checkFields(field.Type, raw, at, names); err != nil {
+					return err
+				}
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if shadowed[strings.ToLower(name)] {
+				continue
+			}
+			v, found := lookupField(values, name)
+			if !found {
+				if strings.Contains(opts, ",omitempty") {
+					continue
+				}
+				return fmt.Errorf("missing field %s%s", strings.TrimPrefix(at+".", "."), name)
+			}
+			if err := checkFields(field.Type, v, at+"."+name, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldName returns the JSON name of field
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// lookupField returns the value of key in values, matching case insensitively like encoding/json
+func lookupField(values map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := values[key]; ok {
+		return v, true
+	}
+	for k, v := range values {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
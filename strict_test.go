@@ -0,0 +1,72 @@
+package tgo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	// checkpoint returns a checkpoint whose block has fields added and removed
+	checkpoint := func(added, removed string) rawBody {
+		block := `{"hash":"BLsaved","level":9,"proto":1,"predecessor":"BLparent","timestamp":"2019-01-01T00:00:00Z","validation_pass":4,"operations_hash":"LLo","fitness":["00"],"context":"CoV","priority":0,"signature":"sig","protocol":"Pt","chain_id":"NetX"` + added + `}`
+		if removed != "" {
+			block = strings.Replace(block, removed, "", 1)
+		}
+		return rawBody(`{"block":` + block + `,"save_point":9,"caboose":0,"history_mode":"full"}`)
+	}
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/checkpoint": checkpoint("", ""),
+	})
+	client.Strict = true
+	got, err := client.GetCheckpoint(context.Background(), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Block.Hash != "BLsaved" || got.HistoryMode != "full" {
+		t.Fatalf("unexpected checkpoint %+v", got)
+	}
+
+	// a field added by a protocol upgrade
+	node.route("GET /chains/main/checkpoint", checkpoint(`,"liquidity_baking_escape_vote":false`, ""))
+	if _, err := client.GetCheckpoint(context.Background(), "main"); err == nil || !strings.Contains(err.Error(), "liquidity_baking_escape_vote") {
+		t.Fatalf("expected an unknown field error got %v", err)
+	}
+	// a field removed by a protocol upgrade
+	node.route("GET /chains/main/checkpoint", checkpoint("", `,"priority":0`))
+	if _, err := client.GetCheckpoint(context.Background(), "main"); err == nil || !strings.Contains(err.Error(), "missing field block.priority") {
+		t.Fatalf("expected a missing field error got %v", err)
+	}
+
+	client.Strict = false
+	if _, err := client.GetCheckpoint(context.Background(), "main"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrictMetadata(t *testing.T) {
+	florence := `{"protocol":"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i","next_protocol":"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i","baker":"tz1baker",` +
+		`"level_info":{"level":1466368,"level_position":1466367,"cycle":357,"cycle_position":4095,"expected_commitment":true},` +
+		`"voting_period_info":{"voting_period":{"index":50,"kind":"proposal","start_position":1466367},"position":1,"remaining":20478}}`
+	node, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/metadata": rawBody(florence),
+	})
+	client.Strict = true
+	got, err := client.GetBlockMetadata(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Level.Level != 1466368 || got.Level.VotingPeriod != 50 {
+		t.Fatalf("unexpected level %+v", got.Level)
+	}
+
+	// metadata decoding itself is checked in the shape of its protocol
+	node.route("GET /chains/main/blocks/head/metadata", rawBody(strings.Replace(florence, `"baker":"tz1baker",`, "", 1)))
+	if _, err := client.GetBlockMetadata(context.Background(), "head"); err == nil || !strings.Contains(err.Error(), "missing field baker") {
+		t.Fatalf("expected a missing field error got %v", err)
+	}
+	node.route("GET /chains/main/blocks/head/metadata", rawBody(strings.Replace(florence, `"level_info":{"level":1466368,"level_position":1466367,"cycle":357,"cycle_position":4095,"expected_commitment":true},`, "", 1)))
+	if _, err := client.GetBlockMetadata(context.Background(), "head"); err == nil || !strings.Contains(err.Error(), "missing field level_info") {
+		t.Fatalf("expected a missing field error got %v", err)
+	}
+}
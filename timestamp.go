@@ -0,0 +1,56 @@
+package tgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a time returned by the node, decoded from an RFC3339 string or from unix
+// seconds given as a number or a string. It is encoded back as an RFC3339 string in UTC.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns t as a Timestamp
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t}
+}
+
+// UnmarshalJSON decodes an RFC3339 string or unix seconds, null leaves the time zero
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	s := string(b)
+	if b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		t.Time = time.Unix(seconds, 0).UTC()
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s", b)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON encodes the time as an RFC3339 string in UTC, null when the time is zero
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// String returns the time in RFC3339 in UTC as the node formats it
+func (t Timestamp) String() string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,44 @@
+package tgo_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestTimestamp(t *testing.T) {
+	expected := time.Date(2019, 9, 1, 12, 0, 0, 0, time.UTC)
+	for _, raw := range []string{`"2019-09-01T12:00:00Z"`, `"2019-09-01T14:00:00+02:00"`, `1567339200`, `"1567339200"`} {
+		var ts tgo.Timestamp
+		if err := json.Unmarshal([]byte(raw), &ts); err != nil {
+			t.Fatal(err)
+		}
+		if !ts.Equal(expected) {
+			t.Fatalf("expected %s from %s got %s", expected, raw, ts)
+		}
+		b, err := json.Marshal(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `"2019-09-01T12:00:00Z"` {
+			t.Fatalf("unexpected encoding %s", b)
+		}
+	}
+
+	header := tgo.BlockHeader{}
+	if err := json.Unmarshal([]byte(`{"hash":"BLa","timestamp":null}`), &header); err != nil || !header.Timestamp.IsZero() {
+		t.Fatalf("expected a zero timestamp got %s, %v", header.Timestamp, err)
+	}
+	if err := json.Unmarshal([]byte(`{"timestamp":"yesterday"}`), &header); err == nil {
+		t.Fatal("expected error for invalid timestamp")
+	}
+	rights := []tgo.BakingRight{}
+	if err := json.Unmarshal([]byte(`[{"level":1},{"level":2,"estimated_time":"2019-09-01T12:00:00Z"}]`), &rights); err != nil {
+		t.Fatal(err)
+	}
+	if rights[0].EstimatedTime != nil || !rights[1].EstimatedTime.Equal(expected) {
+		t.Fatalf("unexpected estimated times %+v", rights)
+	}
+}
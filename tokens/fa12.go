@@ -0,0 +1,111 @@
+package tokens
+
+import (
+	"context"
+	"math/big"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+// FA12 is a client for a token contract implementing FA1.2 (TZIP-7)
+type FA12 struct {
+	rpc      *tgo.RPC
+	Contract string
+	// Ledger locates balances in the contract storage. When nil, balances are read by
+	// running the getBalance view, which requires Callback.
+	Ledger *Ledger
+	// Callback is any contract taking a nat parameter, views send their result to it
+	Callback string
+}
+
+// Ledger describes the big map holding balances in the storage of a token contract
+type Ledger struct {
+	BigMapID int64
+	// KeyType is the type of the big map keys, by default address for FA1.2 and
+	// pair address nat for FA2
+	KeyType *micheline.Node
+	// Balance extracts the balance from a big map value, which must be a nat when unset
+	Balance func(value micheline.Node) (*big.Int, error)
+}
+
+// NewFA12 returns a client for the FA1.2 token at contract
+func NewFA12(rpc *tgo.RPC, contract string) *FA12 {
+	return &FA12{rpc: rpc, Contract: contract}
+}
+
+// GetBalance returns the balance of owner at blockID, from the ledger big map when Ledger is set
+// or through the getBalance view otherwise
+func (t *FA12) GetBalance(ctx context.Context, blockID tgo.BlockID, owner string) (*big.Int, error) {
+	if t.Ledger != nil {
+		keyType := micheline.NewPrim("address")
+		if t.Ledger.KeyType != nil {
+			keyType = *t.Ledger.KeyType
+		}
+		return t.Ledger.get(ctx, t.rpc, blockID, micheline.NewString(owner), keyType)
+	}
+	param := micheline.NewPrim("Pair", micheline.NewString(owner), micheline.NewString(t.Callback))
+	values, err := runView(ctx, t.rpc, blockID, t.Contract, "getBalance", param, t.Callback)
+	if err != nil {
+		return nil, err
+	}
+	return natValue(values[0])
+}
+
+// get reads the balance stored under key, missing keys hold no tokens
+func (l *Ledger) get(ctx context.Context, rpc *tgo.RPC, blockID tgo.BlockID, key, keyType micheline.Node) (*big.Int, error) {
+	hash, err := tgo.BigMapKeyHash(key, keyType)
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := rpc.GetBigMapValue(ctx, blockID, l.BigMapID, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return new(big.Int), nil
+	}
+	if l.Balance != nil {
+		return l.Balance(value)
+	}
+	return natValue(value)
+}
+
+// BuildTransfer returns the contents of a transfer of amount tokens from one address to another,
+// to be added to an OperationBuilder
+func (t *FA12) BuildTransfer(from, to string, amount *big.Int) tgo.OperationContents {
+	return callContents(t.Contract, "transfer", micheline.NewPrim("Pair",
+		micheline.NewString(from),
+		micheline.NewPrim("Pair", micheline.NewString(to), micheline.NewBigInt(amount)),
+	))
+}
+
+// BuildApprove returns the contents of a call allowing spender to transfer up to amount tokens
+// of the sender, to be added to an OperationBuilder
+func (t *FA12) BuildApprove(spender string, amount *big.Int) tgo.OperationContents {
+	return callContents(t.Contract, "approve", micheline.NewPrim("Pair", micheline.NewString(spender), micheline.NewBigInt(amount)))
+}
+
+// Transfers decodes the applied transfers of this token in op
+func (t *FA12) Transfers(op tgo.BlockOperation) []Transfer {
+	transfers := []Transfer{}
+	for _, c := range appliedCalls(op, t.Contract, "transfer") {
+		var param struct {
+			From   string   `micheline:"0"`
+			To     string   `micheline:"1/0"`
+			Amount *big.Int `micheline:"1/1"`
+		}
+		typ := micheline.NewPrim("pair", micheline.NewPrim("address"), micheline.NewPrim("pair", micheline.NewPrim("address"), micheline.NewPrim("nat")))
+		if err := tgo.UnmarshalMicheline(c.Parameters.Value, typ, &param); err != nil {
+			continue
+		}
+		transfers = append(transfers, Transfer{
+			Contract:      t.Contract,
+			OperationHash: op.Hash,
+			From:          param.From,
+			To:            param.To,
+			Amount:        param.Amount,
+		})
+	}
+	return transfers
+}
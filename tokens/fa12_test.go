@@ -0,0 +1,134 @@
+package tokens_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+	"github.com/postables/TGo/tokens"
+)
+
+const (
+	token    = "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi"
+	owner    = "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	callback = "KT1CallbackCallbackCallbackCa11back"
+)
+
+// newNode serves routes keyed by "METHOD /path" and records request bodies
+func newNode(t *testing.T, routes map[string]interface{}) (*tgo.RPC, map[string]string) {
+	bodies := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies[key] = string(body)
+		resp, ok := routes[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return tgo.GenerateClient(server.URL, time.Second*5), bodies
+}
+
+func parse(t *testing.T, src string) micheline.Node {
+	node, err := micheline.Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestFA12GetBalanceFromLedger(t *testing.T) {
+	hash, err := tgo.BigMapKeyHash(micheline.NewString(owner), micheline.NewPrim("address"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc, _ := newNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/big_maps/31/" + hash: parse(t, `Pair {} 1500`),
+	})
+	fa12 := tokens.NewFA12(rpc, token)
+	fa12.Ledger = &tokens.Ledger{BigMapID: 31, Balance: func(value micheline.Node) (*big.Int, error) {
+		return value.Args[1].Int, nil
+	}}
+	balance, err := fa12.GetBalance(context.Background(), "head", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Int64() != 1500 {
+		t.Fatalf("unexpected balance %s", balance)
+	}
+	balance, err = fa12.GetBalance(context.Background(), "head", "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx%unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Sign() != 0 {
+		t.Fatalf("expected no balance for a missing key, got %s", balance)
+	}
+}
+
+func TestFA12GetBalanceFromView(t *testing.T) {
+	rpc, bodies := newNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/" + token + "/script": map[string]interface{}{
+			"code":    parse(t, `parameter unit ; storage unit ; code { FAILWITH }`),
+			"storage": parse(t, `Unit`),
+		},
+		"GET /chains/main/chain_id": "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_code": map[string]interface{}{
+			"storage": parse(t, `Unit`),
+			"operations": []interface{}{map[string]interface{}{
+				"kind":        "transaction",
+				"destination": callback,
+				"amount":      "0",
+				"parameters":  map[string]interface{}{"entrypoint": "default", "value": parse(t, `42`)},
+			}},
+		},
+	})
+	fa12 := tokens.NewFA12(rpc, token)
+	if _, err := fa12.GetBalance(context.Background(), "head", owner); err == nil {
+		t.Fatal("expected error without callback")
+	}
+	fa12.Callback = callback
+	balance, err := fa12.GetBalance(context.Background(), "head", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Int64() != 42 {
+		t.Fatalf("unexpected balance %s", balance)
+	}
+	body := bodies["POST /chains/main/blocks/head/helpers/scripts/run_code"]
+	if !strings.Contains(body, `"entrypoint":"getBalance"`) || !strings.Contains(body, `"chain_id":"NetXdQprcVkpaWU"`) || !strings.Contains(body, `{"string":"`+callback+`"}`) {
+		t.Fatalf("unexpected run_code body %s", body)
+	}
+}
+
+func TestFA12BuildAndDecode(t *testing.T) {
+	fa12 := tokens.NewFA12(nil, token)
+	transfer := fa12.BuildTransfer(owner, "tz1other", big.NewInt(10))
+	if transfer.Destination != token || transfer.Parameters.Entrypoint != "transfer" || transfer.Parameters.Value.String() != `Pair "`+owner+`" (Pair "tz1other" 10)` {
+		t.Fatalf("unexpected transfer %+v", transfer)
+	}
+	approve := fa12.BuildApprove("tz1spender", big.NewInt(5))
+	if approve.Parameters.Entrypoint != "approve" || approve.Parameters.Value.String() != `Pair "tz1spender" 5` {
+		t.Fatalf("unexpected approve %+v", approve)
+	}
+
+	applied := tgo.AppliedContents{OperationContents: transfer}
+	applied.Metadata.OperationResult.Status = "applied"
+	failed := applied
+	failed.Metadata.OperationResult.Status = "backtracked"
+	op := tgo.BlockOperation{Hash: "ooHash", Contents: []tgo.AppliedContents{applied, failed}}
+	transfers := fa12.Transfers(op)
+	if len(transfers) != 1 || transfers[0].From != owner || transfers[0].To != "tz1other" || transfers[0].Amount.Int64() != 10 || transfers[0].OperationHash != "ooHash" {
+		t.Fatalf("unexpected transfers %+v", transfers)
+	}
+}
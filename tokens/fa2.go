@@ -0,0 +1,249 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+// FA2 is a client for a multi token contract implementing FA2 (TZIP-12)
+type FA2 struct {
+	rpc      *tgo.RPC
+	Contract string
+	// Ledger locates balances in the contract storage, its keys are pairs of owner and token id
+	// unless KeyType is address for single asset contracts. When nil, balances are read by
+	// running the balance_of view, which requires Callback.
+	Ledger *Ledger
+	// Callback is any contract taking the balance_of response, views send their result to it
+	Callback string
+}
+
+// NewFA2 returns a client for the FA2 contract at contract
+func NewFA2(rpc *tgo.RPC, contract string) *FA2 {
+	return &FA2{rpc: rpc, Contract: contract}
+}
+
+// BalanceRequest asks for the balance of an owner in a token
+type BalanceRequest struct {
+	Owner   string
+	TokenID int64
+}
+
+// Balance is the balance of an owner in a token
+type Balance struct {
+	Owner   string
+	TokenID int64
+	Balance *big.Int
+}
+
+// TransferBatch moves tokens out of From, FA2 transfers are lists of batches
+type TransferBatch struct {
+	From string
+	Txs  []TransferDestination
+}
+
+// TransferDestination is a single destination of a transfer batch
+type TransferDestination struct {
+	To      string
+	TokenID int64
+	Amount  *big.Int
+}
+
+// OperatorUpdate adds or removes Operator from the addresses allowed to transfer TokenID for Owner
+type OperatorUpdate struct {
+	Remove   bool
+	Owner    string
+	Operator string
+	TokenID  int64
+}
+
+// balanceResponse is the element type of the list sent by balance_of to its callback
+type balanceResponse struct {
+	Owner   string   `micheline:"0/0"`
+	TokenID int64    `micheline:"0/1"`
+	Balance *big.Int `micheline:"1"`
+}
+
+var balanceResponseType = micheline.NewPrim("pair",
+	micheline.NewPrim("pair", micheline.NewPrim("address"), micheline.NewPrim("nat")),
+	micheline.NewPrim("nat"),
+)
+
+// transferType is the parameter type of the transfer entrypoint
+var transferType = micheline.NewPrim("list", micheline.NewPrim("pair",
+	micheline.NewPrim("address").WithAnnots("%from_"),
+	micheline.NewPrim("list", micheline.NewPrim("pair",
+		micheline.NewPrim("address").WithAnnots("%to_"),
+		micheline.NewPrim("pair", micheline.NewPrim("nat").WithAnnots("%token_id"), micheline.NewPrim("nat").WithAnnots("%amount")),
+	)).WithAnnots("%txs"),
+))
+
+// transferParam mirrors transferType
+type transferParam []struct {
+	From string `micheline:"from_"`
+	Txs  []struct {
+		To      string   `micheline:"to_"`
+		TokenID int64    `micheline:"token_id"`
+		Amount  *big.Int `micheline:"amount"`
+	} `micheline:"txs"`
+}
+
+// BalanceOf returns the balances of the requests at blockID by running the balance_of view
+func (t *FA2) BalanceOf(ctx context.Context, blockID tgo.BlockID, requests ...BalanceRequest) ([]Balance, error) {
+	reqs := make([]micheline.Node, len(requests))
+	for i, r := range requests {
+		reqs[i] = micheline.NewPrim("Pair", micheline.NewString(r.Owner), micheline.NewInt(r.TokenID))
+	}
+	param := micheline.NewPrim("Pair", micheline.NewSeq(reqs...), micheline.NewString(t.Callback))
+	values, err := runView(ctx, t.rpc, blockID, t.Contract, "balance_of", param, t.Callback)
+	if err != nil {
+		return nil, err
+	}
+	responses := []balanceResponse{}
+	if err := tgo.UnmarshalMicheline(values[0], micheline.NewPrim("list", balanceResponseType), &responses); err != nil {
+		return nil, fmt.Errorf("invalid balance_of response: %w", err)
+	}
+	balances := make([]Balance, len(responses))
+	for i, r := range responses {
+		balances[i] = Balance{Owner: r.Owner, TokenID: r.TokenID, Balance: r.Balance}
+	}
+	return balances, nil
+}
+
+// GetBalance returns the balance of owner in tokenID at blockID, from the ledger big map when
+// Ledger is set or through the balance_of view otherwise
+func (t *FA2) GetBalance(ctx context.Context, blockID tgo.BlockID, owner string, tokenID int64) (*big.Int, error) {
+	if t.Ledger != nil {
+		key, keyType := t.ledgerKey(owner, tokenID)
+		return t.Ledger.get(ctx, t.rpc, blockID, key, keyType)
+	}
+	balances, err := t.BalanceOf(ctx, blockID, BalanceRequest{Owner: owner, TokenID: tokenID})
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) != 1 {
+		return nil, fmt.Errorf("expected one balance, got %d", len(balances))
+	}
+	return balances[0].Balance, nil
+}
+
+// ledgerKeyType returns the type of the ledger keys
+func (t *FA2) ledgerKeyType() micheline.Node {
+	if t.Ledger != nil && t.Ledger.KeyType != nil {
+		return *t.Ledger.KeyType
+	}
+	return micheline.NewPrim("pair", micheline.NewPrim("address"), micheline.NewPrim("nat"))
+}
+
+// ledgerKey returns the ledger key of owner for tokenID along with its type
+func (t *FA2) ledgerKey(owner string, tokenID int64) (micheline.Node, micheline.Node) {
+	keyType := t.ledgerKeyType()
+	if keyType.IsPrim("address") {
+		return micheline.NewString(owner), keyType
+	}
+	return micheline.NewPrim("Pair", micheline.NewString(owner), micheline.NewInt(tokenID)), keyType
+}
+
+// BuildTransfer returns the contents of a transfer call moving tokens in one or more batches,
+// to be added to an OperationBuilder
+func (t *FA2) BuildTransfer(batches ...TransferBatch) tgo.OperationContents {
+	items := make([]micheline.Node, len(batches))
+	for i, batch := range batches {
+		txs := make([]micheline.Node, len(batch.Txs))
+		for j, tx := range batch.Txs {
+			txs[j] = micheline.NewPrim("Pair",
+				micheline.NewString(tx.To),
+				micheline.NewPrim("Pair", micheline.NewInt(tx.TokenID), micheline.NewBigInt(tx.Amount)),
+			)
+		}
+		items[i] = micheline.NewPrim("Pair", micheline.NewString(batch.From), micheline.NewSeq(txs...))
+	}
+	return callContents(t.Contract, "transfer", micheline.NewSeq(items...))
+}
+
+// BuildUpdateOperators returns the contents of an update_operators call, to be added to an OperationBuilder
+func (t *FA2) BuildUpdateOperators(updates ...OperatorUpdate) tgo.OperationContents {
+	items := make([]micheline.Node, len(updates))
+	for i, u := range updates {
+		operator := micheline.NewPrim("Pair",
+			micheline.NewString(u.Owner),
+			micheline.NewPrim("Pair", micheline.NewString(u.Operator), micheline.NewInt(u.TokenID)),
+		)
+		if u.Remove {
+			items[i] = micheline.NewPrim("Right", operator)
+		} else {
+			items[i] = micheline.NewPrim("Left", operator)
+		}
+	}
+	return callContents(t.Contract, "update_operators", micheline.NewSeq(items...))
+}
+
+// Transfers decodes the applied transfers of this contract in op, one per destination
+func (t *FA2) Transfers(op tgo.BlockOperation) []Transfer {
+	transfers := []Transfer{}
+	for _, c := range appliedCalls(op, t.Contract, "transfer") {
+		param := transferParam{}
+		if err := tgo.UnmarshalMicheline(c.Parameters.Value, transferType, &param); err != nil {
+			continue
+		}
+		for _, batch := range param {
+			for _, tx := range batch.Txs {
+				transfers = append(transfers, Transfer{
+					Contract:      t.Contract,
+					OperationHash: op.Hash,
+					From:          batch.From,
+					To:            tx.To,
+					TokenID:       tx.TokenID,
+					Amount:        tx.Amount,
+				})
+			}
+		}
+	}
+	return transfers
+}
+
+// LedgerChanges returns the balances written to the ledger big map by the applied contents of op
+// and the operations they triggered, a removed key is reported as a zero balance. Ledger must be set.
+func (t *FA2) LedgerChanges(op tgo.BlockOperation) ([]Balance, error) {
+	if t.Ledger == nil {
+		return nil, fmt.Errorf("the ledger of %s is unknown", t.Contract)
+	}
+	keyType := t.ledgerKeyType()
+	changes := []Balance{}
+	for _, diff := range op.BigMapDiffs()[t.Ledger.BigMapID] {
+		if !diff.IsUpdate() || diff.Key == nil {
+			continue
+		}
+		change := Balance{Balance: new(big.Int)}
+		if keyType.IsPrim("address") {
+			if err := tgo.UnmarshalMicheline(*diff.Key, keyType, &change.Owner); err != nil {
+				return nil, err
+			}
+		} else {
+			var key struct {
+				Owner   string `micheline:"0"`
+				TokenID int64  `micheline:"1"`
+			}
+			if err := tgo.UnmarshalMicheline(*diff.Key, keyType, &key); err != nil {
+				return nil, err
+			}
+			change.Owner, change.TokenID = key.Owner, key.TokenID
+		}
+		if diff.Value != nil {
+			var err error
+			if t.Ledger.Balance != nil {
+				change.Balance, err = t.Ledger.Balance(*diff.Value)
+			} else {
+				change.Balance, err = natValue(*diff.Value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,124 @@
+package tokens_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+	"github.com/postables/TGo/tokens"
+)
+
+func TestFA2BalanceOf(t *testing.T) {
+	rpc, bodies := newNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/" + token + "/script": map[string]interface{}{
+			"code":    parse(t, `parameter unit ; storage unit ; code { FAILWITH }`),
+			"storage": parse(t, `Unit`),
+		},
+		"GET /chains/main/chain_id": "NetXdQprcVkpaWU",
+		"POST /chains/main/blocks/head/helpers/scripts/run_code": map[string]interface{}{
+			"storage": parse(t, `Unit`),
+			"operations": []interface{}{map[string]interface{}{
+				"kind":        "transaction",
+				"destination": callback,
+				"amount":      "0",
+				"parameters": map[string]interface{}{"entrypoint": "default", "value": parse(t,
+					`{ Pair (Pair 0x000002298c03ed7d454a101eb7022bc95f7e5f41ac78 0) 7 ; Pair (Pair "tz1other" 3) 0 }`)},
+			}},
+		},
+	})
+	fa2 := tokens.NewFA2(rpc, token)
+	fa2.Callback = callback
+	balances, err := fa2.BalanceOf(context.Background(), "head", tokens.BalanceRequest{Owner: owner}, tokens.BalanceRequest{Owner: "tz1other", TokenID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []tokens.Balance{{Owner: owner, Balance: big.NewInt(7)}, {Owner: "tz1other", TokenID: 3, Balance: big.NewInt(0)}}
+	if !reflect.DeepEqual(balances, want) {
+		t.Fatalf("unexpected balances %+v", balances)
+	}
+	body := bodies["POST /chains/main/blocks/head/helpers/scripts/run_code"]
+	if !strings.Contains(body, `"entrypoint":"balance_of"`) {
+		t.Fatalf("unexpected run_code body %s", body)
+	}
+}
+
+func TestFA2GetBalanceFromLedger(t *testing.T) {
+	key := micheline.NewPrim("Pair", micheline.NewString(owner), micheline.NewInt(2))
+	hash, err := tgo.BigMapKeyHash(key, parse(t, `pair address nat`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc, _ := newNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/big_maps/8/" + hash: parse(t, `99`),
+	})
+	fa2 := tokens.NewFA2(rpc, token)
+	fa2.Ledger = &tokens.Ledger{BigMapID: 8}
+	balance, err := fa2.GetBalance(context.Background(), "head", owner, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Int64() != 99 {
+		t.Fatalf("unexpected balance %s", balance)
+	}
+}
+
+func TestFA2BuildAndDecode(t *testing.T) {
+	fa2 := tokens.NewFA2(nil, token)
+	transfer := fa2.BuildTransfer(tokens.TransferBatch{From: owner, Txs: []tokens.TransferDestination{
+		{To: "tz1a", TokenID: 1, Amount: big.NewInt(10)},
+		{To: "tz1b", TokenID: 2, Amount: big.NewInt(20)},
+	}})
+	want := `{ Pair "` + owner + `" { Pair "tz1a" (Pair 1 10) ; Pair "tz1b" (Pair 2 20) } }`
+	if transfer.Parameters.Entrypoint != "transfer" || transfer.Parameters.Value.String() != want {
+		t.Fatalf("unexpected transfer %s", transfer.Parameters.Value)
+	}
+	update := fa2.BuildUpdateOperators(
+		tokens.OperatorUpdate{Owner: owner, Operator: "KT1op", TokenID: 1},
+		tokens.OperatorUpdate{Remove: true, Owner: owner, Operator: "KT1op", TokenID: 2},
+	)
+	want = `{ Left (Pair "` + owner + `" (Pair "KT1op" 1)) ; Right (Pair "` + owner + `" (Pair "KT1op" 2)) }`
+	if update.Parameters.Entrypoint != "update_operators" || update.Parameters.Value.String() != want {
+		t.Fatalf("unexpected update %s", update.Parameters.Value)
+	}
+
+	applied := tgo.AppliedContents{}
+	receipt := `{"kind":"transaction","destination":"` + token + `","amount":"0",
+		"parameters":{"entrypoint":"transfer","value":` + mustJSON(t, transfer.Parameters.Value) + `},
+		"metadata":{"operation_result":{"status":"applied","big_map_diff":[
+			{"action":"update","big_map":"8","key_hash":"expr1","key":{"prim":"Pair","args":[{"bytes":"000002298c03ed7d454a101eb7022bc95f7e5f41ac78"},{"int":"1"}]},"value":{"int":"5"}},
+			{"big_map":"8","key_hash":"expr2","key":{"prim":"Pair","args":[{"string":"tz1a"},{"int":"1"}]}},
+			{"action":"update","big_map":"9","key_hash":"expr3","key":{"int":"1"},"value":{"int":"1"}}]}}}`
+	if err := json.Unmarshal([]byte(receipt), &applied); err != nil {
+		t.Fatal(err)
+	}
+	op := tgo.BlockOperation{Hash: "ooHash", Contents: []tgo.AppliedContents{applied}}
+	transfers := fa2.Transfers(op)
+	if len(transfers) != 2 || transfers[1].To != "tz1b" || transfers[1].TokenID != 2 || transfers[1].Amount.Int64() != 20 || transfers[0].From != owner {
+		t.Fatalf("unexpected transfers %+v", transfers)
+	}
+	if _, err := fa2.LedgerChanges(op); err == nil {
+		t.Fatal("expected error without ledger")
+	}
+	fa2.Ledger = &tokens.Ledger{BigMapID: 8}
+	changes, err := fa2.LedgerChanges(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChanges := []tokens.Balance{{Owner: owner, TokenID: 1, Balance: big.NewInt(5)}, {Owner: "tz1a", TokenID: 1, Balance: big.NewInt(0)}}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,87 @@
+// Package tokens reads balances and builds calls for token contracts following the FA1.2 and FA2 standards
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+// Transfer is a movement of tokens decoded from an operation, Amount is in the token's smallest unit
+type Transfer struct {
+	Contract      string
+	OperationHash tgo.OperationHash
+	From          string
+	To            string
+	// TokenID is always 0 for FA1.2 tokens
+	TokenID int64
+	Amount  *big.Int
+}
+
+// callContents returns the contents of a call to entrypoint of contract without tez
+func callContents(contract, entrypoint string, value micheline.Node) tgo.OperationContents {
+	return tgo.OperationContents{
+		Kind:        "transaction",
+		Destination: contract,
+		Amount:      "0",
+		Parameters:  &tgo.Parameters{Entrypoint: entrypoint, Value: value},
+	}
+}
+
+// runView runs a view entrypoint following the callback convention of the token standards:
+// the contract code is run on its current storage and the values it would send to callback
+// are returned, the internal operations are never applied
+func runView(ctx context.Context, rpc *tgo.RPC, blockID tgo.BlockID, contract, entrypoint string, param micheline.Node, callback string) ([]micheline.Node, error) {
+	if callback == "" {
+		return nil, fmt.Errorf("a callback contract is required to call %s", entrypoint)
+	}
+	script, err := rpc.GetScript(ctx, blockID, contract)
+	if err != nil {
+		return nil, err
+	}
+	result, err := rpc.RunCode(ctx, blockID, tgo.RunCodeInput{
+		Script:     script.Code,
+		Storage:    script.Storage,
+		Input:      param,
+		Entrypoint: entrypoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := []micheline.Node{}
+	for _, op := range result.Operations {
+		if op.Kind == "transaction" && op.Destination == callback && op.Parameters != nil {
+			values = append(values, op.Parameters.Value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s of %s sent nothing to %s", entrypoint, contract, callback)
+	}
+	return values, nil
+}
+
+// natValue returns the value of a nat node
+func natValue(n micheline.Node) (*big.Int, error) {
+	if n.Type != micheline.IntNode || n.Int == nil || n.Int.Sign() < 0 {
+		return nil, fmt.Errorf("expected a nat, got %s", n)
+	}
+	return new(big.Int).Set(n.Int), nil
+}
+
+// appliedCalls returns the applied calls to entrypoint of contract found in op
+func appliedCalls(op tgo.BlockOperation, contract, entrypoint string) []tgo.AppliedContents {
+	calls := []tgo.AppliedContents{}
+	for _, c := range op.Contents {
+		if c.Kind != "transaction" || c.Destination != contract || c.Parameters == nil {
+			continue
+		}
+		if c.Parameters.Entrypoint != entrypoint || c.Metadata.OperationResult.Status != "applied" {
+			continue
+		}
+		calls = append(calls, c)
+	}
+	return calls
+}
@@ -0,0 +1,137 @@
+package tgo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetNetworkSelf calls GET /network/self, the peer id of the node
+func (rpc *RPC) GetNetworkSelf(ctx context.Context) (PeerID, error) {
+	var self PeerID
+	err := rpc.get(ctx, "/network/self", &self)
+	return self, err
+}
+
+// Topology is the p2p neighborhood of a node, the peers it knows of and its connections to them
+type Topology struct {
+	Self  PeerID         `json:"self"`
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// TopologyNode is the node itself or a peer known to it
+type TopologyNode struct {
+	ID PeerID `json:"id"`
+	// State is running, accepted or disconnected, empty for the node itself
+	State   string `json:"state,omitempty"`
+	Trusted bool   `json:"trusted,omitempty"`
+	Score   int64  `json:"score"`
+	// Points are the known points the peer was reached at
+	Points []Point `json:"points,omitempty"`
+}
+
+// TopologyEdge is a connection between the node and a peer, going from the side which opened it
+type TopologyEdge struct {
+	From  PeerID `json:"from"`
+	To    PeerID `json:"to"`
+	Point Point  `json:"point"`
+	// Inflow and Outflow are the bytes per second currently received from and sent to the
+	// peer by the node
+	Inflow  int64 `json:"inflow"`
+	Outflow int64 `json:"outflow"`
+}
+
+// GetTopology combines the connections, peers and points of the node into its topology
+func (rpc *RPC) GetTopology(ctx context.Context) (Topology, error) {
+	self, err := rpc.GetNetworkSelf(ctx)
+	if err != nil {
+		return Topology{}, err
+	}
+	connections := []ConnectionsResponse{}
+	if err := rpc.get(ctx, "/network/connections", &connections); err != nil {
+		return Topology{}, err
+	}
+	peers, err := rpc.GetPeers(ctx)
+	if err != nil {
+		return Topology{}, err
+	}
+	points, err := rpc.GetPoints(ctx)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	topology := Topology{Self: self, Nodes: []TopologyNode{{ID: self}}, Edges: []TopologyEdge{}}
+	reachedAt := map[PeerID][]Point{}
+	for _, p := range points {
+		if p.P2PPeerID != "" {
+			reachedAt[p.P2PPeerID] = append(reachedAt[p.P2PPeerID], p.Point)
+		}
+	}
+	stats := map[PeerID]KnownPeer{}
+	for _, p := range peers {
+		stats[p.ID] = p
+		topology.Nodes = append(topology.Nodes, TopologyNode{ID: p.ID, State: p.State, Trusted: p.Trusted, Score: int64(p.Score), Points: reachedAt[p.ID]})
+	}
+	for _, c := range connections {
+		edge := TopologyEdge{From: self, To: c.PeerID, Point: c.IDPoint}
+		if c.Incoming {
+			edge.From, edge.To = c.PeerID, self
+		}
+		if p, ok := stats[c.PeerID]; ok {
+			edge.Inflow, edge.Outflow = int64(p.Stat.CurrentInflow), int64(p.Stat.CurrentOutflow)
+		}
+		topology.Edges = append(topology.Edges, edge)
+	}
+	sort.Slice(topology.Nodes[1:], func(i, j int) bool { return topology.Nodes[i+1].ID < topology.Nodes[j+1].ID })
+	sort.Slice(topology.Edges, func(i, j int) bool {
+		return topology.Edges[i].From+topology.Edges[i].To < topology.Edges[j].From+topology.Edges[j].To
+	})
+	return topology, nil
+}
+
+// WriteDOT renders the topology in the DOT language of Graphviz, the node filled, trusted peers
+// in bold and disconnected peers dashed, the connections labelled with their bandwidth
+func (t Topology) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph topology {")
+	for _, n := range t.Nodes {
+		label := []string{string(n.ID)}
+		for _, p := range n.Points {
+			label = append(label, p.String())
+		}
+		var style []string
+		switch {
+		case n.ID == t.Self:
+			style = append(style, "filled")
+		case n.State == "disconnected":
+			style = append(style, "dashed")
+		}
+		if n.Trusted {
+			style = append(style, "bold")
+		}
+		fmt.Fprintf(b, "\t%s [label=%s", strconv.Quote(string(n.ID)), strconv.Quote(strings.Join(label, "\n")))
+		if len(style) > 0 {
+			fmt.Fprintf(b, " style=%s", strconv.Quote(strings.Join(style, ",")))
+		}
+		fmt.Fprintln(b, "];")
+	}
+	for _, e := range t.Edges {
+		label := fmt.Sprintf("%s\nin %d B/s, out %d B/s", e.Point, e.Inflow, e.Outflow)
+		fmt.Fprintf(b, "\t%s -> %s [label=%s];\n", strconv.Quote(string(e.From)), strconv.Quote(string(e.To)), strconv.Quote(label))
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// WriteJSON writes the topology to w as indented JSON
+func (t Topology) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
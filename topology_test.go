@@ -0,0 +1,62 @@
+package tgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestTopology(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /network/self": rawBody(`"idtSelf"`),
+		"GET /network/connections": rawBody(`[
+			{"incoming":true,"peer_id":"idtA","id_point":{"addr":"1.2.3.4","port":9732}},
+			{"incoming":false,"peer_id":"idtB","id_point":{"addr":"5.6.7.8","port":9732}}
+		]`),
+		"GET /network/peers": rawBody(`[
+			["idtB",{"score":2,"trusted":true,"state":"running","stat":{"current_inflow":512,"current_outflow":"256"}}],
+			["idtA",{"score":1,"state":"running"}],
+			["idtC",{"state":"disconnected"}]
+		]`),
+		"GET /network/points": rawBody(`[["5.6.7.8:9732",{"trusted":true,"state":{"event_kind":"running","p2p_peer_id":"idtB"},"p2p_peer_id":"idtB"}]]`),
+	})
+	topology, err := client.GetTopology(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topology.Nodes) != 4 || topology.Nodes[0].ID != "idtSelf" || topology.Nodes[2].ID != "idtB" ||
+		len(topology.Nodes[2].Points) != 1 || topology.Nodes[2].Points[0].String() != "5.6.7.8:9732" {
+		t.Fatalf("unexpected nodes %+v", topology.Nodes)
+	}
+	// incoming connections go from the peer to the node
+	if len(topology.Edges) != 2 || topology.Edges[0].From != "idtA" || topology.Edges[0].To != "idtSelf" ||
+		topology.Edges[1].From != "idtSelf" || topology.Edges[1].Inflow != 512 || topology.Edges[1].Outflow != 256 {
+		t.Fatalf("unexpected edges %+v", topology.Edges)
+	}
+
+	dot := &bytes.Buffer{}
+	if err := topology.WriteDOT(dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`"idtB" [label="idtB\n5.6.7.8:9732" style="bold"];`,
+		`"idtC" [label="idtC" style="dashed"];`,
+		`"idtSelf" -> "idtB" [label="5.6.7.8:9732\nin 512 B/s, out 256 B/s"];`,
+	} {
+		if !strings.Contains(dot.String(), line) {
+			t.Fatalf("expected %s in\n%s", line, dot)
+		}
+	}
+	encoded := &bytes.Buffer{}
+	if err := topology.WriteJSON(encoded); err != nil {
+		t.Fatal(err)
+	}
+	decoded := tgo.Topology{}
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil || len(decoded.Edges) != 2 || decoded.Self != "idtSelf" {
+		t.Fatalf("unexpected decoded topology %+v: %v", decoded, err)
+	}
+}
@@ -0,0 +1,93 @@
+package tgo
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// default transport settings, sized for services making many requests to a single node
+const (
+	defaultMaxIdleConnsPerHost = 64
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+)
+
+// TransportOptions tunes the connections kept open to the node, zero values use defaults
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept for reuse, 64 by default
+	// instead of the 2 of net/http, which forces busy clients to open a connection per request
+	// and exhaust ephemeral ports
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost bounds the connections open at once, unbounded when 0
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept, 90 seconds by default
+	IdleConnTimeout time.Duration
+	// KeepAlive is the period of TCP keep-alive probes, 30 seconds by default
+	KeepAlive time.Duration
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+	// UnixSocket is the path of a unix socket every connection is made to instead of the
+	// host of the request URL, for nodes whose RPC is only served locally
+	UnixSocket string
+	// Proxy routes requests through an HTTP, HTTPS or SOCKS5 proxy, e.g. socks5://127.0.0.1:9050
+	// for Tor, instead of the proxy set by the environment
+	Proxy *url.URL
+}
+
+// NewTransport returns an HTTP transport configured with opts
+func NewTransport(opts TransportOptions) *http.Transport {
+	if opts.MaxIdleConnsPerHost <= 0 {
+		opts.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout <= 0 {
+		opts.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+	dialer := &net.Dialer{Timeout: defaultDialTimeout, KeepAlive: opts.KeepAlive}
+	dial := dialer.DialContext
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != nil {
+		proxy = http.ProxyURL(opts.Proxy)
+	}
+	if opts.UnixSocket != "" {
+		dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", opts.UnixSocket)
+		}
+		proxy = nil
+	}
+	return &http.Transport{
+		Proxy:               proxy,
+		DialContext:         dial,
+		MaxIdleConns:        opts.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     opts.MaxConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+		DisableKeepAlives:   opts.DisableKeepAlives,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// SetTransport replaces the HTTP client of rpc by one using a transport configured with opts,
+// keeping the timeout of the current client. The transport is owned by rpc, see Close.
+func (rpc *RPC) SetTransport(opts TransportOptions) {
+	var timeout time.Duration
+	if rpc.Client != nil {
+		timeout = rpc.Client.Timeout
+	}
+	rpc.transport = NewTransport(opts)
+	rpc.Client = &http.Client{Transport: rpc.transport, Timeout: timeout}
+}
+
+// GenerateUnixSocketClient returns a client for the node serving its RPC on the unix socket
+// at socketPath
+func GenerateUnixSocketClient(socketPath string, timeout time.Duration) *RPC {
+	rpc := GenerateClient("http://localhost", timeout)
+	rpc.SetTransport(TransportOptions{UnixSocket: socketPath})
+	return rpc
+}
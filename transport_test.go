@@ -0,0 +1,119 @@
+package tgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestSetTransport(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"level": 7})
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+	client := tgo.GenerateClient(server.URL, time.Second*5)
+	client.SetTransport(tgo.TransportOptions{MaxIdleConnsPerHost: 8})
+	if client.Client.Timeout != time.Second*5 {
+		t.Fatalf("expected the timeout to be kept got %s", client.Client.Timeout)
+	}
+	transport := client.Client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 8 || transport.IdleConnTimeout != 90*time.Second {
+		t.Fatalf("unexpected transport %+v", transport)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetBlockHeader(context.Background(), "head"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 1 {
+		t.Fatalf("expected the connection to be reused got %d connections", connections)
+	}
+}
+
+func TestUnixSocketClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "rpc.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chains/main/blocks/head/header" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"level": 7})
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := tgo.GenerateUnixSocketClient(socket, time.Second*5)
+	header, err := client.GetBlockHeader(context.Background(), "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Level != 7 {
+		t.Fatalf("unexpected header %+v", header)
+	}
+}
+
+func TestTransportProxy(t *testing.T) {
+	var mu sync.Mutex
+	proxied := []string{}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// requests to a proxy carry the absolute URL of the node
+		mu.Lock()
+		proxied = append(proxied, r.URL.String())
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"level": 7})
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := tgo.GenerateClient("http://node.invalid:8732", time.Second*5)
+	client.SetTransport(tgo.TransportOptions{Proxy: proxyURL})
+	if _, err := client.GetBlockHeader(context.Background(), "head"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(proxied) != 1 || proxied[0] != "http://node.invalid:8732/chains/main/blocks/head/header" {
+		t.Fatalf("unexpected proxied requests %v", proxied)
+	}
+
+	socks, _ := url.Parse("socks5://127.0.0.1:9050")
+	transport := tgo.NewTransport(tgo.TransportOptions{Proxy: socks})
+	req, _ := http.NewRequest(http.MethodGet, "http://node.invalid:8732", nil)
+	if u, err := transport.Proxy(req); err != nil || u.String() != "socks5://127.0.0.1:9050" {
+		t.Fatalf("unexpected proxy %v, %v", u, err)
+	}
+}
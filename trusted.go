@@ -0,0 +1,107 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// TrustedPeers is a snapshot of the peers and points trusted by a node
+type TrustedPeers struct {
+	Peers  []PeerID `json:"peers"`
+	Points []Point  `json:"points"`
+}
+
+// GetTrustedPeers returns the peers and points the node trusts
+func (rpc *RPC) GetTrustedPeers(ctx context.Context) (TrustedPeers, error) {
+	peers, err := rpc.GetPeers(ctx)
+	if err != nil {
+		return TrustedPeers{}, err
+	}
+	points, err := rpc.GetPoints(ctx)
+	if err != nil {
+		return TrustedPeers{}, err
+	}
+	trusted := TrustedPeers{Peers: []PeerID{}, Points: []Point{}}
+	for _, p := range peers {
+		if p.Trusted {
+			trusted.Peers = append(trusted.Peers, p.ID)
+		}
+	}
+	for _, p := range points {
+		if p.Trusted {
+			trusted.Points = append(trusted.Points, p.Point)
+		}
+	}
+	return trusted, nil
+}
+
+// ExportTrustedPeers writes the peers and points the node trusts to w as JSON, to be applied
+// to other nodes with ImportTrustedPeers
+func (rpc *RPC) ExportTrustedPeers(ctx context.Context, w io.Writer) error {
+	trusted, err := rpc.GetTrustedPeers(ctx)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trusted)
+}
+
+// PeerResult is the outcome of changing the ACL of a peer
+type PeerResult struct {
+	PeerID PeerID
+	Err    error
+}
+
+// TrustedPeersImport holds the outcome of trusting every peer and point of a snapshot
+type TrustedPeersImport struct {
+	Peers  []PeerResult
+	Points []PointResult
+}
+
+// Failed reports whether a peer or a point could not be trusted
+func (i TrustedPeersImport) Failed() bool {
+	for _, p := range i.Peers {
+		if p.Err != nil {
+			return true
+		}
+	}
+	for _, p := range i.Points {
+		if !p.Trusted {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportTrustedPeers reads a snapshot written by ExportTrustedPeers from r and trusts its peers
+// and points on the node, connecting to the points as AddTrustedPoints does. Peers unknown to
+// the node cannot be trusted yet and fail with ErrPeerNotFound. An error is only returned when
+// the snapshot cannot be read.
+func (rpc *RPC) ImportTrustedPeers(ctx context.Context, r io.Reader, timeout time.Duration) (TrustedPeersImport, error) {
+	trusted := TrustedPeers{}
+	if err := json.NewDecoder(r).Decode(&trusted); err != nil {
+		return TrustedPeersImport{}, err
+	}
+	var result TrustedPeersImport
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result.Points = rpc.AddTrustedPoints(ctx, trusted.Points, timeout)
+	}()
+	result.Peers = make([]PeerResult, len(trusted.Peers))
+	for i, peer := range trusted.Peers {
+		result.Peers[i] = PeerResult{PeerID: peer}
+		if err := ctx.Err(); err != nil {
+			result.Peers[i].Err = err
+			continue
+		}
+		result.Peers[i].Err = rpc.setPeerACL(ctx, peer, ACLTrust)
+	}
+	wg.Wait()
+	return result, nil
+}
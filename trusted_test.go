@@ -0,0 +1,52 @@
+package tgo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestTrustedPeers(t *testing.T) {
+	_, source := newFakeNode(t, map[string]interface{}{
+		"GET /network/peers": rawBody(`[["idtAZ3",{"score":3,"trusted":true,"state":"running"}],["idtB",{"trusted":false,"state":"running"}]]`),
+		"GET /network/points": rawBody(`[
+			["1.2.3.4:9732",{"trusted":true,"state":{"event_kind":"running","p2p_peer_id":"idtAZ3"},
+				"last_established_connection":["idtAZ3","2019-09-01T12:00:00Z"]}],
+			["[::ffff:5.6.7.8]:9732",{"trusted":false,"state":{"event_kind":"disconnected"}}]
+		]`),
+	})
+	snapshot := &bytes.Buffer{}
+	if err := source.ExportTrustedPeers(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	target, client := newFakeNode(t, map[string]interface{}{
+		"PATCH /network/peers/idtAZ3":        rawBody(`{}`),
+		"PUT /network/points/1.2.3.4:9732":   rawBody(`{}`),
+		"PATCH /network/points/1.2.3.4:9732": rawBody(`{}`),
+	})
+	result, err := client.ImportTrustedPeers(context.Background(), snapshot, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Peers) != 1 || result.Peers[0].PeerID != "idtAZ3" || result.Peers[0].Err != nil ||
+		len(result.Points) != 1 || result.Points[0].Point != (tgo.Point{Addr: "1.2.3.4", Port: 9732}) || !result.Points[0].Trusted || result.Failed() {
+		t.Fatalf("unexpected import %+v", result)
+	}
+	target.mu.Lock()
+	body := target.bodies["PATCH /network/peers/idtAZ3"]
+	target.mu.Unlock()
+	if len(body) != 1 || body[0] != `{"acl":"trust"}` {
+		t.Fatalf("unexpected bodies %v", body)
+	}
+
+	// peers unknown to the node fail
+	result, err = client.ImportTrustedPeers(context.Background(), bytes.NewBufferString(`{"peers":["idtMissing"],"points":[]}`), time.Second)
+	if err != nil || !result.Failed() || !errors.Is(result.Peers[0].Err, tgo.ErrPeerNotFound) {
+		t.Fatalf("unexpected import %+v: %v", result, err)
+	}
+}
@@ -0,0 +1,160 @@
+package tgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// ValidateData checks that data is a well formed value of the Michelson type typ, the way the
+// node would before running a contract. Addresses, keys and signatures may be given in
+// readable or optimized form. Lambdas are only checked to be sequences.
+func ValidateData(data, typ micheline.Node) error {
+	if typ.Type != micheline.PrimNode {
+		return fmt.Errorf("invalid michelson type %s", typ)
+	}
+	expect := func(ok bool) error {
+		if !ok {
+			return fmt.Errorf("invalid %s value %s", typ.Prim, data)
+		}
+		return nil
+	}
+	switch typ.Prim {
+	case "unit":
+		return expect(data.IsPrim("Unit") && len(data.Args) == 0)
+	case "bool":
+		return expect((data.IsPrim("True") || data.IsPrim("False")) && len(data.Args) == 0)
+	case "int":
+		return expect(data.Type == micheline.IntNode && data.Int != nil)
+	case "nat":
+		return expect(data.Type == micheline.IntNode && data.Int != nil && data.Int.Sign() >= 0)
+	case "mutez":
+		return expect(data.Type == micheline.IntNode && data.Int != nil && data.Int.Sign() >= 0 && data.Int.IsInt64())
+	case "string":
+		return expect(data.Type == micheline.StringNode)
+	case "bytes":
+		return expect(data.Type == micheline.BytesNode)
+	case "timestamp":
+		if data.Type == micheline.StringNode {
+			_, err := time.Parse(time.RFC3339, data.Str)
+			return expect(err == nil)
+		}
+		return expect(data.Type == micheline.IntNode && data.Int != nil)
+	case "address", "contract":
+		return validateBase58(data, typ, encodeAddress, decodeAddress)
+	case "key_hash":
+		return validateBase58(data, typ, encodeKeyHash, decodeKeyHash)
+	case "key":
+		return validateBase58(data, typ, encodePublicKey, decodePublicKey)
+	case "signature":
+		return validateBase58(data, typ, encodeSignature, func(b []byte) (string, error) {
+			if len(b) != signatureSize {
+				return "", fmt.Errorf("invalid signature %x", b)
+			}
+			return "", nil
+		})
+	case "chain_id":
+		return validateBase58(data, typ, func(s string) ([]byte, error) { return b58CheckDecode(s, prefixChainID) }, func(b []byte) (string, error) {
+			if len(b) != 4 {
+				return "", fmt.Errorf("invalid chain id %x", b)
+			}
+			return "", nil
+		})
+	case "option":
+		if len(typ.Args) != 1 {
+			return fmt.Errorf("invalid michelson type %s", typ)
+		}
+		if data.IsPrim("None") && len(data.Args) == 0 {
+			return nil
+		}
+		if data.IsPrim("Some") && len(data.Args) == 1 {
+			return ValidateData(data.Args[0], typ.Args[0])
+		}
+		return expect(false)
+	case "or":
+		if len(typ.Args) != 2 {
+			return fmt.Errorf("invalid michelson type %s", typ)
+		}
+		switch {
+		case data.IsPrim("Left") && len(data.Args) == 1:
+			return ValidateData(data.Args[0], typ.Args[0])
+		case data.IsPrim("Right") && len(data.Args) == 1:
+			return ValidateData(data.Args[0], typ.Args[1])
+		}
+		return expect(false)
+	case "pair":
+		typ = binaryPair(typ, "pair")
+		if len(typ.Args) != 2 {
+			return fmt.Errorf("invalid michelson type %s", typ)
+		}
+		if data.Type == micheline.SeqNode && len(data.Args) >= 2 {
+			data = micheline.NewPrim("Pair", data.Args...)
+		}
+		data = binaryPair(data, "Pair")
+		if !data.IsPrim("Pair") || len(data.Args) != 2 {
+			return expect(false)
+		}
+		if err := ValidateData(data.Args[0], typ.Args[0]); err != nil {
+			return err
+		}
+		return ValidateData(data.Args[1], typ.Args[1])
+	case "list", "set":
+		if len(typ.Args) != 1 {
+			return fmt.Errorf("invalid michelson type %s", typ)
+		}
+		if data.Type != micheline.SeqNode {
+			return expect(false)
+		}
+		for _, elem := range data.Args {
+			if err := ValidateData(elem, typ.Args[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "map", "big_map":
+		if len(typ.Args) != 2 {
+			return fmt.Errorf("invalid michelson type %s", typ)
+		}
+		if typ.Prim == "big_map" && data.Type == micheline.IntNode {
+			return nil
+		}
+		if data.Type != micheline.SeqNode {
+			return expect(false)
+		}
+		for _, elt := range data.Args {
+			if !elt.IsPrim("Elt") || len(elt.Args) != 2 {
+				return fmt.Errorf("invalid %s element %s", typ.Prim, elt)
+			}
+			if err := ValidateData(elt.Args[0], typ.Args[0]); err != nil {
+				return err
+			}
+			if err := ValidateData(elt.Args[1], typ.Args[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "lambda":
+		return expect(data.Type == micheline.SeqNode)
+	case "operation":
+		return fmt.Errorf("operation values cannot be given as data")
+	}
+	return nil
+}
+
+// validateBase58 checks a value given either as a base58 string or in its binary form
+func validateBase58(data, typ micheline.Node, encode func(string) ([]byte, error), decode func([]byte) (string, error)) error {
+	var err error
+	switch data.Type {
+	case micheline.StringNode:
+		_, err = encode(data.Str)
+	case micheline.BytesNode:
+		_, err = decode(data.Bytes)
+	default:
+		err = fmt.Errorf("expected a string or bytes")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s value %s: %w", typ.Prim, data, err)
+	}
+	return nil
+}
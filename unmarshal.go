@@ -0,0 +1,359 @@
+package tgo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/postables/TGo/micheline"
+)
+
+// MichelineUnmarshaler is implemented by types that decode Michelson values themselves
+type MichelineUnmarshaler interface {
+	UnmarshalMicheline(data, typ micheline.Node) error
+}
+
+var (
+	nodeType   = reflect.TypeOf(micheline.Node{})
+	bigIntType = reflect.TypeOf(big.Int{})
+	timeType   = reflect.TypeOf(time.Time{})
+	pairPath   = regexp.MustCompile(`^[01](/[01])*$`)
+)
+
+// UnmarshalMicheline stores data of Michelson type typ, such as contract storage or call
+// parameters, in the value pointed to by v.
+//
+// Struct fields are matched against the field annotations found in the pair tree of typ.
+// The `micheline` tag names the annotation, without its leading %, or gives a path of
+// pair positions such as "1/0" for the car of the cdr, combs being read as nested pairs.
+// Untagged fields match annotations case insensitively and are skipped when none match,
+// fields tagged "-" are ignored.
+//
+// Options decode to pointers, nil for None. Lists and sets decode to slices, maps to Go
+// maps, ints to integer types, *big.Int or time.Time for timestamps, and addresses, keys,
+// signatures and chain ids to their base58 strings whether the node returned them in
+// readable or optimized form. A micheline.Node target receives the raw data.
+func UnmarshalMicheline(data, typ micheline.Node, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("unmarshal target must be a non nil pointer")
+	}
+	return unmarshalValue(data, typ, rv.Elem())
+}
+
+func unmarshalValue(data, typ micheline.Node, v reflect.Value) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(MichelineUnmarshaler); ok {
+			return u.UnmarshalMicheline(data, typ)
+		}
+	}
+	if v.Type() == nodeType {
+		v.Set(reflect.ValueOf(data))
+		return nil
+	}
+	if typ.IsPrim("option") && len(typ.Args) == 1 {
+		switch {
+		case data.IsPrim("None"):
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		case data.IsPrim("Some") && len(data.Args) == 1:
+			data, typ = data.Args[0], typ.Args[0]
+		default:
+			return fmt.Errorf("expected Some or None for type %s, got %s", typ, data)
+		}
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(data, typ, v.Elem())
+	}
+	switch v.Type() {
+	case bigIntType:
+		if data.Type != micheline.IntNode || data.Int == nil {
+			return fmt.Errorf("expected an int, got %s", data)
+		}
+		v.Set(reflect.ValueOf(*new(big.Int).Set(data.Int)))
+		return nil
+	case timeType:
+		t, err := timestampValue(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return unmarshalStruct(data, typ, v)
+	case reflect.String:
+		s, err := readableString(data, typ)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		switch {
+		case data.IsPrim("True"):
+			v.SetBool(true)
+		case data.IsPrim("False"):
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("expected a bool, got %s", data)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := intValue(data, typ)
+		if err != nil {
+			return err
+		}
+		if !i.IsInt64() || v.OverflowInt(i.Int64()) {
+			return fmt.Errorf("%s overflows %s", i, v.Type())
+		}
+		v.SetInt(i.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := intValue(data, typ)
+		if err != nil {
+			return err
+		}
+		if i.Sign() < 0 || !i.IsUint64() || v.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("%s overflows %s", i, v.Type())
+		}
+		v.SetUint(i.Uint64())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if data.Type != micheline.BytesNode {
+				return fmt.Errorf("expected bytes, got %s", data)
+			}
+			v.SetBytes(append([]byte{}, data.Bytes...))
+			return nil
+		}
+		if data.Type != micheline.SeqNode {
+			return fmt.Errorf("expected a sequence, got %s", data)
+		}
+		elemType := micheline.Node{}
+		if len(typ.Args) == 1 {
+			elemType = typ.Args[0]
+		}
+		slice := reflect.MakeSlice(v.Type(), len(data.Args), len(data.Args))
+		for i, elem := range data.Args {
+			if err := unmarshalValue(elem, elemType, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		if data.Type == micheline.IntNode {
+			return fmt.Errorf("big map %s must be read by id", data)
+		}
+		if data.Type != micheline.SeqNode {
+			return fmt.Errorf("expected a map, got %s", data)
+		}
+		keyType, valueType := micheline.Node{}, micheline.Node{}
+		if len(typ.Args) == 2 {
+			keyType, valueType = typ.Args[0], typ.Args[1]
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(data.Args))
+		for _, elt := range data.Args {
+			if !elt.IsPrim("Elt") || len(elt.Args) != 2 {
+				return fmt.Errorf("expected Elt in map, got %s", elt)
+			}
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := unmarshalValue(elt.Args[0], keyType, key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := unmarshalValue(elt.Args[1], valueType, value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("cannot unmarshal into %s", v.Type())
+		}
+		v.Set(reflect.ValueOf(data))
+	default:
+		return fmt.Errorf("cannot unmarshal into %s", v.Type())
+	}
+	return nil
+}
+
+// unmarshalStruct fills the fields of a struct from the annotated pair tree
+func unmarshalStruct(data, typ micheline.Node, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("micheline")
+		if tag == "-" {
+			continue
+		}
+		var path []int
+		switch {
+		case pairPath.MatchString(tag):
+			for _, p := range strings.Split(tag, "/") {
+				n, _ := strconv.Atoi(p)
+				path = append(path, n)
+			}
+		case tag != "":
+			var ok bool
+			if path, ok = findField(typ, func(annot string) bool { return annot == tag }); !ok {
+				return fmt.Errorf("no field annotated %%%s in %s", tag, typ)
+			}
+		default:
+			var ok bool
+			if path, ok = findField(typ, func(annot string) bool { return strings.EqualFold(annot, field.Name) }); !ok {
+				continue
+			}
+		}
+		fieldData, fieldType, err := followPair(data, typ, path)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if err := unmarshalValue(fieldData, fieldType, v.Field(i)); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// findField returns the path through nested pairs to the first type whose field annotation matches
+func findField(typ micheline.Node, match func(string) bool) ([]int, bool) {
+	typ = binaryPair(typ, "pair")
+	if !typ.IsPrim("pair") {
+		return nil, false
+	}
+	for i, arg := range typ.Args {
+		if annot := arg.FieldAnnot(); annot != "" && match(annot) {
+			return []int{i}, true
+		}
+	}
+	for i, arg := range typ.Args {
+		if path, ok := findField(arg, match); ok {
+			return append([]int{i}, path...), true
+		}
+	}
+	return nil, false
+}
+
+// followPair walks data and its type along a path of pair positions
+func followPair(data, typ micheline.Node, path []int) (micheline.Node, micheline.Node, error) {
+	for _, i := range path {
+		typ = binaryPair(typ, "pair")
+		if data.Type == micheline.SeqNode {
+			data = micheline.NewPrim("Pair", data.Args...)
+		}
+		data = binaryPair(data, "Pair")
+		if !data.IsPrim("Pair") || len(data.Args) != 2 {
+			return data, typ, fmt.Errorf("expected a pair, got %s", data)
+		}
+		data = data.Args[i]
+		if len(typ.Args) == 2 {
+			typ = typ.Args[i]
+		} else {
+			typ = micheline.Node{}
+		}
+	}
+	return data, typ, nil
+}
+
+// binaryPair rewrites a comb of more than two elements as nested pairs
+func binaryPair(n micheline.Node, prim string) micheline.Node {
+	if !n.IsPrim(prim) || len(n.Args) <= 2 {
+		return n
+	}
+	return micheline.NewPrim(prim, n.Args[0], micheline.NewPrim(prim, n.Args[1:]...)).WithAnnots(n.Annots...)
+}
+
+// intValue returns the integer held by data, timestamps given as strings are read as seconds
+func intValue(data, typ micheline.Node) (*big.Int, error) {
+	if data.Type == micheline.IntNode && data.Int != nil {
+		return data.Int, nil
+	}
+	if data.Type == micheline.StringNode && typ.IsPrim("timestamp") {
+		t, err := timestampValue(data)
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(t.Unix()), nil
+	}
+	return nil, fmt.Errorf("expected an int, got %s", data)
+}
+
+// timestampValue reads a timestamp given in seconds or as an RFC3339 string
+func timestampValue(data micheline.Node) (time.Time, error) {
+	switch data.Type {
+	case micheline.IntNode:
+		if data.Int != nil && data.Int.IsInt64() {
+			return time.Unix(data.Int.Int64(), 0).UTC(), nil
+		}
+	case micheline.StringNode:
+		t, err := time.Parse(time.RFC3339, data.Str)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", data.Str, err)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expected a timestamp, got %s", data)
+}
+
+// readableString returns a string value, converting optimized values of typ to base58
+func readableString(data, typ micheline.Node) (string, error) {
+	switch data.Type {
+	case micheline.StringNode:
+		return data.Str, nil
+	case micheline.IntNode:
+		if typ.IsPrim("timestamp") {
+			t, err := timestampValue(data)
+			if err != nil {
+				return "", err
+			}
+			return t.Format(time.RFC3339), nil
+		}
+	case micheline.BytesNode:
+		switch typ.Prim {
+		case "address", "contract":
+			return decodeAddress(data.Bytes)
+		case "key_hash":
+			return decodeKeyHash(data.Bytes)
+		case "key":
+			return decodePublicKey(data.Bytes)
+		case "signature":
+			if len(data.Bytes) == signatureSize {
+				return b58CheckEncode(prefixSig, data.Bytes), nil
+			}
+		case "chain_id":
+			if len(data.Bytes) == 4 {
+				return b58CheckEncode(prefixChainID, data.Bytes), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("expected a string, got %s", data)
+}
+
+// Section returns the argument of a top level section of the code, such as its storage type
+func (s *Script) Section(name string) (micheline.Node, bool) {
+	for _, section := range s.Code.Args {
+		if section.IsPrim(name) && len(section.Args) == 1 {
+			return section.Args[0], true
+		}
+	}
+	return micheline.Node{}, false
+}
+
+// UnmarshalStorage decodes the storage of the script into v, see UnmarshalMicheline
+func (s *Script) UnmarshalStorage(v interface{}) error {
+	typ, ok := s.Section("storage")
+	if !ok {
+		return errors.New("script has no storage section")
+	}
+	return UnmarshalMicheline(s.Storage, typ, v)
+}
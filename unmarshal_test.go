@@ -0,0 +1,109 @@
+package tgo_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+	"github.com/postables/TGo/micheline"
+)
+
+type auctionStorage struct {
+	Owner    string    `micheline:"owner"`
+	Ends     time.Time `micheline:"ends"`
+	Bid      *big.Int  `micheline:"highest_bid"`
+	Bidder   *string   `micheline:"bidder"`
+	Paused   bool
+	Bids     map[string]uint64 `micheline:"bids"`
+	Tags     []string          `micheline:"1/1/1/1/1/1/0"`
+	Metadata int64             `micheline:"metadata"`
+	Ignored  string            `micheline:"-"`
+}
+
+func TestUnmarshalMicheline(t *testing.T) {
+	typ, err := micheline.Parse(`pair (address %owner)
+		(pair (timestamp %ends) (mutez %highest_bid) (option %bidder address) (bool %paused)
+		      (map %bids address mutez) (list string) (big_map %metadata string bytes))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := micheline.Parse(`Pair 0x000002298c03ed7d454a101eb7022bc95f7e5f41ac78
+		(Pair 60 1000000000000000000000 (Some "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi") True
+		      { Elt "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" 15 } { "a" ; "b" } 42)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := auctionStorage{Ignored: "kept"}
+	if err := tgo.UnmarshalMicheline(data, typ, &storage); err != nil {
+		t.Fatal(err)
+	}
+	if storage.Owner != "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" || !storage.Ends.Equal(time.Unix(60, 0)) {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+	if storage.Bid.String() != "1000000000000000000000" || storage.Bidder == nil || *storage.Bidder != "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi" {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+	if !storage.Paused || storage.Bids["tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"] != 15 || len(storage.Tags) != 2 || storage.Tags[1] != "b" {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+	if storage.Metadata != 42 || storage.Ignored != "kept" {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+
+	none, _ := micheline.Parse(`Pair "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" "1970-01-01T00:01:00Z" 0 None False {} {} 0`)
+	storage = auctionStorage{}
+	if err := tgo.UnmarshalMicheline(none, typ, &storage); err != nil {
+		t.Fatal(err)
+	}
+	if storage.Bidder != nil || !storage.Ends.Equal(time.Unix(60, 0)) {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+
+	var wrong struct {
+		Owner int64 `micheline:"owner"`
+	}
+	if err := tgo.UnmarshalMicheline(data, typ, &wrong); err == nil {
+		t.Fatal("expected error for an address in an int")
+	}
+	var missing struct {
+		Total int64 `micheline:"total"`
+	}
+	if err := tgo.UnmarshalMicheline(data, typ, &missing); err == nil {
+		t.Fatal("expected error for a missing annotation")
+	}
+}
+
+func TestScriptUnmarshalStorage(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/contracts/KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi/script": map[string]interface{}{
+			"code": []interface{}{
+				map[string]interface{}{"prim": "parameter", "args": []interface{}{map[string]interface{}{"prim": "unit"}}},
+				map[string]interface{}{"prim": "storage", "args": []interface{}{map[string]interface{}{"prim": "pair", "args": []interface{}{
+					map[string]interface{}{"prim": "nat", "annots": []string{"%counter"}},
+					map[string]interface{}{"prim": "key_hash", "annots": []string{"%admin"}},
+				}}}},
+				map[string]interface{}{"prim": "code", "args": []interface{}{[]interface{}{}}},
+			},
+			"storage": map[string]interface{}{"prim": "Pair", "args": []interface{}{
+				map[string]interface{}{"int": "7"},
+				map[string]interface{}{"bytes": "0002298c03ed7d454a101eb7022bc95f7e5f41ac78"},
+			}},
+		},
+	})
+	script, err := client.GetScript(context.Background(), "head", "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var storage struct {
+		Counter uint
+		Admin   string
+	}
+	if err := script.UnmarshalStorage(&storage); err != nil {
+		t.Fatal(err)
+	}
+	if storage.Counter != 7 || storage.Admin != "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx" {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+}
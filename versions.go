@@ -0,0 +1,148 @@
+package tgo
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ProtocolVersion numbers the protocols in the order they were activated on mainnet, the
+// shape of responses depends on the version of the protocol of the block they describe
+type ProtocolVersion int
+
+// Versions of the mainnet protocols
+const (
+	ProtoGenesis ProtocolVersion = iota
+	ProtoAlpha1
+	ProtoAlpha2
+	ProtoAlpha3
+	ProtoAthens
+	ProtoBabylon
+	ProtoCarthage
+	ProtoDelphi
+	ProtoEdo
+	ProtoFlorence
+	ProtoGranada
+	ProtoHangzhou
+	ProtoIthaca
+)
+
+// protocolVersions maps the hashes of the mainnet protocols to their version, protocols
+// amended by a second proposal appear twice
+var protocolVersions = map[ProtocolHash]ProtocolVersion{
+	"PrihK96nBAFSxVL1GLJTVhu9YnzkMFiBeuJRPA8NwuZVZCE1L6i": ProtoGenesis,
+	"Ps9mPmXaRzmzk35gbAYNCAw6UXdE2qoABTHbN2oEEc1qM7CwT9P": ProtoGenesis,
+	"PtCJ7pwoxe8JasnHY8YonnLYjcVHmhiARPJvqcC6VfHT5s8k8sY": ProtoAlpha1,
+	"PsYLVpVvgbLhAhoqAkMFUo6gudkJ9weNXhUYCiLDzcUpFpkk8Wt": ProtoAlpha2,
+	"PsddFKi32cMJ2qPjf43Qv5GDWLDPZb3T3bF6fLKiF5HtvHNU7aP": ProtoAlpha3,
+	"Pt24m4xiPbLDhVgVfABUjirbmda3yohdN82Sp9FeuAXJ4eV9otd": ProtoAthens,
+	"PsBABY5HQTSkA4297zNHfsZNKtxULfL18y95qb3m53QJiXGmrbU": ProtoBabylon,
+	"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS": ProtoBabylon,
+	"PsCARTHAGazKbHtnKfLzQg3kms52kSRpgnDY982a9oYsSXRLQEb": ProtoCarthage,
+	"PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo": ProtoDelphi,
+	"PtEdoTezd3RHSC31mpxxo1npxFjoWWcFgQtxapi51Z8TLu6v6Uq": ProtoEdo,
+	"PtEdo2ZkT9oKpimTah6x2embF25oss54njMuPzkJTEi5RqfdZFA": ProtoEdo,
+	"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i": ProtoFlorence,
+	"PtGRANADsDU8R9daYKAgWnQYAJ64omN1o3KMGVCykShA97vQbvV": ProtoGranada,
+	"PtHangzHogokSuiMHemCuowEavgYTP8J5qQ9fQS793MHYFpCY3r": ProtoHangzhou,
+	"Psithaca2MLRFYargivpo7YvUr7wUDqyxrdhC5CQq78mRvimz6A": ProtoIthaca,
+}
+
+// Version returns the version of the protocol. Protocols missing from the mainnet list, such
+// as test protocols or the empty hash, are assumed to be the most recent.
+func (h ProtocolHash) Version() ProtocolVersion {
+	if v, ok := protocolVersions[h]; ok {
+		return v
+	}
+	return ProtoIthaca
+}
+
+// blockMetadata decodes the fields of block metadata shared by all protocols, without the
+// methods of BlockMetadata
+type blockMetadata BlockMetadata
+
+// levelInfo is the level of a block as described from Florence on, the voting period is
+// given apart in votingPeriodInfo
+type levelInfo struct {
+	Level              int64 `json:"level"`
+	LevelPosition      int64 `json:"level_position"`
+	Cycle              int64 `json:"cycle"`
+	CyclePosition      int64 `json:"cycle_position"`
+	ExpectedCommitment bool  `json:"expected_commitment"`
+}
+
+// votingPeriodInfo locates a block within its voting period from Florence on
+type votingPeriodInfo struct {
+	VotingPeriod struct {
+		Index         int64  `json:"index"`
+		Kind          string `json:"kind"`
+		StartPosition int64  `json:"start_position"`
+	} `json:"voting_period"`
+	Position  int64 `json:"position"`
+	Remaining int64 `json:"remaining"`
+}
+
+// metadataFlorence is the block metadata of Florence and later protocols, where level was
+// replaced by level_info and voting_period_info
+type metadataFlorence struct {
+	blockMetadata
+	// Level shadows the level of blockMetadata so it is left out
+	Level            *BlockLevel       `json:"level,omitempty"`
+	LevelInfo        *levelInfo        `json:"level_info"`
+	VotingPeriodInfo *votingPeriodInfo `json:"voting_period_info"`
+}
+
+// UnmarshalJSON decodes block metadata in the shape used by its protocol
+func (m *BlockMetadata) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*blockMetadata)(m)); err != nil {
+		return err
+	}
+	if m.Protocol.Version() < ProtoFlorence {
+		return nil
+	}
+	florence := metadataFlorence{}
+	if err := json.Unmarshal(data, &florence); err != nil {
+		return err
+	}
+	// metadata without protocol cannot be told apart, the level is kept if given the old way
+	if info := florence.LevelInfo; info != nil {
+		m.Level = BlockLevel{
+			Level:              info.Level,
+			LevelPosition:      info.LevelPosition,
+			Cycle:              info.Cycle,
+			CyclePosition:      info.CyclePosition,
+			ExpectedCommitment: info.ExpectedCommitment,
+		}
+	}
+	if period := florence.VotingPeriodInfo; period != nil {
+		m.Level.VotingPeriod = period.VotingPeriod.Index
+		m.Level.VotingPeriodPosition = period.Position
+	}
+	return nil
+}
+
+// strictShape returns the shape of the block metadata raw in strict mode, which depends on its
+// protocol as in UnmarshalJSON
+func (m *BlockMetadata) strictShape(raw interface{}) reflect.Type {
+	values, _ := raw.(map[string]interface{})
+	protocol, _ := values["protocol"].(string)
+	if ProtocolHash(protocol).Version() < ProtoFlorence {
+		return reflect.TypeOf(blockMetadata{})
+	}
+	return reflect.TypeOf(metadataFlorence{})
+}
+
+// MarshalJSON encodes block metadata in the shape used by its protocol
+func (m BlockMetadata) MarshalJSON() ([]byte, error) {
+	if m.Protocol.Version() < ProtoFlorence {
+		return json.Marshal(blockMetadata(m))
+	}
+	florence := metadataFlorence{blockMetadata: blockMetadata(m), LevelInfo: &levelInfo{
+		Level:              m.Level.Level,
+		LevelPosition:      m.Level.LevelPosition,
+		Cycle:              m.Level.Cycle,
+		CyclePosition:      m.Level.CyclePosition,
+		ExpectedCommitment: m.Level.ExpectedCommitment,
+	}, VotingPeriodInfo: &votingPeriodInfo{Position: m.Level.VotingPeriodPosition}}
+	florence.VotingPeriodInfo.VotingPeriod.Index = m.Level.VotingPeriod
+	return json.Marshal(florence)
+}
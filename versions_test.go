@@ -0,0 +1,55 @@
+package tgo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestProtocolVersion(t *testing.T) {
+	for hash, expected := range map[tgo.ProtocolHash]tgo.ProtocolVersion{
+		"PsBABY5HQTSkA4297zNHfsZNKtxULfL18y95qb3m53QJiXGmrbU": tgo.ProtoBabylon,
+		"PsBabyM1eUXZseaJdmXFApDSBqj8YBfwELoxZHHW77EMcAbbwAS": tgo.ProtoBabylon,
+		"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i": tgo.ProtoFlorence,
+		"PrihK96nBAFSxVL1GLJTVhu9YnzkMFiBeuJRPA8NwuZVZCE1L6i": tgo.ProtoGenesis,
+		"ProtoALphaALphaALphaALphaALphaALphaALphaALphaDdp3zK": tgo.ProtoIthaca,
+	} {
+		if hash.Version() != expected {
+			t.Errorf("%s: expected version %d got %d", hash, expected, hash.Version())
+		}
+	}
+}
+
+func TestBlockMetadataVersions(t *testing.T) {
+	expected := tgo.BlockLevel{Level: 1466368, LevelPosition: 1466367, Cycle: 358, CyclePosition: 0, VotingPeriod: 49, VotingPeriodPosition: 0, ExpectedCommitment: false}
+	for protocol, raw := range map[string]string{
+		"PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo": `{"protocol":"PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo","baker":"tz1a",
+			"level":{"level":1466368,"level_position":1466367,"cycle":358,"cycle_position":0,"voting_period":49,"voting_period_position":0,"expected_commitment":false}}`,
+		"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i": `{"protocol":"PsFLorenaUUuikDWvMDr6fGBRG8kt3e3D3fHoXK1j1BFRxeSH4i","baker":"tz1a",
+			"level_info":{"level":1466368,"level_position":1466367,"cycle":358,"cycle_position":0,"expected_commitment":false},
+			"voting_period_info":{"voting_period":{"index":49,"kind":"proposal","start_position":1466367},"position":0,"remaining":20479}}`,
+	} {
+		metadata := tgo.BlockMetadata{}
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Level != expected || metadata.Baker != "tz1a" {
+			t.Fatalf("%s: unexpected metadata %+v", protocol, metadata)
+		}
+		// metadata is encoded back in the shape of its protocol
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		florence := tgo.ProtocolHash(protocol).Version() >= tgo.ProtoFlorence
+		if strings.Contains(string(b), `"level_info"`) != florence || strings.Contains(string(b), `"level":{`) == florence {
+			t.Fatalf("%s: unexpected encoding %s", protocol, b)
+		}
+		decoded := tgo.BlockMetadata{}
+		if err := json.Unmarshal(b, &decoded); err != nil || decoded.Level != expected {
+			t.Fatalf("%s: unexpected round trip %+v: %v", protocol, decoded, err)
+		}
+	}
+}
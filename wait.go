@@ -0,0 +1,91 @@
+package tgo
+
+import (
+	"context"
+	"time"
+)
+
+// waitLookback is how many blocks below the head WaitForOperation searches when it starts
+const waitLookback = 10
+
+// OperationInclusion describes the block an operation was included in
+type OperationInclusion struct {
+	BlockHash     BlockHash
+	Level         int64
+	Confirmations int64
+	Operation     BlockOperation
+}
+
+// WaitForOperation polls the head of the chain until the operation opHash has been included
+// and confirmed by the given number of blocks, counting its own block. Blocks replaced by a
+// reorganisation are rescanned so the returned inclusion is always on the canonical chain.
+func (rpc *RPC) WaitForOperation(ctx context.Context, opHash OperationHash, confirmations int64) (OperationInclusion, error) {
+	canonical := map[int64]BlockHash{}
+	var inclusion *OperationInclusion
+	floor := int64(-1)
+	for {
+		head, err := rpc.GetBlockHeader(ctx, "head")
+		if err != nil {
+			return OperationInclusion{}, err
+		}
+		if floor < 0 {
+			floor = head.Level - waitLookback
+		}
+		if canonical[head.Level] != head.Hash {
+			for level := range canonical {
+				if level > head.Level {
+					delete(canonical, level)
+				}
+			}
+			if inclusion != nil && inclusion.Level > head.Level {
+				inclusion = nil
+			}
+			// walk back from the new head until reaching a block already known to be canonical
+			block := head
+			for {
+				canonical[block.Level] = block.Hash
+				if inclusion != nil && inclusion.Level == block.Level && inclusion.BlockHash != block.Hash {
+					inclusion = nil
+				}
+				op, found, err := rpc.findOperation(ctx, block.Hash, opHash)
+				if err != nil {
+					return OperationInclusion{}, err
+				}
+				if found {
+					inclusion = &OperationInclusion{BlockHash: block.Hash, Level: block.Level, Operation: op}
+				}
+				if block.Level-1 <= floor || canonical[block.Level-1] == block.Predecessor {
+					break
+				}
+				if block, err = rpc.GetBlockHeader(ctx, block.Predecessor.ID()); err != nil {
+					return OperationInclusion{}, err
+				}
+			}
+		}
+		if inclusion != nil && head.Level-inclusion.Level+1 >= confirmations {
+			inclusion.Confirmations = head.Level - inclusion.Level + 1
+			return *inclusion, nil
+		}
+		select {
+		case <-ctx.Done():
+			return OperationInclusion{}, ctx.Err()
+		case <-time.After(rpc.PollInterval):
+		}
+	}
+}
+
+// findOperation searches the operations of a block for opHash
+func (rpc *RPC) findOperation(ctx context.Context, blockHash BlockHash, opHash OperationHash) (BlockOperation, bool, error) {
+	passes, err := rpc.GetBlockOperations(ctx, blockHash.ID())
+	if err != nil {
+		return BlockOperation{}, false, err
+	}
+	for _, ops := range passes {
+		for _, op := range ops {
+			if op.Hash == opHash {
+				return op, true, nil
+			}
+		}
+	}
+	return BlockOperation{}, false, nil
+}
@@ -0,0 +1,62 @@
+package tgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestWaitForOperation(t *testing.T) {
+	routes := map[string]interface{}{}
+	addBlock := func(hash, predecessor string, level int64, opHashes ...string) tgo.BlockHeader {
+		header := tgo.BlockHeader{Hash: tgo.BlockHash(hash), Predecessor: tgo.BlockHash(predecessor), Level: level}
+		routes["GET /chains/main/blocks/"+hash+"/header"] = header
+		ops := []tgo.BlockOperation{}
+		for _, h := range opHashes {
+			ops = append(ops, tgo.BlockOperation{Hash: tgo.OperationHash(h)})
+		}
+		routes["GET /chains/main/blocks/"+hash+"/operations"] = [][]tgo.BlockOperation{{}, {}, {}, ops}
+		return header
+	}
+	for level := int64(90); level <= 100; level++ {
+		addBlock(fmt.Sprintf("C%d", level), fmt.Sprintf("C%d", level-1), level)
+	}
+	// the operation is first included in A101, then A101 and A102 are replaced by a fork including it in B102
+	heads := []tgo.BlockHeader{
+		routes["GET /chains/main/blocks/C100/header"].(tgo.BlockHeader),
+		addBlock("A101", "C100", 101, "ooHash"),
+		addBlock("A102", "A101", 102),
+		addBlock("B103", "B102", 103),
+		addBlock("B104", "B103", 104),
+	}
+	addBlock("B101", "C100", 101)
+	addBlock("B102", "B101", 102, "ooOther", "ooHash")
+	polls := 0
+	routes["GET /chains/main/blocks/head/header"] = func([]byte) interface{} {
+		head := heads[polls]
+		if polls < len(heads)-1 {
+			polls++
+		}
+		return head
+	}
+	_, client := newFakeNode(t, routes)
+	client.PollInterval = time.Millisecond
+
+	inclusion, err := client.WaitForOperation(context.Background(), "ooHash", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inclusion.BlockHash != "B102" || inclusion.Level != 102 || inclusion.Confirmations != 3 {
+		t.Fatalf("unexpected inclusion %+v", inclusion)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if _, err := client.WaitForOperation(ctx, "ooMissing", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+}
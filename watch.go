@@ -0,0 +1,79 @@
+package tgo
+
+import (
+	"context"
+)
+
+// AddressOperation is the content of an operation of a block involving a watched address
+type AddressOperation struct {
+	// Address is the watched address, the source, destination or delegate of the content or of
+	// an operation it emitted
+	Address   string          `json:"address"`
+	Operation OperationHash   `json:"operation"`
+	Block     BlockHash       `json:"block"`
+	Level     int64           `json:"level"`
+	Contents  AppliedContents `json:"contents"`
+}
+
+// WatchOperations follows the heads of the chain and emits the contents of the operations of
+// every new head involving one of addresses until ctx is cancelled. A content involving several
+// watched addresses is emitted once for each. Heads are not confirmed, the operations of a block
+// later replaced by a reorganisation are not withdrawn. Both channels are closed once watching
+// stops. errs receives the failures to read a block and the error that stopped the head stream
+// if any, as described in the package documentation.
+func (rpc *RPC) WatchOperations(ctx context.Context, addresses ...string) (<-chan AddressOperation, <-chan error) {
+	watched := make(map[string]bool, len(addresses))
+	for _, a := range addresses {
+		watched[a] = true
+	}
+	found := make(chan AddressOperation)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(found)
+		defer close(errs)
+		heads, headErrs := rpc.MonitorHeads(ctx, rpc.ChainAlias(ctx))
+		for head := range heads {
+			passes, err := rpc.GetBlockOperations(ctx, head.Hash.ID())
+			if err != nil {
+				reportError(errs, err)
+				continue
+			}
+			for _, ops := range passes {
+				for _, op := range ops {
+					for _, c := range op.Contents {
+						for _, address := range involved(c, watched) {
+							select {
+							case found <- AddressOperation{Address: address, Operation: op.Hash, Block: head.Hash, Level: head.Level, Contents: c}:
+							case <-ctx.Done():
+								return
+							}
+						}
+					}
+				}
+			}
+		}
+		if err := <-headErrs; err != nil {
+			errs <- err
+		}
+	}()
+	return found, errs
+}
+
+// involved returns the watched addresses involved in c or in the operations it emitted
+func involved(c AppliedContents, watched map[string]bool) []string {
+	var addresses []string
+	seen := map[string]bool{}
+	add := func(candidates ...string) {
+		for _, a := range candidates {
+			if watched[a] && !seen[a] {
+				seen[a] = true
+				addresses = append(addresses, a)
+			}
+		}
+	}
+	add(c.Source, c.Destination, c.Delegate)
+	for _, internal := range c.Metadata.InternalOperationResults {
+		add(internal.Source, internal.Destination, internal.Delegate)
+	}
+	return addresses
+}
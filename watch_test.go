@@ -0,0 +1,30 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchOperations(t *testing.T) {
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /monitor/heads/main": rawBody(`{"hash":"BLh","level":7}`),
+		"GET /chains/main/blocks/BLh/operations": rawBody(`[[],[],[],[
+			{"hash":"opA","contents":[{"kind":"transaction","source":"tz1a","destination":"tz1b","amount":"1"}]},
+			{"hash":"opB","contents":[{"kind":"transaction","source":"tz1c","destination":"KT1d","amount":"0",
+				"metadata":{"operation_result":{"status":"applied"},"internal_operation_results":[
+					{"kind":"transaction","source":"KT1d","destination":"tz1a","amount":"5","nonce":0,"result":{"status":"applied"}}]}}]},
+			{"hash":"opC","contents":[{"kind":"delegation","source":"tz1c","delegate":"tz1e"}]}]]`),
+	})
+	client.PollInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	found, _ := client.WatchOperations(ctx, "tz1a", "tz1b", "tz1e")
+	expected := []struct{ address, operation string }{{"tz1a", "opA"}, {"tz1b", "opA"}, {"tz1a", "opB"}, {"tz1e", "opC"}}
+	for _, e := range expected {
+		op := <-found
+		if op.Address != e.address || string(op.Operation) != e.operation || op.Block != "BLh" || op.Level != 7 {
+			t.Fatalf("expected %s of %s, got %+v", e.operation, e.address, op)
+		}
+	}
+}
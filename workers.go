@@ -0,0 +1,77 @@
+package tgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// WorkerStatus is the lifecycle phase of a node worker
+type WorkerStatus struct {
+	Phase string `json:"phase"`
+	Birth string `json:"birth,omitempty"`
+	Since string `json:"since,omitempty"`
+}
+
+// WorkerRequest is a request queued or being processed by a worker
+type WorkerRequest struct {
+	Pushed    string          `json:"pushed"`
+	Treated   string          `json:"treated,omitempty"`
+	Completed string          `json:"completed,omitempty"`
+	Request   json.RawMessage `json:"request"`
+}
+
+// WorkerEvents is a group of recent worker events sharing a log level
+type WorkerEvents struct {
+	Level  string            `json:"level"`
+	Events []json.RawMessage `json:"events"`
+}
+
+// WorkerState holds the detailed state of a worker, its pending requests and recent events
+type WorkerState struct {
+	Status          WorkerStatus    `json:"status"`
+	PendingRequests []WorkerRequest `json:"pending_requests"`
+	Backlog         []WorkerEvents  `json:"backlog"`
+	CurrentRequest  *WorkerRequest  `json:"current_request,omitempty"`
+}
+
+// WorkerSummary is an entry of the per chain worker listings
+type WorkerSummary struct {
+	ChainID ChainID      `json:"chain_id"`
+	Status  WorkerStatus `json:"status"`
+}
+
+// GetBlockValidatorWorker calls GET /workers/block_validator
+func (rpc *RPC) GetBlockValidatorWorker(ctx context.Context) (WorkerState, error) {
+	state := WorkerState{}
+	err := rpc.get(ctx, "/workers/block_validator", &state)
+	return state, err
+}
+
+// GetChainValidatorWorkers calls GET /workers/chain_validators
+func (rpc *RPC) GetChainValidatorWorkers(ctx context.Context) ([]WorkerSummary, error) {
+	workers := []WorkerSummary{}
+	err := rpc.get(ctx, "/workers/chain_validators", &workers)
+	return workers, err
+}
+
+// GetChainValidatorWorker calls GET /workers/chain_validators/<chain_id>
+func (rpc *RPC) GetChainValidatorWorker(ctx context.Context, chainID ChainID) (WorkerState, error) {
+	state := WorkerState{}
+	err := rpc.get(ctx, fmt.Sprintf("/workers/chain_validators/%s", chainID), &state)
+	return state, err
+}
+
+// GetPrevalidatorWorkers calls GET /workers/prevalidators
+func (rpc *RPC) GetPrevalidatorWorkers(ctx context.Context) ([]WorkerSummary, error) {
+	workers := []WorkerSummary{}
+	err := rpc.get(ctx, "/workers/prevalidators", &workers)
+	return workers, err
+}
+
+// GetPrevalidatorWorker calls GET /workers/prevalidators/<chain_id>
+func (rpc *RPC) GetPrevalidatorWorker(ctx context.Context, chainID ChainID) (WorkerState, error) {
+	state := WorkerState{}
+	err := rpc.get(ctx, fmt.Sprintf("/workers/prevalidators/%s", chainID), &state)
+	return state, err
+}
@@ -0,0 +1,46 @@
+package tgo_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWorkers(t *testing.T) {
+	state := rawBody(`{"status":{"phase":"running","since":"2019-08-01T00:00:00Z"},"pending_requests":[{"pushed":"2019-08-01T00:00:01Z","request":{"block":"BLx"}}],"backlog":[{"level":"notice","events":[{"message":"validated"}]}],"current_request":{"pushed":"2019-08-01T00:00:00Z","treated":"2019-08-01T00:00:00Z","request":{"block":"BLy"}}}`)
+	summaries := rawBody(`[{"chain_id":"NetXdQprcVkpaWU","status":{"phase":"running","since":"2019-08-01T00:00:00Z"}}]`)
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /workers/block_validator":                  state,
+		"GET /workers/chain_validators":                 summaries,
+		"GET /workers/chain_validators/NetXdQprcVkpaWU": state,
+		"GET /workers/prevalidators":                    summaries,
+		"GET /workers/prevalidators/NetXdQprcVkpaWU":    state,
+	})
+	ctx := context.Background()
+	validator, err := client.GetBlockValidatorWorker(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validator.Status.Phase != "running" || len(validator.PendingRequests) != 1 || validator.CurrentRequest == nil || len(validator.Backlog[0].Events) != 1 {
+		t.Fatalf("unexpected state %+v", validator)
+	}
+	for _, list := range []func(context.Context) (int, error){
+		func(ctx context.Context) (int, error) {
+			w, err := client.GetChainValidatorWorkers(ctx)
+			return len(w), err
+		},
+		func(ctx context.Context) (int, error) {
+			w, err := client.GetPrevalidatorWorkers(ctx)
+			return len(w), err
+		},
+	} {
+		if n, err := list(ctx); err != nil || n != 1 {
+			t.Fatalf("unexpected workers %d %v", n, err)
+		}
+	}
+	if _, err := client.GetChainValidatorWorker(ctx, "NetXdQprcVkpaWU"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetPrevalidatorWorker(ctx, "NetXdQprcVkpaWU"); err != nil {
+		t.Fatal(err)
+	}
+}
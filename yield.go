@@ -0,0 +1,78 @@
+package tgo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// secondsPerYear is the length of a year used to annualize yields
+const secondsPerYear = 365.25 * 24 * 60 * 60
+
+// StakingYield projects the rewards of a delegate from its share of the stake, amounts are in
+// mutez and yields are percentages of the staking balance
+type StakingYield struct {
+	StakingBalance int64
+	TotalStaking   int64
+	// Share is the fraction of the stake held by the delegate, the expected fraction of rights
+	Share float64
+	// CycleRewards are the rewards expected per cycle when every block is baked at priority 0
+	// with all its endorsements
+	CycleRewards  int64
+	CycleYield    float64
+	CyclesPerYear float64
+	// AnnualYield is CycleYield over a year without compounding
+	AnnualYield float64
+}
+
+// EstimateStakingYield projects the per-cycle and annualized returns of stakingBalance out of
+// totalStaking, the staking balance of all the active delegates, under constants
+func EstimateStakingYield(constants Constants, stakingBalance, totalStaking int64) (StakingYield, error) {
+	if totalStaking <= 0 || stakingBalance < 0 || stakingBalance > totalStaking {
+		return StakingYield{}, errors.New("staking balance must be between 0 and a positive total staking")
+	}
+	if constants.BlocksPerCycle <= 0 || len(constants.TimeBetweenBlocks) == 0 {
+		return StakingYield{}, errors.New("blocks_per_cycle and time_between_blocks are required")
+	}
+	blockTime, err := time.ParseDuration(constants.TimeBetweenBlocks[0] + "s")
+	if err != nil || blockTime <= 0 {
+		return StakingYield{}, errors.New("invalid time_between_blocks")
+	}
+	bakingReward := firstMutez(constants.BlockReward)
+	if len(constants.BakingRewardPerEndorsement) > 0 {
+		bakingReward = constants.BakingRewardPerEndorsement[0] * constants.EndorsersPerBlock
+	}
+	blockRewards := bakingReward + firstMutez(constants.EndorsementReward)*constants.EndorsersPerBlock
+
+	yield := StakingYield{StakingBalance: stakingBalance, TotalStaking: totalStaking}
+	yield.Share = float64(stakingBalance) / float64(totalStaking)
+	yield.CycleRewards = int64(yield.Share * float64(blockRewards*constants.BlocksPerCycle))
+	if stakingBalance > 0 {
+		yield.CycleYield = float64(yield.CycleRewards) * 100 / float64(stakingBalance)
+	}
+	yield.CyclesPerYear = secondsPerYear / (blockTime.Seconds() * float64(constants.BlocksPerCycle))
+	yield.AnnualYield = yield.CycleYield * yield.CyclesPerYear
+	return yield, nil
+}
+
+// GetStakingYield estimates the staking yield of delegate from the constants and its staking
+// balance at head, totalStaking being the staking balance of all the active delegates
+func (rpc *RPC) GetStakingYield(ctx context.Context, delegate string, totalStaking int64) (StakingYield, error) {
+	constants, err := rpc.GetConstants(ctx, "head")
+	if err != nil {
+		return StakingYield{}, err
+	}
+	staking, err := rpc.GetStakingBalance(ctx, "head", delegate)
+	if err != nil {
+		return StakingYield{}, err
+	}
+	return EstimateStakingYield(constants, staking, totalStaking)
+}
+
+// firstMutez returns the priority 0 entry of rewards
+func firstMutez(rewards MutezList) int64 {
+	if len(rewards) == 0 {
+		return 0
+	}
+	return rewards[0]
+}
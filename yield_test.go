@@ -0,0 +1,48 @@
+package tgo_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	tgo "github.com/postables/TGo"
+)
+
+func TestGetStakingYield(t *testing.T) {
+	delegate := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+	_, client := newFakeNode(t, map[string]interface{}{
+		"GET /chains/main/blocks/head/context/constants": map[string]interface{}{
+			"blocks_per_cycle": 4096, "time_between_blocks": []string{"60", "40"}, "endorsers_per_block": 32,
+			"baking_reward_per_endorsement": []string{"1250000", "187500"}, "endorsement_reward": []string{"1250000", "833333"},
+		},
+		"GET /chains/main/blocks/head/context/delegates/" + delegate + "/staking_balance": "1000000000000",
+	})
+	yield, err := client.GetStakingYield(context.Background(), delegate, 100000000000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1% of 4096 blocks rewarding 40 tez for baking and 40 tez for endorsing
+	if yield.Share != 0.01 || yield.CycleRewards != 3276800000 {
+		t.Fatalf("unexpected yield %+v", yield)
+	}
+	if math.Abs(yield.CycleYield-0.32768) > 1e-9 || math.Abs(yield.CyclesPerYear-128.408203125) > 1e-9 {
+		t.Fatalf("unexpected yield %+v", yield)
+	}
+	if math.Abs(yield.AnnualYield-0.32768*128.408203125) > 1e-9 {
+		t.Fatalf("unexpected annual yield %v", yield.AnnualYield)
+	}
+
+	// before babylon the block reward is flat
+	constants := tgo.Constants{BlocksPerCycle: 4096, TimeBetweenBlocks: []string{"60"}, EndorsersPerBlock: 32,
+		BlockReward: tgo.MutezList{16000000}, EndorsementReward: tgo.MutezList{2000000}}
+	yield, err = tgo.EstimateStakingYield(constants, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if yield.CycleRewards != 2048*80000000 {
+		t.Fatalf("unexpected cycle rewards %d", yield.CycleRewards)
+	}
+	if _, err := tgo.EstimateStakingYield(constants, 3, 2); err == nil {
+		t.Fatal("expected error for a staking balance above the total")
+	}
+}
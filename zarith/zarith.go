@@ -0,0 +1,112 @@
+// Package zarith encodes and decodes the variable length integers of the Tezos binary format,
+// used for amounts, fees, counters and limits of operations and for integers packed by PACK
+package zarith
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrTruncated is returned when the input ends before the last byte of the integer
+	ErrTruncated = errors.New("zarith: truncated integer")
+	// ErrNotCanonical is returned for encodings ending with a zero byte, which are rejected
+	// by the node
+	ErrNotCanonical = errors.New("zarith: trailing zero byte")
+	// ErrNegative is returned when encoding a negative natural
+	ErrNegative = errors.New("zarith: negative natural")
+)
+
+// AppendNat appends the natural n: seven bits per byte starting with the least significant,
+// the high bit of each byte flags a continuation
+func AppendNat(out []byte, n *big.Int) ([]byte, error) {
+	if n.Sign() < 0 {
+		return nil, ErrNegative
+	}
+	return appendBits(out, new(big.Int).Set(n)), nil
+}
+
+// AppendInt appends the signed integer i: the first byte carries the continuation flag, the
+// sign and six bits, following bytes seven bits each
+func AppendInt(out []byte, i *big.Int) []byte {
+	abs := new(big.Int).Abs(i)
+	first := lowByte(abs) & 0x3f
+	if i.Sign() < 0 {
+		first |= 0x40
+	}
+	abs.Rsh(abs, 6)
+	if abs.Sign() == 0 {
+		return append(out, first)
+	}
+	return appendBits(append(out, first|0x80), abs)
+}
+
+// DecodeNat decodes a natural at the start of b and returns it with the number of bytes read
+func DecodeNat(b []byte) (*big.Int, int, error) {
+	n := new(big.Int)
+	read, err := readBits(b, n, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return n, read, nil
+}
+
+// DecodeInt decodes a signed integer at the start of b and returns it with the number of
+// bytes read
+func DecodeInt(b []byte) (*big.Int, int, error) {
+	if len(b) == 0 {
+		return nil, 0, ErrTruncated
+	}
+	i := big.NewInt(int64(b[0] & 0x3f))
+	read := 1
+	if b[0]&0x80 != 0 {
+		rest := new(big.Int)
+		n, err := readBits(b[1:], rest, 6)
+		if err != nil {
+			return nil, 0, err
+		}
+		i.Or(i, rest)
+		read += n
+	}
+	if b[0]&0x40 != 0 {
+		i.Neg(i)
+	}
+	return i, read, nil
+}
+
+// appendBits appends the non negative n seven bits per byte, consuming n
+func appendBits(out []byte, n *big.Int) []byte {
+	for {
+		b := lowByte(n) & 0x7f
+		n.Rsh(n, 7)
+		if n.Sign() == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+// readBits reads seven bits per byte of b into n, shifted by shift bits, and returns the
+// number of bytes read
+func readBits(b []byte, n *big.Int, shift uint) (int, error) {
+	for i, c := range b {
+		n.Or(n, new(big.Int).Lsh(big.NewInt(int64(c&0x7f)), shift))
+		shift += 7
+		if c&0x80 == 0 {
+			if c == 0 && (i > 0 || shift > 7) {
+				return 0, ErrNotCanonical
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, ErrTruncated
+}
+
+// lowByte returns the least significant byte of a non negative integer
+func lowByte(i *big.Int) byte {
+	words := i.Bits()
+	if len(words) == 0 {
+		return 0
+	}
+	return byte(words[0])
+}
@@ -0,0 +1,83 @@
+package zarith_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/postables/TGo/zarith"
+)
+
+func TestNat(t *testing.T) {
+	huge, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	for _, c := range []struct {
+		n    *big.Int
+		want string
+	}{
+		{big.NewInt(0), "00"},
+		{big.NewInt(1), "01"},
+		{big.NewInt(127), "7f"},
+		{big.NewInt(128), "8001"},
+		{big.NewInt(10000), "904e"},
+		{huge, "808080f5ddb8ebe4b56c"},
+	} {
+		b, err := zarith.AppendNat(nil, c.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(b) != c.want {
+			t.Fatalf("expected %s for %s got %x", c.want, c.n, b)
+		}
+		n, read, err := zarith.DecodeNat(append(b, 0xff))
+		if err != nil || read != len(b) || n.Cmp(c.n) != 0 {
+			t.Fatalf("decoding %x got %s, %d, %v", b, n, read, err)
+		}
+	}
+	if _, err := zarith.AppendNat(nil, big.NewInt(-1)); err != zarith.ErrNegative {
+		t.Fatalf("expected ErrNegative got %v", err)
+	}
+}
+
+func TestInt(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-1000000000000000000000", 10)
+	for _, c := range []struct {
+		i    *big.Int
+		want string
+	}{
+		{big.NewInt(0), "00"},
+		{big.NewInt(1), "01"},
+		{big.NewInt(-1), "41"},
+		{big.NewInt(63), "3f"},
+		{big.NewInt(64), "8001"},
+		{big.NewInt(-64), "c001"},
+		{huge, "c08080eabbf1d6c9ebd801"},
+	} {
+		b := zarith.AppendInt(nil, c.i)
+		if hex.EncodeToString(b) != c.want {
+			t.Fatalf("expected %s for %s got %x", c.want, c.i, b)
+		}
+		i, read, err := zarith.DecodeInt(b)
+		if err != nil || read != len(b) || i.Cmp(c.i) != 0 {
+			t.Fatalf("decoding %x got %s, %d, %v", b, i, read, err)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, c := range []struct {
+		in  string
+		err error
+	}{
+		{"", zarith.ErrTruncated},
+		{"80", zarith.ErrTruncated},
+		{"8000", zarith.ErrNotCanonical},
+	} {
+		b, _ := hex.DecodeString(c.in)
+		if _, _, err := zarith.DecodeNat(b); err != c.err {
+			t.Fatalf("expected %v decoding nat %s got %v", c.err, c.in, err)
+		}
+		if _, _, err := zarith.DecodeInt(b); err != c.err {
+			t.Fatalf("expected %v decoding int %s got %v", c.err, c.in, err)
+		}
+	}
+}